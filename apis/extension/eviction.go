@@ -0,0 +1,28 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import corev1 "k8s.io/api/core/v1"
+
+const (
+	// PodConditionEvictionRequested indicates the pod is going to be evicted by koordlet. The owner of the pod can
+	// watch the condition to checkpoint the work before the pod is terminated.
+	PodConditionEvictionRequested corev1.PodConditionType = PodDomainPrefix + "/EvictionRequested"
+	// PodConditionKilled indicates the containers of the pod are killed directly by koordlet to reclaim the resources
+	// urgently, e.g. when the node is close to the global OOM.
+	PodConditionKilled corev1.PodConditionType = PodDomainPrefix + "/Killed"
+)
@@ -29,6 +29,16 @@ const (
 	MidMemory   corev1.ResourceName = ResourceDomainPrefix + "mid-memory"
 )
 
+const (
+	// io throughput resources which are only reported in NodeMetric for observation, not allocatable
+	ResourceNetRxBandwidth     corev1.ResourceName = DomainPrefix + "net-rx-bandwidth"
+	ResourceNetTxBandwidth     corev1.ResourceName = DomainPrefix + "net-tx-bandwidth"
+	ResourceDiskReadBandwidth  corev1.ResourceName = DomainPrefix + "disk-read-bandwidth"
+	ResourceDiskWriteBandwidth corev1.ResourceName = DomainPrefix + "disk-write-bandwidth"
+	ResourceDiskReadIOPS       corev1.ResourceName = DomainPrefix + "disk-read-iops"
+	ResourceDiskWriteIOPS      corev1.ResourceName = DomainPrefix + "disk-write-iops"
+)
+
 const (
 	// AnnotationExtendedResourceSpec specifies the resource requirements of extended resources for internal usage.
 	// It annotates the requests/limits of extended resources and can be used by runtime proxy and koordlet that
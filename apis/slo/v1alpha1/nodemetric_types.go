@@ -40,7 +40,8 @@ type NodeMetricInfo struct {
 	NodeUsage ResourceMap `json:"nodeUsage,omitempty"`
 	// AggregatedNodeUsages will report only if there are enough samples
 	AggregatedNodeUsages []AggregatedUsage `json:"aggregatedNodeUsages,omitempty"`
-	// SystemUsage is the resource usage of daemon processes and OS kernel, calculated by `NodeUsage - sum(podUsage)`
+	// SystemUsage is the resource usage of daemon processes and OS kernel, calculated by
+	// `NodeUsage - sum(podUsage) - sum(hostAppUsage)`
 	SystemUsage ResourceMap `json:"systemUsage,omitempty"`
 	// AggregatedSystemUsages will report only if there are enough samples
 	// Deleted pods will be excluded during aggregation
@@ -60,6 +61,33 @@ type PodMetricInfo struct {
 	Extensions *ExtensionsMap `json:"extensions,omitempty"`
 }
 
+const (
+	// PodMetricExtensionCPUBurstBudget is the key of PodCPUBurstBudget in the Extensions of PodMetricInfo.
+	PodMetricExtensionCPUBurstBudget = "cpuBurstBudget"
+)
+
+// PodCPUBurstBudget is the cfs quota burst budget of the pod accounted by koordlet, measured in the time of running at
+// twice the cpu limit. The cfs quota of the pod scales down once the used budget reaches the allowed.
+type PodCPUBurstBudget struct {
+	// Used is the burst budget consumed during the burst period
+	Used metav1.Duration `json:"used,omitempty"`
+	// Allowed is the burst budget allowed during the burst period, decided by `CFSQuotaBurstPeriodSeconds` and
+	// `CFSQuotaBurstPercent` of the CPUBurstConfig
+	Allowed metav1.Duration `json:"allowed,omitempty"`
+}
+
+// HostApplicationMetricInfo is the resource usage of the host application declared in the NodeSLO
+type HostApplicationMetricInfo struct {
+	// Name of the host application
+	Name string `json:"name,omitempty"`
+	// Usage is the resource usage of the host application
+	Usage ResourceMap `json:"usage,omitempty"`
+	// Priority class of the application
+	Priority apiext.PriorityClass `json:"priority,omitempty"`
+	// QoS class of the application
+	QoS apiext.QoSClass `json:"qos,omitempty"`
+}
+
 // NodeMetricSpec defines the desired state of NodeMetric
 type NodeMetricSpec struct {
 	// CollectPolicy defines the Metric collection policy
@@ -101,6 +129,9 @@ type NodeMetricStatus struct {
 
 	// ProdReclaimableMetric is the indicator statistics of Prod type resources reclaimable
 	ProdReclaimableMetric *ReclaimableMetric `json:"prodReclaimableMetric,omitempty"`
+
+	// HostApplicationMetric contains the metrics of out-of-band applications on node.
+	HostApplicationMetric []*HostApplicationMetricInfo `json:"hostApplicationMetric,omitempty"`
 }
 
 // +genclient
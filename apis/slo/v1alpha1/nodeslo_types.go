@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,8 +28,23 @@ import (
 type CPUQOS struct {
 	// group identity value for pods, default = 0
 	GroupIdentity *int64 `json:"groupIdentity,omitempty" validate:"omitempty,min=-1,max=2"`
+	// CoreSchedPolicy indicates how the core scheduling cookies are assigned to the pods, default = none.
+	// Tasks with different cookies never run concurrently on the SMT siblings of a physical core, so assigning a
+	// cookie to BE pods is enough to isolate them from LS pods (which have no cookie).
+	// 1. "none": the pods have no cookie.
+	// 2. "pod": each pod has a unique cookie, which also isolates the pods of the class from each other.
+	// 3. "group": all pods of the class share one cookie.
+	CoreSchedPolicy *CoreSchedPolicy `json:"coreSchedPolicy,omitempty" validate:"omitempty,oneof=none pod group"`
 }
 
+type CoreSchedPolicy string
+
+const (
+	CoreSchedPolicyNone  CoreSchedPolicy = "none"
+	CoreSchedPolicyPod   CoreSchedPolicy = "pod"
+	CoreSchedPolicyGroup CoreSchedPolicy = "group"
+)
+
 // MemoryQOS enables memory qos features.
 type MemoryQOS struct {
 	// memcg qos
@@ -178,11 +194,72 @@ type BlkIOQOSCfg struct {
 	BlkIOQOS `json:",inline"`
 }
 
+// NetworkQOS shapes the network bandwidth of pods belonging to a qos class.
+// All the percentages are relative to the total bandwidth of the node network interface.
+type NetworkQOS struct {
+	// IngressRequestPercent describes the guaranteed ingress bandwidth of the qos class.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	IngressRequestPercent *int64 `json:"ingressRequestPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// IngressLimitPercent describes the maximum ingress bandwidth of the qos class.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	IngressLimitPercent *int64 `json:"ingressLimitPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// EgressRequestPercent describes the guaranteed egress bandwidth of the qos class.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	EgressRequestPercent *int64 `json:"egressRequestPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// EgressLimitPercent describes the maximum egress bandwidth of the qos class.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	EgressLimitPercent *int64 `json:"egressLimitPercent,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+// NetworkQOSCfg stores node-level config of network qos
+type NetworkQOSCfg struct {
+	// Enable indicates whether the network qos is enabled.
+	Enable     *bool `json:"enable,omitempty"`
+	NetworkQOS `json:",inline"`
+}
+
+// SwapQOS describes the swap usage of the qos class, which relies on the swap accounting of cgroups-v2.
+type SwapQOS struct {
+	// SwapLimitPercent describes the maximum swap the qos class can use, in percentage of the node swap capacity.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	SwapLimitPercent *int64 `json:"swapLimitPercent,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+// SwapQOSCfg stores node-level config of swap qos
+type SwapQOSCfg struct {
+	// Enable indicates whether the swap qos is enabled.
+	Enable  *bool `json:"enable,omitempty"`
+	SwapQOS `json:",inline"`
+}
+
+// HugepageQOS describes the hugepage limits of the qos class.
+type HugepageQOS struct {
+	// Limits describes the maximum hugepage usage of the qos class for each page size, e.g. hugepages-2Mi=1Gi.
+	// The page sizes not specified are unlimited. It only works for the LS and BE classes which have the qos-level
+	// cgroups, while the limits of the pod-level cgroups are set by kubelet.
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+}
+
+// HugepageQOSCfg stores node-level config of hugepage qos
+type HugepageQOSCfg struct {
+	// Enable indicates whether the hugepage qos is enabled.
+	Enable      *bool `json:"enable,omitempty"`
+	HugepageQOS `json:",inline"`
+}
+
 type ResourceQOS struct {
-	CPUQOS     *CPUQOSCfg     `json:"cpuQOS,omitempty"`
-	MemoryQOS  *MemoryQOSCfg  `json:"memoryQOS,omitempty"`
-	BlkIOQOS   *BlkIOQOSCfg   `json:"blkioQOS,omitempty"`
-	ResctrlQOS *ResctrlQOSCfg `json:"resctrlQOS,omitempty"`
+	CPUQOS      *CPUQOSCfg      `json:"cpuQOS,omitempty"`
+	MemoryQOS   *MemoryQOSCfg   `json:"memoryQOS,omitempty"`
+	BlkIOQOS    *BlkIOQOSCfg    `json:"blkioQOS,omitempty"`
+	ResctrlQOS  *ResctrlQOSCfg  `json:"resctrlQOS,omitempty"`
+	NetworkQOS  *NetworkQOSCfg  `json:"networkQOS,omitempty"`
+	SwapQOS     *SwapQOSCfg     `json:"swapQOS,omitempty"`
+	HugepageQOS *HugepageQOSCfg `json:"hugepageQOS,omitempty"`
 }
 
 type ResourceQOSStrategy struct {
@@ -216,6 +293,13 @@ const (
 	EvictByAllocatablePolicy CPUEvictPolicy = "evictByAllocatable"
 )
 
+type MemoryEvictPolicy string
+
+const (
+	EvictByMemoryUsagePolicy MemoryEvictPolicy = "evictByUsage"
+	EvictByMemoryPSIPolicy   MemoryEvictPolicy = "evictByPSI"
+)
+
 type ResourceThresholdStrategy struct {
 	// whether the strategy is enabled, default = false
 	Enable *bool `json:"enable,omitempty"`
@@ -235,6 +319,33 @@ type ResourceThresholdStrategy struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=0
 	MemoryEvictLowerPercent *int64 `json:"memoryEvictLowerPercent,omitempty" validate:"omitempty,min=0,max=100,ltfield=MemoryEvictThresholdPercent"`
+	// critical: BE containers are killed directly instead of evicted when the memory usage exceeds
+	// MemoryEvictKillThresholdPercent, which should be larger than MemoryEvictThresholdPercent. Disabled if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemoryEvictKillThresholdPercent *int64 `json:"memoryEvictKillThresholdPercent,omitempty" validate:"omitempty,min=0,max=100,gtfield=MemoryEvictThresholdPercent"`
+	// MemoryEvictPolicy defines the policy for the BEMemoryEvict feature.
+	// Default: `evictByUsage`.
+	MemoryEvictPolicy MemoryEvictPolicy `json:"memoryEvictPolicy,omitempty"`
+	// when the policy is `evictByPSI`, BE pods are evicted if avg(memory full avg10 pressure) of any prod pod exceeds
+	// MemoryEvictPSIFullAvg10ThresholdPercent, default = 5
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemoryEvictPSIFullAvg10ThresholdPercent *int64 `json:"memoryEvictPSIFullAvg10ThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// when the policy is `evictByPSI`, BE pods are evicted if avg(memory some avg10 pressure) of any prod pod exceeds
+	// MemoryEvictPSISomeAvg10ThresholdPercent, default = 20
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemoryEvictPSISomeAvg10ThresholdPercent *int64 `json:"memoryEvictPSISomeAvg10ThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// avg(memory pressure) is calculated based on the most recent MemoryEvictPSITimeWindowSeconds data
+	MemoryEvictPSITimeWindowSeconds *int64 `json:"memoryEvictPSITimeWindowSeconds,omitempty" validate:"omitempty,gt=0"`
+	// memory suppress threshold percentage (0,100), the prod pods begin to be reclaimed above it. BE memory is limited
+	// to keep the node memory usage under it, which should be less than MemoryEvictThresholdPercent. Disabled if not set.
+	// It is usually set to the memoryReclaimThresholdPercent of the colocation strategy, which is only used by the
+	// slo-controller to calculate the batch resources and is not delivered to the koordlet.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemorySuppressThresholdPercent *int64 `json:"memorySuppressThresholdPercent,omitempty" validate:"omitempty,min=0,max=100,ltfield=MemoryEvictThresholdPercent"`
 
 	// be.satisfactionRate = be.CPURealLimit/be.CPURequest
 	// if be.satisfactionRate > CPUEvictBESatisfactionUpperPercent/100, then stop to evict.
@@ -275,6 +386,15 @@ type ResctrlQOS struct {
 	MBAPercent *int64 `json:"mbaPercent,omitempty" validate:"omitempty,min=0,max=100"`
 }
 
+// PodResctrlQOSConfig is the resctrl config specified in the pod annotation. The pod is bound to a dedicated resctrl
+// group instead of the shared group of its QoS class.
+type PodResctrlQOSConfig struct {
+	// Group is the name of the resctrl group shared by the pods of the same workload.
+	// The pod uses its own resctrl group if empty.
+	Group      string `json:"group,omitempty"`
+	ResctrlQOS `json:",inline"`
+}
+
 type CPUBurstPolicy string
 
 const (
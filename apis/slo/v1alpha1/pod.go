@@ -30,6 +30,8 @@ const (
 	AnnotationPodMemoryQoS = apiext.DomainPrefix + "memoryQOS"
 
 	AnnotationPodBlkioQoS = apiext.DomainPrefix + "blkioQOS"
+
+	AnnotationPodResctrlQoS = apiext.DomainPrefix + "resctrlQOS"
 )
 
 func GetPodCPUBurstConfig(pod *corev1.Pod) (*CPUBurstConfig, error) {
@@ -64,3 +66,19 @@ func GetPodMemoryQoSConfig(pod *corev1.Pod) (*PodMemoryQOSConfig, error) {
 	}
 	return &cfg, nil
 }
+
+func GetPodResctrlQoSConfig(pod *corev1.Pod) (*PodResctrlQOSConfig, error) {
+	if pod == nil || pod.Annotations == nil {
+		return nil, nil
+	}
+	value, exist := pod.Annotations[AnnotationPodResctrlQoS]
+	if !exist {
+		return nil, nil
+	}
+	cfg := PodResctrlQOSConfig{}
+	err := json.Unmarshal([]byte(value), &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
@@ -194,6 +194,11 @@ func (in *CPUQOS) DeepCopyInto(out *CPUQOS) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CoreSchedPolicy != nil {
+		in, out := &in.CoreSchedPolicy, &out.CoreSchedPolicy
+		*out = new(CoreSchedPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUQOS.
@@ -242,6 +247,22 @@ func (in *CgroupPath) DeepCopy() *CgroupPath {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostApplicationMetricInfo) DeepCopyInto(out *HostApplicationMetricInfo) {
+	*out = *in
+	in.Usage.DeepCopyInto(&out.Usage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostApplicationMetricInfo.
+func (in *HostApplicationMetricInfo) DeepCopy() *HostApplicationMetricInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(HostApplicationMetricInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostApplicationSpec) DeepCopyInto(out *HostApplicationSpec) {
 	*out = *in
@@ -282,6 +303,49 @@ func (in *HostApplicationStrategy) DeepCopy() *HostApplicationStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HugepageQOS) DeepCopyInto(out *HugepageQOS) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugepageQOS.
+func (in *HugepageQOS) DeepCopy() *HugepageQOS {
+	if in == nil {
+		return nil
+	}
+	out := new(HugepageQOS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HugepageQOSCfg) DeepCopyInto(out *HugepageQOSCfg) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	in.HugepageQOS.DeepCopyInto(&out.HugepageQOS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugepageQOSCfg.
+func (in *HugepageQOSCfg) DeepCopy() *HugepageQOSCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(HugepageQOSCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IOCfg) DeepCopyInto(out *IOCfg) {
 	*out = *in
@@ -413,6 +477,62 @@ func (in *MemoryQOSCfg) DeepCopy() *MemoryQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkQOS) DeepCopyInto(out *NetworkQOS) {
+	*out = *in
+	if in.IngressRequestPercent != nil {
+		in, out := &in.IngressRequestPercent, &out.IngressRequestPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IngressLimitPercent != nil {
+		in, out := &in.IngressLimitPercent, &out.IngressLimitPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EgressRequestPercent != nil {
+		in, out := &in.EgressRequestPercent, &out.EgressRequestPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EgressLimitPercent != nil {
+		in, out := &in.EgressLimitPercent, &out.EgressLimitPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkQOS.
+func (in *NetworkQOS) DeepCopy() *NetworkQOS {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkQOS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkQOSCfg) DeepCopyInto(out *NetworkQOSCfg) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	in.NetworkQOS.DeepCopyInto(&out.NetworkQOS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkQOSCfg.
+func (in *NetworkQOSCfg) DeepCopy() *NetworkQOSCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkQOSCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeMetric) DeepCopyInto(out *NodeMetric) {
 	*out = *in
@@ -586,6 +706,17 @@ func (in *NodeMetricStatus) DeepCopyInto(out *NodeMetricStatus) {
 		*out = new(ReclaimableMetric)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostApplicationMetric != nil {
+		in, out := &in.HostApplicationMetric, &out.HostApplicationMetric
+		*out = make([]*HostApplicationMetricInfo, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(HostApplicationMetricInfo)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricStatus.
@@ -718,6 +849,23 @@ func (in *NodeSLOStatus) DeepCopy() *NodeSLOStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodCPUBurstBudget) DeepCopyInto(out *PodCPUBurstBudget) {
+	*out = *in
+	out.Used = in.Used
+	out.Allowed = in.Allowed
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodCPUBurstBudget.
+func (in *PodCPUBurstBudget) DeepCopy() *PodCPUBurstBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodCPUBurstBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodMemoryQOSConfig) DeepCopyInto(out *PodMemoryQOSConfig) {
 	*out = *in
@@ -754,6 +902,22 @@ func (in *PodMetricInfo) DeepCopy() *PodMetricInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodResctrlQOSConfig) DeepCopyInto(out *PodResctrlQOSConfig) {
+	*out = *in
+	in.ResctrlQOS.DeepCopyInto(&out.ResctrlQOS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodResctrlQOSConfig.
+func (in *PodResctrlQOSConfig) DeepCopy() *PodResctrlQOSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodResctrlQOSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReclaimableMetric) DeepCopyInto(out *ReclaimableMetric) {
 	*out = *in
@@ -873,6 +1037,21 @@ func (in *ResourceQOS) DeepCopyInto(out *ResourceQOS) {
 		*out = new(ResctrlQOSCfg)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NetworkQOS != nil {
+		in, out := &in.NetworkQOS, &out.NetworkQOS
+		*out = new(NetworkQOSCfg)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SwapQOS != nil {
+		in, out := &in.SwapQOS, &out.SwapQOS
+		*out = new(SwapQOSCfg)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HugepageQOS != nil {
+		in, out := &in.HugepageQOS, &out.HugepageQOS
+		*out = new(HugepageQOSCfg)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQOS.
@@ -948,6 +1127,31 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MemoryEvictKillThresholdPercent != nil {
+		in, out := &in.MemoryEvictKillThresholdPercent, &out.MemoryEvictKillThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryEvictPSIFullAvg10ThresholdPercent != nil {
+		in, out := &in.MemoryEvictPSIFullAvg10ThresholdPercent, &out.MemoryEvictPSIFullAvg10ThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryEvictPSISomeAvg10ThresholdPercent != nil {
+		in, out := &in.MemoryEvictPSISomeAvg10ThresholdPercent, &out.MemoryEvictPSISomeAvg10ThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryEvictPSITimeWindowSeconds != nil {
+		in, out := &in.MemoryEvictPSITimeWindowSeconds, &out.MemoryEvictPSITimeWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemorySuppressThresholdPercent != nil {
+		in, out := &in.MemorySuppressThresholdPercent, &out.MemorySuppressThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
 	if in.CPUEvictBESatisfactionUpperPercent != nil {
 		in, out := &in.CPUEvictBESatisfactionUpperPercent, &out.CPUEvictBESatisfactionUpperPercent
 		*out = new(int64)
@@ -980,6 +1184,47 @@ func (in *ResourceThresholdStrategy) DeepCopy() *ResourceThresholdStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwapQOS) DeepCopyInto(out *SwapQOS) {
+	*out = *in
+	if in.SwapLimitPercent != nil {
+		in, out := &in.SwapLimitPercent, &out.SwapLimitPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwapQOS.
+func (in *SwapQOS) DeepCopy() *SwapQOS {
+	if in == nil {
+		return nil
+	}
+	out := new(SwapQOS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwapQOSCfg) DeepCopyInto(out *SwapQOSCfg) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	in.SwapQOS.DeepCopyInto(&out.SwapQOS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwapQOSCfg.
+func (in *SwapQOSCfg) DeepCopy() *SwapQOSCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(SwapQOSCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemStrategy) DeepCopyInto(out *SystemStrategy) {
 	*out = *in
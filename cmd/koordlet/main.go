@@ -33,6 +33,8 @@ import (
 	agent "github.com/koordinator-sh/koordinator/pkg/koordlet"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/config"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 )
 
 func main() {
@@ -84,10 +86,25 @@ func main() {
 		if features.DefaultKoordletFeatureGate.Enabled(features.AuditEventsHTTPHandler) {
 			mux.HandleFunc("/events", audit.HttpHandler())
 		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.PredictionHTTPHandler) {
+			mux.HandleFunc(prediction.PredictionPath, prediction.HttpHandler(d.PredictServer()))
+		}
 		// http.HandleFunc("/healthz", d.HealthzHandler())
 		klog.Fatalf("Prometheus monitoring failed: %v", http.ListenAndServe(*options.ServerAddr, mux))
 	}()
 
+	// Expose the metric cache query endpoint on the local address only
+	if features.DefaultKoordletFeatureGate.Enabled(features.MetricCacheQueryHTTPHandler) {
+		go func() {
+			klog.Infof("Starting metric cache query server on %v", *options.QueryAddr)
+			engine := d.PromQLEngine()
+			mux := http.NewServeMux()
+			mux.HandleFunc(metriccache.PromQLQueryPath, engine.QueryHandler())
+			mux.HandleFunc(metriccache.PromQLQueryRangePath, engine.QueryRangeHandler())
+			klog.Fatalf("Metric cache query server failed: %v", http.ListenAndServe(*options.QueryAddr, mux))
+		}()
+	}
+
 	// Start the Cmd
 	klog.Info("Starting the koordlet daemon")
 	d.Run(stopCtx.Done())
@@ -64,6 +64,13 @@ const (
 	// BEMemoryEvict evict best-effort pod based on node memory usage.
 	BEMemoryEvict featuregate.Feature = "BEMemoryEvict"
 
+	// owner: @zwzhang0107 @saintube
+	// alpha: v1.5
+	//
+	// BEMemorySuppress limits the memory of best-effort pods based on node memory usage, forcing them into reclaim
+	// before the eviction is necessary.
+	BEMemorySuppress featuregate.Feature = "BEMemorySuppress"
+
 	// owner: @saintube @zwzhang0107
 	// alpha: v0.2
 	// beta: v1.1
@@ -133,6 +140,60 @@ const (
 	//
 	// ColdPageCollector enables coldPageCollector feature of koordlet.
 	ColdPageCollector featuregate.Feature = "ColdPageCollector"
+
+	// owner: @TheBeatles1994 @chzhj
+	// alpha: v1.5
+	//
+	// NetQOS shapes the network bandwidth of pods according to their qos classes.
+	NetQOS featuregate.Feature = "NetQOS"
+
+	// owner: @songtao98 @zwzhang0107
+	// alpha: v1.5
+	//
+	// NetIOCollector enables the network throughput collector of koordlet.
+	NetIOCollector featuregate.Feature = "NetIOCollector"
+
+	// owner: @songtao98 @zwzhang0107
+	// alpha: v1.5
+	//
+	// DiskIOCollector enables the block io throughput collector of koordlet.
+	DiskIOCollector featuregate.Feature = "DiskIOCollector"
+
+	// owner: @zwzhang0107
+	// alpha: v1.5
+	//
+	// MetricCacheQueryHTTPHandler is used to query the metric cache with PromQL from the koordlet local query address.
+	MetricCacheQueryHTTPHandler featuregate.Feature = "MetricCacheQueryHTTPHandler"
+
+	// owner: @zwzhang0107
+	// alpha: v1.5
+	//
+	// PredictionHTTPHandler is used to list the prediction results from koordlet port.
+	PredictionHTTPHandler featuregate.Feature = "PredictionHTTPHandler"
+
+	// owner: @songtao98 @zwzhang0107
+	// alpha: v1.5
+	//
+	// InterferenceDetect detects the interference on LS pods by CPI and CPU PSI, and suppresses BE pods progressively.
+	InterferenceDetect featuregate.Feature = "InterferenceDetect"
+
+	// owner: @saintube @zwzhang0107
+	// alpha: v1.5
+	//
+	// ResctrlCollector enables the collector of the llc occupancy and memory bandwidth of the resctrl groups.
+	ResctrlCollector featuregate.Feature = "ResctrlCollector"
+
+	// owner: @saintube @zwzhang0107
+	// alpha: v1.5
+	//
+	// SwapHugepageQOS limits the swap usage of BE pods and the hugepage usage of each qos class.
+	SwapHugepageQOS featuregate.Feature = "SwapHugepageQOS"
+
+	// owner: @songtao98 @zwzhang0107
+	// alpha: v1.5
+	//
+	// HostApplicationCollector collects the resource usage of the host applications declared in the NodeSLO.
+	HostApplicationCollector featuregate.Feature = "HostApplicationCollector"
 )
 
 func init() {
@@ -144,23 +205,33 @@ var (
 	DefaultKoordletFeatureGate        featuregate.FeatureGate        = DefaultMutableKoordletFeatureGate
 
 	defaultKoordletFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-		AuditEvents:            {Default: false, PreRelease: featuregate.Alpha},
-		AuditEventsHTTPHandler: {Default: false, PreRelease: featuregate.Alpha},
-		BECPUSuppress:          {Default: true, PreRelease: featuregate.Beta},
-		BECPUManager:           {Default: false, PreRelease: featuregate.Alpha},
-		BECPUEvict:             {Default: false, PreRelease: featuregate.Alpha},
-		BEMemoryEvict:          {Default: false, PreRelease: featuregate.Alpha},
-		CPUBurst:               {Default: true, PreRelease: featuregate.Beta},
-		SystemConfig:           {Default: false, PreRelease: featuregate.Alpha},
-		RdtResctrl:             {Default: true, PreRelease: featuregate.Beta},
-		CgroupReconcile:        {Default: false, PreRelease: featuregate.Alpha},
-		NodeTopologyReport:     {Default: true, PreRelease: featuregate.Beta},
-		Accelerators:           {Default: false, PreRelease: featuregate.Alpha},
-		CPICollector:           {Default: false, PreRelease: featuregate.Alpha},
-		Libpfm4:                {Default: false, PreRelease: featuregate.Alpha},
-		PSICollector:           {Default: false, PreRelease: featuregate.Alpha},
-		BlkIOReconcile:         {Default: false, PreRelease: featuregate.Alpha},
-		ColdPageCollector:      {Default: false, PreRelease: featuregate.Alpha},
+		AuditEvents:                 {Default: false, PreRelease: featuregate.Alpha},
+		AuditEventsHTTPHandler:      {Default: false, PreRelease: featuregate.Alpha},
+		BECPUSuppress:               {Default: true, PreRelease: featuregate.Beta},
+		BECPUManager:                {Default: false, PreRelease: featuregate.Alpha},
+		BECPUEvict:                  {Default: false, PreRelease: featuregate.Alpha},
+		BEMemoryEvict:               {Default: false, PreRelease: featuregate.Alpha},
+		BEMemorySuppress:            {Default: false, PreRelease: featuregate.Alpha},
+		CPUBurst:                    {Default: true, PreRelease: featuregate.Beta},
+		SystemConfig:                {Default: false, PreRelease: featuregate.Alpha},
+		RdtResctrl:                  {Default: true, PreRelease: featuregate.Beta},
+		CgroupReconcile:             {Default: false, PreRelease: featuregate.Alpha},
+		NodeTopologyReport:          {Default: true, PreRelease: featuregate.Beta},
+		Accelerators:                {Default: false, PreRelease: featuregate.Alpha},
+		CPICollector:                {Default: false, PreRelease: featuregate.Alpha},
+		Libpfm4:                     {Default: false, PreRelease: featuregate.Alpha},
+		PSICollector:                {Default: false, PreRelease: featuregate.Alpha},
+		BlkIOReconcile:              {Default: false, PreRelease: featuregate.Alpha},
+		ColdPageCollector:           {Default: false, PreRelease: featuregate.Alpha},
+		NetQOS:                      {Default: false, PreRelease: featuregate.Alpha},
+		NetIOCollector:              {Default: false, PreRelease: featuregate.Alpha},
+		DiskIOCollector:             {Default: false, PreRelease: featuregate.Alpha},
+		MetricCacheQueryHTTPHandler: {Default: false, PreRelease: featuregate.Alpha},
+		PredictionHTTPHandler:       {Default: false, PreRelease: featuregate.Alpha},
+		InterferenceDetect:          {Default: false, PreRelease: featuregate.Alpha},
+		ResctrlCollector:            {Default: false, PreRelease: featuregate.Alpha},
+		SwapHugepageQOS:             {Default: false, PreRelease: featuregate.Alpha},
+		HostApplicationCollector:    {Default: false, PreRelease: featuregate.Alpha},
 	}
 )
 
@@ -172,7 +243,7 @@ func IsFeatureDisabled(nodeSLO *slov1alpha1.NodeSLO, feature featuregate.Feature
 
 	spec := nodeSLO.Spec
 	switch feature {
-	case BECPUSuppress, BEMemoryEvict, BECPUEvict:
+	case BECPUSuppress, BEMemoryEvict, BEMemorySuppress, BECPUEvict, InterferenceDetect:
 		if spec.ResourceUsedThresholdWithBE == nil || spec.ResourceUsedThresholdWithBE.Enable == nil {
 			return true, fmt.Errorf("cannot parse feature config for invalid nodeSLO %v", nodeSLO)
 		}
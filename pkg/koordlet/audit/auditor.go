@@ -23,7 +23,9 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,12 +38,20 @@ var (
 )
 
 func NewAuditor(c *Config) Auditor {
-	logWriter := NewFluentEventLogger(c.LogDir, c.MaxDiskSpaceMB, c.Verbose)
+	broadcaster := newEventBroadcaster()
+	logWriter := &eventFluentWriter{
+		writer: &broadcastEventWriter{
+			EventWriter: NewEventLogger(c.LogDir, c.MaxDiskSpaceMB, c.Verbose),
+			verbose:     c.Verbose,
+			broadcaster: broadcaster,
+		},
+	}
 	logReader := NewEventReader(c.LogDir)
 	return &auditor{
 		config:        c,
 		logWriter:     logWriter,
 		logReader:     logReader,
+		broadcaster:   broadcaster,
 		activeReaders: list.New(),
 	}
 }
@@ -54,6 +64,9 @@ type Auditor interface {
 	Run(stopCh <-chan struct{}) error
 	LoggerWriter() EventFluentWriter
 	HttpHandler() func(http.ResponseWriter, *http.Request)
+	// Subscribe returns the channel to receive the events logged since now, and the func to cancel the subscription.
+	// The events are dropped if the subscriber falls behind.
+	Subscribe() (<-chan *Event, func())
 }
 
 type JsonResponse struct {
@@ -74,6 +87,9 @@ type auditor struct {
 	logWriter EventFluentWriter
 	logReader EventReader
 
+	broadcaster   *eventBroadcaster
+	activeStreams int32
+
 	activeReadersMutex sync.Mutex
 	activeReaders      *list.List
 }
@@ -82,6 +98,10 @@ func (a *auditor) LoggerWriter() EventFluentWriter {
 	return a.logWriter
 }
 
+func (a *auditor) Subscribe() (<-chan *Event, func()) {
+	return a.broadcaster.Subscribe(defaultSubscriberBufferSize)
+}
+
 func (a *auditor) findActiveReader(token string) *readerContext {
 	a.activeReadersMutex.Lock()
 	defer a.activeReadersMutex.Unlock()
@@ -134,6 +154,16 @@ func (a *auditor) HttpHandler() func(http.ResponseWriter, *http.Request) {
 
 		klog.Infof("handle query client=%v pageToken=%v size=%v", r.RemoteAddr, pageToken, sizeStr)
 
+		filter, err := ParseEventFilter(r.URL.Query())
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if isAccepted(r, "text/event-stream") {
+			a.serveEventStream(rw, r, filter)
+			return
+		}
+
 		size := a.config.DefaultEventsLimit
 		if s, err := strconv.Atoi(sizeStr); err == nil {
 			if s > a.config.MaxEventsLimit {
@@ -174,7 +204,8 @@ func (a *auditor) HttpHandler() func(http.ResponseWriter, *http.Request) {
 				return
 			}
 			activeReader.refreshAt = time.Now()
-			for i := 0; i < size; i++ {
+			failures := 0
+			for len(events)+failures < size {
 				event, err := activeReader.reverseIterator.Next()
 				if err == io.EOF {
 					readEOF = true
@@ -182,6 +213,15 @@ func (a *auditor) HttpHandler() func(http.ResponseWriter, *http.Request) {
 				}
 				if err != nil {
 					klog.V(4).Infof("reader %v failed: %v", activeReader.pageToken, err)
+					failures++
+					continue
+				}
+				// the events are read in reverse order, so the rest ones are all out of the time range
+				if filter.isBeforeRange(event) {
+					readEOF = true
+					break
+				}
+				if !filter.Match(event) {
 					continue
 				}
 
@@ -189,15 +229,7 @@ func (a *auditor) HttpHandler() func(http.ResponseWriter, *http.Request) {
 			}
 		}()
 
-		acceptJson := false
-		acceptTypes := r.Header["Accept"]
-		for i := range acceptTypes {
-			if acceptTypes[i] == "application/json" {
-				acceptJson = true
-				break
-			}
-		}
-		if acceptJson {
+		if isAccepted(r, "application/json") {
 			response := &JsonResponse{Events: events}
 			if !readEOF {
 				response.NextPageToken = activeReader.pageToken
@@ -227,6 +259,69 @@ func (a *auditor) HttpHandler() func(http.ResponseWriter, *http.Request) {
 	}
 }
 
+// serveEventStream pushes the newly logged events which match the filter as server-sent events until the client
+// disconnects, e.g. `curl -H "Accept: text/event-stream" "http://${koordlet-server-addr}/events?level=1"`
+func (a *auditor) serveEventStream(rw http.ResponseWriter, r *http.Request, filter *EventFilter) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	if atomic.AddInt32(&a.activeStreams, 1) > int32(a.config.MaxConcurrentReaders) {
+		atomic.AddInt32(&a.activeStreams, -1)
+		http.Error(rw, fmt.Sprintf("streams exceed the limit(%v)", a.config.MaxConcurrentReaders), http.StatusTooManyRequests)
+		return
+	}
+	defer atomic.AddInt32(&a.activeStreams, -1)
+
+	events, cancel := a.Subscribe()
+	defer cancel()
+	klog.Infof("start streaming events to client=%v", r.RemoteAddr)
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			klog.Infof("stop streaming events to client=%v", r.RemoteAddr)
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !filter.Until.IsZero() && event.CreatedAt.After(filter.Until) {
+				return
+			}
+			if !filter.Match(event) {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				klog.V(4).Infof("failed to marshal event for client=%v, err: %v", r.RemoteAddr, err)
+				continue
+			}
+			if _, err = fmt.Fprintf(rw, "data: %s\n\n", data); err != nil {
+				klog.V(4).Infof("failed to stream events to client=%v, err: %v", r.RemoteAddr, err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func isAccepted(r *http.Request, contentType string) bool {
+	for _, accept := range r.Header["Accept"] {
+		if strings.Contains(accept, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *auditor) Run(stopCh <-chan struct{}) error {
 	timer := time.NewTicker(a.config.TickerDuration)
 	defer timer.Stop()
@@ -260,6 +355,11 @@ func (a *emptyAuditor) HttpHandler() func(http.ResponseWriter, *http.Request) {
 	return func(rw http.ResponseWriter, r *http.Request) {}
 }
 
+func (a *emptyAuditor) Subscribe() (<-chan *Event, func()) {
+	// nothing is logged, so the subscribers never receive an event
+	return make(chan *Event), func() {}
+}
+
 type emptyEventFluentWriter struct {
 }
 
@@ -305,3 +405,8 @@ func V(verbose int) *EventHelper {
 func HttpHandler() func(http.ResponseWriter, *http.Request) {
 	return Default.HttpHandler()
 }
+
+// Subscribe receives the audit events logged since now with the `Default` auditor.
+func Subscribe() (<-chan *Event, func()) {
+	return Default.Subscribe()
+}
@@ -17,12 +17,17 @@ limitations under the License.
 package audit
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -253,3 +258,149 @@ func TestAuditorLoggerMaxActiveReaders(t *testing.T) {
 		t.Error("failed to expired reader")
 	}
 }
+
+func TestAuditorLoggerFilters(t *testing.T) {
+	tempDir := t.TempDir()
+
+	c := NewDefaultConfig()
+	c.LogDir = tempDir
+	ad := NewAuditor(c)
+	logger := ad.LoggerWriter()
+	for i := 0; i < 10; i++ {
+		logger.V(i%3).Pod("default", fmt.Sprintf("pod-%d", i%2)).Reason(fmt.Sprintf("reason-%d", i%5)).Message("%d", i).Do()
+	}
+	logger.Flush()
+
+	server := httptest.NewServer(http.HandlerFunc(ad.HttpHandler()))
+	defer server.Close()
+
+	client := http.Client{}
+	tests := []struct {
+		name         string
+		query        string
+		wantStatus   int
+		wantMessages []string
+	}{
+		{
+			name:         "filter by pod and level",
+			query:        "namespace=default&name=pod-0&level=1",
+			wantStatus:   http.StatusOK,
+			wantMessages: []string{"6", "4", "0"},
+		},
+		{
+			name:         "filter by reasons",
+			query:        "reason=reason-1,reason-3",
+			wantStatus:   http.StatusOK,
+			wantMessages: []string{"8", "6", "3", "1"},
+		},
+		{
+			name:         "out of the time range",
+			query:        "since=" + time.Now().Add(time.Hour).Format(time.RFC3339),
+			wantStatus:   http.StatusOK,
+			wantMessages: []string{},
+		},
+		{
+			name:       "invalid filter",
+			query:      "level=x",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", makeRequestUrl(10, server.URL, "")+"&"+tt.query, nil)
+			req.Header.Add("Accept", "application/json")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("failed to get events: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("unexpected status, expected %v actual %v", tt.wantStatus, resp.StatusCode)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			body, _ := io.ReadAll(resp.Body)
+			response := &JsonResponse{}
+			if err := json.Unmarshal(body, response); err != nil {
+				t.Fatal(err)
+			}
+			messages := make([]string, 0, len(response.Events))
+			for _, event := range response.Events {
+				messages = append(messages, event.Message)
+			}
+			if !reflect.DeepEqual(tt.wantMessages, messages) {
+				t.Errorf("failed to filter events, expected %v actual %v", tt.wantMessages, messages)
+			}
+			if response.NextPageToken != "" {
+				t.Errorf("expected to read to the end, got token %v", response.NextPageToken)
+			}
+		})
+	}
+}
+
+func TestAuditorLoggerEventStream(t *testing.T) {
+	tempDir := t.TempDir()
+
+	c := NewDefaultConfig()
+	c.LogDir = tempDir
+	c.MaxConcurrentReaders = 1
+	ad := NewAuditor(c)
+	logger := ad.LoggerWriter()
+
+	server := httptest.NewServer(http.HandlerFunc(ad.HttpHandler()))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := http.Client{}
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"?reason=evictPod", nil)
+	req.Header.Add("Accept", "text/event-stream")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to stream events: %v", err)
+	}
+	defer resp.Body.Close()
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("unexpected content type %v", contentType)
+	}
+
+	// streams exceed the limit
+	req2, _ := http.NewRequest("GET", server.URL, nil)
+	req2.Header.Add("Accept", "text/event-stream")
+	resp2, err := client.Do(req2)
+	if err != nil {
+		t.Fatalf("failed to stream events: %v", err)
+	}
+	resp2.Body.Close()
+	if resp2.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("unexpected status, expected %v actual %v", http.StatusTooManyRequests, resp2.StatusCode)
+	}
+
+	logger.V(1).Node().Reason("suppressBE").Message("ignored").Do()
+	logger.V(0).Pod("default", "pod-1").Reason("evictPod").Message("evicted").Do()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read the stream: %v", err)
+	}
+	if !strings.HasPrefix(line, "data: ") {
+		t.Fatalf("unexpected line %q", line)
+	}
+	event := &Event{}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Reason != "evictPod" || event.Name != "pod-1" || event.Level != "0" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	cancel()
+	for i := 0; i < 100 && atomic.LoadInt32(&ad.(*auditor).activeStreams) != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&ad.(*auditor).activeStreams) != 0 {
+		t.Error("failed to close the stream")
+	}
+}
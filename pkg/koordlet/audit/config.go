@@ -30,6 +30,12 @@ type Config struct {
 	DefaultEventsLimit   int
 	MaxEventsLimit       int
 	TickerDuration       time.Duration
+	// KubeEventVerbose mirrors the events whose verbose is no larger than it to Kubernetes Events, e.g. 0 for the
+	// evictions, 1 for the BE cfs quota suppressions and 3 for the other suppressions and cgroup updates. The events
+	// of the dry-run mode are not mirrored. Negative disables the mirroring.
+	KubeEventVerbose int
+	// KubeEventReasons is a comma-separated list of the reasons to mirror, empty means all reasons.
+	KubeEventReasons string
 }
 
 func NewDefaultConfig() *Config {
@@ -42,6 +48,7 @@ func NewDefaultConfig() *Config {
 		DefaultEventsLimit:   256,
 		MaxEventsLimit:       2048,
 		TickerDuration:       time.Minute,
+		KubeEventVerbose:     -1,
 	}
 }
 
@@ -51,4 +58,6 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.MaxDiskSpaceMB, "audit-max-disk-space-mb", c.MaxDiskSpaceMB, "Max disk space occupied of audit log")
 	fs.IntVar(&c.MaxConcurrentReaders, "audit-max-concurrent-readers", c.MaxConcurrentReaders, "Max concurrent readers of the audit log")
 	fs.IntVar(&c.MaxEventsLimit, "audit-max-events-limit", c.MaxEventsLimit, "Max events limit in one request of the audit log")
+	fs.IntVar(&c.KubeEventVerbose, "audit-kube-event-verbose", c.KubeEventVerbose, "Mirror the audit events whose verbose is no larger than it to Kubernetes Events, e.g. 0 for evictions, 1 for BE cfs quota suppressions and 3 for other suppressions and cgroup updates. Dry-run events are not mirrored. Negative disables the mirroring")
+	fs.StringVar(&c.KubeEventReasons, "audit-kube-event-reasons", c.KubeEventReasons, "Comma-separated reasons of the audit events to mirror to Kubernetes Events, empty means all reasons")
 }
@@ -34,6 +34,7 @@ func Test_NewDefaultConfig(t *testing.T) {
 		DefaultEventsLimit:   256,
 		MaxEventsLimit:       2048,
 		TickerDuration:       time.Minute,
+		KubeEventVerbose:     -1,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -45,6 +46,8 @@ func Test_InitFlags(t *testing.T) {
 		"--audit-log-dir=/tmp/log/koordlet",
 		"--audit-verbose=4",
 		"--audit-max-disk-space-mb=32",
+		"--audit-kube-event-verbose=1",
+		"--audit-kube-event-reasons=a,b",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -82,6 +85,8 @@ func Test_InitFlags(t *testing.T) {
 				DefaultEventsLimit:   256,
 				MaxEventsLimit:       2048,
 				TickerDuration:       time.Minute,
+				KubeEventVerbose:     1,
+				KubeEventReasons:     "a,b",
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
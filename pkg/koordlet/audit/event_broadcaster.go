@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"sync"
+)
+
+const defaultSubscriberBufferSize = 128
+
+// eventBroadcaster delivers the logged events to the subscribers, e.g. the live tailing readers and the event sinks.
+// The delivery never blocks the logging, so the events are dropped for the subscribers falling behind.
+type eventBroadcaster struct {
+	mutex       sync.RWMutex
+	subscribers map[chan *Event]struct{}
+}
+
+func newEventBroadcaster() *eventBroadcaster {
+	return &eventBroadcaster{
+		subscribers: map[chan *Event]struct{}{},
+	}
+}
+
+// Subscribe returns the channel to receive the events and the func to cancel the subscription.
+func (b *eventBroadcaster) Subscribe(bufferSize int) (<-chan *Event, func()) {
+	ch := make(chan *Event, bufferSize)
+	b.mutex.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mutex.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subscribers, ch)
+			b.mutex.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+func (b *eventBroadcaster) Publish(event *Event) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for ch := range b.subscribers {
+		e := *event
+		select {
+		case ch <- &e:
+		default:
+		}
+	}
+}
+
+func (b *eventBroadcaster) Len() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return len(b.subscribers)
+}
+
+// broadcastEventWriter publishes the events which are written to the underlying writer.
+type broadcastEventWriter struct {
+	EventWriter
+	verbose     int
+	broadcaster *eventBroadcaster
+}
+
+func (w *broadcastEventWriter) Log(verbose int, event *Event) error {
+	err := w.EventWriter.Log(verbose, event)
+	if event == nil || (w.verbose > 0 && verbose > w.verbose) {
+		return err
+	}
+	w.broadcaster.Publish(event)
+	return err
+}
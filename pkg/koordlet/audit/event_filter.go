@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventFilter selects the audit events by the time range, object, reasons and level.
+// The zero value matches all events.
+type EventFilter struct {
+	// Since and Until bound the CreatedAt of the events, zero means unbounded.
+	Since time.Time
+	Until time.Time
+	// Namespace and Name match the object of the events.
+	Namespace string
+	Name      string
+	// Reasons matches any of the event reasons.
+	Reasons map[string]struct{}
+	// MaxLevel matches the events whose verbose is no larger than it, negative means unlimited.
+	MaxLevel int
+}
+
+// NewEventFilter returns a filter which matches all events.
+func NewEventFilter() *EventFilter {
+	return &EventFilter{MaxLevel: -1}
+}
+
+// ParseEventFilter parses the filter from the query parameters, e.g.
+// ?since=2023-01-02T15:04:05Z&until=2023-01-02T16:04:05Z&namespace=default&name=pod-1&reason=a,b&level=1
+func ParseEventFilter(query url.Values) (*EventFilter, error) {
+	f := NewEventFilter()
+	var err error
+	if s := query.Get("since"); s != "" {
+		if f.Since, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, fmt.Errorf("invalid since %q, err: %w", s, err)
+		}
+	}
+	if s := query.Get("until"); s != "" {
+		if f.Until, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, fmt.Errorf("invalid until %q, err: %w", s, err)
+		}
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return nil, fmt.Errorf("until %v is before since %v", f.Until, f.Since)
+	}
+	f.Namespace = query.Get("namespace")
+	f.Name = query.Get("name")
+	if s := query.Get("reason"); s != "" {
+		f.Reasons = parseReasons(s)
+	}
+	if s := query.Get("level"); s != "" {
+		if f.MaxLevel, err = strconv.Atoi(s); err != nil || f.MaxLevel < 0 {
+			return nil, fmt.Errorf("invalid level %q", s)
+		}
+	}
+	return f, nil
+}
+
+func parseReasons(s string) map[string]struct{} {
+	reasons := map[string]struct{}{}
+	for _, reason := range strings.Split(s, ",") {
+		if reason = strings.TrimSpace(reason); reason != "" {
+			reasons[reason] = struct{}{}
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	return reasons
+}
+
+// Match returns whether the event is selected by the filter.
+func (f *EventFilter) Match(event *Event) bool {
+	if event == nil {
+		return false
+	}
+	if !f.Since.IsZero() && event.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.CreatedAt.After(f.Until) {
+		return false
+	}
+	if f.Namespace != "" && event.Namespace != f.Namespace {
+		return false
+	}
+	if f.Name != "" && event.Name != f.Name {
+		return false
+	}
+	if f.Reasons != nil {
+		if _, ok := f.Reasons[event.Reason]; !ok {
+			return false
+		}
+	}
+	if f.MaxLevel >= 0 {
+		// events logged without a level cannot be selected by the level
+		level, err := strconv.Atoi(event.Level)
+		if err != nil || level > f.MaxLevel {
+			return false
+		}
+	}
+	return true
+}
+
+// isBeforeRange returns whether the event is earlier than the time range, so the reverse reading can stop.
+func (f *EventFilter) isBeforeRange(event *Event) bool {
+	return event != nil && !f.Since.IsZero() && event.CreatedAt.Before(f.Since)
+}
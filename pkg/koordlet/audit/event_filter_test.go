@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEventFilter(t *testing.T) {
+	since := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	until := since.Add(time.Hour)
+	tests := []struct {
+		name    string
+		query   string
+		want    *EventFilter
+		wantErr bool
+	}{
+		{
+			name:  "empty query",
+			query: "",
+			want:  NewEventFilter(),
+		},
+		{
+			name:  "all filters",
+			query: "since=2023-01-02T15:04:05Z&until=2023-01-02T16:04:05Z&namespace=default&name=pod-1&reason=a,,b&level=1",
+			want: &EventFilter{
+				Since:     since,
+				Until:     until,
+				Namespace: "default",
+				Name:      "pod-1",
+				Reasons:   map[string]struct{}{"a": {}, "b": {}},
+				MaxLevel:  1,
+			},
+		},
+		{
+			name:    "invalid since",
+			query:   "since=yesterday",
+			wantErr: true,
+		},
+		{
+			name:    "until before since",
+			query:   "since=2023-01-02T16:04:05Z&until=2023-01-02T15:04:05Z",
+			wantErr: true,
+		},
+		{
+			name:    "invalid level",
+			query:   "level=-1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			got, gotErr := ParseEventFilter(query)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventFilter_Match(t *testing.T) {
+	now := time.Now()
+	testEvent := &Event{
+		CreatedAt: now,
+		Type:      "pod",
+		Level:     "1",
+		Namespace: "default",
+		Name:      "pod-1",
+		Reason:    "a",
+	}
+	tests := []struct {
+		name   string
+		filter *EventFilter
+		event  *Event
+		want   bool
+	}{
+		{
+			name:   "nil event",
+			filter: NewEventFilter(),
+			want:   false,
+		},
+		{
+			name:   "match all",
+			filter: NewEventFilter(),
+			event:  testEvent,
+			want:   true,
+		},
+		{
+			name:   "match all filters",
+			filter: &EventFilter{Since: now.Add(-time.Minute), Until: now.Add(time.Minute), Namespace: "default", Name: "pod-1", Reasons: map[string]struct{}{"a": {}}, MaxLevel: 1},
+			event:  testEvent,
+			want:   true,
+		},
+		{
+			name:   "before since",
+			filter: &EventFilter{Since: now.Add(time.Minute), MaxLevel: -1},
+			event:  testEvent,
+			want:   false,
+		},
+		{
+			name:   "after until",
+			filter: &EventFilter{Until: now.Add(-time.Minute), MaxLevel: -1},
+			event:  testEvent,
+			want:   false,
+		},
+		{
+			name:   "namespace mismatched",
+			filter: &EventFilter{Namespace: "kube-system", MaxLevel: -1},
+			event:  testEvent,
+			want:   false,
+		},
+		{
+			name:   "reason mismatched",
+			filter: &EventFilter{Reasons: map[string]struct{}{"b": {}}, MaxLevel: -1},
+			event:  testEvent,
+			want:   false,
+		},
+		{
+			name:   "level too large",
+			filter: &EventFilter{MaxLevel: 0},
+			event:  testEvent,
+			want:   false,
+		},
+		{
+			name:   "level missing",
+			filter: &EventFilter{MaxLevel: 3},
+			event:  &Event{Type: "node"},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(tt.event))
+		})
+	}
+}
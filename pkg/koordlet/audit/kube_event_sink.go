@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+// PodGetter returns the pod of the namespace and name, or nil if not found.
+type PodGetter func(namespace, name string) *corev1.Pod
+
+// KubeEventSink mirrors the selected audit events to Kubernetes Events, so that the key actions of koordlet like
+// the evictions and suppressions can be seen with `kubectl get events` or `kubectl describe`.
+type KubeEventSink struct {
+	auditor   Auditor
+	recorder  record.EventRecorder
+	filter    *EventFilter
+	nodeRef   *corev1.ObjectReference
+	podGetter PodGetter
+}
+
+// NewKubeEventSink returns a sink mirroring the events of the auditor. It returns nil if the mirroring is disabled.
+func NewKubeEventSink(c *Config, auditor Auditor, recorder record.EventRecorder, nodeName string, podGetter PodGetter) *KubeEventSink {
+	if c.KubeEventVerbose < 0 {
+		return nil
+	}
+	filter := NewEventFilter()
+	filter.MaxLevel = c.KubeEventVerbose
+	filter.Reasons = parseReasons(c.KubeEventReasons)
+	return &KubeEventSink{
+		auditor:  auditor,
+		recorder: recorder,
+		filter:   filter,
+		// same as the node reference of the kubelet events
+		nodeRef: &corev1.ObjectReference{
+			Kind: "Node",
+			Name: nodeName,
+			UID:  types.UID(nodeName),
+		},
+		podGetter: podGetter,
+	}
+}
+
+func (s *KubeEventSink) Run(stopCh <-chan struct{}) {
+	events, cancel := s.auditor.Subscribe()
+	defer cancel()
+	klog.Infof("start mirroring audit events to kubernetes events, verbose %v, reasons %v",
+		s.filter.MaxLevel, s.filter.Reasons)
+	for {
+		select {
+		case <-stopCh:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			s.record(event)
+		}
+	}
+}
+
+func (s *KubeEventSink) record(event *Event) {
+	// the dry-run decisions are not applied, so they are kept in the audit log only
+	if event.DryRun || !s.filter.Match(event) || event.Reason == "" {
+		return
+	}
+	eventType := corev1.EventTypeNormal
+	// the events of verbose 0 are the most serious ones, e.g. evictions
+	if level, err := strconv.Atoi(event.Level); err == nil && level == 0 {
+		eventType = corev1.EventTypeWarning
+	}
+	message := event.Message
+	if event.Container != "" {
+		message = fmt.Sprintf("container %s: %s", event.Container, message)
+	}
+
+	if event.Type == "pod" {
+		s.recorder.Event(s.getPodReference(event.Namespace, event.Name), eventType, event.Reason, message)
+		return
+	}
+	if event.Type != "node" && event.Name != "" {
+		message = fmt.Sprintf("%s %s: %s", event.Type, event.Name, message)
+	}
+	s.recorder.Event(s.nodeRef, eventType, event.Reason, message)
+}
+
+func (s *KubeEventSink) getPodReference(namespace, name string) *corev1.ObjectReference {
+	ref := &corev1.ObjectReference{
+		Kind:       "Pod",
+		APIVersion: "v1",
+		Namespace:  namespace,
+		Name:       name,
+	}
+	// the uid is required to show the event in `kubectl describe pod`
+	if s.podGetter != nil {
+		if pod := s.podGetter(namespace, name); pod != nil {
+			ref.UID = pod.UID
+			ref.ResourceVersion = pod.ResourceVersion
+		}
+	}
+	return ref
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestNewKubeEventSink(t *testing.T) {
+	c := NewDefaultConfig()
+	c.LogDir = t.TempDir()
+	assert.Nil(t, NewKubeEventSink(c, NewAuditor(c), record.NewFakeRecorder(1), "test-node", nil))
+	c.KubeEventVerbose = 1
+	c.KubeEventReasons = "a,b"
+	sink := NewKubeEventSink(c, NewAuditor(c), record.NewFakeRecorder(1), "test-node", nil)
+	assert.NotNil(t, sink)
+	assert.Equal(t, 1, sink.filter.MaxLevel)
+	assert.Equal(t, map[string]struct{}{"a": {}, "b": {}}, sink.filter.Reasons)
+}
+
+func TestKubeEventSink_Run(t *testing.T) {
+	c := NewDefaultConfig()
+	c.LogDir = t.TempDir()
+	c.KubeEventVerbose = 1
+	c.KubeEventReasons = "evictPod,suppressBE"
+	ad := NewAuditor(c)
+	recorder := record.NewFakeRecorder(10)
+	podGetter := func(namespace, name string) *corev1.Pod {
+		if namespace == "default" && name == "pod-1" {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: "uid-1"}}
+		}
+		return nil
+	}
+	sink := NewKubeEventSink(c, ad, recorder, "test-node", podGetter)
+	assert.Equal(t, &corev1.ObjectReference{Kind: "Pod", APIVersion: "v1", Namespace: "default", Name: "pod-1", UID: "uid-1"}, sink.getPodReference("default", "pod-1"))
+	assert.Equal(t, &corev1.ObjectReference{Kind: "Pod", APIVersion: "v1", Namespace: "default", Name: "pod-2"}, sink.getPodReference("default", "pod-2"))
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go sink.Run(stopCh)
+	assert.Eventually(t, func() bool {
+		return ad.(*auditor).broadcaster.Len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	logger := ad.LoggerWriter()
+	logger.V(0).Pod("default", "pod-1").Container("c1").Reason("evictPod").Message("memory %v", "exceeded").Do()
+	logger.V(1).Node().Reason("suppressBE").Message("cpu suppressed").Do()
+	// filtered by the reason and the verbose
+	logger.V(0).Node().Reason("otherReason").Message("ignored").Do()
+	logger.V(2).Node().Reason("suppressBE").Message("ignored").Do()
+	// the dry-run events are not mirrored
+	logger.V(0).Pod("default", "pod-1").Reason("evictPod").DryRun().Message("dry-run evict").Do()
+	logger.V(1).Group("be").Reason("suppressBE").Message("cfs quota updated").Do()
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		select {
+		case e := <-recorder.Events:
+			got = append(got, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for events, got %v", got)
+		}
+	}
+	assert.Equal(t, []string{
+		"Warning evictPod container c1: memory exceeded",
+		"Normal suppressBE cpu suppressed",
+		"Normal suppressBE group be: cfs quota updated",
+	}, got)
+	select {
+	case e := <-recorder.Events:
+		t.Errorf("unexpected event %v", e)
+	default:
+	}
+}
@@ -18,6 +18,7 @@ package audit
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -44,6 +45,7 @@ type Event struct {
 	Container string    `json:"container,omitempty"`
 	Reason    string    `json:"reason,omitempty"`
 	Message   string    `json:"message,omitempty"`
+	DryRun    bool      `json:"dryRun,omitempty"`
 }
 
 // EventHelper is a helper struct use to support fluent APIs
@@ -128,6 +130,12 @@ func (e *EventHelper) Reason(reason string) *EventHelper {
 	return e
 }
 
+// DryRun marks the event as a decision of the dry-run mode, which is not applied
+func (e *EventHelper) DryRun() *EventHelper {
+	e.Event.DryRun = true
+	return e
+}
+
 // Message set the message as the inputs
 func (e *EventHelper) Message(format string, args ...interface{}) *EventHelper {
 	e.Event.Message = fmt.Sprintf(format, args...)
@@ -137,6 +145,7 @@ func (e *EventHelper) Message(format string, args ...interface{}) *EventHelper {
 // Do write the event to the writer
 func (e *EventHelper) Do() error {
 	e.Event.CreatedAt = time.Now().Local()
+	e.Event.Level = strconv.Itoa(e.verbose)
 	if e.writer != nil {
 		return e.writer.Log(e.verbose, &e.Event)
 	}
@@ -22,21 +22,27 @@ import (
 	"time"
 
 	topologyclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientset "k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	clientsetbeta1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	"github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/config"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	statesinformerimpl "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/impl"
@@ -54,6 +60,10 @@ func init() {
 
 type Daemon interface {
 	Run(stopCh <-chan struct{})
+	// PromQLEngine returns the engine to evaluate PromQL queries over the metric cache.
+	PromQLEngine() *metriccache.PromQLEngine
+	// PredictServer returns the server which predicts the resource usages of the node and pods.
+	PredictServer() prediction.PredictServer
 }
 
 type daemon struct {
@@ -63,6 +73,7 @@ type daemon struct {
 	qosManager     qosmanager.QOSManager
 	runtimeHook    runtimehooks.RuntimeHook
 	predictServer  prediction.PredictServer
+	auditSink      *audit.KubeEventSink
 }
 
 func NewDaemon(config *config.Configuration) (Daemon, error) {
@@ -93,6 +104,8 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 
 	cgroupDriver := system.GetCgroupDriver()
 	system.SetupCgroupPathFormatter(cgroupDriver)
+	resourceexecutor.RecordTransactionFunc = metrics.RecordResourceUpdateTransaction
+	resourceexecutor.RecordRollbackFunc = metrics.RecordResourceUpdateRollback
 
 	collectorService := metricsadvisor.NewMetricAdvisor(config.CollectorConf, statesInformer, metricCache)
 
@@ -116,10 +129,39 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 		runtimeHook:    runtimeHook,
 		predictServer:  predictServer,
 	}
+	if features.DefaultKoordletFeatureGate.Enabled(features.AuditEvents) {
+		d.auditSink = newAuditKubeEventSink(config.AuditConf, kubeClient, nodeName, statesInformer)
+	}
 
 	return d, nil
 }
 
+func newAuditKubeEventSink(c *audit.Config, kubeClient clientset.Interface, nodeName string, statesInformer statesinformer.StatesInformer) *audit.KubeEventSink {
+	if c.KubeEventVerbose < 0 {
+		return nil
+	}
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&clientcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "koordlet-audit", Host: nodeName})
+	podGetter := func(namespace, name string) *corev1.Pod {
+		for _, podMeta := range statesInformer.GetAllPods() {
+			if podMeta.Pod != nil && podMeta.Pod.Namespace == namespace && podMeta.Pod.Name == name {
+				return podMeta.Pod
+			}
+		}
+		return nil
+	}
+	return audit.NewKubeEventSink(c, audit.Default, recorder, nodeName, podGetter)
+}
+
+func (d *daemon) PromQLEngine() *metriccache.PromQLEngine {
+	return metriccache.NewPromQLEngine(d.metricCache)
+}
+
+func (d *daemon) PredictServer() prediction.PredictServer {
+	return d.predictServer
+}
+
 func (d *daemon) Run(stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	klog.Infof("Starting daemon")
@@ -176,6 +218,11 @@ func (d *daemon) Run(stopCh <-chan struct{}) {
 		}
 	}()
 
+	// start mirroring audit events
+	if d.auditSink != nil {
+		go d.auditSink.Run(stopCh)
+	}
+
 	klog.Info("Start daemon successfully")
 	<-stopCh
 	klog.Info("Shutting down daemon")
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/prometheus/prometheus/tsdb"
+	cliflag "k8s.io/component-base/cli/flag"
 )
 
 type Config struct {
@@ -32,6 +33,8 @@ type Config struct {
 	TSDBEnablePromMetrics bool
 	TSDBStripeSize        int
 	TSDBMaxBytes          int64
+	// roll up the raw samples into 1m/5m averages for the queries of long time ranges
+	TSDBEnableDownsample bool
 
 	// not necessary now since it is in-memory empty dir now
 	TSDBWALSegmentSize            int
@@ -39,6 +42,16 @@ type Config struct {
 	TSDBMinBlockDuration          time.Duration
 	TSDBMaxBlockDuration          time.Duration
 	TSDBHeadChunksWriteBufferSize int
+
+	// remote-write is disabled if the url is empty
+	RemoteWriteURL            string
+	RemoteWriteTimeout        time.Duration
+	RemoteWriteBatchSize      int
+	RemoteWriteFlushInterval  time.Duration
+	RemoteWriteMaxRetries     int
+	RemoteWriteWALPath        string
+	RemoteWriteWALMaxBytes    int64
+	RemoteWriteExternalLabels map[string]string
 }
 
 func NewDefaultConfig() *Config {
@@ -57,6 +70,13 @@ func NewDefaultConfig() *Config {
 		TSDBMinBlockDuration:          30 * time.Minute, // 30 minutes
 		TSDBMaxBlockDuration:          30 * time.Minute, // 30 minutes
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,      // 1 MB
+
+		RemoteWriteTimeout:        30 * time.Second,
+		RemoteWriteBatchSize:      1000,
+		RemoteWriteFlushInterval:  15 * time.Second,
+		RemoteWriteMaxRetries:     3,
+		RemoteWriteWALMaxBytes:    64 * 1024 * 1024, // 64 MB
+		RemoteWriteExternalLabels: map[string]string{},
 	}
 }
 
@@ -69,6 +89,7 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.TSDBEnablePromMetrics, "tsdb-enable-prometheus-metric", c.TSDBEnablePromMetrics, "Enable prometheus metric for tsdb")
 	fs.IntVar(&c.TSDBStripeSize, "tsdb-stripe-size", c.TSDBStripeSize, "Size in entries of the series hash map. Reducing the size will save memory but impact performance.")
 	fs.Int64Var(&c.TSDBMaxBytes, "tsdb-max-bytes", c.TSDBMaxBytes, "Maximum number of bytes in blocks to be retained.")
+	fs.BoolVar(&c.TSDBEnableDownsample, "tsdb-enable-downsample", c.TSDBEnableDownsample, "Enable rolling up the samples into 1m/5m averages, which are queried for the avg aggregations of the time ranges no less than 1h/5h.")
 
 	fs.IntVar(&c.TSDBWALSegmentSize, "tsdb-wal-segment-size", c.TSDBWALSegmentSize, "Byte size of WAL(Write Ahead Log).")
 	fs.Int64Var(&c.TSDBMaxBlockChunkSegmentSize, "tsdb-max-block-chunk-segment-size", c.TSDBMaxBlockChunkSegmentSize, "The max size of block chunk segment files.")
@@ -76,4 +97,13 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.TSDBMaxBlockDuration, "tsdb-max-block-duration", c.TSDBMaxBlockDuration, "The maximum timestamp range of compacted blocks, recommend >= 1h or this will cause chunks_head leak.")
 	fs.IntVar(&c.TSDBHeadChunksWriteBufferSize, "tsdb-head-chunks-write-buffer-size", c.TSDBHeadChunksWriteBufferSize, "Write buffer size used by the head chunks mapper.")
 
+	fs.StringVar(&c.RemoteWriteURL, "remote-write-url", c.RemoteWriteURL, "The url of the prometheus remote-write endpoint to export the raw metric samples. Remote-write is disabled if empty.")
+	fs.DurationVar(&c.RemoteWriteTimeout, "remote-write-timeout", c.RemoteWriteTimeout, "Timeout of each remote-write request.")
+	fs.IntVar(&c.RemoteWriteBatchSize, "remote-write-batch-size", c.RemoteWriteBatchSize, "Max number of samples in a remote-write batch.")
+	fs.DurationVar(&c.RemoteWriteFlushInterval, "remote-write-flush-interval", c.RemoteWriteFlushInterval, "Interval to flush and send the remote-write batches.")
+	fs.IntVar(&c.RemoteWriteMaxRetries, "remote-write-max-retries", c.RemoteWriteMaxRetries, "Max retries of sending a remote-write batch before waiting for the next flush.")
+	fs.StringVar(&c.RemoteWriteWALPath, "remote-write-wal-path", c.RemoteWriteWALPath, "Path of the remote-write WAL to keep the unsent batches. Default is the remote-write dir under the tsdb path.")
+	fs.Int64Var(&c.RemoteWriteWALMaxBytes, "remote-write-wal-max-bytes", c.RemoteWriteWALMaxBytes, "Maximum number of bytes of the remote-write WAL, the oldest batches are dropped when exceeded.")
+	fs.Var(cliflag.NewMapStringString(&c.RemoteWriteExternalLabels), "remote-write-external-labels", "The labels to add to the exported series, e.g. node=node-0,cluster=c0.")
+
 }
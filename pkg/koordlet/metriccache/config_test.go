@@ -41,6 +41,13 @@ func Test_NewDefaultConfig(t *testing.T) {
 		TSDBMinBlockDuration:          30 * time.Minute,
 		TSDBMaxBlockDuration:          30 * time.Minute,
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,
+
+		RemoteWriteTimeout:        30 * time.Second,
+		RemoteWriteBatchSize:      1000,
+		RemoteWriteFlushInterval:  15 * time.Second,
+		RemoteWriteMaxRetries:     3,
+		RemoteWriteWALMaxBytes:    64 * 1024 * 1024,
+		RemoteWriteExternalLabels: map[string]string{},
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -57,12 +64,22 @@ func Test_InitFlags(t *testing.T) {
 		"--tsdb-enable-prometheus-metric=false",
 		"--tsdb-stripe-size=10240",
 		"--tsdb-max-bytes=65536",
+		"--tsdb-enable-downsample=true",
 
 		"--tsdb-wal-segment-size=2048",
 		"--tsdb-max-block-chunk-segment-size=4096",
 		"--tsdb-min-block-duration=10m",
 		"--tsdb-max-block-duration=20m",
 		"--tsdb-head-chunks-write-buffer-size=512",
+
+		"--remote-write-url=http://localhost:9090/api/v1/write",
+		"--remote-write-timeout=10s",
+		"--remote-write-batch-size=100",
+		"--remote-write-flush-interval=5s",
+		"--remote-write-max-retries=5",
+		"--remote-write-wal-path=/test-remote-write/",
+		"--remote-write-wal-max-bytes=1024",
+		"--remote-write-external-labels=node=test-node,cluster=test-cluster",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -75,12 +92,22 @@ func Test_InitFlags(t *testing.T) {
 		TSDBEnablePromMetrics bool
 		TSDBStripeSize        int
 		TSDBMaxBytes          int64
+		TSDBEnableDownsample  bool
 
 		TSDBWALSegmentSize            int
 		TSDBMaxBlockChunkSegmentSize  int64
 		TSDBMinBlockDuration          time.Duration
 		TSDBMaxBlockDuration          time.Duration
 		TSDBHeadChunksWriteBufferSize int
+
+		RemoteWriteURL            string
+		RemoteWriteTimeout        time.Duration
+		RemoteWriteBatchSize      int
+		RemoteWriteFlushInterval  time.Duration
+		RemoteWriteMaxRetries     int
+		RemoteWriteWALPath        string
+		RemoteWriteWALMaxBytes    int64
+		RemoteWriteExternalLabels map[string]string
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -100,11 +127,23 @@ func Test_InitFlags(t *testing.T) {
 				TSDBEnablePromMetrics:         false,
 				TSDBStripeSize:                10240,
 				TSDBMaxBytes:                  65536,
+				TSDBEnableDownsample:          true,
 				TSDBWALSegmentSize:            2048,
 				TSDBMaxBlockChunkSegmentSize:  4096,
 				TSDBMinBlockDuration:          10 * time.Minute,
 				TSDBMaxBlockDuration:          20 * time.Minute,
 				TSDBHeadChunksWriteBufferSize: 512,
+				RemoteWriteURL:                "http://localhost:9090/api/v1/write",
+				RemoteWriteTimeout:            10 * time.Second,
+				RemoteWriteBatchSize:          100,
+				RemoteWriteFlushInterval:      5 * time.Second,
+				RemoteWriteMaxRetries:         5,
+				RemoteWriteWALPath:            "/test-remote-write/",
+				RemoteWriteWALMaxBytes:        1024,
+				RemoteWriteExternalLabels: map[string]string{
+					"node":    "test-node",
+					"cluster": "test-cluster",
+				},
 			},
 			args: args{fs: fs},
 		},
@@ -120,12 +159,22 @@ func Test_InitFlags(t *testing.T) {
 				TSDBEnablePromMetrics: tt.fields.TSDBEnablePromMetrics,
 				TSDBStripeSize:        tt.fields.TSDBStripeSize,
 				TSDBMaxBytes:          tt.fields.TSDBMaxBytes,
+				TSDBEnableDownsample:  tt.fields.TSDBEnableDownsample,
 
 				TSDBWALSegmentSize:            tt.fields.TSDBWALSegmentSize,
 				TSDBMaxBlockChunkSegmentSize:  tt.fields.TSDBMaxBlockChunkSegmentSize,
 				TSDBMinBlockDuration:          tt.fields.TSDBMinBlockDuration,
 				TSDBMaxBlockDuration:          tt.fields.TSDBMaxBlockDuration,
 				TSDBHeadChunksWriteBufferSize: tt.fields.TSDBHeadChunksWriteBufferSize,
+
+				RemoteWriteURL:            tt.fields.RemoteWriteURL,
+				RemoteWriteTimeout:        tt.fields.RemoteWriteTimeout,
+				RemoteWriteBatchSize:      tt.fields.RemoteWriteBatchSize,
+				RemoteWriteFlushInterval:  tt.fields.RemoteWriteFlushInterval,
+				RemoteWriteMaxRetries:     tt.fields.RemoteWriteMaxRetries,
+				RemoteWriteWALPath:        tt.fields.RemoteWriteWALPath,
+				RemoteWriteWALMaxBytes:    tt.fields.RemoteWriteWALMaxBytes,
+				RemoteWriteExternalLabels: tt.fields.RemoteWriteExternalLabels,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
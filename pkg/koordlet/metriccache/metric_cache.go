@@ -63,6 +63,8 @@ type metricCache struct {
 	config *Config
 	TSDBStorage
 	KVStorage
+	remoteWriteExporter *remoteWriteExporter
+	downsampler         *tsdbDownsampler
 }
 
 func NewMetricCache(cfg *Config) (MetricCache, error) {
@@ -71,14 +73,32 @@ func NewMetricCache(cfg *Config) (MetricCache, error) {
 		return nil, err
 	}
 	kvdb := NewMemoryStorage()
-	return &metricCache{
+	m := &metricCache{
 		config:      cfg,
 		TSDBStorage: tsdb,
 		KVStorage:   kvdb,
-	}, nil
+	}
+	if s, ok := tsdb.(*tsdbStorage); ok && cfg.TSDBEnableDownsample {
+		m.downsampler = newTSDBDownsampler(s)
+	}
+	if cfg.RemoteWriteURL != "" {
+		exporter, err := newRemoteWriteExporter(cfg)
+		if err != nil {
+			return nil, err
+		}
+		m.remoteWriteExporter = exporter
+		m.TSDBStorage = &remoteWriteStorage{TSDBStorage: tsdb, exporter: exporter}
+	}
+	return m, nil
 }
 
 func (m *metricCache) Run(stopCh <-chan struct{}) error {
+	if m.remoteWriteExporter != nil {
+		go m.remoteWriteExporter.Run(stopCh)
+	}
+	if m.downsampler != nil {
+		go m.downsampler.Run(stopCh)
+	}
 	<-stopCh
 	m.Close()
 	return nil
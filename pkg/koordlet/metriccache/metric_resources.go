@@ -26,22 +26,47 @@ var (
 	NodeGPUMemUsageMetric  = defaultMetricFactory.New(NodeMetricGPUMemUsage).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 	NodeGPUMemTotalMetric  = defaultMetricFactory.New(NodeMetricGPUMemTotal).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 
+	// network and disk io
+	NodeNetRxBytesMetric    = defaultMetricFactory.New(NodeMetricNetRxBytes)
+	NodeNetTxBytesMetric    = defaultMetricFactory.New(NodeMetricNetTxBytes)
+	NodeDiskReadBPSMetric   = defaultMetricFactory.New(NodeMetricDiskReadBPS)
+	NodeDiskWriteBPSMetric  = defaultMetricFactory.New(NodeMetricDiskWriteBPS)
+	NodeDiskReadIOPSMetric  = defaultMetricFactory.New(NodeMetricDiskReadIOPS)
+	NodeDiskWriteIOPSMetric = defaultMetricFactory.New(NodeMetricDiskWriteIOPS)
+
 	// define system resource usage as independent metric, although this can be calculate by node-sum(pod), but the time series are
 	// unaligned across different type of metric, which makes it hard to aggregate.
 	SystemCPUUsageMetric    = defaultMetricFactory.New(SysMetricCPUUsage)
 	SystemMemoryUsageMetric = defaultMetricFactory.New(SysMetricMemoryUsage)
 
+	// host application
+	HostAppCPUUsageMetric    = defaultMetricFactory.New(HostAppMetricCPUUsage).withPropertySchema(MetricPropertyHostAppName)
+	HostAppMemoryUsageMetric = defaultMetricFactory.New(HostAppMetricMemoryUsage).withPropertySchema(MetricPropertyHostAppName)
+
 	PodCPUUsageMetric     = defaultMetricFactory.New(PodMetricCPUUsage).withPropertySchema(MetricPropertyPodUID)
 	PodMemUsageMetric     = defaultMetricFactory.New(PodMetricMemoryUsage).withPropertySchema(MetricPropertyPodUID)
 	PodCPUThrottledMetric = defaultMetricFactory.New(PodMetricCPUThrottled).withPropertySchema(MetricPropertyPodUID)
 	PodGPUCoreUsageMetric = defaultMetricFactory.New(PodMetricGPUCoreUsage).withPropertySchema(MetricPropertyPodUID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 	PodGPUMemUsageMetric  = defaultMetricFactory.New(PodMetricGPUMemUsage).withPropertySchema(MetricPropertyPodUID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 
+	// network and disk io
+	PodNetRxBytesMetric    = defaultMetricFactory.New(PodMetricNetRxBytes).withPropertySchema(MetricPropertyPodUID)
+	PodNetTxBytesMetric    = defaultMetricFactory.New(PodMetricNetTxBytes).withPropertySchema(MetricPropertyPodUID)
+	PodDiskReadBPSMetric   = defaultMetricFactory.New(PodMetricDiskReadBPS).withPropertySchema(MetricPropertyPodUID)
+	PodDiskWriteBPSMetric  = defaultMetricFactory.New(PodMetricDiskWriteBPS).withPropertySchema(MetricPropertyPodUID)
+	PodDiskReadIOPSMetric  = defaultMetricFactory.New(PodMetricDiskReadIOPS).withPropertySchema(MetricPropertyPodUID)
+	PodDiskWriteIOPSMetric = defaultMetricFactory.New(PodMetricDiskWriteIOPS).withPropertySchema(MetricPropertyPodUID)
+
 	ContainerCPUUsageMetric     = defaultMetricFactory.New(ContainerMetricCPUUsage).withPropertySchema(MetricPropertyContainerID)
 	ContainerMemUsageMetric     = defaultMetricFactory.New(ContainerMetricMemoryUsage).withPropertySchema(MetricPropertyContainerID)
 	ContainerGPUCoreUsageMetric = defaultMetricFactory.New(ContainerMetricGPUCoreUsage).withPropertySchema(MetricPropertyContainerID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 	ContainerGPUMemUsageMetric  = defaultMetricFactory.New(ContainerMetricGPUMemUsage).withPropertySchema(MetricPropertyContainerID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 	ContainerCPUThrottledMetric = defaultMetricFactory.New(ContainerMetricCPUThrottled).withPropertySchema(MetricPropertyContainerID)
+
+	// cpu burst
+	PodCPUBurstUsedSecondsMetric    = defaultMetricFactory.New(PodMetricCPUBurstUsedSeconds).withPropertySchema(MetricPropertyPodUID)
+	PodCPUBurstAllowedSecondsMetric = defaultMetricFactory.New(PodMetricCPUBurstAllowedSeconds).withPropertySchema(MetricPropertyPodUID)
+
 	// cold memory metrics
 	NodeMemoryWithHotPageUsageMetric      = defaultMetricFactory.New(NodeMemoryWithHotPageUsage)
 	PodMemoryWithHotPageUsageMetric       = defaultMetricFactory.New(PodMemoryWithHotPageUsage).withPropertySchema(MetricPropertyPodUID)
@@ -59,6 +84,11 @@ var (
 	PodPSIMetric                       = defaultMetricFactory.New(PodMetricPSI).withPropertySchema(MetricPropertyPodUID, MetricPropertyPSIResource, MetricPropertyPSIPrecision, MetricPropertyPSIDegree)
 	PodPSICPUFullSupportedMetric       = defaultMetricFactory.New(PodMetricPSICPUFullSupported).withPropertySchema(MetricPropertyPodUID)
 
+	// resctrl
+	ResctrlLLCOccupancyMetric = defaultMetricFactory.New(ResctrlMetricLLCOccupancy).withPropertySchema(MetricPropertyResctrlGroup)
+	ResctrlMBMTotalBPSMetric  = defaultMetricFactory.New(ResctrlMetricMBMTotalBPS).withPropertySchema(MetricPropertyResctrlGroup)
+	ResctrlMBMLocalBPSMetric  = defaultMetricFactory.New(ResctrlMetricMBMLocalBPS).withPropertySchema(MetricPropertyResctrlGroup)
+
 	// BE
 	NodeBEMetric = defaultMetricFactory.New(NodeMetricBE).withPropertySchema(MetricPropertyBEResource, MetricPropertyBEAllocation)
 )
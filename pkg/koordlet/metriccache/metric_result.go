@@ -82,8 +82,19 @@ const (
 	AggregationTypeP50   AggregationType = "p50"
 	AggregationTypeLast  AggregationType = "last"
 	AggregationTypeCount AggregationType = "count"
+	AggregationTypeMax   AggregationType = "max"
+	AggregationTypeMin   AggregationType = "min"
+	// AggregationTypeStdDev is the population standard deviation
+	AggregationTypeStdDev AggregationType = "stddev"
+	// AggregationTypeRate is the per-second increase of the counter metrics
+	AggregationTypeRate AggregationType = "rate"
+	// AggregationTypeEWMA is the exponentially weighted moving average with the alpha DefaultEWMAAlpha
+	AggregationTypeEWMA AggregationType = "ewma"
 )
 
+// DefaultEWMAAlpha is the smoothing factor of the AggregationTypeEWMA
+const DefaultEWMAAlpha = 0.3
+
 // AggregateParam defines the field name of value and time in series struct
 type AggregateParam struct {
 	ValueFieldName string
@@ -169,6 +180,16 @@ func getAggregateFunc(aggregationType AggregationType) AggregationFunc {
 		return fieldLastOfMetricList
 	case AggregationTypeCount:
 		return fieldCountOfMetricList
+	case AggregationTypeMax:
+		return fieldMaxOfMetricList
+	case AggregationTypeMin:
+		return fieldMinOfMetricList
+	case AggregationTypeStdDev:
+		return fieldStdDevOfMetricList
+	case AggregationTypeRate:
+		return fieldRateOfMetricList
+	case AggregationTypeEWMA:
+		return ewmaFuncOfMetricList(DefaultEWMAAlpha)
 	default:
 		return fieldAvgOfMetricList
 	}
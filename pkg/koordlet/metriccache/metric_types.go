@@ -42,9 +42,21 @@ const (
 	NodeMetricGPUMemUsage  MetricKind = "node_gpu_memory_usage"
 	NodeMetricGPUMemTotal  MetricKind = "node_gpu_memory_total"
 
+	// network and disk io throughput of node, in bytes or operations per second
+	NodeMetricNetRxBytes    MetricKind = "node_net_rx_bytes"
+	NodeMetricNetTxBytes    MetricKind = "node_net_tx_bytes"
+	NodeMetricDiskReadBPS   MetricKind = "node_disk_read_bps"
+	NodeMetricDiskWriteBPS  MetricKind = "node_disk_write_bps"
+	NodeMetricDiskReadIOPS  MetricKind = "node_disk_read_iops"
+	NodeMetricDiskWriteIOPS MetricKind = "node_disk_write_iops"
+
 	SysMetricCPUUsage    MetricKind = "sys_cpu_usage"
 	SysMetricMemoryUsage MetricKind = "sys_memory_usage"
 
+	// out-of-band applications declared in the NodeSLO
+	HostAppMetricCPUUsage    MetricKind = "host_application_cpu_usage"
+	HostAppMetricMemoryUsage MetricKind = "host_application_memory_usage"
+
 	// NodeBE
 	NodeMetricBE MetricKind = "node_be"
 
@@ -58,6 +70,14 @@ const (
 	PodMetricGPUMemUsage  MetricKind = "pod_gpu_memory_usage"
 	// PodMetricGPUMemTotal       MetricKind = "pod_gpu_memory_total"
 
+	// network and disk io throughput of pod, in bytes or operations per second
+	PodMetricNetRxBytes    MetricKind = "pod_net_rx_bytes"
+	PodMetricNetTxBytes    MetricKind = "pod_net_tx_bytes"
+	PodMetricDiskReadBPS   MetricKind = "pod_disk_read_bps"
+	PodMetricDiskWriteBPS  MetricKind = "pod_disk_write_bps"
+	PodMetricDiskReadIOPS  MetricKind = "pod_disk_read_iops"
+	PodMetricDiskWriteIOPS MetricKind = "pod_disk_write_iops"
+
 	ContainerMetricCPUUsage     MetricKind = "container_cpu_usage"
 	ContainerMetricMemoryUsage  MetricKind = "container_memory_usage"
 	ContainerMetricGPUCoreUsage MetricKind = "container_gpu_core_usage"
@@ -67,6 +87,10 @@ const (
 	PodMetricCPUThrottled       MetricKind = "pod_cpu_throttled"
 	ContainerMetricCPUThrottled MetricKind = "container_cpu_throttled"
 
+	// cfs quota burst budget of pod accounted by the cpu burst strategy, in seconds
+	PodMetricCPUBurstUsedSeconds    MetricKind = "pod_cpu_burst_used_seconds"
+	PodMetricCPUBurstAllowedSeconds MetricKind = "pod_cpu_burst_allowed_seconds"
+
 	// CPI
 	ContainerMetricCPI MetricKind = "container_cpi"
 
@@ -76,6 +100,11 @@ const (
 	PodMetricPSI                       MetricKind = "pod_psi"
 	PodMetricPSICPUFullSupported       MetricKind = "pod_psi_cpu_full_supported"
 
+	// resctrl monitoring of the resctrl groups, the memory bandwidth is in bytes per second
+	ResctrlMetricLLCOccupancy MetricKind = "resctrl_llc_occupancy"
+	ResctrlMetricMBMTotalBPS  MetricKind = "resctrl_mbm_total_bps"
+	ResctrlMetricMBMLocalBPS  MetricKind = "resctrl_mbm_local_bps"
+
 	//cold memory metrics
 	NodeMemoryWithHotPageUsage      MetricKind = "node_memory_with_hot_page_usage"
 	PodMemoryWithHotPageUsage       MetricKind = "pod_memory_with_hot_page_usage"
@@ -103,6 +132,10 @@ const (
 
 	MetricPropertyBEResource   MetricProperty = "be_resource"
 	MetricPropertyBEAllocation MetricProperty = "be_allocation"
+
+	MetricPropertyResctrlGroup MetricProperty = "resctrl_group"
+
+	MetricPropertyHostAppName MetricProperty = "host_application_name"
 )
 
 // MetricPropertyValue is the property value
@@ -139,6 +172,8 @@ var MetricPropertiesFunc = struct {
 	PodGPU              func(string, string, string) map[MetricProperty]string
 	ContainerGPU        func(string, string, string) map[MetricProperty]string
 	NodeBE              func(string, string) map[MetricProperty]string
+	ResctrlGroup        func(string) map[MetricProperty]string
+	HostApplication     func(string) map[MetricProperty]string
 }{
 	Pod: func(podUID string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID}
@@ -170,6 +205,12 @@ var MetricPropertiesFunc = struct {
 	NodeBE: func(beResource, beResourceAllocation string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyBEResource: beResource, MetricPropertyBEAllocation: beResourceAllocation}
 	},
+	ResctrlGroup: func(group string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyResctrlGroup: group}
+	},
+	HostApplication: func(appName string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyHostAppName: appName}
+	},
 }
 
 // point is the struct to describe metric
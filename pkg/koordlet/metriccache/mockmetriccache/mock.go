@@ -27,6 +27,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	storage "github.com/prometheus/prometheus/storage"
 )
 
 // MockMetricCache is a mock of MetricCache interface.
@@ -124,6 +125,21 @@ func (mr *MockMetricCacheMockRecorder) Run(stopCh interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockMetricCache)(nil).Run), stopCh)
 }
 
+// SeriesQuerier mocks base method.
+func (m *MockMetricCache) SeriesQuerier(startTime, endTime time.Time) (storage.Querier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesQuerier", startTime, endTime)
+	ret0, _ := ret[0].(storage.Querier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SeriesQuerier indicates an expected call of SeriesQuerier.
+func (mr *MockMetricCacheMockRecorder) SeriesQuerier(startTime, endTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesQuerier", reflect.TypeOf((*MockMetricCache)(nil).SeriesQuerier), startTime, endTime)
+}
+
 // Set mocks base method.
 func (m *MockMetricCache) Set(key, value interface{}) {
 	m.ctrl.T.Helper()
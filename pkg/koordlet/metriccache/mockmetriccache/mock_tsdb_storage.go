@@ -27,6 +27,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	storage "github.com/prometheus/prometheus/storage"
 )
 
 // MockTSDBStorage is a mock of TSDBStorage interface.
@@ -95,6 +96,21 @@ func (mr *MockTSDBStorageMockRecorder) Querier(startTime, endTime interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Querier", reflect.TypeOf((*MockTSDBStorage)(nil).Querier), startTime, endTime)
 }
 
+// SeriesQuerier mocks base method.
+func (m *MockTSDBStorage) SeriesQuerier(startTime, endTime time.Time) (storage.Querier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesQuerier", startTime, endTime)
+	ret0, _ := ret[0].(storage.Querier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SeriesQuerier indicates an expected call of SeriesQuerier.
+func (mr *MockTSDBStorageMockRecorder) SeriesQuerier(startTime, endTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesQuerier", reflect.TypeOf((*MockTSDBStorage)(nil).SeriesQuerier), startTime, endTime)
+}
+
 // MockAppendable is a mock of Appendable interface.
 type MockAppendable struct {
 	ctrl     *gomock.Controller
@@ -221,6 +237,44 @@ func (mr *MockQueryableMockRecorder) Querier(startTime, endTime interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Querier", reflect.TypeOf((*MockQueryable)(nil).Querier), startTime, endTime)
 }
 
+// MockSeriesQueryable is a mock of SeriesQueryable interface.
+type MockSeriesQueryable struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesQueryableMockRecorder
+}
+
+// MockSeriesQueryableMockRecorder is the mock recorder for MockSeriesQueryable.
+type MockSeriesQueryableMockRecorder struct {
+	mock *MockSeriesQueryable
+}
+
+// NewMockSeriesQueryable creates a new mock instance.
+func NewMockSeriesQueryable(ctrl *gomock.Controller) *MockSeriesQueryable {
+	mock := &MockSeriesQueryable{ctrl: ctrl}
+	mock.recorder = &MockSeriesQueryableMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesQueryable) EXPECT() *MockSeriesQueryableMockRecorder {
+	return m.recorder
+}
+
+// SeriesQuerier mocks base method.
+func (m *MockSeriesQueryable) SeriesQuerier(startTime, endTime time.Time) (storage.Querier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeriesQuerier", startTime, endTime)
+	ret0, _ := ret[0].(storage.Querier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SeriesQuerier indicates an expected call of SeriesQuerier.
+func (mr *MockSeriesQueryableMockRecorder) SeriesQuerier(startTime, endTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeriesQuerier", reflect.TypeOf((*MockSeriesQueryable)(nil).SeriesQuerier), startTime, endTime)
+}
+
 // MockQuerier is a mock of Querier interface.
 type MockQuerier struct {
 	ctrl     *gomock.Controller
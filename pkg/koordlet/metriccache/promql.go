@@ -0,0 +1,426 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	// DefaultPromQLLookbackDelta is the max duration to look back for the latest sample of an instant vector selector
+	DefaultPromQLLookbackDelta = 5 * time.Minute
+	// maxPromQLRangePoints is the max number of steps of a range query, which is the same as prometheus
+	maxPromQLRangePoints = 11000
+)
+
+// PromQLPoint is a sample of the PromQL result, which is marshaled as `[<unix seconds>, "<value>"]`
+type PromQLPoint struct {
+	T int64 // milli-seconds
+	V float64
+}
+
+func (p PromQLPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{float64(p.T) / 1000, strconv.FormatFloat(p.V, 'f', -1, 64)})
+}
+
+// PromQLSeries is a series of the PromQL result. Value is set for the vector result while Values is set for the
+// matrix result.
+type PromQLSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  *PromQLPoint      `json:"value,omitempty"`
+	Values []PromQLPoint     `json:"values,omitempty"`
+}
+
+// PromQLResult is the data of a PromQL query, which follows the format of the prometheus HTTP API.
+type PromQLResult struct {
+	ResultType parser.ValueType `json:"resultType"`
+	Result     []*PromQLSeries  `json:"result"`
+}
+
+// PromQLEngine evaluates a subset of PromQL over the TSDB, which supports:
+// 1. instant vector selectors, e.g. `pod_cpu_usage{pod_uid=~"abc.*"}`, with the offset modifier;
+// 2. range vector selectors in the instant queries, e.g. `container_psi[5m]`;
+// 3. the `<aggregation>_over_time` functions, e.g. `max_over_time(node_cpu_usage[10m])`;
+// 4. the aggregation operators sum, avg, min, max and count with the `by`/`without` clause.
+type PromQLEngine struct {
+	queryable     SeriesQueryable
+	lookbackDelta time.Duration
+}
+
+func NewPromQLEngine(queryable SeriesQueryable) *PromQLEngine {
+	return &PromQLEngine{
+		queryable:     queryable,
+		lookbackDelta: DefaultPromQLLookbackDelta,
+	}
+}
+
+// InstantQuery evaluates the query at the given time.
+func (e *PromQLEngine) InstantQuery(query string, ts time.Time) (*PromQLResult, error) {
+	expr, err := parser.ParseExpr(query)
+	if err != nil {
+		return nil, err
+	}
+	series, err := e.eval(expr, ts.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	sortPromQLSeries(series)
+	return &PromQLResult{
+		ResultType: expr.Type(),
+		Result:     series,
+	}, nil
+}
+
+// RangeQuery evaluates the query at each step in the time range [start, end] and returns a matrix.
+func (e *PromQLEngine) RangeQuery(query string, start, end time.Time, step time.Duration) (*PromQLResult, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("zero or negative query resolution step widths are not accepted")
+	}
+	if step < time.Millisecond {
+		return nil, fmt.Errorf("query resolution step widths below 1ms are not accepted")
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end timestamp must not be before start time")
+	}
+	if end.Sub(start)/step > maxPromQLRangePoints {
+		return nil, fmt.Errorf("exceeded maximum resolution of %d points per timeseries", maxPromQLRangePoints)
+	}
+	expr, err := parser.ParseExpr(query)
+	if err != nil {
+		return nil, err
+	}
+	if expr.Type() != parser.ValueTypeVector {
+		return nil, fmt.Errorf("invalid expression type %q for range query, must be vector", expr.Type())
+	}
+
+	seriesMap := map[string]*PromQLSeries{}
+	for t := start; !t.After(end); t = t.Add(step) {
+		vector, err := e.eval(expr, t.UnixMilli())
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range vector {
+			key := labels.FromMap(s.Metric).String()
+			merged, ok := seriesMap[key]
+			if !ok {
+				merged = &PromQLSeries{Metric: s.Metric}
+				seriesMap[key] = merged
+			}
+			merged.Values = append(merged.Values, *s.Value)
+		}
+	}
+	series := make([]*PromQLSeries, 0, len(seriesMap))
+	for _, s := range seriesMap {
+		series = append(series, s)
+	}
+	sortPromQLSeries(series)
+	return &PromQLResult{
+		ResultType: parser.ValueTypeMatrix,
+		Result:     series,
+	}, nil
+}
+
+func (e *PromQLEngine) eval(expr parser.Expr, ts int64) ([]*PromQLSeries, error) {
+	switch ex := expr.(type) {
+	case *parser.ParenExpr:
+		return e.eval(ex.Expr, ts)
+	case *parser.VectorSelector:
+		return e.evalVectorSelector(ex, ts)
+	case *parser.MatrixSelector:
+		return e.evalMatrixSelector(ex, ts)
+	case *parser.Call:
+		return e.evalCall(ex, ts)
+	case *parser.AggregateExpr:
+		return e.evalAggregateExpr(ex, ts)
+	default:
+		return nil, fmt.Errorf("unsupported expression %q", expr.String())
+	}
+}
+
+// evalVectorSelector returns the latest sample of each series in the lookback window
+func (e *PromQLEngine) evalVectorSelector(vs *parser.VectorSelector, ts int64) ([]*PromQLSeries, error) {
+	series, err := e.selectSeries(vs, e.lookbackDelta, ts)
+	if err != nil {
+		return nil, err
+	}
+	vector := make([]*PromQLSeries, 0, len(series))
+	for _, s := range series {
+		if len(s.Values) <= 0 {
+			continue
+		}
+		vector = append(vector, &PromQLSeries{
+			Metric: s.Metric,
+			Value:  &PromQLPoint{T: ts, V: s.Values[len(s.Values)-1].V},
+		})
+	}
+	return vector, nil
+}
+
+func (e *PromQLEngine) evalMatrixSelector(ms *parser.MatrixSelector, ts int64) ([]*PromQLSeries, error) {
+	vs, ok := ms.VectorSelector.(*parser.VectorSelector)
+	if !ok {
+		return nil, fmt.Errorf("unsupported expression %q", ms.String())
+	}
+	return e.selectSeries(vs, ms.Range, ts)
+}
+
+// selectSeries returns all samples of the matched series in the time range (ts - offset - window, ts - offset]
+func (e *PromQLEngine) selectSeries(vs *parser.VectorSelector, window time.Duration, ts int64) ([]*PromQLSeries, error) {
+	if vs.Timestamp != nil || vs.StartOrEnd != 0 {
+		return nil, fmt.Errorf("@ modifier is not supported")
+	}
+	maxt := ts - vs.OriginalOffset.Milliseconds()
+	mint := maxt - window.Milliseconds() + 1
+	querier, err := e.queryable.SeriesQuerier(time.UnixMilli(mint), time.UnixMilli(maxt))
+	if err != nil {
+		return nil, err
+	}
+	defer querier.Close()
+
+	var result []*PromQLSeries
+	ss := querier.Select(false, nil, vs.LabelMatchers...)
+	for ss.Next() {
+		series := ss.At()
+		s := &PromQLSeries{Metric: series.Labels().Map()}
+		it := series.Iterator()
+		for it.Next() {
+			t, v := it.At()
+			if t < mint || t > maxt {
+				continue
+			}
+			s.Values = append(s.Values, PromQLPoint{T: t, V: v})
+		}
+		if it.Err() != nil {
+			return nil, it.Err()
+		}
+		result = append(result, s)
+	}
+	if ss.Err() != nil {
+		return nil, ss.Err()
+	}
+	return result, nil
+}
+
+var promQLOverTimeFuncs = map[string]func(values []PromQLPoint, param float64) float64{
+	"avg_over_time": func(values []PromQLPoint, _ float64) float64 {
+		sum := 0.0
+		for _, p := range values {
+			sum += p.V
+		}
+		return sum / float64(len(values))
+	},
+	"min_over_time": func(values []PromQLPoint, _ float64) float64 {
+		min := values[0].V
+		for _, p := range values[1:] {
+			min = math.Min(min, p.V)
+		}
+		return min
+	},
+	"max_over_time": func(values []PromQLPoint, _ float64) float64 {
+		max := values[0].V
+		for _, p := range values[1:] {
+			max = math.Max(max, p.V)
+		}
+		return max
+	},
+	"sum_over_time": func(values []PromQLPoint, _ float64) float64 {
+		sum := 0.0
+		for _, p := range values {
+			sum += p.V
+		}
+		return sum
+	},
+	"count_over_time": func(values []PromQLPoint, _ float64) float64 {
+		return float64(len(values))
+	},
+	"last_over_time": func(values []PromQLPoint, _ float64) float64 {
+		return values[len(values)-1].V
+	},
+	"quantile_over_time": func(values []PromQLPoint, q float64) float64 {
+		sorted := make([]float64, 0, len(values))
+		for _, p := range values {
+			sorted = append(sorted, p.V)
+		}
+		sort.Float64s(sorted)
+		return quantileOfSorted(q, sorted)
+	},
+}
+
+func (e *PromQLEngine) evalCall(call *parser.Call, ts int64) ([]*PromQLSeries, error) {
+	fn, ok := promQLOverTimeFuncs[call.Func.Name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported function %q", call.Func.Name)
+	}
+	args := call.Args
+	param := 0.0
+	if call.Func.Name == "quantile_over_time" {
+		literal, ok := unwrapParenExpr(args[0]).(*parser.NumberLiteral)
+		if !ok {
+			return nil, fmt.Errorf("unsupported quantile parameter %q, must be a number", args[0].String())
+		}
+		param = literal.Val
+		args = args[1:]
+	}
+	ms, ok := unwrapParenExpr(args[0]).(*parser.MatrixSelector)
+	if !ok {
+		return nil, fmt.Errorf("unsupported argument %q of function %s", args[0].String(), call.Func.Name)
+	}
+	matrix, err := e.evalMatrixSelector(ms, ts)
+	if err != nil {
+		return nil, err
+	}
+	vector := make([]*PromQLSeries, 0, len(matrix))
+	for _, s := range matrix {
+		if len(s.Values) <= 0 {
+			continue
+		}
+		vector = append(vector, &PromQLSeries{
+			Metric: dropMetricName(s.Metric),
+			Value:  &PromQLPoint{T: ts, V: fn(s.Values, param)},
+		})
+	}
+	return vector, nil
+}
+
+func (e *PromQLEngine) evalAggregateExpr(aggr *parser.AggregateExpr, ts int64) ([]*PromQLSeries, error) {
+	switch aggr.Op {
+	case parser.SUM, parser.AVG, parser.MIN, parser.MAX, parser.COUNT:
+	default:
+		return nil, fmt.Errorf("unsupported aggregation %q", aggr.Op.String())
+	}
+	vector, err := e.eval(aggr.Expr, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	type group struct {
+		metric map[string]string
+		value  float64
+		count  int
+	}
+	groups := map[string]*group{}
+	var groupKeys []string
+	for _, s := range vector {
+		metric := groupingLabels(s.Metric, aggr.Grouping, aggr.Without)
+		key := labels.FromMap(metric).String()
+		g, ok := groups[key]
+		if !ok {
+			groups[key] = &group{metric: metric, value: s.Value.V, count: 1}
+			groupKeys = append(groupKeys, key)
+			continue
+		}
+		g.count++
+		switch aggr.Op {
+		case parser.SUM, parser.AVG:
+			g.value += s.Value.V
+		case parser.MIN:
+			g.value = math.Min(g.value, s.Value.V)
+		case parser.MAX:
+			g.value = math.Max(g.value, s.Value.V)
+		}
+	}
+
+	result := make([]*PromQLSeries, 0, len(groups))
+	for _, key := range groupKeys {
+		g := groups[key]
+		value := g.value
+		switch aggr.Op {
+		case parser.AVG:
+			value = g.value / float64(g.count)
+		case parser.COUNT:
+			value = float64(g.count)
+		}
+		result = append(result, &PromQLSeries{
+			Metric: g.metric,
+			Value:  &PromQLPoint{T: ts, V: value},
+		})
+	}
+	return result, nil
+}
+
+func groupingLabels(metric map[string]string, grouping []string, without bool) map[string]string {
+	result := map[string]string{}
+	if without {
+		for k, v := range metric {
+			result[k] = v
+		}
+		delete(result, labels.MetricName)
+		for _, name := range grouping {
+			delete(result, name)
+		}
+		return result
+	}
+	for _, name := range grouping {
+		if v, ok := metric[name]; ok {
+			result[name] = v
+		}
+	}
+	return result
+}
+
+func dropMetricName(metric map[string]string) map[string]string {
+	result := make(map[string]string, len(metric))
+	for k, v := range metric {
+		if k != labels.MetricName {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func unwrapParenExpr(expr parser.Expr) parser.Expr {
+	for {
+		paren, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.Expr
+	}
+}
+
+// quantileOfSorted calculates the quantile in the same way as prometheus, which interpolates linearly between the
+// two nearest ranks.
+func quantileOfSorted(q float64, values []float64) float64 {
+	if len(values) == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+	rank := q * float64(len(values)-1)
+	lowerIndex := math.Max(0, math.Floor(rank))
+	upperIndex := math.Min(float64(len(values)-1), lowerIndex+1)
+	weight := rank - math.Floor(rank)
+	return values[int(lowerIndex)]*(1-weight) + values[int(upperIndex)]*weight
+}
+
+func sortPromQLSeries(series []*PromQLSeries) {
+	sort.Slice(series, func(i, j int) bool {
+		return labels.Compare(labels.FromMap(series[i].Metric), labels.FromMap(series[j].Metric)) < 0
+	})
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/klog/v2"
+)
+
+const (
+	PromQLQueryPath      = "/api/v1/query"
+	PromQLQueryRangePath = "/api/v1/query_range"
+)
+
+var timeNow = time.Now
+
+type promQLResponse struct {
+	Status string        `json:"status"`
+	Data   *PromQLResult `json:"data,omitempty"`
+}
+
+// QueryHandler serves the instant queries in the same way as the prometheus HTTP API, e.g.
+// GET /api/v1/query?query=pod_cpu_usage{pod_uid="xxx"}&time=1680000000
+func (e *PromQLEngine) QueryHandler() func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		query := r.FormValue("query")
+		klog.V(4).Infof("handle promql query client=%v query=%v", r.RemoteAddr, query)
+		ts, err := parseTimeParam(r.FormValue("time"), timeNow())
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid parameter \"time\": %v", err), http.StatusBadRequest)
+			return
+		}
+		result, err := e.InstantQuery(query, ts)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writePromQLResult(rw, result)
+	}
+}
+
+// QueryRangeHandler serves the range queries in the same way as the prometheus HTTP API, e.g.
+// GET /api/v1/query_range?query=pod_cpu_usage&start=1680000000&end=1680000600&step=60s
+func (e *PromQLEngine) QueryRangeHandler() func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		query := r.FormValue("query")
+		klog.V(4).Infof("handle promql range query client=%v query=%v", r.RemoteAddr, query)
+		start, err := parseTimeParam(r.FormValue("start"), time.Time{})
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid parameter \"start\": %v", err), http.StatusBadRequest)
+			return
+		}
+		end, err := parseTimeParam(r.FormValue("end"), time.Time{})
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid parameter \"end\": %v", err), http.StatusBadRequest)
+			return
+		}
+		step, err := parseDurationParam(r.FormValue("step"))
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid parameter \"step\": %v", err), http.StatusBadRequest)
+			return
+		}
+		result, err := e.RangeQuery(query, start, end, step)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writePromQLResult(rw, result)
+	}
+}
+
+func writePromQLResult(rw http.ResponseWriter, result *PromQLResult) {
+	if result.Result == nil {
+		result.Result = []*PromQLSeries{}
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(&promQLResponse{Status: "success", Data: result}); err != nil {
+		klog.V(4).Infof("failed to write promql result, err: %v", err)
+	}
+}
+
+// parseTimeParam parses the time in the unix seconds or RFC3339 format, the default value is returned if empty
+func parseTimeParam(s string, defaultValue time.Time) (time.Time, error) {
+	if s == "" {
+		if defaultValue.IsZero() {
+			return time.Time{}, fmt.Errorf("time is required")
+		}
+		return defaultValue, nil
+	}
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, ns := math.Modf(t)
+		return time.Unix(int64(sec), int64(math.Round(ns*1000))*int64(time.Millisecond)), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+// parseDurationParam parses the duration in the seconds or prometheus duration format, e.g. "30", "1m"
+func parseDurationParam(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(d * float64(time.Second)), nil
+	}
+	if d, err := model.ParseDuration(s); err == nil {
+		return time.Duration(d), nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
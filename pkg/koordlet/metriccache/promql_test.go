@@ -0,0 +1,302 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPromQLStorage(t *testing.T, now time.Time) TSDBStorage {
+	dir := t.TempDir()
+	conf := NewDefaultConfig()
+	conf.TSDBPath = dir
+	conf.TSDBEnablePromMetrics = false
+	db, err := NewTSDBStorage(conf)
+	assert.NoError(t, err)
+
+	var samples []MetricSample
+	for i, v := range []float64{1, 2, 3} {
+		ts := now.Add(time.Duration(i-2) * time.Minute)
+		s, err := PodCPUUsageMetric.GenerateSample(MetricPropertiesFunc.Pod("pod-1"), ts, v)
+		assert.NoError(t, err)
+		samples = append(samples, s)
+		s, err = PodCPUUsageMetric.GenerateSample(MetricPropertiesFunc.Pod("pod-2"), ts, 10*v)
+		assert.NoError(t, err)
+		samples = append(samples, s)
+	}
+	appender := db.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+	return db
+}
+
+func TestPromQLEngine_InstantQuery(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	tests := []struct {
+		name     string
+		query    string
+		ts       time.Time
+		wantType parser.ValueType
+		want     []*PromQLSeries
+		wantErr  bool
+	}{
+		{
+			name:     "select the latest samples",
+			query:    `pod_cpu_usage{pod_uid="pod-1"}`,
+			ts:       now,
+			wantType: parser.ValueTypeVector,
+			want: []*PromQLSeries{
+				{
+					Metric: map[string]string{"__name__": "pod_cpu_usage", "pod_uid": "pod-1"},
+					Value:  &PromQLPoint{T: now.UnixMilli(), V: 3},
+				},
+			},
+		},
+		{
+			name:     "select with offset",
+			query:    `pod_cpu_usage{pod_uid="pod-1"} offset 1m`,
+			ts:       now,
+			wantType: parser.ValueTypeVector,
+			want: []*PromQLSeries{
+				{
+					Metric: map[string]string{"__name__": "pod_cpu_usage", "pod_uid": "pod-1"},
+					Value:  &PromQLPoint{T: now.UnixMilli(), V: 2},
+				},
+			},
+		},
+		{
+			name:     "out of lookback window",
+			query:    `pod_cpu_usage`,
+			ts:       now.Add(10 * time.Minute),
+			wantType: parser.ValueTypeVector,
+			want:     []*PromQLSeries{},
+		},
+		{
+			name:     "select range",
+			query:    `pod_cpu_usage{pod_uid=~"pod-2"}[90s]`,
+			ts:       now,
+			wantType: parser.ValueTypeMatrix,
+			want: []*PromQLSeries{
+				{
+					Metric: map[string]string{"__name__": "pod_cpu_usage", "pod_uid": "pod-2"},
+					Values: []PromQLPoint{
+						{T: now.Add(-time.Minute).UnixMilli(), V: 20},
+						{T: now.UnixMilli(), V: 30},
+					},
+				},
+			},
+		},
+		{
+			name:     "aggregate over time",
+			query:    `max_over_time(pod_cpu_usage[5m])`,
+			ts:       now,
+			wantType: parser.ValueTypeVector,
+			want: []*PromQLSeries{
+				{
+					Metric: map[string]string{"pod_uid": "pod-1"},
+					Value:  &PromQLPoint{T: now.UnixMilli(), V: 3},
+				},
+				{
+					Metric: map[string]string{"pod_uid": "pod-2"},
+					Value:  &PromQLPoint{T: now.UnixMilli(), V: 30},
+				},
+			},
+		},
+		{
+			name:     "quantile over time",
+			query:    `quantile_over_time(0.5, pod_cpu_usage{pod_uid="pod-1"}[5m])`,
+			ts:       now,
+			wantType: parser.ValueTypeVector,
+			want: []*PromQLSeries{
+				{
+					Metric: map[string]string{"pod_uid": "pod-1"},
+					Value:  &PromQLPoint{T: now.UnixMilli(), V: 2},
+				},
+			},
+		},
+		{
+			name:     "sum of all pods",
+			query:    `sum(avg_over_time(pod_cpu_usage[5m]))`,
+			ts:       now,
+			wantType: parser.ValueTypeVector,
+			want: []*PromQLSeries{
+				{
+					Metric: map[string]string{},
+					Value:  &PromQLPoint{T: now.UnixMilli(), V: 22},
+				},
+			},
+		},
+		{
+			name:     "max by pod",
+			query:    `max by (pod_uid) (pod_cpu_usage)`,
+			ts:       now,
+			wantType: parser.ValueTypeVector,
+			want: []*PromQLSeries{
+				{
+					Metric: map[string]string{"pod_uid": "pod-1"},
+					Value:  &PromQLPoint{T: now.UnixMilli(), V: 3},
+				},
+				{
+					Metric: map[string]string{"pod_uid": "pod-2"},
+					Value:  &PromQLPoint{T: now.UnixMilli(), V: 30},
+				},
+			},
+		},
+		{
+			name:    "invalid query",
+			query:   `pod_cpu_usage{`,
+			ts:      now,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported function",
+			query:   `rate(pod_cpu_usage[5m])`,
+			ts:      now,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported binary expression",
+			query:   `pod_cpu_usage * 2`,
+			ts:      now,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestPromQLStorage(t, now)
+			defer db.Close()
+
+			e := NewPromQLEngine(db)
+			got, gotErr := e.InstantQuery(tt.query, tt.ts)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.wantType, got.ResultType)
+			assert.Equal(t, tt.want, got.Result)
+		})
+	}
+}
+
+func TestPromQLEngine_RangeQuery(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	db := newTestPromQLStorage(t, now)
+	defer db.Close()
+	e := NewPromQLEngine(db)
+
+	got, err := e.RangeQuery(`pod_cpu_usage{pod_uid="pod-1"}`, now.Add(-2*time.Minute), now, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, parser.ValueTypeMatrix, got.ResultType)
+	assert.Equal(t, []*PromQLSeries{
+		{
+			Metric: map[string]string{"__name__": "pod_cpu_usage", "pod_uid": "pod-1"},
+			Values: []PromQLPoint{
+				{T: now.Add(-2 * time.Minute).UnixMilli(), V: 1},
+				{T: now.Add(-time.Minute).UnixMilli(), V: 2},
+				{T: now.UnixMilli(), V: 3},
+			},
+		},
+	}, got.Result)
+
+	_, err = e.RangeQuery(`pod_cpu_usage`, now, now.Add(-time.Minute), time.Minute)
+	assert.Error(t, err)
+	_, err = e.RangeQuery(`pod_cpu_usage`, now.Add(-time.Hour), now, time.Millisecond)
+	assert.Error(t, err)
+	_, err = e.RangeQuery(`pod_cpu_usage`, now, now, 100*time.Microsecond)
+	assert.Error(t, err)
+	_, err = e.RangeQuery(`pod_cpu_usage[5m]`, now.Add(-time.Minute), now, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestPromQLEngine_Handlers(t *testing.T) {
+	now := time.Unix(time.Now().Unix(), 0)
+	db := newTestPromQLStorage(t, now)
+	defer db.Close()
+	e := NewPromQLEngine(db)
+	oldTimeNow := timeNow
+	timeNow = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNow = oldTimeNow
+	}()
+
+	// instant query
+	req := httptest.NewRequest(http.MethodGet, `/api/v1/query?query=pod_cpu_usage{pod_uid="pod-1"}`, nil)
+	rw := httptest.NewRecorder()
+	e.QueryHandler()(rw, req)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &got))
+	assert.Equal(t, map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"resultType": "vector",
+			"result": []interface{}{
+				map[string]interface{}{
+					"metric": map[string]interface{}{"__name__": "pod_cpu_usage", "pod_uid": "pod-1"},
+					"value":  []interface{}{float64(now.Unix()), "3"},
+				},
+			},
+		},
+	}, got)
+
+	// range query
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/query_range?query=pod_cpu_usage&start="+
+		now.Add(-time.Minute).Format(time.RFC3339)+"&end="+now.Format(time.RFC3339)+"&step=1m", nil)
+	rw = httptest.NewRecorder()
+	e.QueryRangeHandler()(rw, req)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	got = nil
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &got))
+	assert.Equal(t, "matrix", got["data"].(map[string]interface{})["resultType"])
+	assert.Len(t, got["data"].(map[string]interface{})["result"], 2)
+
+	// bad requests
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/query_range?query=pod_cpu_usage&start=1&end=2", nil)
+	rw = httptest.NewRecorder()
+	e.QueryRangeHandler()(rw, req)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/query_range?query=pod_cpu_usage&start=1&end=2&step=0.0001", nil)
+	rw = httptest.NewRecorder()
+	e.QueryRangeHandler()(rw, req)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/query?query=unknown_func(pod_cpu_usage)", nil)
+	rw = httptest.NewRecorder()
+	e.QueryHandler()(rw, req)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+}
+
+func Test_parseTimeParam(t *testing.T) {
+	got, err := parseTimeParam("1680000000.5", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1680000000500), got.UnixMilli())
+	got, err = parseTimeParam("2023-03-28T10:40:00Z", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1680000000), got.Unix())
+	_, err = parseTimeParam("", time.Time{})
+	assert.Error(t, err)
+	_, err = parseTimeParam("invalid", time.Time{})
+	assert.Error(t, err)
+}
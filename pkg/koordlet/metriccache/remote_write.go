@@ -0,0 +1,435 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"k8s.io/klog/v2"
+)
+
+const (
+	remoteWriteWALFileSuffix = ".batch"
+	remoteWriteBackoffBase   = 500 * time.Millisecond
+	remoteWriteUserAgent     = "koordlet-remote-write"
+	// remoteWriteMaxPendingBatches is the max number of batches kept in memory before flushed into the WAL
+	remoteWriteMaxPendingBatches = 10
+)
+
+var _ TSDBStorage = &remoteWriteStorage{}
+
+// remoteWriteStorage wraps the TSDBStorage to export the committed samples to the remote-write exporter
+type remoteWriteStorage struct {
+	TSDBStorage
+	exporter *remoteWriteExporter
+}
+
+func (r *remoteWriteStorage) Appender() Appender {
+	return &remoteWriteAppender{
+		Appender: r.TSDBStorage.Appender(),
+		exporter: r.exporter,
+	}
+}
+
+// remoteWriteAppender enqueues the samples to the exporter after they are committed into the TSDB
+type remoteWriteAppender struct {
+	Appender
+	exporter *remoteWriteExporter
+	samples  []MetricSample
+}
+
+func (r *remoteWriteAppender) Append(s []MetricSample) error {
+	if err := r.Appender.Append(s); err != nil {
+		return err
+	}
+	r.samples = append(r.samples, s...)
+	return nil
+}
+
+func (r *remoteWriteAppender) Commit() error {
+	if err := r.Appender.Commit(); err != nil {
+		return err
+	}
+	r.exporter.Enqueue(r.samples)
+	r.samples = nil
+	return nil
+}
+
+// remoteWriteExporter batches the raw samples and sends them to the remote storage with the prometheus remote-write
+// protocol. The batches are persisted in a bounded on-disk WAL before sending, so they can survive the failures of
+// the remote endpoint and the restarts of koordlet.
+type remoteWriteExporter struct {
+	url            string
+	batchSize      int
+	flushInterval  time.Duration
+	maxRetries     int
+	externalLabels []prompb.Label
+	client         *http.Client
+	wal            *remoteWriteWAL
+
+	lock sync.Mutex
+	// pending keeps the single-sample series in the order of arrival, so the oldest can be dropped when it is full
+	pending    []prompb.TimeSeries
+	maxPending int
+	flushCh    chan struct{}
+}
+
+func newRemoteWriteExporter(cfg *Config) (*remoteWriteExporter, error) {
+	if cfg.RemoteWriteFlushInterval <= 0 {
+		return nil, fmt.Errorf("invalid remote-write flush interval %v, must be positive", cfg.RemoteWriteFlushInterval)
+	}
+	if cfg.RemoteWriteBatchSize <= 0 {
+		return nil, fmt.Errorf("invalid remote-write batch size %d, must be positive", cfg.RemoteWriteBatchSize)
+	}
+	walPath := cfg.RemoteWriteWALPath
+	if walPath == "" {
+		walPath = filepath.Join(cfg.TSDBPath, "remote-write")
+	}
+	wal, err := newRemoteWriteWAL(walPath, cfg.RemoteWriteWALMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	externalLabels := make([]prompb.Label, 0, len(cfg.RemoteWriteExternalLabels))
+	for k, v := range cfg.RemoteWriteExternalLabels {
+		externalLabels = append(externalLabels, prompb.Label{Name: k, Value: v})
+	}
+	return &remoteWriteExporter{
+		url:            cfg.RemoteWriteURL,
+		batchSize:      cfg.RemoteWriteBatchSize,
+		flushInterval:  cfg.RemoteWriteFlushInterval,
+		maxRetries:     cfg.RemoteWriteMaxRetries,
+		externalLabels: externalLabels,
+		client:         &http.Client{Timeout: cfg.RemoteWriteTimeout},
+		wal:            wal,
+		maxPending:     cfg.RemoteWriteBatchSize * remoteWriteMaxPendingBatches,
+		flushCh:        make(chan struct{}, 1),
+	}, nil
+}
+
+// Enqueue adds the samples into the pending batch, and triggers a flush if the batch is full.
+// The oldest samples are dropped when the pending samples exceed the limit, e.g. the flush is blocked for a long time.
+func (e *remoteWriteExporter) Enqueue(samples []MetricSample) {
+	if len(samples) <= 0 {
+		return
+	}
+	e.lock.Lock()
+	for _, s := range samples {
+		e.pending = append(e.pending, e.toTimeSeries(s))
+	}
+	if dropped := len(e.pending) - e.maxPending; e.maxPending > 0 && dropped > 0 {
+		klog.Warningf("remote-write pending samples exceed the limit %d, drop the oldest %d samples", e.maxPending, dropped)
+		e.pending = append([]prompb.TimeSeries{}, e.pending[dropped:]...)
+	}
+	isFull := len(e.pending) >= e.batchSize
+	e.lock.Unlock()
+
+	if isFull {
+		select {
+		case e.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *remoteWriteExporter) Run(stopCh <-chan struct{}) {
+	klog.Infof("start remote-write exporter to %s", e.url)
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.flushCh:
+		case <-stopCh:
+			// persist the pending samples to send them after restarted
+			if err := e.flush(); err != nil {
+				klog.Warningf("failed to flush remote-write samples before exiting, err: %v", err)
+			}
+			return
+		}
+		if err := e.flush(); err != nil {
+			klog.Warningf("failed to flush remote-write samples, err: %v", err)
+		}
+		e.sendAll(stopCh)
+	}
+}
+
+// flush writes the pending batch into the WAL
+func (e *remoteWriteExporter) flush() error {
+	e.lock.Lock()
+	pending := e.pending
+	e.pending = nil
+	e.lock.Unlock()
+	if len(pending) <= 0 {
+		return nil
+	}
+
+	req := &prompb.WriteRequest{Timeseries: groupTimeSeries(pending)}
+	data, err := req.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal write request failed, err: %w", err)
+	}
+	return e.wal.Write(snappy.Encode(nil, data))
+}
+
+// sendAll sends the batches in the WAL in order, and stops at the first batch failed after retries
+func (e *remoteWriteExporter) sendAll(stopCh <-chan struct{}) {
+	for _, name := range e.wal.List() {
+		data, err := e.wal.Read(name)
+		if err != nil {
+			klog.Warningf("failed to read remote-write batch %s, drop it, err: %v", name, err)
+			e.wal.Remove(name)
+			continue
+		}
+		err = e.sendWithRetry(data, stopCh)
+		if err == nil {
+			e.wal.Remove(name)
+			continue
+		}
+		if _, ok := err.(*nonRecoverableError); ok {
+			klog.Warningf("failed to send remote-write batch %s, drop it, err: %v", name, err)
+			e.wal.Remove(name)
+			continue
+		}
+		klog.V(4).Infof("failed to send remote-write batch %s, retry later, err: %v", name, err)
+		return
+	}
+}
+
+func (e *remoteWriteExporter) sendWithRetry(data []byte, stopCh <-chan struct{}) error {
+	var err error
+	backoff := remoteWriteBackoffBase
+	for i := 0; i <= e.maxRetries; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-stopCh:
+				return err
+			}
+			backoff *= 2
+		}
+		if err = e.send(data); err == nil {
+			return nil
+		}
+		if _, ok := err.(*nonRecoverableError); ok {
+			return err
+		}
+	}
+	return err
+}
+
+type nonRecoverableError struct {
+	error
+}
+
+func (e *remoteWriteExporter) send(data []byte) error {
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, e.url, bytes.NewReader(data))
+	if err != nil {
+		return &nonRecoverableError{err}
+	}
+	req.Header.Add("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", remoteWriteUserAgent)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	err = fmt.Errorf("server returned HTTP status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	// retry on the server errors and the rate limiting like prometheus does
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return &nonRecoverableError{err}
+}
+
+func (e *remoteWriteExporter) toTimeSeries(s MetricSample) prompb.TimeSeries {
+	properties := s.GetProperties()
+	labels := make([]prompb.Label, 0, len(properties)+len(e.externalLabels)+1)
+	labels = append(labels, prompb.Label{Name: metricLabelName, Value: s.GetKind()})
+	for k, v := range properties {
+		if k == metricLabelName {
+			continue
+		}
+		labels = append(labels, prompb.Label{Name: k, Value: v})
+	}
+	for _, l := range e.externalLabels {
+		if _, ok := properties[l.Name]; !ok {
+			labels = append(labels, l)
+		}
+	}
+	// labels of the remote-write series must be sorted by name
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+	return prompb.TimeSeries{
+		Labels:  labels,
+		Samples: []prompb.Sample{{Timestamp: s.timestamp(), Value: s.value()}},
+	}
+}
+
+// groupTimeSeries merges the samples of the series with the same label set, since the remote storage expects one
+// series per label set in a write request. The series keep the order of their first samples.
+func groupTimeSeries(series []prompb.TimeSeries) []prompb.TimeSeries {
+	var grouped []prompb.TimeSeries
+	index := map[string]int{}
+	for _, ts := range series {
+		key := labelsKey(ts.Labels)
+		i, ok := index[key]
+		if !ok {
+			i = len(grouped)
+			index[key] = i
+			grouped = append(grouped, prompb.TimeSeries{Labels: ts.Labels})
+		}
+		grouped[i].Samples = append(grouped[i].Samples, ts.Samples...)
+	}
+	for i := range grouped {
+		// samples of a series must be in the order of timestamp
+		sort.SliceStable(grouped[i].Samples, func(a, b int) bool {
+			return grouped[i].Samples[a].Timestamp < grouped[i].Samples[b].Timestamp
+		})
+	}
+	return grouped
+}
+
+// labelsKey returns the identity of the sorted labels
+func labelsKey(labels []prompb.Label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(l.Name)
+		b.WriteByte(0xff)
+		b.WriteString(l.Value)
+		b.WriteByte(0xff)
+	}
+	return b.String()
+}
+
+// remoteWriteWAL stores the encoded batches as files named by the increasing sequence numbers. The oldest batches
+// are dropped when the total size exceeds the limit.
+type remoteWriteWAL struct {
+	dir      string
+	maxBytes int64
+
+	lock    sync.Mutex
+	nextSeq uint64
+}
+
+func newRemoteWriteWAL(dir string, maxBytes int64) (*remoteWriteWAL, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	w := &remoteWriteWAL{dir: dir, maxBytes: maxBytes}
+	names := w.List()
+	if len(names) > 0 {
+		lastSeq, _ := parseRemoteWriteWALSeq(names[len(names)-1])
+		w.nextSeq = lastSeq + 1
+	}
+	return w, nil
+}
+
+func (w *remoteWriteWAL) Write(data []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	name := fmt.Sprintf("%020d%s", w.nextSeq, remoteWriteWALFileSuffix)
+	tmpPath := filepath.Join(w.dir, name+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filepath.Join(w.dir, name)); err != nil {
+		return err
+	}
+	w.nextSeq++
+	w.truncate()
+	return nil
+}
+
+// List returns the names of the batches in the order of sequence
+func (w *remoteWriteWAL) List() []string {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		klog.Warningf("failed to list remote-write wal %s, err: %v", w.dir, err)
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		if _, ok := parseRemoteWriteWALSeq(entry.Name()); ok {
+			names = append(names, entry.Name())
+		}
+	}
+	// the names are zero-padded so the lexical order is the sequence order
+	sort.Strings(names)
+	return names
+}
+
+func (w *remoteWriteWAL) Read(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(w.dir, name))
+}
+
+func (w *remoteWriteWAL) Remove(name string) {
+	if err := os.Remove(filepath.Join(w.dir, name)); err != nil && !os.IsNotExist(err) {
+		klog.Warningf("failed to remove remote-write batch %s, err: %v", name, err)
+	}
+}
+
+// truncate drops the oldest batches until the total size is under the limit
+func (w *remoteWriteWAL) truncate() {
+	if w.maxBytes <= 0 {
+		return
+	}
+	names := w.List()
+	sizes := make([]int64, len(names))
+	var total int64
+	for i, name := range names {
+		info, err := os.Stat(filepath.Join(w.dir, name))
+		if err != nil {
+			continue
+		}
+		sizes[i] = info.Size()
+		total += info.Size()
+	}
+	for i := 0; i < len(names)-1 && total > w.maxBytes; i++ {
+		klog.Warningf("remote-write wal exceeds the limit %d bytes, drop the oldest batch %s", w.maxBytes, names[i])
+		w.Remove(names[i])
+		total -= sizes[i]
+	}
+}
+
+func parseRemoteWriteWALSeq(name string) (uint64, bool) {
+	if !strings.HasSuffix(name, remoteWriteWALFileSuffix) {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(strings.TrimSuffix(name, remoteWriteWALFileSuffix), 10, 64)
+	return seq, err == nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRemoteWriteServer struct {
+	lock       sync.Mutex
+	statusCode int
+	requests   []*prompb.WriteRequest
+}
+
+func (s *testRemoteWriteServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.statusCode != http.StatusOK {
+		rw.WriteHeader(s.statusCode)
+		return
+	}
+	compressed, _ := io.ReadAll(r.Body)
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	req := &prompb.WriteRequest{}
+	if err = req.Unmarshal(data); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.requests = append(s.requests, req)
+}
+
+func Test_remoteWriteExporter(t *testing.T) {
+	server := &testRemoteWriteServer{statusCode: http.StatusOK}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	conf := NewDefaultConfig()
+	conf.TSDBPath = dir
+	conf.TSDBEnablePromMetrics = false
+	conf.RemoteWriteURL = ts.URL
+	conf.RemoteWriteMaxRetries = 0
+	conf.RemoteWriteExternalLabels = map[string]string{"node": "test-node"}
+	m, err := NewMetricCache(conf)
+	assert.NoError(t, err)
+	defer m.Close()
+	exporter := m.(*metricCache).remoteWriteExporter
+	assert.NotNil(t, exporter)
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	s, err := PodCPUUsageMetric.GenerateSample(MetricPropertiesFunc.Pod("test-pod"), now, 1.5)
+	assert.NoError(t, err)
+	appender := m.Appender()
+	assert.NoError(t, appender.Append([]MetricSample{s}))
+	assert.NoError(t, appender.Commit())
+
+	// samples are sent after committed
+	assert.NoError(t, exporter.flush())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	exporter.sendAll(stopCh)
+	assert.Equal(t, []*prompb.WriteRequest{
+		{
+			Timeseries: []prompb.TimeSeries{
+				{
+					Labels: []prompb.Label{
+						{Name: "__name__", Value: "pod_cpu_usage"},
+						{Name: "node", Value: "test-node"},
+						{Name: "pod_uid", Value: "test-pod"},
+					},
+					Samples: []prompb.Sample{{Timestamp: now.UnixMilli(), Value: 1.5}},
+				},
+			},
+		},
+	}, server.requests)
+	assert.Empty(t, exporter.wal.List())
+
+	// keep the batch in the wal when the server fails
+	server.statusCode = http.StatusServiceUnavailable
+	exporter.Enqueue([]MetricSample{s})
+	assert.NoError(t, exporter.flush())
+	exporter.sendAll(stopCh)
+	assert.Len(t, exporter.wal.List(), 1)
+
+	// resend the batch when the server recovers
+	server.statusCode = http.StatusOK
+	exporter.sendAll(stopCh)
+	assert.Len(t, server.requests, 2)
+	assert.Empty(t, exporter.wal.List())
+
+	// drop the batch which is rejected by the server
+	server.statusCode = http.StatusBadRequest
+	exporter.Enqueue([]MetricSample{s})
+	assert.NoError(t, exporter.flush())
+	exporter.sendAll(stopCh)
+	assert.Empty(t, exporter.wal.List())
+}
+
+func Test_remoteWriteExporter_Enqueue(t *testing.T) {
+	conf := NewDefaultConfig()
+	conf.TSDBPath = t.TempDir()
+	conf.RemoteWriteURL = "http://localhost:9090/api/v1/write"
+	conf.RemoteWriteBatchSize = 2
+	exporter, err := newRemoteWriteExporter(conf)
+	assert.NoError(t, err)
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	var samples []MetricSample
+	for i := 0; i < 2*remoteWriteMaxPendingBatches+2; i++ {
+		s, err := PodCPUUsageMetric.GenerateSample(MetricPropertiesFunc.Pod("test-pod"), now.Add(time.Duration(i)*time.Second), float64(i))
+		assert.NoError(t, err)
+		samples = append(samples, s)
+	}
+	other, err := PodCPUUsageMetric.GenerateSample(MetricPropertiesFunc.Pod("other-pod"), now, 1)
+	assert.NoError(t, err)
+
+	// the oldest samples are dropped when the pending buffer is full
+	exporter.Enqueue(samples)
+	assert.Len(t, exporter.pending, 2*remoteWriteMaxPendingBatches)
+	assert.Equal(t, now.Add(2*time.Second).UnixMilli(), exporter.pending[0].Samples[0].Timestamp)
+
+	// the samples of the same label set are grouped into one series
+	exporter.pending = nil
+	exporter.Enqueue([]MetricSample{samples[1], other, samples[0]})
+	got := groupTimeSeries(exporter.pending)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "test-pod", got[0].Labels[1].Value)
+	assert.Equal(t, []prompb.Sample{
+		{Timestamp: now.UnixMilli(), Value: 0},
+		{Timestamp: now.Add(time.Second).UnixMilli(), Value: 1},
+	}, got[0].Samples)
+	assert.Equal(t, "other-pod", got[1].Labels[1].Value)
+	assert.Len(t, got[1].Samples, 1)
+}
+
+func Test_newRemoteWriteExporter_invalid(t *testing.T) {
+	conf := NewDefaultConfig()
+	conf.TSDBPath = t.TempDir()
+	conf.RemoteWriteURL = "http://localhost:9090/api/v1/write"
+	conf.RemoteWriteFlushInterval = 0
+	_, err := newRemoteWriteExporter(conf)
+	assert.Error(t, err)
+
+	conf = NewDefaultConfig()
+	conf.TSDBPath = t.TempDir()
+	conf.RemoteWriteURL = "http://localhost:9090/api/v1/write"
+	conf.RemoteWriteBatchSize = 0
+	_, err = newRemoteWriteExporter(conf)
+	assert.Error(t, err)
+}
+
+func Test_remoteWriteWAL(t *testing.T) {
+	dir := t.TempDir()
+	w, err := newRemoteWriteWAL(dir, 10)
+	assert.NoError(t, err)
+
+	assert.NoError(t, w.Write([]byte("aaaa")))
+	assert.NoError(t, w.Write([]byte("bbbb")))
+	assert.Equal(t, []string{"00000000000000000000.batch", "00000000000000000001.batch"}, w.List())
+
+	// drop the oldest batch when exceeding the max bytes
+	assert.NoError(t, w.Write([]byte("cccc")))
+	assert.Equal(t, []string{"00000000000000000001.batch", "00000000000000000002.batch"}, w.List())
+	got, err := w.Read("00000000000000000001.batch")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("bbbb"), got)
+
+	// ignore unknown files and continue the sequence after restarted
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "unknown"), []byte("x"), 0644))
+	w, err = newRemoteWriteWAL(dir, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write([]byte("dd")))
+	assert.Equal(t, []string{"00000000000000000001.batch", "00000000000000000002.batch", "00000000000000000003.batch"}, w.List())
+}
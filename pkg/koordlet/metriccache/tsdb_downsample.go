@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// downsampleDelay waits the out-of-order samples before rolling up a window
+	downsampleDelay = time.Minute
+	// downsampleMaxBacklog is the max age of the windows to roll up, since the TSDB head rejects the samples which
+	// are too old to append
+	downsampleMaxBacklog = 10 * time.Minute
+	// downsampleSeparator separates the metric kind and the rollup suffix, which never appears in the raw kinds
+	downsampleSeparator = ":"
+)
+
+// downsampleResolution rolls up the raw samples into the windows of the resolution, and the rollups are used for the
+// queries whose range is no less than the minQueryRange.
+type downsampleResolution struct {
+	resolution    time.Duration
+	minQueryRange time.Duration
+}
+
+// downsampleResolutions are sorted in the descending order of the resolution, so the coarsest rollup is preferred
+var downsampleResolutions = []downsampleResolution{
+	{resolution: 5 * time.Minute, minQueryRange: 5 * time.Hour},
+	{resolution: time.Minute, minQueryRange: time.Hour},
+}
+
+// DownsampledMetricKind returns the kind of the rollup series, which keeps the average of the raw samples in each
+// window of the resolution, e.g. node_cpu_usage:avg_5m
+func DownsampledMetricKind(kind string, resolution time.Duration) string {
+	return kind + downsampleSeparator + "avg_" + model.Duration(resolution).String()
+}
+
+// getDownsampleResolution returns the resolution of rollups to query for the time range, or zero to query the raw
+func getDownsampleResolution(startTime, endTime time.Time) time.Duration {
+	for _, r := range downsampleResolutions {
+		if endTime.Sub(startTime) >= r.minQueryRange {
+			return r.resolution
+		}
+	}
+	return 0
+}
+
+// tsdbDownsampler rolls up the raw series of the tsdbStorage periodically.
+type tsdbDownsampler struct {
+	storage *tsdbStorage
+	// the end of the last rolled up window of each resolution
+	watermarks map[time.Duration]time.Time
+}
+
+func newTSDBDownsampler(storage *tsdbStorage) *tsdbDownsampler {
+	return &tsdbDownsampler{
+		storage:    storage,
+		watermarks: map[time.Duration]time.Time{},
+	}
+}
+
+func (d *tsdbDownsampler) Run(stopCh <-chan struct{}) {
+	klog.Infof("start tsdb downsampler")
+	ticker := time.NewTicker(downsampleResolutions[len(downsampleResolutions)-1].resolution)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.downsample(time.Now())
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// downsample rolls up all the completed windows before the given time
+func (d *tsdbDownsampler) downsample(now time.Time) {
+	for _, r := range downsampleResolutions {
+		windowEnd := now.Add(-downsampleDelay).Truncate(r.resolution)
+		windowStart, ok := d.watermarks[r.resolution]
+		if !ok || windowStart.Before(now.Add(-downsampleMaxBacklog)) {
+			// start from the latest window since the older rollups may not be appendable
+			windowStart = windowEnd.Add(-r.resolution)
+		}
+		for ; !windowStart.Add(r.resolution).After(windowEnd); windowStart = windowStart.Add(r.resolution) {
+			if err := d.rollup(windowStart, r.resolution); err != nil {
+				klog.Warningf("failed to downsample window %v with resolution %v, err: %v", windowStart, r.resolution, err)
+				break
+			}
+			d.watermarks[r.resolution] = windowStart.Add(r.resolution)
+		}
+	}
+}
+
+// rollup appends the average of the raw samples in the window [windowStart, windowStart+resolution) for each series
+func (d *tsdbDownsampler) rollup(windowStart time.Time, resolution time.Duration) error {
+	mint, maxt := windowStart.UnixMilli(), windowStart.Add(resolution).UnixMilli()-1
+	querier, err := d.storage.db.Querier(context.TODO(), mint, maxt)
+	if err != nil {
+		return err
+	}
+	defer querier.Close()
+
+	// select the raw series only, whose names have no separator
+	rawMatcher, err := labels.NewMatcher(labels.MatchRegexp, metricLabelName, "[^"+downsampleSeparator+"]+")
+	if err != nil {
+		return err
+	}
+	appender := d.storage.db.Appender(context.TODO())
+	count := 0
+	ss := querier.Select(false, nil, rawMatcher)
+	for ss.Next() {
+		series := ss.At()
+		sum, n := 0.0, 0
+		it := series.Iterator()
+		for it.Next() {
+			_, v := it.At()
+			sum += v
+			n++
+		}
+		if it.Err() != nil {
+			_ = appender.Rollback()
+			return it.Err()
+		}
+		if n <= 0 {
+			continue
+		}
+		lb := labels.NewBuilder(series.Labels())
+		lb.Set(metricLabelName, DownsampledMetricKind(series.Labels().Get(metricLabelName), resolution))
+		if _, err = appender.Append(0, lb.Labels(nil), maxt, sum/float64(n)); err != nil {
+			rollbackErr := appender.Rollback()
+			return fmt.Errorf("append error %v, rollback error %v", err, rollbackErr)
+		}
+		count++
+	}
+	if ss.Err() != nil {
+		_ = appender.Rollback()
+		return ss.Err()
+	}
+	klog.V(5).Infof("downsample %d series in window %v with resolution %v", count, windowStart, resolution)
+	return appender.Commit()
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownsampledMetricKind(t *testing.T) {
+	assert.Equal(t, "node_cpu_usage:avg_1m", DownsampledMetricKind("node_cpu_usage", time.Minute))
+	assert.Equal(t, "node_cpu_usage:avg_5m", DownsampledMetricKind("node_cpu_usage", 5*time.Minute))
+}
+
+func Test_getDownsampleResolution(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), getDownsampleResolution(now.Add(-30*time.Minute), now))
+	assert.Equal(t, time.Minute, getDownsampleResolution(now.Add(-time.Hour), now))
+	assert.Equal(t, 5*time.Minute, getDownsampleResolution(now.Add(-6*time.Hour), now))
+}
+
+func Test_tsdbDownsampler(t *testing.T) {
+	dir := t.TempDir()
+	conf := NewDefaultConfig()
+	conf.TSDBPath = dir
+	conf.TSDBEnablePromMetrics = false
+	conf.TSDBEnableDownsample = true
+	db, err := NewTSDBStorage(conf)
+	assert.NoError(t, err)
+	defer db.Close()
+	storage := db.(*tsdbStorage)
+
+	// append 1s samples in two windows of 1m, the average of each window is 1 and 3
+	now := time.Now()
+	windowStart := now.Add(-5 * time.Minute).Truncate(time.Minute)
+	var samples []MetricSample
+	for i := 0; i < 120; i++ {
+		v := 0.0
+		if i%2 == 1 {
+			v = 2
+		}
+		if i >= 60 {
+			v += 2
+		}
+		s, err := NodeCPUUsageMetric.GenerateSample(nil, windowStart.Add(time.Duration(i)*time.Second), v)
+		assert.NoError(t, err)
+		samples = append(samples, s)
+	}
+	appender := db.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+
+	d := newTSDBDownsampler(storage)
+	d.watermarks[time.Minute] = windowStart
+	d.downsample(windowStart.Add(2*time.Minute + downsampleDelay))
+	assert.Equal(t, windowStart.Add(2*time.Minute), d.watermarks[time.Minute])
+
+	queryMeta, err := NodeCPUUsageMetric.BuildQueryMeta(nil)
+	assert.NoError(t, err)
+
+	avgHints := &QueryHints{Aggregation: AggregationTypeAVG}
+
+	// query the rollups for the avg of the long time range
+	querier, err := db.Querier(windowStart, windowStart.Add(time.Hour))
+	assert.NoError(t, err)
+	result := DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, avgHints, result))
+	assert.Equal(t, 2, result.Count())
+	got, err := result.Value(AggregationTypeAVG)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, got)
+
+	// query the raw samples for the other aggregations, since the rollups only keep the averages
+	querier, err = db.Querier(windowStart, windowStart.Add(time.Hour))
+	assert.NoError(t, err)
+	result = DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, nil, result))
+	assert.Equal(t, 120, result.Count())
+	got, err = result.Value(AggregationTypeMax)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, got)
+
+	// fallback to the raw samples if the rollups do not cover the time range
+	querier, err = db.Querier(windowStart.Add(-time.Hour), windowStart.Add(time.Hour))
+	assert.NoError(t, err)
+	result = DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, avgHints, result))
+	assert.Equal(t, 120, result.Count())
+
+	// query the raw samples for the short time range
+	querier, err = db.Querier(windowStart, windowStart.Add(30*time.Minute))
+	assert.NoError(t, err)
+	result = DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, nil, result))
+	assert.Equal(t, 120, result.Count())
+	got, err = result.Value(AggregationTypeAVG)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, got)
+}
@@ -32,6 +32,7 @@ import (
 type TSDBStorage interface {
 	Appendable
 	Queryable
+	SeriesQueryable
 
 	// Close closes the storage and all its underlying resources.
 	Close() error
@@ -60,6 +61,12 @@ type Queryable interface {
 	Querier(startTime, endTime time.Time) (Querier, error)
 }
 
+// SeriesQueryable handles raw queries of series selected by label matchers, e.g. for the PromQL queries.
+type SeriesQueryable interface {
+	// SeriesQuerier returns a raw querier of the TSDB over the given time range.
+	SeriesQuerier(startTime, endTime time.Time) (promstorage.Querier, error)
+}
+
 // Querier provides querying access over time series data of a fixed time range.
 type Querier interface {
 	// Query add series to MetricResult that matches the given meta.
@@ -72,6 +79,10 @@ type Querier interface {
 // It is only an option for implementation of MetricResult to use, e.g. GroupedResult
 type QueryHints struct {
 	// GroupBy []string
+
+	// Aggregation is the aggregation which the caller applies on the result. The rollups only keep the averages, so
+	// they are queried only for the AggregationTypeAVG.
+	Aggregation AggregationType
 }
 
 var _ TSDBStorage = &tsdbStorage{}
@@ -79,6 +90,8 @@ var _ TSDBStorage = &tsdbStorage{}
 // tsdbStorage implements TSDBStorage
 type tsdbStorage struct {
 	db *tsdb.DB
+	// query the rollups for the long time ranges if the downsampling is enabled
+	downsampleEnabled bool
 }
 
 func (t *tsdbStorage) Appender() Appender {
@@ -93,9 +106,19 @@ func (t *tsdbStorage) Querier(startTime, endTime time.Time) (Querier, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &tsdbQuerier{
+	querier := &tsdbQuerier{
 		querier: q,
-	}, nil
+		mint:    startTime.UnixMilli(),
+	}
+	if t.downsampleEnabled {
+		querier.resolution = getDownsampleResolution(startTime, endTime)
+	}
+	return querier, nil
+}
+
+func (t *tsdbStorage) SeriesQuerier(startTime, endTime time.Time) (promstorage.Querier, error) {
+	klog.V(7).Infof("query series start %v, end %v", startTime.UnixMilli(), endTime.UnixMilli())
+	return t.db.Querier(context.TODO(), startTime.UnixMilli(), endTime.UnixMilli())
 }
 
 func (t *tsdbStorage) Close() error {
@@ -129,7 +152,8 @@ func NewTSDBStorage(conf *Config) (TSDBStorage, error) {
 		return nil, err
 	}
 	return &tsdbStorage{
-		db: db,
+		db:                db,
+		downsampleEnabled: conf.TSDBEnableDownsample,
 	}, nil
 }
 
@@ -164,27 +188,25 @@ var _ Querier = &tsdbQuerier{}
 // tsdbQuerier implements Querier
 type tsdbQuerier struct {
 	querier promstorage.Querier
+	mint    int64
+	// query the rollups of the resolution if it is not zero
+	resolution time.Duration
 }
 
 func (t *tsdbQuerier) Query(meta MetricMeta, hints *QueryHints, result MetricResult) error {
 	defer t.querier.Close()
-	properties := meta.GetProperties()
-	labelMatchers := make([]*labels.Matcher, 0, len(properties)+1)
-
-	nameLabelMatcher, err := labels.NewMatcher(labels.MatchEqual, metricLabelName, meta.GetKind())
-	if err != nil {
-		return err
-	}
-	labelMatchers = append(labelMatchers, nameLabelMatcher)
-
-	for k, v := range properties {
-		matcher, err := labels.NewMatcher(labels.MatchEqual, k, v)
-		if err != nil {
+	if t.resolution > 0 && hints != nil && hints.Aggregation == AggregationTypeAVG {
+		ok, err := t.queryDownsampled(meta, result)
+		if err != nil || ok {
 			return err
 		}
-		labelMatchers = append(labelMatchers, matcher)
+		klog.V(6).Infof("rollups of %v are not enough for the query, fallback to the raw samples", meta.GetKind())
 	}
 
+	labelMatchers, err := buildLabelMatchers(meta.GetKind(), meta.GetProperties())
+	if err != nil {
+		return err
+	}
 	ss := t.querier.Select(false, nil, labelMatchers...)
 	for ss.Next() {
 		if ss.Err() != nil {
@@ -197,3 +219,55 @@ func (t *tsdbQuerier) Query(meta MetricMeta, hints *QueryHints, result MetricRes
 	}
 	return nil
 }
+
+// queryDownsampled adds the rollup series into the result if the rollups cover the beginning of the query range
+func (t *tsdbQuerier) queryDownsampled(meta MetricMeta, result MetricResult) (bool, error) {
+	labelMatchers, err := buildLabelMatchers(DownsampledMetricKind(meta.GetKind(), t.resolution), meta.GetProperties())
+	if err != nil {
+		return false, err
+	}
+	var seriesList []promstorage.Series
+	covered := false
+	ss := t.querier.Select(false, nil, labelMatchers...)
+	for ss.Next() {
+		series := ss.At()
+		it := series.Iterator()
+		if it.Next() {
+			// the first rollup is at the end of the first window
+			if ts, _ := it.At(); ts <= t.mint+2*t.resolution.Milliseconds() {
+				covered = true
+			}
+		}
+		seriesList = append(seriesList, series)
+	}
+	if ss.Err() != nil {
+		return false, ss.Err()
+	}
+	if !covered {
+		return false, nil
+	}
+	for _, series := range seriesList {
+		if err := result.AddSeries(series); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func buildLabelMatchers(kind string, properties map[string]string) ([]*labels.Matcher, error) {
+	labelMatchers := make([]*labels.Matcher, 0, len(properties)+1)
+	nameLabelMatcher, err := labels.NewMatcher(labels.MatchEqual, metricLabelName, kind)
+	if err != nil {
+		return nil, err
+	}
+	labelMatchers = append(labelMatchers, nameLabelMatcher)
+
+	for k, v := range properties {
+		matcher, err := labels.NewMatcher(labels.MatchEqual, k, v)
+		if err != nil {
+			return nil, err
+		}
+		labelMatchers = append(labelMatchers, matcher)
+	}
+	return labelMatchers, nil
+}
@@ -18,6 +18,7 @@ package metriccache
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
 	"time"
@@ -156,6 +157,135 @@ func fieldCountOfMetricList(metricsList interface{}, aggregateParam AggregatePar
 	return float64(metrics.Len()), nil
 }
 
+type timeValue struct {
+	timestamp time.Time
+	value     float64
+}
+
+// fieldTimeValuesOfMetricList extracts the time and value fields of the metrics, and sorts them by time
+func fieldTimeValuesOfMetricList(metricsList interface{}, aggregateParam AggregateParam) ([]timeValue, error) {
+	inputType := reflect.TypeOf(metricsList).Kind()
+	if inputType != reflect.Slice && inputType != reflect.Array {
+		return nil, fmt.Errorf("metrics input type must be slice or array, %v is illegal", inputType.String())
+	}
+
+	metrics := reflect.ValueOf(metricsList)
+	if metrics.Len() == 0 {
+		return nil, fmt.Errorf("metric input is empty")
+	}
+
+	values := make([]timeValue, 0, metrics.Len())
+	for i := 0; i < metrics.Len(); i++ {
+		metricStruct := metrics.Index(i)
+		if metricStruct.Kind() == reflect.Ptr {
+			// convert to struct for list with ptr
+			metricStruct = metricStruct.Elem()
+		}
+		fieldValue := metricStruct.FieldByName(aggregateParam.ValueFieldName)
+		if !fieldValue.IsValid() {
+			return nil, fmt.Errorf("fieldValue not Valid, metricStruct: %v ", metricStruct)
+		}
+		fieldType := fieldValue.Type().Kind()
+		if fieldType != reflect.Float32 && fieldType != reflect.Float64 {
+			return nil, fmt.Errorf("field type must be float32 or float64, %v is illegal", fieldType.String())
+		}
+
+		fieldTimeValue := metricStruct.FieldByName(aggregateParam.TimeFieldName)
+		if !fieldTimeValue.IsValid() || !fieldTimeValue.CanInterface() {
+			return nil, fmt.Errorf("fieldTimeValue not Valid, metricStruct: %v ", metricStruct)
+		}
+		timestamp, ok := fieldTimeValue.Interface().(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("timestamp field type must be time.Time, %v is illegal", fieldTimeValue)
+		}
+		values = append(values, timeValue{timestamp: timestamp, value: fieldValue.Float()})
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].timestamp.Before(values[j].timestamp)
+	})
+	return values, nil
+}
+
+func fieldMaxOfMetricList(metricsList interface{}, aggregateParam AggregateParam) (float64, error) {
+	values, err := fieldTimeValuesOfMetricList(metricsList, aggregateParam)
+	if err != nil {
+		return 0, err
+	}
+	max := values[0].value
+	for _, v := range values[1:] {
+		max = math.Max(max, v.value)
+	}
+	return max, nil
+}
+
+func fieldMinOfMetricList(metricsList interface{}, aggregateParam AggregateParam) (float64, error) {
+	values, err := fieldTimeValuesOfMetricList(metricsList, aggregateParam)
+	if err != nil {
+		return 0, err
+	}
+	min := values[0].value
+	for _, v := range values[1:] {
+		min = math.Min(min, v.value)
+	}
+	return min, nil
+}
+
+// fieldStdDevOfMetricList returns the population standard deviation of the metrics
+func fieldStdDevOfMetricList(metricsList interface{}, aggregateParam AggregateParam) (float64, error) {
+	values, err := fieldTimeValuesOfMetricList(metricsList, aggregateParam)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v.value
+	}
+	avg := sum / float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v.value - avg) * (v.value - avg)
+	}
+	return math.Sqrt(variance / float64(len(values))), nil
+}
+
+// fieldRateOfMetricList returns the per-second increase between the first and the last metrics, which is used for
+// the counter metrics. The counter resets are not handled.
+func fieldRateOfMetricList(metricsList interface{}, aggregateParam AggregateParam) (float64, error) {
+	values, err := fieldTimeValuesOfMetricList(metricsList, aggregateParam)
+	if err != nil {
+		return 0, err
+	}
+	first, last := values[0], values[len(values)-1]
+	duration := last.timestamp.Sub(first.timestamp).Seconds()
+	if duration <= 0 {
+		return 0, fmt.Errorf("metrics time range must be positive for rate, got %v", duration)
+	}
+	return (last.value - first.value) / duration, nil
+}
+
+// fieldEWMAOfMetricList returns the exponentially weighted moving average of the metrics in the order of time,
+// the later metrics have higher weights.
+func fieldEWMAOfMetricList(metricsList interface{}, aggregateParam AggregateParam, alpha float64) (float64, error) {
+	if alpha <= 0 || alpha > 1 {
+		return 0, fmt.Errorf("ewma alpha must be in (0, 1], %v is illegal", alpha)
+	}
+	values, err := fieldTimeValuesOfMetricList(metricsList, aggregateParam)
+	if err != nil {
+		return 0, err
+	}
+	ewma := values[0].value
+	for _, v := range values[1:] {
+		ewma = alpha*v.value + (1-alpha)*ewma
+	}
+	return ewma, nil
+}
+
+func ewmaFuncOfMetricList(alpha float64) AggregationFunc {
+	return func(metricsList interface{}, param AggregateParam) (float64, error) {
+		return fieldEWMAOfMetricList(metricsList, param, alpha)
+	}
+}
+
 func percentileFuncOfMetricList(percentile float32) AggregationFunc {
 	return func(metricsList interface{}, param AggregateParam) (float64, error) {
 		return fieldPercentileOfMetricList(metricsList, param, percentile)
@@ -386,3 +386,77 @@ func Test_fieldLastOfMetricListBool(t *testing.T) {
 		})
 	}
 }
+
+func Test_fieldAggregationsOfMetricList(t *testing.T) {
+	now := time.Now()
+	points := []*Point{
+		{Timestamp: now.Add(2 * time.Second), Value: 8},
+		{Timestamp: now, Value: 2},
+		{Timestamp: now.Add(time.Second), Value: 4},
+		{Timestamp: now.Add(3 * time.Second), Value: 6},
+	}
+	tests := []struct {
+		name            string
+		aggregationType AggregationType
+		metricsList     interface{}
+		want            float64
+		wantErr         bool
+	}{
+		{
+			name:            "max",
+			aggregationType: AggregationTypeMax,
+			metricsList:     points,
+			want:            8,
+		},
+		{
+			name:            "min",
+			aggregationType: AggregationTypeMin,
+			metricsList:     points,
+			want:            2,
+		},
+		{
+			name:            "stddev",
+			aggregationType: AggregationTypeStdDev,
+			metricsList:     points,
+			want:            2.23606797749979,
+		},
+		{
+			name:            "rate in the order of time",
+			aggregationType: AggregationTypeRate,
+			metricsList:     points,
+			want:            4.0 / 3,
+		},
+		{
+			name:            "ewma in the order of time",
+			aggregationType: AggregationTypeEWMA,
+			metricsList:     points,
+			// 2 -> 2.6 -> 4.22 -> 4.754
+			want: 4.754,
+		},
+		{
+			name:            "rate needs a time range",
+			aggregationType: AggregationTypeRate,
+			metricsList:     points[:1],
+			wantErr:         true,
+		},
+		{
+			name:            "empty input",
+			aggregationType: AggregationTypeMax,
+			metricsList:     []*Point{},
+			wantErr:         true,
+		},
+		{
+			name:            "illegal input",
+			aggregationType: AggregationTypeMin,
+			metricsList:     1,
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getAggregateFunc(tt.aggregationType)(tt.metricsList, pointsDefaultAggregateParam)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
@@ -65,6 +65,18 @@ var (
 		Help:      "Number of cpu cores used by node in realtime",
 	}, []string{NodeKey})
 
+	PodSourceSyncStatus = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_source_sync_status",
+		Help:      "the count of pod list syncs from each pod source",
+	}, []string{NodeKey, PodSourceKey, StatusKey})
+
+	PodSourceInconsistentPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_source_inconsistent_pods",
+		Help:      "Number of running pods which are inconsistent between kubelet and the container runtime",
+	}, []string{NodeKey})
+
 	CommonCollectors = []prometheus.Collector{
 		KoordletStartTime,
 		CollectNodeCPUInfoStatus,
@@ -73,6 +85,8 @@ var (
 		PodEviction,
 		PodEvictionDetail.GetCounterVec(),
 		NodeUsedCPU,
+		PodSourceSyncStatus,
+		PodSourceInconsistentPods,
 	}
 )
 
@@ -141,6 +155,27 @@ func RecordNodeUsedCPU(value float64) {
 	NodeUsedCPU.With(labels).Set(value)
 }
 
+func RecordPodSourceSyncStatus(source string, err error) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodSourceKey] = source
+	labels[StatusKey] = StatusSucceed
+	if err != nil {
+		labels[StatusKey] = StatusFailed
+	}
+	PodSourceSyncStatus.With(labels).Inc()
+}
+
+func RecordPodSourceInconsistentPods(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	PodSourceInconsistentPods.With(labels).Set(value)
+}
+
 func labelsClone(labels prometheus.Labels) prometheus.Labels {
 	copyLabels := prometheus.Labels{}
 	for key, value := range labels {
@@ -31,9 +31,23 @@ var (
 		Help:      "Run-time replenished within a period (in microseconds) in container-level set by koordlet",
 	}, []string{NodeKey, PodNamespace, PodName, ContainerID, ContainerName})
 
+	PodCPUBurstUsedSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_cpu_burst_used_seconds",
+		Help:      "The cfs quota burst budget (in seconds of running at twice the cpu limit) consumed by the pod, the cfs quota scales down when it reaches the allowed",
+	}, []string{NodeKey, PodNamespace, PodName, PodUID})
+
+	PodCPUBurstAllowedSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_cpu_burst_allowed_seconds",
+		Help:      "The cfs quota burst budget (in seconds of running at twice the cpu limit) allowed for the pod",
+	}, []string{NodeKey, PodNamespace, PodName, PodUID})
+
 	CPUBurstCollector = []prometheus.Collector{
 		ContainerScaledCFSBurstUS,
 		ContainerScaledCFSQuotaUS,
+		PodCPUBurstUsedSeconds,
+		PodCPUBurstAllowedSeconds,
 	}
 )
 
@@ -61,6 +75,30 @@ func RecordContainerScaledCFSQuotaUS(podNS, podName, containerID, containerName
 	ContainerScaledCFSQuotaUS.With(labels).Set(value)
 }
 
+func RecordPodCPUBurstBudgetSeconds(podNS, podName, podUID string, used, allowed float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodNamespace] = podNS
+	labels[PodName] = podName
+	labels[PodUID] = podUID
+	PodCPUBurstUsedSeconds.With(labels).Set(used)
+	PodCPUBurstAllowedSeconds.With(labels).Set(allowed)
+}
+
+func DeletePodCPUBurstBudgetSeconds(podNS, podName, podUID string) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodNamespace] = podNS
+	labels[PodName] = podName
+	labels[PodUID] = podUID
+	PodCPUBurstUsedSeconds.Delete(labels)
+	PodCPUBurstAllowedSeconds.Delete(labels)
+}
+
 func ResetCPUBurstCollector() {
 	ContainerScaledCFSBurstUS.Reset()
 	ContainerScaledCFSQuotaUS.Reset()
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	BESuppressMemory = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_suppress_memory_bytes",
+		Help:      "Memory limit of BE pods suppressed by koordlet in bytes",
+	}, []string{NodeKey})
+
+	BESuppressLSUsedMemory = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_suppress_ls_used_memory_bytes",
+		Help:      "Memory used by LS in bytes. We consider non-BE pods and podMeta-missing pods as LS.",
+	}, []string{NodeKey})
+
+	MemorySuppressCollector = []prometheus.Collector{
+		BESuppressMemory,
+		BESuppressLSUsedMemory,
+	}
+)
+
+func RecordBESuppressMemoryBytes(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BESuppressMemory.With(labels).Set(value)
+}
+
+func RecordBESuppressLSUsedMemoryBytes(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BESuppressLSUsedMemory.With(labels).Set(value)
+}
@@ -30,8 +30,11 @@ func init() {
 	prometheus.MustRegister(CPICollectors...)
 	prometheus.MustRegister(PSICollectors...)
 	prometheus.MustRegister(CPUSuppressCollector...)
+	prometheus.MustRegister(MemorySuppressCollector...)
 	prometheus.MustRegister(CPUBurstCollector...)
 	prometheus.MustRegister(PredictionCollectors...)
+	prometheus.MustRegister(QOSStrategyCollectors...)
+	prometheus.MustRegister(ResourceExecutorCollectors...)
 }
 
 const (
@@ -58,6 +61,14 @@ const (
 
 	ResourceKey = "resource"
 
+	PodSourceKey = "source"
+
+	StrategyKey  = "strategy"
+	ActionKey    = "action"
+	TargetKey    = "target"
+	ActionUpdate = "update"
+	ActionEvict  = "evict"
+
 	UnitKey     = "unit"
 	UnitCore    = "core"
 	UnitByte    = "byte"
@@ -137,9 +137,15 @@ func TestCommonCollectors(t *testing.T) {
 		RecordCollectNodeLocalStorageInfoStatus(nil)
 		RecordBESuppressCores("cfsQuota", float64(1000))
 		RecordBESuppressLSUsedCPU(1.0)
+		RecordBESuppressMemoryBytes(1024)
+		RecordBESuppressLSUsedMemoryBytes(1024)
 		RecordNodeUsedCPU(2.0)
+		RecordPodSourceSyncStatus("kubelet", testingErr)
+		RecordPodSourceSyncStatus("cri", nil)
+		RecordPodSourceInconsistentPods(1)
 		RecordContainerScaledCFSBurstUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
 		RecordContainerScaledCFSQuotaUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
+		RecordPodCPUBurstBudgetSeconds(testingPod.Namespace, testingPod.Name, string(testingPod.UID), 10, 60)
 		RecordPodEviction(testingPod.Namespace, testingPod.Name, "evictByCPU")
 		ResetContainerCPI()
 		RecordContainerCPI(testingContainer, testingPod, 1, 1)
@@ -287,3 +293,37 @@ func TestPredictorCollectors(t *testing.T) {
 		RecordNodePredictedResourceReclaimable(string(corev1.ResourceMemory), UnitByte, "testPredictor", float64(testNodeReclaimable.Memory().Value()))
 	})
 }
+
+func TestQOSStrategyCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordQOSStrategyDryRunDecision("testStrategy", ActionUpdate, "cpu.cfs_quota_us")
+		RecordQOSStrategyDryRunDecision("testStrategy", ActionEvict, "testReason")
+	})
+}
+
+func TestResourceExecutorCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+
+	t.Run("test", func(t *testing.T) {
+		Register(testingNode)
+		defer Register(nil)
+		RecordResourceUpdateTransaction(resourceexecutor.TransactionStatusCommitted)
+		RecordResourceUpdateTransaction(resourceexecutor.TransactionStatusRolledBack)
+		RecordResourceUpdateRollback("cpuset.cpus", nil)
+		RecordResourceUpdateRollback("cpuset.cpus", fmt.Errorf("expected error"))
+	})
+}
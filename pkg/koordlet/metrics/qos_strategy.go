@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	QOSStrategyDryRunDecision = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "qos_strategy_dry_run_decisions",
+		Help:      "Number of decisions made by the qos strategies in the dry-run mode which are not applied. The target is the resource type for updates and the reason for evictions.",
+	}, []string{NodeKey, StrategyKey, ActionKey, TargetKey})
+
+	QOSStrategyCollectors = []prometheus.Collector{
+		QOSStrategyDryRunDecision,
+	}
+)
+
+func RecordQOSStrategyDryRunDecision(strategy, action, target string) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[StrategyKey] = strategy
+	labels[ActionKey] = action
+	labels[TargetKey] = target
+	QOSStrategyDryRunDecision.With(labels).Inc()
+}
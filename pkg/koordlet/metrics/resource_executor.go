@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	ResourceUpdateTransaction = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resource_update_transactions",
+		Help:      "Number of the resource update transactions by the status, i.e. committed, aborted, rolled_back and rollback_failed.",
+	}, []string{NodeKey, StatusKey})
+
+	ResourceUpdateRollback = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resource_update_rollbacks",
+		Help:      "Number of the resources rolled back in the failed update transactions.",
+	}, []string{NodeKey, ResourceKey, StatusKey})
+
+	ResourceExecutorCollectors = []prometheus.Collector{
+		ResourceUpdateTransaction,
+		ResourceUpdateRollback,
+	}
+)
+
+func RecordResourceUpdateTransaction(status string) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[StatusKey] = status
+	ResourceUpdateTransaction.With(labels).Inc()
+}
+
+func RecordResourceUpdateRollback(resourceType string, err error) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResourceKey] = resourceType
+	labels[StatusKey] = StatusSucceed
+	if err != nil {
+		labels[StatusKey] = StatusFailed
+	}
+	ResourceUpdateRollback.With(labels).Inc()
+}
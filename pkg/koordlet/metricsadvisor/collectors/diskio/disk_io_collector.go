@@ -0,0 +1,257 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskio
+
+import (
+	"fmt"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	CollectorName = "DiskIOCollector"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// diskIOStat is the accumulated block io at the collect time
+type diskIOStat struct {
+	system.BlkIOStatRaw
+	Timestamp time.Time
+}
+
+// diskIORate is the block io throughput in bytes or operations per second
+type diskIORate struct {
+	ReadBPS   float64
+	WriteBPS  float64
+	ReadIOPS  float64
+	WriteIOPS float64
+}
+
+// diskIOCollector collects the block io throughput of the node and pods.
+// The node throughput is summed over the physical disks in /proc/diskstats, while the pod throughput is read from
+// the blkio cgroup (cgroups-v1) or the io cgroup (cgroups-v2) of the pod.
+type diskIOCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	appendableDB    metriccache.Appendable
+	statesInformer  statesinformer.StatesInformer
+	cgroupReader    resourceexecutor.CgroupReader
+	podFilter       framework.PodFilter
+
+	lastNodeDiskIOStat *diskIOStat
+	lastPodDiskIOStat  *gocache.Cache
+}
+
+func New(opt *framework.Options) framework.Collector {
+	collectInterval := opt.Config.CollectResUsedInterval
+	podFilter := framework.DefaultPodFilter
+	if filter, ok := opt.PodFilters[CollectorName]; ok {
+		podFilter = filter
+	}
+	return &diskIOCollector{
+		collectInterval:   collectInterval,
+		started:           atomic.NewBool(false),
+		appendableDB:      opt.MetricCache,
+		statesInformer:    opt.StatesInformer,
+		cgroupReader:      opt.CgroupReader,
+		podFilter:         podFilter,
+		lastPodDiskIOStat: gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+	}
+}
+
+var _ framework.PodCollector = &diskIOCollector{}
+
+func (c *diskIOCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.DiskIOCollector)
+}
+
+func (c *diskIOCollector) Setup(ctx *framework.Context) {}
+
+func (c *diskIOCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go wait.Until(c.collectDiskIO, c.collectInterval, stopCh)
+}
+
+func (c *diskIOCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *diskIOCollector) FilterPod(meta *statesinformer.PodMeta) (bool, string) {
+	return c.podFilter.FilterPod(meta)
+}
+
+func (c *diskIOCollector) collectDiskIO() {
+	klog.V(6).Info("start collectDiskIO")
+	metrics := c.collectNodeDiskIO()
+	podMetas := c.statesInformer.GetAllPods()
+	for _, meta := range podMetas {
+		metrics = append(metrics, c.collectPodDiskIO(meta)...)
+	}
+
+	appender := c.appendableDB.Appender()
+	if err := appender.Append(metrics); err != nil {
+		klog.Warningf("append disk io metrics failed, reason: %v", err)
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("commit disk io metrics failed, reason: %v", err)
+		return
+	}
+	c.started.Store(true)
+	klog.V(5).Infof("collectDiskIO finished, pod num %d", len(podMetas))
+}
+
+func (c *diskIOCollector) collectNodeDiskIO() []metriccache.MetricSample {
+	collectTime := timeNow()
+	stats, err := system.GetDiskStats()
+	if err != nil {
+		klog.Warningf("failed to collect node disk io, err: %v", err)
+		return nil
+	}
+	currentStat := &diskIOStat{Timestamp: collectTime}
+	for devName, stat := range stats {
+		if !system.IsPhysicalDisk(devName) {
+			continue
+		}
+		currentStat.ReadBytes += stat.ReadBytes()
+		currentStat.WriteBytes += stat.WriteBytes()
+		currentStat.ReadIOs += stat.ReadIOs
+		currentStat.WriteIOs += stat.WriteIOs
+	}
+
+	lastStat := c.lastNodeDiskIOStat
+	c.lastNodeDiskIOStat = currentStat
+	if lastStat == nil {
+		klog.V(6).Infof("ignore the first node disk io stat collection")
+		return nil
+	}
+	rate, err := calcDiskIORate(currentStat, lastStat)
+	if err != nil {
+		klog.V(4).Infof("failed to calculate node disk io, err: %v", err)
+		return nil
+	}
+
+	metrics, err := generateDiskIOSamples(rate, nil, collectTime, metriccache.NodeDiskReadBPSMetric,
+		metriccache.NodeDiskWriteBPSMetric, metriccache.NodeDiskReadIOPSMetric, metriccache.NodeDiskWriteIOPSMetric)
+	if err != nil {
+		klog.Warningf("generate node disk io metrics failed, err %v", err)
+		return nil
+	}
+	klog.V(6).Infof("collect node disk io finished, metric %+v", rate)
+	return metrics
+}
+
+func (c *diskIOCollector) collectPodDiskIO(meta *statesinformer.PodMeta) []metriccache.MetricSample {
+	pod := meta.Pod
+	uid := string(pod.UID)
+	if filtered, msg := c.FilterPod(meta); filtered {
+		klog.V(5).Infof("skip collect pod %s/%s, reason: %s", pod.Namespace, pod.Name, msg)
+		return nil
+	}
+
+	collectTime := timeNow()
+	stat, err := c.cgroupReader.ReadBlkIOStat(meta.CgroupDir)
+	if err != nil {
+		if pod.Status.Phase == corev1.PodRunning {
+			klog.V(4).Infof("collect pod %s/%s, uid %v disk io failed, err %v", pod.Namespace, pod.Name, uid, err)
+		}
+		return nil
+	}
+	currentStat := &diskIOStat{BlkIOStatRaw: *stat, Timestamp: collectTime}
+
+	lastStatValue, ok := c.lastPodDiskIOStat.Get(uid)
+	c.lastPodDiskIOStat.Set(uid, currentStat, gocache.DefaultExpiration)
+	if !ok {
+		klog.V(6).Infof("collect pod %s/%s, uid %s disk io first point", pod.Namespace, pod.Name, uid)
+		return nil
+	}
+	rate, err := calcDiskIORate(currentStat, lastStatValue.(*diskIOStat))
+	if err != nil {
+		klog.V(4).Infof("failed to calculate pod %s/%s disk io, err: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+
+	metrics, err := generateDiskIOSamples(rate, metriccache.MetricPropertiesFunc.Pod(uid), collectTime,
+		metriccache.PodDiskReadBPSMetric, metriccache.PodDiskWriteBPSMetric, metriccache.PodDiskReadIOPSMetric,
+		metriccache.PodDiskWriteIOPSMetric)
+	if err != nil {
+		klog.Warningf("generate pod %v disk io metrics failed, err %v", util.GetPodKey(pod), err)
+		return nil
+	}
+	klog.V(6).Infof("collect pod %s/%s, uid %s disk io finished, metric %+v", pod.Namespace, pod.Name, uid, rate)
+	return metrics
+}
+
+func generateDiskIOSamples(rate *diskIORate, properties map[metriccache.MetricProperty]string, collectTime time.Time,
+	readBPS, writeBPS, readIOPS, writeIOPS metriccache.MetricResource) ([]metriccache.MetricSample, error) {
+	metrics := make([]metriccache.MetricSample, 0, 4)
+	for _, t := range []struct {
+		resource metriccache.MetricResource
+		value    float64
+	}{
+		{resource: readBPS, value: rate.ReadBPS},
+		{resource: writeBPS, value: rate.WriteBPS},
+		{resource: readIOPS, value: rate.ReadIOPS},
+		{resource: writeIOPS, value: rate.WriteIOPS},
+	} {
+		sample, err := t.resource.GenerateSample(properties, collectTime, t.value)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, sample)
+	}
+	return metrics, nil
+}
+
+// calcDiskIORate returns the block io throughput between two points
+func calcDiskIORate(curPoint, prePoint *diskIOStat) (*diskIORate, error) {
+	duration := curPoint.Timestamp.Sub(prePoint.Timestamp).Seconds()
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid collect duration %v", duration)
+	}
+	// counters may be reset if the cgroup or the device is recreated
+	if curPoint.ReadBytes < prePoint.ReadBytes || curPoint.WriteBytes < prePoint.WriteBytes ||
+		curPoint.ReadIOs < prePoint.ReadIOs || curPoint.WriteIOs < prePoint.WriteIOs {
+		return nil, fmt.Errorf("disk io counters decreased, current %+v, previous %+v", curPoint, prePoint)
+	}
+	return &diskIORate{
+		ReadBPS:   float64(curPoint.ReadBytes-prePoint.ReadBytes) / duration,
+		WriteBPS:  float64(curPoint.WriteBytes-prePoint.WriteBytes) / duration,
+		ReadIOPS:  float64(curPoint.ReadIOs-prePoint.ReadIOs) / duration,
+		WriteIOPS: float64(curPoint.WriteIOs-prePoint.WriteIOs) / duration,
+	}, nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskio
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_calcDiskIORate(t *testing.T) {
+	testNow := time.Now()
+	tests := []struct {
+		name     string
+		curPoint *diskIOStat
+		prePoint *diskIOStat
+		want     *diskIORate
+		wantErr  bool
+	}{
+		{
+			name: "calculate rate",
+			curPoint: &diskIOStat{
+				BlkIOStatRaw: system.BlkIOStatRaw{ReadBytes: 8192, WriteBytes: 16384, ReadIOs: 4, WriteIOs: 8},
+				Timestamp:    testNow,
+			},
+			prePoint: &diskIOStat{
+				BlkIOStatRaw: system.BlkIOStatRaw{ReadBytes: 4096, WriteBytes: 8192, ReadIOs: 2, WriteIOs: 4},
+				Timestamp:    testNow.Add(-2 * time.Second),
+			},
+			want: &diskIORate{
+				ReadBPS:   2048,
+				WriteBPS:  4096,
+				ReadIOPS:  1,
+				WriteIOPS: 2,
+			},
+		},
+		{
+			name:     "invalid duration",
+			curPoint: &diskIOStat{Timestamp: testNow},
+			prePoint: &diskIOStat{Timestamp: testNow},
+			wantErr:  true,
+		},
+		{
+			name: "counters reset",
+			curPoint: &diskIOStat{
+				BlkIOStatRaw: system.BlkIOStatRaw{ReadIOs: 1},
+				Timestamp:    testNow,
+			},
+			prePoint: &diskIOStat{
+				BlkIOStatRaw: system.BlkIOStatRaw{ReadIOs: 2},
+				Timestamp:    testNow.Add(-time.Second),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := calcDiskIORate(tt.curPoint, tt.prePoint)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_diskIOCollector_collectDiskIO(t *testing.T) {
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "test",
+			UID:       "test-pod-uid",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+	testPodMetaDir := "kubepods.slice/kubepods-podtest_pod_uid.slice"
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	// sda: read 24 sectors, 3 ios; write 40 sectors, 5 ios
+	helper.WriteProcSubFileContents(system.ProcDiskStatsName, `   8       0 sda 3 0 24 0 5 0 40 0 0 0 0 0 0 0 0
+   8       1 sda1 3 0 24 0 5 0 40 0 0 0 0 0 0 0 0
+   7       0 loop0 100 0 100 0 100 0 100 0 0 0 0
+`)
+	helper.MkDirAll(filepath.Join(system.SysBlockSubDir, "sda"))
+	helper.MkDirAll(filepath.Join(system.SysBlockSubDir, "loop0"))
+	helper.MkDirAll(filepath.Join(system.SysVirtualBlockSubDir, "loop0"))
+	helper.WriteCgroupFileContents(testPodMetaDir, system.BlkioIOServiceBytes, "8:0 Read 4096\n8:0 Write 8192\nTotal 12288\n")
+	helper.WriteCgroupFileContents(testPodMetaDir, system.BlkioIOServiced, "8:0 Read 2\n8:0 Write 3\nTotal 5\n")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              helper.TempDir,
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		metricCache.Close()
+	}()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		{Pod: testPod, CgroupDir: testPodMetaDir},
+	}).Times(1)
+
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+	c := New(&framework.Options{
+		Config: &framework.Config{
+			CollectResUsedInterval: time.Second,
+		},
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	}).(*diskIOCollector)
+	c.lastNodeDiskIOStat = &diskIOStat{
+		BlkIOStatRaw: system.BlkIOStatRaw{ReadBytes: 2048, WriteBytes: 4096, ReadIOs: 1, WriteIOs: 1},
+		Timestamp:    testNow.Add(-time.Second),
+	}
+	c.lastPodDiskIOStat.Set(string(testPod.UID), &diskIOStat{
+		BlkIOStatRaw: system.BlkIOStatRaw{ReadBytes: 1024, WriteBytes: 4096, ReadIOs: 1, WriteIOs: 1},
+		Timestamp:    testNow.Add(-time.Second),
+	}, gocache.DefaultExpiration)
+
+	c.collectDiskIO()
+	assert.True(t, c.Started())
+
+	querier, err := metricCache.Querier(testNow.Add(-time.Second), testNow.Add(time.Second))
+	assert.NoError(t, err)
+	podProperties := metriccache.MetricPropertiesFunc.Pod(string(testPod.UID))
+	for _, tt := range []struct {
+		resource   metriccache.MetricResource
+		properties map[metriccache.MetricProperty]string
+		want       float64
+	}{
+		{resource: metriccache.NodeDiskReadBPSMetric, want: 10240},
+		{resource: metriccache.NodeDiskWriteBPSMetric, want: 16384},
+		{resource: metriccache.NodeDiskReadIOPSMetric, want: 2},
+		{resource: metriccache.NodeDiskWriteIOPSMetric, want: 4},
+		{resource: metriccache.PodDiskReadBPSMetric, properties: podProperties, want: 3072},
+		{resource: metriccache.PodDiskWriteBPSMetric, properties: podProperties, want: 4096},
+		{resource: metriccache.PodDiskReadIOPSMetric, properties: podProperties, want: 1},
+		{resource: metriccache.PodDiskWriteIOPSMetric, properties: podProperties, want: 2},
+	} {
+		queryMeta, err := tt.resource.BuildQueryMeta(tt.properties)
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		got, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, queryMeta.GetKind())
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostapplication
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+const (
+	CollectorName = "HostApplicationCollector"
+)
+
+var (
+	timeNow = time.Now
+)
+
+type hostAppCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	appendableDB    metriccache.Appendable
+	statesInformer  statesinformer.StatesInformer
+	cgroupReader    resourceexecutor.CgroupReader
+	lastAppCPUStat  *gocache.Cache
+
+	sharedState *framework.SharedState
+}
+
+func New(opt *framework.Options) framework.Collector {
+	collectInterval := opt.Config.CollectResUsedInterval
+	return &hostAppCollector{
+		collectInterval: collectInterval,
+		started:         atomic.NewBool(false),
+		appendableDB:    opt.MetricCache,
+		statesInformer:  opt.StatesInformer,
+		cgroupReader:    opt.CgroupReader,
+		lastAppCPUStat:  gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+	}
+}
+
+func (h *hostAppCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.HostApplicationCollector)
+}
+
+func (h *hostAppCollector) Setup(c *framework.Context) {
+	h.sharedState = c.State
+}
+
+func (h *hostAppCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, h.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go wait.Until(h.collectHostAppResUsed, h.collectInterval, stopCh)
+}
+
+func (h *hostAppCollector) Started() bool {
+	return h.started.Load()
+}
+
+func (h *hostAppCollector) collectHostAppResUsed() {
+	klog.V(6).Info("start collectHostAppResUsed")
+	nodeSLO := h.statesInformer.GetNodeSLO()
+	if nodeSLO == nil {
+		klog.Warningf("get nil node slo during collect host application resource usage")
+		return
+	}
+
+	count := 0
+	metrics := make([]metriccache.MetricSample, 0)
+	allCPUUsageCores := metriccache.Point{Timestamp: timeNow(), Value: 0}
+	allMemoryUsage := metriccache.Point{Timestamp: timeNow(), Value: 0}
+	cgroupDirs := getHostAppCgroupDirs(nodeSLO.Spec.HostApplications)
+	accountedDirs := map[string]bool{}
+	for i := range nodeSLO.Spec.HostApplications {
+		hostApp := &nodeSLO.Spec.HostApplications[i]
+		cgroupDir := cgroupDirs[hostApp.Name]
+		if len(cgroupDir) <= 0 {
+			klog.V(4).Infof("skip collect host application %s, cgroup dir is empty", hostApp.Name)
+			continue
+		}
+
+		collectTime := timeNow()
+		currentCPUUsage, err0 := h.cgroupReader.ReadCPUAcctUsage(cgroupDir)
+		memStat, err1 := h.cgroupReader.ReadMemoryStat(cgroupDir)
+		if err0 != nil || err1 != nil {
+			klog.V(4).Infof("failed to collect host application %s usage, CPU err: %s, Memory err: %s",
+				hostApp.Name, err0, err1)
+			continue
+		}
+
+		lastCPUStatValue, ok := h.lastAppCPUStat.Get(hostApp.Name)
+		h.lastAppCPUStat.Set(hostApp.Name, framework.CPUStat{
+			CPUUsage:  currentCPUUsage,
+			Timestamp: collectTime,
+		}, gocache.DefaultExpiration)
+		if !ok {
+			klog.V(4).Infof("ignore the first cpu stat collection for host application %s", hostApp.Name)
+			continue
+		}
+		lastCPUStat := lastCPUStatValue.(framework.CPUStat)
+		// do subtraction and division first to avoid overflow
+		cpuUsageValue := float64(currentCPUUsage-lastCPUStat.CPUUsage) / float64(collectTime.Sub(lastCPUStat.Timestamp))
+		memUsageValue := float64(memStat.Usage())
+
+		cpuUsageMetric, err := metriccache.HostAppCPUUsageMetric.GenerateSample(
+			metriccache.MetricPropertiesFunc.HostApplication(hostApp.Name), collectTime, cpuUsageValue)
+		if err != nil {
+			klog.V(4).Infof("failed to generate host application %s cpu metrics, err %v", hostApp.Name, err)
+			continue
+		}
+		memUsageMetric, err := metriccache.HostAppMemoryUsageMetric.GenerateSample(
+			metriccache.MetricPropertiesFunc.HostApplication(hostApp.Name), collectTime, memUsageValue)
+		if err != nil {
+			klog.V(4).Infof("failed to generate host application %s memory metrics, err %v", hostApp.Name, err)
+			continue
+		}
+		metrics = append(metrics, cpuUsageMetric, memUsageMetric)
+		klog.V(6).Infof("collect host application %s finished, cpu %v, memory %v", hostApp.Name, cpuUsageValue, memUsageValue)
+
+		count++
+		// the usage of the nested or duplicated cgroup is already accounted
+		if !accountedDirs[cgroupDir] && !isNestedCgroupDir(cgroupDir, cgroupDirs) {
+			accountedDirs[cgroupDir] = true
+			allCPUUsageCores.Value += cpuUsageValue
+			allMemoryUsage.Value += memUsageValue
+		}
+	}
+
+	appender := h.appendableDB.Appender()
+	if err := appender.Append(metrics); err != nil {
+		klog.Warningf("Append host application metrics error: %v", err)
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("Commit host application metrics failed, error: %v", err)
+		return
+	}
+
+	h.sharedState.UpdateHostAppUsage(allCPUUsageCores, allMemoryUsage)
+
+	h.started.Store(true)
+	klog.V(4).Infof("collectHostAppResUsed finished, host application num %d, collected %d",
+		len(nodeSLO.Spec.HostApplications), count)
+}
+
+// getHostAppCgroupDirs returns the cgroup dirs of the host applications, the root cgroup is excluded since it
+// contains all processes on node.
+func getHostAppCgroupDirs(hostApps []slov1alpha1.HostApplicationSpec) map[string]string {
+	cgroupDirs := make(map[string]string, len(hostApps))
+	for i := range hostApps {
+		cgroupDir := koordletutil.GetHostAppCgroupRelativePath(&hostApps[i])
+		if cgroupDir == "." || cgroupDir == "/" {
+			cgroupDir = ""
+		}
+		cgroupDirs[hostApps[i].Name] = cgroupDir
+	}
+	return cgroupDirs
+}
+
+// isNestedCgroupDir returns whether the cgroup dir is a descendant of another cgroup dir.
+func isNestedCgroupDir(cgroupDir string, cgroupDirs map[string]string) bool {
+	for _, dir := range cgroupDirs {
+		if len(dir) <= 0 || dir == cgroupDir {
+			continue
+		}
+		if strings.HasPrefix(cgroupDir, filepath.Clean(dir)+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostapplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_hostAppCollector_collectHostAppResUsed(t *testing.T) {
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	testMemoryStat := `
+total_cache 104857600
+total_rss 104857600
+total_inactive_anon 104857600
+total_active_anon 0
+total_inactive_file 104857600
+total_active_file 0
+total_unevictable 0
+`
+	testNodeSLO := &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			HostApplications: []slov1alpha1.HostApplicationSpec{
+				{
+					Name: "nginx",
+					QoS:  extension.QoSLS,
+				},
+				{
+					// nested in the cgroup of nginx
+					Name: "nginx-worker",
+					QoS:  extension.QoSLS,
+					CgroupPath: &slov1alpha1.CgroupPath{
+						Base:         slov1alpha1.CgroupBaseTypeRoot,
+						ParentDir:    "host-latency-sensitive/nginx",
+						RelativePath: "worker",
+					},
+				},
+				{
+					// cgroup not exist
+					Name: "logger",
+					QoS:  extension.QoSBE,
+				},
+				{
+					// root cgroup is ignored
+					Name: "root-app",
+					QoS:  extension.QoSNone,
+					CgroupPath: &slov1alpha1.CgroupPath{
+						Base: slov1alpha1.CgroupBaseTypeRoot,
+					},
+				},
+			},
+		},
+	}
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteCgroupFileContents("host-latency-sensitive/nginx", system.CPUAcctUsage, "1000000000\n")
+	helper.WriteCgroupFileContents("host-latency-sensitive/nginx", system.MemoryStat, testMemoryStat)
+	helper.WriteCgroupFileContents("host-latency-sensitive/nginx/worker", system.CPUAcctUsage, "500000000\n")
+	helper.WriteCgroupFileContents("host-latency-sensitive/nginx/worker", system.MemoryStat, testMemoryStat)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetNodeSLO().Return(testNodeSLO).AnyTimes()
+
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer metricCache.Close()
+
+	c := New(&framework.Options{
+		Config:         framework.NewDefaultConfig(),
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	})
+	sharedState := framework.NewSharedState()
+	c.Setup(&framework.Context{
+		State: sharedState,
+	})
+	collector := c.(*hostAppCollector)
+
+	// the first collection only records the cpu stat
+	collector.collectHostAppResUsed()
+	assert.True(t, collector.Started())
+	gotCPU, gotMemory := sharedState.GetHostAppUsage()
+	assert.Equal(t, float64(0), gotCPU.Value)
+	assert.Equal(t, float64(0), gotMemory.Value)
+
+	for _, name := range []string{"nginx", "nginx-worker"} {
+		collector.lastAppCPUStat.Set(name, framework.CPUStat{
+			CPUUsage:  0,
+			Timestamp: testNow.Add(-time.Second),
+		}, 0)
+	}
+	collector.collectHostAppResUsed()
+	gotCPU, gotMemory = sharedState.GetHostAppUsage()
+	assert.Equal(t, float64(1), gotCPU.Value)
+	assert.Equal(t, float64(104857600), gotMemory.Value)
+
+	querier, err := metricCache.Querier(testNow.Add(-time.Minute), testNow.Add(time.Minute))
+	assert.NoError(t, err)
+	queryMeta, err := metriccache.HostAppCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication("nginx-worker"))
+	assert.NoError(t, err)
+	result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, nil, result))
+	gotWorkerCPU, err := result.Value(metriccache.AggregationTypeLast)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, gotWorkerCPU)
+}
+
+func Test_isNestedCgroupDir(t *testing.T) {
+	cgroupDirs := map[string]string{
+		"a":     "host-latency-sensitive/a",
+		"a-sub": "host-latency-sensitive/a/sub",
+		"ab":    "host-latency-sensitive/ab",
+		"root":  "",
+	}
+	assert.False(t, isNestedCgroupDir("host-latency-sensitive/a", cgroupDirs))
+	assert.True(t, isNestedCgroupDir("host-latency-sensitive/a/sub", cgroupDirs))
+	assert.False(t, isNestedCgroupDir("host-latency-sensitive/ab", cgroupDirs))
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netio
+
+import (
+	"fmt"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	CollectorName = "NetIOCollector"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// netIOStat is the accumulated traffic at the collect time
+type netIOStat struct {
+	RxBytes   uint64
+	TxBytes   uint64
+	Timestamp time.Time
+}
+
+// netIOCollector collects the network throughput of the node and pods.
+// The node throughput is summed over the physical interfaces in the host network namespace, while the pod throughput
+// is summed over the non-loopback interfaces in the network namespace of the pod.
+type netIOCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	appendableDB    metriccache.Appendable
+	statesInformer  statesinformer.StatesInformer
+	cgroupReader    resourceexecutor.CgroupReader
+	podFilter       framework.PodFilter
+
+	lastNodeNetIOStat *netIOStat
+	lastPodNetIOStat  *gocache.Cache
+}
+
+func New(opt *framework.Options) framework.Collector {
+	collectInterval := opt.Config.CollectResUsedInterval
+	podFilter := framework.DefaultPodFilter
+	if filter, ok := opt.PodFilters[CollectorName]; ok {
+		podFilter = filter
+	}
+	return &netIOCollector{
+		collectInterval:  collectInterval,
+		started:          atomic.NewBool(false),
+		appendableDB:     opt.MetricCache,
+		statesInformer:   opt.StatesInformer,
+		cgroupReader:     opt.CgroupReader,
+		podFilter:        podFilter,
+		lastPodNetIOStat: gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+	}
+}
+
+var _ framework.PodCollector = &netIOCollector{}
+
+func (c *netIOCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.NetIOCollector)
+}
+
+func (c *netIOCollector) Setup(ctx *framework.Context) {}
+
+func (c *netIOCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go wait.Until(c.collectNetIO, c.collectInterval, stopCh)
+}
+
+func (c *netIOCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *netIOCollector) FilterPod(meta *statesinformer.PodMeta) (bool, string) {
+	return c.podFilter.FilterPod(meta)
+}
+
+func (c *netIOCollector) collectNetIO() {
+	klog.V(6).Info("start collectNetIO")
+	metrics := c.collectNodeNetIO()
+	podMetas := c.statesInformer.GetAllPods()
+	for _, meta := range podMetas {
+		metrics = append(metrics, c.collectPodNetIO(meta)...)
+	}
+
+	appender := c.appendableDB.Appender()
+	if err := appender.Append(metrics); err != nil {
+		klog.Warningf("append net io metrics failed, reason: %v", err)
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("commit net io metrics failed, reason: %v", err)
+		return
+	}
+	c.started.Store(true)
+	klog.V(5).Infof("collectNetIO finished, pod num %d", len(podMetas))
+}
+
+func (c *netIOCollector) collectNodeNetIO() []metriccache.MetricSample {
+	collectTime := timeNow()
+	stats, err := system.GetNetDevStats(system.GetProcNetDevPath())
+	if err != nil {
+		klog.Warningf("failed to collect node net io, err: %v", err)
+		return nil
+	}
+	currentStat := sumNetDevStats(stats, func(ifName string) bool {
+		return ifName == system.LoopbackInterfaceName || system.IsVirtualNetInterface(ifName)
+	}, collectTime)
+
+	lastStat := c.lastNodeNetIOStat
+	c.lastNodeNetIOStat = currentStat
+	if lastStat == nil {
+		klog.V(6).Infof("ignore the first node net io stat collection")
+		return nil
+	}
+	rxBytes, txBytes, err := calcNetIORate(currentStat, lastStat)
+	if err != nil {
+		klog.V(4).Infof("failed to calculate node net io, err: %v", err)
+		return nil
+	}
+
+	metrics := make([]metriccache.MetricSample, 0, 2)
+	for _, t := range []struct {
+		resource metriccache.MetricResource
+		value    float64
+	}{
+		{resource: metriccache.NodeNetRxBytesMetric, value: rxBytes},
+		{resource: metriccache.NodeNetTxBytesMetric, value: txBytes},
+	} {
+		sample, err := t.resource.GenerateSample(nil, collectTime, t.value)
+		if err != nil {
+			klog.Warningf("generate node net io metrics failed, err %v", err)
+			return nil
+		}
+		metrics = append(metrics, sample)
+	}
+	klog.V(6).Infof("collect node net io finished, rx %v, tx %v", rxBytes, txBytes)
+	return metrics
+}
+
+func (c *netIOCollector) collectPodNetIO(meta *statesinformer.PodMeta) []metriccache.MetricSample {
+	pod := meta.Pod
+	uid := string(pod.UID)
+	if filtered, msg := c.FilterPod(meta); filtered {
+		klog.V(5).Infof("skip collect pod %s/%s, reason: %s", pod.Namespace, pod.Name, msg)
+		return nil
+	}
+	// the traffic of host network pods cannot be distinguished from the node
+	if pod.Spec.HostNetwork {
+		return nil
+	}
+
+	collectTime := timeNow()
+	pid, err := c.getPodNetNSPID(meta)
+	if err != nil {
+		if pod.Status.Phase == corev1.PodRunning {
+			klog.V(4).Infof("collect pod %s/%s net io failed, err: %v", pod.Namespace, pod.Name, err)
+		}
+		return nil
+	}
+	stats, err := system.GetNetDevStats(system.GetProcPIDNetDevPath(pid))
+	if err != nil {
+		klog.V(4).Infof("collect pod %s/%s net io failed, err: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+	currentStat := sumNetDevStats(stats, func(ifName string) bool {
+		return ifName == system.LoopbackInterfaceName
+	}, collectTime)
+
+	lastStatValue, ok := c.lastPodNetIOStat.Get(uid)
+	c.lastPodNetIOStat.Set(uid, currentStat, gocache.DefaultExpiration)
+	if !ok {
+		klog.V(6).Infof("collect pod %s/%s, uid %s net io first point", pod.Namespace, pod.Name, uid)
+		return nil
+	}
+	rxBytes, txBytes, err := calcNetIORate(currentStat, lastStatValue.(*netIOStat))
+	if err != nil {
+		klog.V(4).Infof("failed to calculate pod %s/%s net io, err: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+
+	metrics := make([]metriccache.MetricSample, 0, 2)
+	for _, t := range []struct {
+		resource metriccache.MetricResource
+		value    float64
+	}{
+		{resource: metriccache.PodNetRxBytesMetric, value: rxBytes},
+		{resource: metriccache.PodNetTxBytesMetric, value: txBytes},
+	} {
+		sample, err := t.resource.GenerateSample(metriccache.MetricPropertiesFunc.Pod(uid), collectTime, t.value)
+		if err != nil {
+			klog.Warningf("generate pod %v net io metrics failed, err %v", util.GetPodKey(pod), err)
+			return nil
+		}
+		metrics = append(metrics, sample)
+	}
+	klog.V(6).Infof("collect pod %s/%s, uid %s net io finished, rx %v, tx %v",
+		pod.Namespace, pod.Name, uid, rxBytes, txBytes)
+	return metrics
+}
+
+// getPodNetNSPID returns a pid of the pod's containers, which shares the network namespace of the pod.
+func (c *netIOCollector) getPodNetNSPID(meta *statesinformer.PodMeta) (int32, error) {
+	pod := meta.Pod
+	for i := range pod.Status.ContainerStatuses {
+		containerStat := &pod.Status.ContainerStatuses[i]
+		if containerStat.State.Running == nil || len(containerStat.ContainerID) == 0 {
+			continue
+		}
+		containerCgroupDir, err := koordletutil.GetContainerCgroupParentDir(meta.CgroupDir, containerStat)
+		if err != nil {
+			klog.V(5).Infof("failed to get cgroup dir of container %s/%s/%s, err: %v",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		pids, err := c.cgroupReader.ReadCPUTasks(containerCgroupDir)
+		if err != nil || len(pids) <= 0 {
+			klog.V(5).Infof("failed to get tasks of container %s/%s/%s, err: %v",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		return pids[0], nil
+	}
+	return -1, fmt.Errorf("no running process found")
+}
+
+func sumNetDevStats(stats map[string]*system.NetDevStat, skipFn func(ifName string) bool, collectTime time.Time) *netIOStat {
+	sum := &netIOStat{Timestamp: collectTime}
+	for ifName, stat := range stats {
+		if skipFn(ifName) {
+			continue
+		}
+		sum.RxBytes += stat.RxBytes
+		sum.TxBytes += stat.TxBytes
+	}
+	return sum
+}
+
+// calcNetIORate returns the receive and transmit throughput in bytes per second between two points
+func calcNetIORate(curPoint, prePoint *netIOStat) (float64, float64, error) {
+	duration := curPoint.Timestamp.Sub(prePoint.Timestamp).Seconds()
+	if duration <= 0 {
+		return 0, 0, fmt.Errorf("invalid collect duration %v", duration)
+	}
+	// counters may be reset if the interfaces are recreated
+	if curPoint.RxBytes < prePoint.RxBytes || curPoint.TxBytes < prePoint.TxBytes {
+		return 0, 0, fmt.Errorf("net io counters decreased, current %+v, previous %+v", curPoint, prePoint)
+	}
+	rxBytes := float64(curPoint.RxBytes-prePoint.RxBytes) / duration
+	txBytes := float64(curPoint.TxBytes-prePoint.TxBytes) / duration
+	return rxBytes, txBytes, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_calcNetIORate(t *testing.T) {
+	testNow := time.Now()
+	tests := []struct {
+		name     string
+		curPoint *netIOStat
+		prePoint *netIOStat
+		wantRx   float64
+		wantTx   float64
+		wantErr  bool
+	}{
+		{
+			name:     "calculate rate",
+			curPoint: &netIOStat{RxBytes: 3000, TxBytes: 5000, Timestamp: testNow},
+			prePoint: &netIOStat{RxBytes: 1000, TxBytes: 1000, Timestamp: testNow.Add(-2 * time.Second)},
+			wantRx:   1000,
+			wantTx:   2000,
+		},
+		{
+			name:     "invalid duration",
+			curPoint: &netIOStat{RxBytes: 3000, TxBytes: 5000, Timestamp: testNow},
+			prePoint: &netIOStat{RxBytes: 1000, TxBytes: 1000, Timestamp: testNow},
+			wantErr:  true,
+		},
+		{
+			name:     "counters reset",
+			curPoint: &netIOStat{RxBytes: 100, TxBytes: 5000, Timestamp: testNow},
+			prePoint: &netIOStat{RxBytes: 1000, TxBytes: 1000, Timestamp: testNow.Add(-time.Second)},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRx, gotTx, gotErr := calcNetIORate(tt.curPoint, tt.prePoint)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.wantRx, gotRx)
+			assert.Equal(t, tt.wantTx, gotTx)
+		})
+	}
+}
+
+func Test_netIOCollector_collectNetIO(t *testing.T) {
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "test",
+			UID:       "test-pod-uid",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: "containerd://testContainerUID",
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+	testHostNetworkPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-host-network-pod",
+			Namespace: "test",
+			UID:       "test-host-network-pod-uid",
+		},
+		Spec: corev1.PodSpec{
+			HostNetwork: true,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+	testPodMetaDir := "kubepods.slice/kubepods-podtest_pod_uid.slice"
+	testContainerDir := "kubepods.slice/kubepods-podtest_pod_uid.slice/cri-containerd-testContainerUID.scope"
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteProcSubFileContents(system.ProcNetDevName, `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 2000000   11307    0    0    0     0          0         0  2000000   11307    0    0    0     0       0          0
+  eth0: 3000       2751    0    0    0     0          0         0  5000       4324    0    0    0     0       0          0
+ veth0: 9000       2751    0    0    0     0          0         0  9000       4324    0    0    0     0       0          0
+`)
+	helper.MkDirAll(system.SysVirtualNetSubDir + "/veth0")
+	helper.WriteCgroupFileContents(testContainerDir, system.CPUTasks, "1000\n1001\n")
+	helper.WriteProcSubFileContents("1000/"+system.ProcNetDevName, `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 2000000   11307    0    0    0     0          0         0  2000000   11307    0    0    0     0       0          0
+  eth0: 1500       2751    0    0    0     0          0         0  1200       4324    0    0    0     0       0          0
+`)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              helper.TempDir,
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		metricCache.Close()
+	}()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		{Pod: testPod, CgroupDir: testPodMetaDir},
+		{Pod: testHostNetworkPod},
+	}).Times(1)
+
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+	c := New(&framework.Options{
+		Config: &framework.Config{
+			CollectResUsedInterval: time.Second,
+		},
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	}).(*netIOCollector)
+	c.lastNodeNetIOStat = &netIOStat{RxBytes: 1000, TxBytes: 1000, Timestamp: testNow.Add(-time.Second)}
+	c.lastPodNetIOStat.Set(string(testPod.UID), &netIOStat{RxBytes: 500, TxBytes: 200, Timestamp: testNow.Add(-time.Second)}, gocache.DefaultExpiration)
+
+	c.collectNetIO()
+	assert.True(t, c.Started())
+
+	querier, err := metricCache.Querier(testNow.Add(-time.Second), testNow.Add(time.Second))
+	assert.NoError(t, err)
+	for _, tt := range []struct {
+		resource   metriccache.MetricResource
+		properties map[metriccache.MetricProperty]string
+		want       float64
+	}{
+		{resource: metriccache.NodeNetRxBytesMetric, want: 2000},
+		{resource: metriccache.NodeNetTxBytesMetric, want: 4000},
+		{resource: metriccache.PodNetRxBytesMetric, properties: metriccache.MetricPropertiesFunc.Pod(string(testPod.UID)), want: 1000},
+		{resource: metriccache.PodNetTxBytesMetric, properties: metriccache.MetricPropertiesFunc.Pod(string(testPod.UID)), want: 1000},
+	} {
+		queryMeta, err := tt.resource.BuildQueryMeta(tt.properties)
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		got, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, queryMeta.GetKind())
+	}
+	queryMeta, err := metriccache.PodNetRxBytesMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod(string(testHostNetworkPod.UID)))
+	assert.NoError(t, err)
+	result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, nil, result))
+	assert.Equal(t, 0, result.Count())
+}
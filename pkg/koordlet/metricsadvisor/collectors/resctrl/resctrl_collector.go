@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	CollectorName = "ResctrlCollector"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// resctrlMonStat is the monitoring data of a resctrl group at the collect time
+type resctrlMonStat struct {
+	MonData   map[string]uint64
+	Timestamp time.Time
+}
+
+// resctrlCollector collects the llc occupancy and the memory bandwidth of the resctrl groups, including the groups of
+// the QoS classes and the groups dedicated to pods.
+type resctrlCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	appendableDB    metriccache.Appendable
+
+	// lastMonStats is the last monitoring data of each resctrl group, which is only accessed in the collect loop
+	lastMonStats map[string]*resctrlMonStat
+}
+
+func New(opt *framework.Options) framework.Collector {
+	return &resctrlCollector{
+		collectInterval: opt.Config.ResctrlCollectorInterval,
+		started:         atomic.NewBool(false),
+		appendableDB:    opt.MetricCache,
+		lastMonStats:    map[string]*resctrlMonStat{},
+	}
+}
+
+var _ framework.Collector = &resctrlCollector{}
+
+func (c *resctrlCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.ResctrlCollector) && c.collectInterval > 0
+}
+
+func (c *resctrlCollector) Setup(ctx *framework.Context) {}
+
+func (c *resctrlCollector) Run(stopCh <-chan struct{}) {
+	go wait.Until(c.collectResctrlMonData, c.collectInterval, stopCh)
+}
+
+func (c *resctrlCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *resctrlCollector) collectResctrlMonData() {
+	klog.V(6).Info("start collectResctrlMonData")
+	groups, err := system.ListResctrlGroups()
+	if err != nil {
+		klog.V(4).Infof("failed to list resctrl groups, err: %v", err)
+		return
+	}
+
+	var metrics []metriccache.MetricSample
+	currentGroups := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		currentGroups[group] = struct{}{}
+		metrics = append(metrics, c.collectGroupMonData(group)...)
+	}
+	// forget the removed groups
+	for group := range c.lastMonStats {
+		if _, ok := currentGroups[group]; !ok {
+			delete(c.lastMonStats, group)
+		}
+	}
+
+	appender := c.appendableDB.Appender()
+	if err := appender.Append(metrics); err != nil {
+		klog.Warningf("append resctrl metrics failed, reason: %v", err)
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("commit resctrl metrics failed, reason: %v", err)
+		return
+	}
+	c.started.Store(true)
+	klog.V(5).Infof("collectResctrlMonData finished, group num %d, metric num %d", len(groups), len(metrics))
+}
+
+func (c *resctrlCollector) collectGroupMonData(group string) []metriccache.MetricSample {
+	collectTime := timeNow()
+	monData, err := system.ReadResctrlMonData(group)
+	if err != nil {
+		klog.V(4).Infof("failed to read mon data of resctrl group %s, err: %v", group, err)
+		return nil
+	}
+	currentStat := &resctrlMonStat{MonData: monData, Timestamp: collectTime}
+	lastStat := c.lastMonStats[group]
+	c.lastMonStats[group] = currentStat
+
+	properties := metriccache.MetricPropertiesFunc.ResctrlGroup(group)
+	var metrics []metriccache.MetricSample
+	if llcOccupancy, ok := monData[system.ResctrlLLCOccupancyName]; ok {
+		sample, err := metriccache.ResctrlLLCOccupancyMetric.GenerateSample(properties, collectTime, float64(llcOccupancy))
+		if err != nil {
+			klog.Warningf("generate resctrl group %s llc occupancy metrics failed, err %v", group, err)
+		} else {
+			metrics = append(metrics, sample)
+		}
+	}
+
+	if lastStat == nil {
+		klog.V(6).Infof("collect resctrl group %s memory bandwidth first point", group)
+		return metrics
+	}
+	for _, t := range []struct {
+		resource metriccache.MetricResource
+		name     string
+	}{
+		{resource: metriccache.ResctrlMBMTotalBPSMetric, name: system.ResctrlMBMTotalBytesName},
+		{resource: metriccache.ResctrlMBMLocalBPSMetric, name: system.ResctrlMBMLocalBytesName},
+	} {
+		bps, err := calcMonDataRate(currentStat, lastStat, t.name)
+		if err != nil {
+			klog.V(5).Infof("failed to calculate %s of resctrl group %s, err: %v", t.name, group, err)
+			continue
+		}
+		sample, err := t.resource.GenerateSample(properties, collectTime, bps)
+		if err != nil {
+			klog.Warningf("generate resctrl group %s %s metrics failed, err %v", group, t.name, err)
+			continue
+		}
+		metrics = append(metrics, sample)
+	}
+	klog.V(6).Infof("collect resctrl group %s finished, mon data %v", group, monData)
+	return metrics
+}
+
+// calcMonDataRate returns the increased bytes per second of the monitoring counter between two points
+func calcMonDataRate(curPoint, prePoint *resctrlMonStat, name string) (float64, error) {
+	cur, curOK := curPoint.MonData[name]
+	pre, preOK := prePoint.MonData[name]
+	if !curOK || !preOK {
+		return 0, fmt.Errorf("counter is unavailable")
+	}
+	duration := curPoint.Timestamp.Sub(prePoint.Timestamp).Seconds()
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid collect duration %v", duration)
+	}
+	// counters may be reset if the group is recreated
+	if cur < pre {
+		return 0, fmt.Errorf("counter decreased, current %v, previous %v", cur, pre)
+	}
+	return float64(cur-pre) / duration, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_calcMonDataRate(t *testing.T) {
+	testNow := time.Now()
+	tests := []struct {
+		name     string
+		curPoint *resctrlMonStat
+		prePoint *resctrlMonStat
+		want     float64
+		wantErr  bool
+	}{
+		{
+			name:     "calculate rate",
+			curPoint: &resctrlMonStat{MonData: map[string]uint64{system.ResctrlMBMTotalBytesName: 5000}, Timestamp: testNow},
+			prePoint: &resctrlMonStat{MonData: map[string]uint64{system.ResctrlMBMTotalBytesName: 1000}, Timestamp: testNow.Add(-2 * time.Second)},
+			want:     2000,
+		},
+		{
+			name:     "counter unavailable",
+			curPoint: &resctrlMonStat{MonData: map[string]uint64{}, Timestamp: testNow},
+			prePoint: &resctrlMonStat{MonData: map[string]uint64{system.ResctrlMBMTotalBytesName: 1000}, Timestamp: testNow.Add(-time.Second)},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid duration",
+			curPoint: &resctrlMonStat{MonData: map[string]uint64{system.ResctrlMBMTotalBytesName: 5000}, Timestamp: testNow},
+			prePoint: &resctrlMonStat{MonData: map[string]uint64{system.ResctrlMBMTotalBytesName: 1000}, Timestamp: testNow},
+			wantErr:  true,
+		},
+		{
+			name:     "counter reset",
+			curPoint: &resctrlMonStat{MonData: map[string]uint64{system.ResctrlMBMTotalBytesName: 100}, Timestamp: testNow},
+			prePoint: &resctrlMonStat{MonData: map[string]uint64{system.ResctrlMBMTotalBytesName: 1000}, Timestamp: testNow.Add(-time.Second)},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := calcMonDataRate(tt.curPoint, tt.prePoint, system.ResctrlMBMTotalBytesName)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_resctrlCollector_collectResctrlMonData(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	writeMonData := func(group string, llcOccupancy, mbmTotal, mbmLocal string) {
+		monDataDir := filepath.Join(system.GetResctrlGroupRootDirPath(group), system.ResctrlMonDataDir, "mon_L3_00")
+		helper.WriteFileContents(filepath.Join(monDataDir, system.ResctrlLLCOccupancyName), llcOccupancy)
+		helper.WriteFileContents(filepath.Join(monDataDir, system.ResctrlMBMTotalBytesName), mbmTotal)
+		helper.WriteFileContents(filepath.Join(monDataDir, system.ResctrlMBMLocalBytesName), mbmLocal)
+	}
+	helper.WriteFileContents(system.GetResctrlTasksFilePath("LS"), "")
+	helper.WriteFileContents(system.GetResctrlTasksFilePath("koord-pod-p0"), "")
+	writeMonData("LS", "1048576\n", "10000\n", "5000\n")
+	writeMonData("koord-pod-p0", "524288\n", "4000\n", "2000\n")
+
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              helper.TempDir,
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		metricCache.Close()
+	}()
+
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+	c := New(&framework.Options{
+		Config: &framework.Config{
+			ResctrlCollectorInterval: time.Second,
+		},
+		MetricCache: metricCache,
+	}).(*resctrlCollector)
+	c.lastMonStats["LS"] = &resctrlMonStat{
+		MonData: map[string]uint64{
+			system.ResctrlMBMTotalBytesName: 6000,
+			system.ResctrlMBMLocalBytesName: 3000,
+		},
+		Timestamp: testNow.Add(-2 * time.Second),
+	}
+	c.lastMonStats["removed-group"] = &resctrlMonStat{Timestamp: testNow.Add(-2 * time.Second)}
+
+	c.collectResctrlMonData()
+	assert.True(t, c.Started())
+	assert.Len(t, c.lastMonStats, 2)
+	assert.Nil(t, c.lastMonStats["removed-group"])
+
+	querier, err := metricCache.Querier(testNow.Add(-time.Second), testNow.Add(time.Second))
+	assert.NoError(t, err)
+	for _, tt := range []struct {
+		resource  metriccache.MetricResource
+		group     string
+		want      float64
+		wantCount int
+	}{
+		{resource: metriccache.ResctrlLLCOccupancyMetric, group: "LS", want: 1048576, wantCount: 1},
+		{resource: metriccache.ResctrlMBMTotalBPSMetric, group: "LS", want: 2000, wantCount: 1},
+		{resource: metriccache.ResctrlMBMLocalBPSMetric, group: "LS", want: 1000, wantCount: 1},
+		{resource: metriccache.ResctrlLLCOccupancyMetric, group: "koord-pod-p0", want: 524288, wantCount: 1},
+		// the first point of the memory bandwidth is skipped
+		{resource: metriccache.ResctrlMBMTotalBPSMetric, group: "koord-pod-p0", wantCount: 0},
+	} {
+		queryMeta, err := tt.resource.BuildQueryMeta(metriccache.MetricPropertiesFunc.ResctrlGroup(tt.group))
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		assert.Equal(t, tt.wantCount, result.Count(), queryMeta.GetKind())
+		if tt.wantCount <= 0 {
+			continue
+		}
+		got, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, queryMeta.GetKind())
+	}
+}
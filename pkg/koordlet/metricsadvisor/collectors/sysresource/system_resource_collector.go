@@ -106,10 +106,13 @@ func (s *systemResourceCollector) collectSysResUsed() {
 		return
 	}
 
+	// get host application resource usage, which is not collected if no host application declared
+	hostAppCPUUsage, hostAppMemoryUsage := s.getHostAppResourceUsage()
+
 	// calculate system resource usage
 	collectTime := timeNow()
-	systemCPUUsage := util.MaxFloat64(nodeCPU.Value-podsCPUUsage, 0)
-	systemMemoryUsage := util.MaxFloat64(nodeMemory.Value-podsMemoryUsage, 0)
+	systemCPUUsage := util.MaxFloat64(nodeCPU.Value-podsCPUUsage-hostAppCPUUsage, 0)
+	systemMemoryUsage := util.MaxFloat64(nodeMemory.Value-podsMemoryUsage-hostAppMemoryUsage, 0)
 	systemCPUMetric, err := metriccache.SystemCPUUsageMetric.GenerateSample(nil, collectTime, systemCPUUsage)
 	if err != nil {
 		klog.Warningf("generate system cpu metric failed, err %v", err)
@@ -161,3 +164,17 @@ func (s *systemResourceCollector) getAllPodsResourceUsage() (cpuCore float64, me
 	}
 	return
 }
+
+func (s *systemResourceCollector) getHostAppResourceUsage() (cpuCore float64, memory float64) {
+	validTime := timeNow().Add(-s.outdatedInterval)
+	hostAppCPU, hostAppMemory := s.sharedState.GetHostAppUsage()
+	if hostAppCPU == nil || hostAppMemory == nil {
+		return 0, 0
+	}
+	if hostAppCPU.Timestamp.Before(validTime) || hostAppMemory.Timestamp.Before(validTime) {
+		klog.V(4).Infof("host application resource metric is timeout, valid time %v, metric time is %v and %v",
+			validTime.String(), hostAppCPU.Timestamp.String(), hostAppMemory.Timestamp.String())
+		return 0, 0
+	}
+	return hostAppCPU.Value, hostAppMemory.Value
+}
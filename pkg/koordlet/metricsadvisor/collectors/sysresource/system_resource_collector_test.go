@@ -41,8 +41,9 @@ func Test_systemResourceCollector_collectSysResUsed(t *testing.T) {
 		memory float64
 	}
 	type fields struct {
-		nodeUsage *usageField
-		podUsage  map[string]usageField
+		nodeUsage    *usageField
+		podUsage     map[string]usageField
+		hostAppUsage *usageField
 	}
 	type want struct {
 		systemCPU    *float64
@@ -152,6 +153,58 @@ func Test_systemResourceCollector_collectSysResUsed(t *testing.T) {
 				systemMemory: pointer.Float64(1024),
 			},
 		},
+		{
+			name: "exclude host application usage",
+			fields: fields{
+				nodeUsage: &usageField{
+					ts:     timeNow(),
+					cpu:    2,
+					memory: 2048,
+				},
+				podUsage: map[string]usageField{
+					"test-collector": {
+						ts:     timeNow(),
+						cpu:    0.5,
+						memory: 512,
+					},
+				},
+				hostAppUsage: &usageField{
+					ts:     timeNow(),
+					cpu:    1,
+					memory: 1024,
+				},
+			},
+			want: want{
+				systemCPU:    pointer.Float64(0.5),
+				systemMemory: pointer.Float64(512),
+			},
+		},
+		{
+			name: "ignore outdated host application usage",
+			fields: fields{
+				nodeUsage: &usageField{
+					ts:     timeNow(),
+					cpu:    2,
+					memory: 2048,
+				},
+				podUsage: map[string]usageField{
+					"test-collector": {
+						ts:     timeNow(),
+						cpu:    0.5,
+						memory: 512,
+					},
+				},
+				hostAppUsage: &usageField{
+					ts:     timeNow().Add(-config.CollectSysMetricOutdatedInterval * 2),
+					cpu:    1,
+					memory: 1024,
+				},
+			},
+			want: want{
+				systemCPU:    pointer.Float64(1.5),
+				systemMemory: pointer.Float64(1536),
+			},
+		},
 	}
 	for _, tt := range tests {
 		helper := system.NewFileTestUtil(t)
@@ -183,6 +236,12 @@ func Test_systemResourceCollector_collectSysResUsed(t *testing.T) {
 					metriccache.Point{Timestamp: pod.ts, Value: pod.memory},
 				)
 			}
+			if tt.fields.hostAppUsage != nil {
+				s.sharedState.UpdateHostAppUsage(
+					metriccache.Point{Timestamp: tt.fields.hostAppUsage.ts, Value: tt.fields.hostAppUsage.cpu},
+					metriccache.Point{Timestamp: tt.fields.hostAppUsage.ts, Value: tt.fields.hostAppUsage.memory},
+				)
+			}
 			s.collectSysResUsed()
 
 			querier, err := metricCache.Querier(timeNow().Add(-s.outdatedInterval), timeNow())
@@ -18,9 +18,13 @@ package prediction
 
 import (
 	"encoding/json"
+	"fmt"
+	"hash/crc32"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -30,23 +34,113 @@ import (
 
 const (
 	TmpFileSuffix = ".tmp"
+
+	// CheckpointVersion is the version of the checkpoint file format. The files without the version are written by
+	// the legacy checkpointer which encodes the ModelCheckpoint directly, and they are migrated when restoring.
+	CheckpointVersion = 2
+	// SnapshotFileName is the file bundling all checkpoints in the compaction mode.
+	SnapshotFileName = "__snapshot__"
 )
 
 // ModelCheckpoint represents a checkpoint for a model.
 type ModelCheckpoint struct {
-	UID         UIDType
+	UID UIDType
+	// Model is the name of the predict model, and empty means the histogram model
+	Model       string `json:",omitempty"`
 	CPU         *histogram.HistogramCheckpoint
 	Memory      *histogram.HistogramCheckpoint
 	LastUpdated metav1.Time
+	Samples     int64 `json:",omitempty"`
+	// CPUData and MemoryData are the checkpoints of the models other than the histogram model
+	CPUData    json.RawMessage `json:",omitempty"`
+	MemoryData json.RawMessage `json:",omitempty"`
 
 	Error error `json:"-,omitempty"`
 }
 
+// checkpointFile is the versioned format of the checkpoint files. The Data is a ModelCheckpoint, or a list of
+// ModelCheckpoint for the snapshot, and the Checksum is the CRC32 of the Data to detect the corrupted files.
+type checkpointFile struct {
+	Version  int             `json:"version"`
+	Checksum uint32          `json:"checksum"`
+	Data     json.RawMessage `json:"data"`
+}
+
+func encodeCheckpointFile(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&checkpointFile{
+		Version:  CheckpointVersion,
+		Checksum: crc32.ChecksumIEEE(data),
+		Data:     data,
+	})
+}
+
+// decodeCheckpointFile decodes the content into v, and it returns whether the content is in the legacy format.
+func decodeCheckpointFile(content []byte, v interface{}) (bool, error) {
+	file := &checkpointFile{}
+	if err := json.Unmarshal(content, file); err != nil {
+		return false, err
+	}
+	if file.Version <= 0 { // legacy format
+		return true, json.Unmarshal(content, v)
+	}
+	if file.Version > CheckpointVersion {
+		return false, fmt.Errorf("unsupported checkpoint version %d", file.Version)
+	}
+	if checksum := crc32.ChecksumIEEE(file.Data); checksum != file.Checksum {
+		return false, fmt.Errorf("checksum mismatched, expected %d, actual %d", file.Checksum, checksum)
+	}
+	return false, json.Unmarshal(file.Data, v)
+}
+
+// writeFileAtomic writes the content to a temporary file and renames it to the filename, so the file is either the
+// old one or the complete new one after a crash.
+func writeFileAtomic(filename string, content []byte) error {
+	tmpFilename := filename + TmpFileSuffix
+	file, err := os.Create(tmpFilename)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(content); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpFilename)
+		return err
+	}
+	if err = os.Rename(tmpFilename, filename); err != nil {
+		return err
+	}
+	// sync the directory to persist the rename
+	dir, err := os.Open(filepath.Dir(filename))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
 // Checkpointer is an interface for saving and restoring model checkpoints.
 type Checkpointer interface {
 	Save(checkpoint ModelCheckpoint) error
 	Remove(UID UIDType) error
 	Restore() ([]*ModelCheckpoint, error)
+	// Flush persists the changes buffered by Save and Remove.
+	Flush() error
+}
+
+// NewCheckpointer creates the checkpointer according to the config.
+func NewCheckpointer(cfg *Config) Checkpointer {
+	if cfg.CheckpointCompaction {
+		return NewSnapshotCheckpointer(cfg.CheckpointFilepath)
+	}
+	return NewFileCheckpointer(cfg.CheckpointFilepath)
 }
 
 // NewFileCheckpointer creates a new file-based checkpointer with the specified directory.
@@ -61,20 +155,11 @@ type fileCheckpointer struct {
 
 // Save saves the given model as a checkpoint with the specified UID.
 func (f *fileCheckpointer) Save(checkpoint ModelCheckpoint) error {
-	filename := filepath.Join(f.path, string(checkpoint.UID))
-	tmpFilename := filename + TmpFileSuffix
-	file, err := os.Create(tmpFilename)
+	content, err := encodeCheckpointFile(&checkpoint)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	if err := encoder.Encode(checkpoint); err != nil {
-		return err
-	}
-
-	return os.Rename(tmpFilename, filename)
+	return writeFileAtomic(filepath.Join(f.path, string(checkpoint.UID)), content)
 }
 
 // Remove removes the given model the specified UID.
@@ -84,13 +169,81 @@ func (f *fileCheckpointer) Remove(UID UIDType) error {
 }
 
 // Restore returns a slice of ModelCheckpoint instances by scanning and decoding checkpoint files from the specified path.
+// The snapshot left by the compaction mode is restored first, and the checkpoint files are applied on top of it. The
+// snapshot and the legacy checkpoint files are rewritten into the checkpoint files in the current format.
 func (f *fileCheckpointer) Restore() ([]*ModelCheckpoint, error) {
+	checkpoints, err := f.restoreSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	fileCheckpoints, err := f.restoreFiles(true)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*ModelCheckpoint, 0, len(checkpoints)+len(fileCheckpoints))
+	fileUIDs := map[UIDType]bool{}
+	for _, checkpoint := range fileCheckpoints {
+		if checkpoint.Error != nil {
+			// the corrupted file is overwritten by the snapshot if possible
+			if _, ok := checkpoints[checkpoint.UID]; !ok {
+				results = append(results, checkpoint)
+			}
+			continue
+		}
+		checkpoints[checkpoint.UID] = checkpoint
+		fileUIDs[checkpoint.UID] = true
+	}
+	migrated := true
+	for uid, checkpoint := range checkpoints {
+		if !fileUIDs[uid] {
+			if err := f.Save(*checkpoint); err != nil {
+				klog.Errorf("failed to migrate checkpoint %s from snapshot, err: %v", uid, err)
+				migrated = false
+			}
+		}
+		results = append(results, checkpoint)
+	}
+	// the snapshot is removed after migrated, so it cannot override the newer checkpoint files on the next restore
+	if migrated {
+		if err := os.Remove(filepath.Join(f.path, SnapshotFileName)); err != nil && !os.IsNotExist(err) {
+			klog.Errorf("failed to remove checkpoint snapshot after migration, err: %v", err)
+		}
+	}
+	return results, nil
+}
+
+// Flush does nothing since the checkpoints are saved immediately.
+func (f *fileCheckpointer) Flush() error {
+	return nil
+}
+
+// restoreSnapshot returns the checkpoints in the snapshot file. A corrupted snapshot is discarded.
+func (f *fileCheckpointer) restoreSnapshot() (map[UIDType]*ModelCheckpoint, error) {
+	checkpoints := map[UIDType]*ModelCheckpoint{}
+	snapshotPath := filepath.Join(f.path, SnapshotFileName)
+	content, err := os.ReadFile(snapshotPath)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var snapshot []*ModelCheckpoint
+	if _, err = decodeCheckpointFile(content, &snapshot); err != nil {
+		klog.Errorf("failed to decode checkpoint snapshot %s, discard it, err: %v", snapshotPath, err)
+	}
+	for _, checkpoint := range snapshot {
+		checkpoints[checkpoint.UID] = checkpoint
+	}
+	return checkpoints, nil
+}
+
+func (f *fileCheckpointer) restoreFiles(migrate bool) ([]*ModelCheckpoint, error) {
 	models := make([]*ModelCheckpoint, 0, 32)
 	err := filepath.Walk(f.path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() {
+		if info.IsDir() || filepath.Base(path) == SnapshotFileName {
 			return nil
 		}
 		if strings.HasSuffix(path, TmpFileSuffix) {
@@ -99,22 +252,136 @@ func (f *fileCheckpointer) Restore() ([]*ModelCheckpoint, error) {
 			return nil
 		}
 
-		file, err := os.Open(path)
+		content, err := os.ReadFile(path)
 		if err != nil {
 			klog.InfoS("open file failed, skip it", path)
 			return nil
 		}
-		defer file.Close()
 
 		checkpoint := &ModelCheckpoint{}
-		decoder := json.NewDecoder(file)
-		if err := decoder.Decode(checkpoint); err != nil {
+		legacy, err := decodeCheckpointFile(content, checkpoint)
+		if err != nil {
+			klog.Errorf("failed to decode checkpoint %s, err: %v", path, err)
 			checkpoint.Error = err
 		}
 		// reset UID to the file name
 		checkpoint.UID = UIDType(filepath.Base(path))
+		if legacy && checkpoint.Error == nil && migrate {
+			if err := f.Save(*checkpoint); err != nil {
+				klog.Errorf("failed to migrate legacy checkpoint %s, err: %v", path, err)
+			} else {
+				klog.InfoS("migrate legacy checkpoint", "uid", checkpoint.UID)
+			}
+		}
 		models = append(models, checkpoint)
 		return nil
 	})
 	return models, err
 }
+
+// NewSnapshotCheckpointer creates a checkpointer which bundles all checkpoints into a single snapshot file.
+func NewSnapshotCheckpointer(path string) *snapshotCheckpointer {
+	return &snapshotCheckpointer{
+		fileCheckpointer: fileCheckpointer{path: path},
+		checkpoints:      map[UIDType]*ModelCheckpoint{},
+		fileUIDs:         map[UIDType]bool{},
+	}
+}
+
+// snapshotCheckpointer keeps the checkpoints in memory and writes them into the snapshot file on Flush, which bounds
+// the number of files and the disk writes. The checkpoint files written in the non-compaction mode are merged into the
+// snapshot on Restore, and removed after the snapshot is flushed.
+type snapshotCheckpointer struct {
+	fileCheckpointer
+	lock        sync.Mutex
+	checkpoints map[UIDType]*ModelCheckpoint
+	// fileUIDs are the checkpoint files to remove after the snapshot is flushed
+	fileUIDs map[UIDType]bool
+	dirty    bool
+}
+
+func (s *snapshotCheckpointer) Save(checkpoint ModelCheckpoint) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.checkpoints[checkpoint.UID] = &checkpoint
+	s.dirty = true
+	return nil
+}
+
+func (s *snapshotCheckpointer) Remove(UID UIDType) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.checkpoints[UID]; ok {
+		delete(s.checkpoints, UID)
+		s.dirty = true
+	}
+	return nil
+}
+
+// Restore returns the checkpoints in the snapshot and the checkpoint files, and the newer one is kept if a UID
+// exists in both. A corrupted snapshot is discarded, and it is overwritten on the next Flush.
+func (s *snapshotCheckpointer) Restore() ([]*ModelCheckpoint, error) {
+	checkpoints, err := s.restoreSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	fileCheckpoints, err := s.restoreFiles(false)
+	if err != nil {
+		return nil, err
+	}
+	var results []*ModelCheckpoint
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, checkpoint := range fileCheckpoints {
+		s.fileUIDs[checkpoint.UID] = true
+		s.dirty = true
+		if checkpoint.Error != nil {
+			results = append(results, checkpoint)
+			continue
+		}
+		if old, ok := checkpoints[checkpoint.UID]; !ok || old.LastUpdated.Before(&checkpoint.LastUpdated) {
+			checkpoints[checkpoint.UID] = checkpoint
+		}
+	}
+	for uid, checkpoint := range checkpoints {
+		s.checkpoints[uid] = checkpoint
+		results = append(results, checkpoint)
+	}
+	return results, nil
+}
+
+// Flush writes the checkpoints into the snapshot file if they are changed.
+func (s *snapshotCheckpointer) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.dirty {
+		return nil
+	}
+	snapshot := make([]*ModelCheckpoint, 0, len(s.checkpoints))
+	for _, checkpoint := range s.checkpoints {
+		snapshot = append(snapshot, checkpoint)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].UID < snapshot[j].UID
+	})
+	content, err := encodeCheckpointFile(snapshot)
+	if err != nil {
+		return err
+	}
+	if err = writeFileAtomic(s.snapshotPath(), content); err != nil {
+		return err
+	}
+	for uid := range s.fileUIDs {
+		if err := s.fileCheckpointer.Remove(uid); err != nil && !os.IsNotExist(err) {
+			klog.Errorf("failed to remove checkpoint file %v after compaction, err: %v", uid, err)
+			continue
+		}
+		delete(s.fileUIDs, uid)
+	}
+	s.dirty = false
+	return nil
+}
+
+func (s *snapshotCheckpointer) snapshotPath() string {
+	return filepath.Join(s.path, SnapshotFileName)
+}
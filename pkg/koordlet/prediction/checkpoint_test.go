@@ -17,9 +17,13 @@ limitations under the License.
 package prediction
 
 import (
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/koordinator-sh/koordinator/pkg/util/histogram"
@@ -129,3 +133,139 @@ func TestRestoreError(t *testing.T) {
 		t.Errorf("Expected nil checkpoints, got %+v", checkpoints)
 	}
 }
+
+func TestRestoreLegacyAndCorruptedCheckpoints(t *testing.T) {
+	tempDir := t.TempDir()
+	legacy := ModelCheckpoint{
+		UID:         "legacy",
+		CPU:         &histogram.HistogramCheckpoint{TotalWeight: 2},
+		Memory:      &histogram.HistogramCheckpoint{TotalWeight: 3},
+		LastUpdated: metav1.Now(),
+	}
+	legacyContent, err := json.Marshal(&legacy)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "legacy"), legacyContent, 0644))
+
+	checkpointer := NewFileCheckpointer(tempDir)
+	assert.NoError(t, checkpointer.Save(ModelCheckpoint{UID: "corrupted", LastUpdated: metav1.Now()}))
+	content, err := os.ReadFile(filepath.Join(tempDir, "corrupted"))
+	assert.NoError(t, err)
+	file := &checkpointFile{}
+	assert.NoError(t, json.Unmarshal(content, file))
+	file.Checksum++
+	content, err = json.Marshal(file)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "corrupted"), content, 0644))
+
+	assert.NoError(t, checkpointer.Save(ModelCheckpoint{UID: "truncated", LastUpdated: metav1.Now()}))
+	content, err = os.ReadFile(filepath.Join(tempDir, "truncated"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "truncated"), content[:len(content)/2], 0644))
+
+	futureContent, err := json.Marshal(&checkpointFile{Version: CheckpointVersion + 1})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "future"), futureContent, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "tmp"+TmpFileSuffix), []byte("{"), 0644))
+
+	checkpoints, err := checkpointer.Restore()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		if checkpoint.UID == "legacy" {
+			assert.NoError(t, checkpoint.Error)
+			assert.Equal(t, 2.0, checkpoint.CPU.TotalWeight)
+		} else {
+			assert.Error(t, checkpoint.Error, checkpoint.UID)
+		}
+	}
+	assert.NoFileExists(t, filepath.Join(tempDir, "tmp"+TmpFileSuffix))
+
+	// the legacy checkpoint is migrated to the current version
+	content, err = os.ReadFile(filepath.Join(tempDir, "legacy"))
+	assert.NoError(t, err)
+	file = &checkpointFile{}
+	assert.NoError(t, json.Unmarshal(content, file))
+	assert.Equal(t, CheckpointVersion, file.Version)
+	restored := &ModelCheckpoint{}
+	legacyFormat, err := decodeCheckpointFile(content, restored)
+	assert.NoError(t, err)
+	assert.False(t, legacyFormat)
+	assert.Equal(t, 3.0, restored.Memory.TotalWeight)
+}
+
+func TestSnapshotCheckpointer(t *testing.T) {
+	tempDir := t.TempDir()
+	now := metav1.Now()
+	older := metav1.NewTime(now.Add(-time.Hour))
+
+	// checkpoints saved in the non-compaction mode
+	fileCheckpointer := NewFileCheckpointer(tempDir)
+	assert.NoError(t, fileCheckpointer.Save(ModelCheckpoint{UID: "model1", LastUpdated: older, Samples: 1}))
+	assert.NoError(t, fileCheckpointer.Save(ModelCheckpoint{UID: "model2", LastUpdated: now, Samples: 2}))
+
+	checkpointer := NewSnapshotCheckpointer(tempDir)
+	checkpoints, err := checkpointer.Restore()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(checkpoints))
+	assert.NoError(t, checkpointer.Save(ModelCheckpoint{UID: "model3", LastUpdated: now, Samples: 3}))
+	assert.NoError(t, checkpointer.Remove("model2"))
+	assert.NoError(t, checkpointer.Flush())
+
+	// the checkpoint files are compacted into the snapshot
+	entries, err := os.ReadDir(tempDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, SnapshotFileName, entries[0].Name())
+
+	// the newer checkpoint file overrides the snapshot
+	assert.NoError(t, fileCheckpointer.Save(ModelCheckpoint{UID: "model1", LastUpdated: now, Samples: 10}))
+	checkpointer = NewSnapshotCheckpointer(tempDir)
+	checkpoints, err = checkpointer.Restore()
+	assert.NoError(t, err)
+	samples := map[UIDType]int64{}
+	for _, checkpoint := range checkpoints {
+		samples[checkpoint.UID] = checkpoint.Samples
+	}
+	assert.Equal(t, map[UIDType]int64{"model1": 10, "model3": 3}, samples)
+
+	// the corrupted snapshot is discarded
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, SnapshotFileName), []byte(`{"version":2,"checksum":1,"data":[]}`), 0644))
+	assert.NoError(t, os.Remove(filepath.Join(tempDir, "model1")))
+	checkpoints, err = NewSnapshotCheckpointer(tempDir).Restore()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(checkpoints))
+}
+
+func TestFileCheckpointerRestoreSnapshot(t *testing.T) {
+	tempDir := t.TempDir()
+	now := metav1.Now()
+
+	// checkpoints saved in the compaction mode
+	snapshotCheckpointer := NewSnapshotCheckpointer(tempDir)
+	assert.NoError(t, snapshotCheckpointer.Save(ModelCheckpoint{UID: "model1", LastUpdated: now, Samples: 1}))
+	assert.NoError(t, snapshotCheckpointer.Save(ModelCheckpoint{UID: "model2", LastUpdated: now, Samples: 2}))
+	assert.NoError(t, snapshotCheckpointer.Flush())
+
+	// the checkpoint file is applied on top of the snapshot
+	fileCheckpointer := NewFileCheckpointer(tempDir)
+	assert.NoError(t, fileCheckpointer.Save(ModelCheckpoint{UID: "model2", LastUpdated: now, Samples: 20}))
+	checkpoints, err := fileCheckpointer.Restore()
+	assert.NoError(t, err)
+	samples := map[UIDType]int64{}
+	for _, checkpoint := range checkpoints {
+		samples[checkpoint.UID] = checkpoint.Samples
+	}
+	assert.Equal(t, map[UIDType]int64{"model1": 1, "model2": 20}, samples)
+
+	// the snapshot is migrated into the checkpoint files
+	entries, err := os.ReadDir(tempDir)
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"model1", "model2"}, names)
+	checkpoints, err = fileCheckpointer.Restore()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(checkpoints))
+}
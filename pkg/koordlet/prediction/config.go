@@ -23,6 +23,7 @@ import (
 
 type Config struct {
 	CheckpointFilepath           string
+	CheckpointCompaction         bool
 	ColdStartDuration            time.Duration
 	SafetyMarginPercent          int
 	MemoryHistogramDecayHalfLife time.Duration
@@ -31,6 +32,9 @@ type Config struct {
 	ModelExpirationDuration      time.Duration
 	ModelCheckpointInterval      time.Duration
 	ModelCheckpointMaxPerStep    int
+	PredictModel                 string
+	SeasonalPeriod               time.Duration
+	SeasonalSlotDuration         time.Duration
 }
 
 func NewDefaultConfig() *Config {
@@ -44,11 +48,15 @@ func NewDefaultConfig() *Config {
 		ModelExpirationDuration:      30 * time.Minute,
 		ModelCheckpointInterval:      10 * time.Minute,
 		ModelCheckpointMaxPerStep:    12,
+		PredictModel:                 HistogramModelName,
+		SeasonalPeriod:               24 * time.Hour,
+		SeasonalSlotDuration:         15 * time.Minute,
 	}
 }
 
 func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.CheckpointFilepath, "prediction-checkpoint-filepath", c.CheckpointFilepath, "The filepath is used to store the checkpoints of the prediction system's state.")
+	fs.BoolVar(&c.CheckpointCompaction, "prediction-checkpoint-compaction", c.CheckpointCompaction, "Bundle the checkpoints of all models into a single snapshot file instead of one file per model")
 	fs.DurationVar(&c.ColdStartDuration, "prediction-cold-start-duration", c.ColdStartDuration, "Cold start refers to the period of time after a Pod starts and enters into a stable state")
 	fs.IntVar(&c.SafetyMarginPercent, "prediction-safety-margin-percent", c.SafetyMarginPercent, "The redundancy preserved above peak prediction")
 	fs.DurationVar(&c.MemoryHistogramDecayHalfLife, "prediction-memory-histogram-decay-halflife", c.MemoryHistogramDecayHalfLife, "Half-life, the older the data, the lower the weight")
@@ -57,4 +65,7 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.ModelExpirationDuration, "prediction-model-expiration-duration", c.ModelExpirationDuration, "Expiration of prediction model without updated")
 	fs.DurationVar(&c.ModelCheckpointInterval, "prediction-model-checkpoint-interval", c.ModelCheckpointInterval, "Interval of prediction model take checkpoint")
 	fs.IntVar(&c.ModelCheckpointMaxPerStep, "prediction-model-checkpoint-max-per-step", c.ModelCheckpointMaxPerStep, "The maximum number of prediction models saved at a time")
+	fs.StringVar(&c.PredictModel, "prediction-model", c.PredictModel, "The model to predict the resource usages, e.g. histogram, holtWinters, dayOfWeekQuantile")
+	fs.DurationVar(&c.SeasonalPeriod, "prediction-model-seasonal-period", c.SeasonalPeriod, "The seasonal period of the holtWinters model")
+	fs.DurationVar(&c.SeasonalSlotDuration, "prediction-model-seasonal-slot-duration", c.SeasonalSlotDuration, "The duration of each slot in the seasonal period of the holtWinters model")
 }
@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DayOfWeekQuantileModelName is the model which learns the quantiles of each hour in a week, which fits the
+	// workloads with weekly patterns, e.g. the traffic on weekdays and weekends
+	DayOfWeekQuantileModelName = "dayOfWeekQuantile"
+
+	// hourly slots of a week in UTC
+	dayOfWeekSlots = 7 * 24
+	// the learning rate of the online quantile estimation
+	dayOfWeekLearningRate = 0.05
+	// smoothing factor of the sample scale, which normalizes the learning steps of different resources
+	dayOfWeekScaleFactor = 0.05
+	// the slot uses the quantiles of the whole week before it has collected enough samples
+	dayOfWeekMinSlotSamples = 30
+)
+
+// dayOfWeekQuantiles are the quantiles tracked by the model, and others are interpolated between them
+var dayOfWeekQuantiles = []float64{0.5, 0.6, 0.9, 0.95, 0.98, 0.995}
+
+var _ PredictModel = &dayOfWeekQuantilePredictModel{}
+
+// dayOfWeekQuantilePredictModel estimates the quantiles of each hour in a week with the online quantile regression,
+// i.e. each estimation θ moves by lr * scale * weight * (q - 1{x < θ}) for each sample x.
+type dayOfWeekQuantilePredictModel struct {
+	state dayOfWeekQuantileState
+}
+
+// dayOfWeekQuantileState is the state of the model, which is also the checkpoint data
+type dayOfWeekQuantileState struct {
+	Scale float64 `json:"scale"`
+	// Slots are the estimations of the hourly slots, and Global is the estimation of the whole week
+	Slots  []dayOfWeekQuantileSlot `json:"slots"`
+	Global dayOfWeekQuantileSlot   `json:"global"`
+}
+
+type dayOfWeekQuantileSlot struct {
+	Quantiles []float64 `json:"quantiles"`
+	Samples   int64     `json:"samples"`
+}
+
+func newDayOfWeekQuantilePredictModel(_ *Config, _ v1.ResourceName) PredictModel {
+	m := &dayOfWeekQuantilePredictModel{
+		state: dayOfWeekQuantileState{
+			Slots:  make([]dayOfWeekQuantileSlot, dayOfWeekSlots),
+			Global: dayOfWeekQuantileSlot{Quantiles: make([]float64, len(dayOfWeekQuantiles))},
+		},
+	}
+	for i := range m.state.Slots {
+		m.state.Slots[i].Quantiles = make([]float64, len(dayOfWeekQuantiles))
+	}
+	return m
+}
+
+func (d *dayOfWeekQuantilePredictModel) AddSample(value float64, weight float64, t time.Time) {
+	s := &d.state
+	if s.Global.Samples <= 0 {
+		s.Scale = math.Abs(value)
+	} else {
+		s.Scale = (1-dayOfWeekScaleFactor)*s.Scale + dayOfWeekScaleFactor*math.Abs(value)
+	}
+	step := dayOfWeekLearningRate * s.Scale * weight
+	slot := &s.Slots[dayOfWeekSlotOf(t)]
+	for _, estimation := range []*dayOfWeekQuantileSlot{slot, &s.Global} {
+		// start from the first sample to converge faster
+		if estimation.Samples <= 0 {
+			for i := range estimation.Quantiles {
+				estimation.Quantiles[i] = value
+			}
+		}
+		for i, q := range dayOfWeekQuantiles {
+			if value < estimation.Quantiles[i] {
+				estimation.Quantiles[i] += step * (q - 1)
+			} else {
+				estimation.Quantiles[i] += step * q
+			}
+		}
+		// keep the estimations monotonic
+		sort.Float64s(estimation.Quantiles)
+		estimation.Samples++
+	}
+}
+
+// Percentile returns the larger prediction of the hour at the given time and the next hour.
+func (d *dayOfWeekQuantilePredictModel) Percentile(percentile float64, t time.Time) float64 {
+	if d.state.Global.Samples <= 0 {
+		return 0
+	}
+	return math.Max(d.slotPercentile(dayOfWeekSlotOf(t), percentile),
+		d.slotPercentile(dayOfWeekSlotOf(t.Add(time.Hour)), percentile))
+}
+
+func (d *dayOfWeekQuantilePredictModel) slotPercentile(slot int, percentile float64) float64 {
+	estimation := &d.state.Slots[slot]
+	if estimation.Samples < dayOfWeekMinSlotSamples {
+		estimation = &d.state.Global
+	}
+	qs := estimation.Quantiles
+	if percentile <= dayOfWeekQuantiles[0] {
+		return math.Max(qs[0], 0)
+	}
+	last := len(dayOfWeekQuantiles) - 1
+	if percentile >= dayOfWeekQuantiles[last] {
+		return math.Max(qs[last], 0)
+	}
+	i := sort.SearchFloat64s(dayOfWeekQuantiles, percentile)
+	// interpolate between the tracked quantiles
+	ratio := (percentile - dayOfWeekQuantiles[i-1]) / (dayOfWeekQuantiles[i] - dayOfWeekQuantiles[i-1])
+	return math.Max(qs[i-1]+ratio*(qs[i]-qs[i-1]), 0)
+}
+
+func (d *dayOfWeekQuantilePredictModel) SaveToCheckpoint() (*PredictModelCheckpoint, error) {
+	data, err := json.Marshal(&d.state)
+	if err != nil {
+		return nil, err
+	}
+	return &PredictModelCheckpoint{Data: data}, nil
+}
+
+func (d *dayOfWeekQuantilePredictModel) LoadFromCheckpoint(ckpt *PredictModelCheckpoint) error {
+	if ckpt == nil || len(ckpt.Data) <= 0 {
+		return fmt.Errorf("day-of-week quantile checkpoint is empty")
+	}
+	state := dayOfWeekQuantileState{}
+	if err := json.Unmarshal(ckpt.Data, &state); err != nil {
+		return err
+	}
+	if len(state.Slots) != dayOfWeekSlots {
+		return fmt.Errorf("slots of the checkpoint %d mismatches the model %d", len(state.Slots), dayOfWeekSlots)
+	}
+	for _, estimation := range append(state.Slots, state.Global) {
+		if len(estimation.Quantiles) != len(dayOfWeekQuantiles) {
+			return fmt.Errorf("quantiles of the checkpoint %d mismatches the model %d",
+				len(estimation.Quantiles), len(dayOfWeekQuantiles))
+		}
+	}
+	d.state = state
+	return nil
+}
+
+func dayOfWeekSlotOf(t time.Time) int {
+	t = t.UTC()
+	return int(t.Weekday())*24 + t.Hour()
+}
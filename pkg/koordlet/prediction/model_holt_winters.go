@@ -0,0 +1,221 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// HoltWintersModelName is the additive Holt-Winters model, which fits the seasonal workloads like daily cycles
+	HoltWintersModelName = "holtWinters"
+
+	// smoothing factors of the level, trend and seasonal components
+	holtWintersAlpha = 0.3
+	holtWintersBeta  = 0.05
+	holtWintersGamma = 0.3
+	// smoothing factor of the residual variance, which is updated on each sample
+	holtWintersResidualFactor = 0.05
+	// maxPredictPercentile limits the percentile to estimate with the normal distribution of the residuals
+	maxPredictPercentile = 0.999
+)
+
+var _ PredictModel = &holtWintersPredictModel{}
+
+// holtWintersPredictModel aggregates the samples into the slots, and smooths the slot averages with the additive
+// Holt-Winters method, i.e. forecast = level + trend + seasonal[slot]. The percentiles are estimated by the forecast
+// and the normal distribution of the residuals between the samples and the forecasts.
+// The first season initializes the level with the average and the seasonal components with the deviations of the slots.
+type holtWintersPredictModel struct {
+	slotDuration time.Duration
+	state        holtWintersState
+}
+
+// holtWintersState is the state of the model, which is also the checkpoint data
+type holtWintersState struct {
+	Level       float64   `json:"level"`
+	Trend       float64   `json:"trend"`
+	Seasonal    []float64 `json:"seasonal"`
+	ResidualVar float64   `json:"residualVar"`
+	Initialized bool      `json:"initialized"`
+	// Observed marks the slots collected in the first season, and it is reset after the initialization
+	Observed  []bool `json:"observed,omitempty"`
+	FirstSlot int64  `json:"firstSlot"`
+	InitSlots int    `json:"initSlots"`
+	// the slot of the last smoothing and the slot collecting the samples
+	LastSlot    int64   `json:"lastSlot"`
+	CurrentSlot int64   `json:"currentSlot"`
+	SlotSum     float64 `json:"slotSum"`
+	SlotWeight  float64 `json:"slotWeight"`
+}
+
+// validateHoltWintersConfig checks the seasonal period can be divided into the slots
+func validateHoltWintersConfig(cfg *Config) error {
+	if cfg.SeasonalSlotDuration <= 0 {
+		return fmt.Errorf("invalid seasonal slot duration %v, must be positive", cfg.SeasonalSlotDuration)
+	}
+	if cfg.SeasonalPeriod <= 0 {
+		return fmt.Errorf("invalid seasonal period %v, must be positive", cfg.SeasonalPeriod)
+	}
+	if cfg.SeasonalPeriod < cfg.SeasonalSlotDuration {
+		return fmt.Errorf("seasonal period %v is shorter than the slot duration %v", cfg.SeasonalPeriod, cfg.SeasonalSlotDuration)
+	}
+	return nil
+}
+
+func newHoltWintersPredictModel(cfg *Config, _ v1.ResourceName) PredictModel {
+	slotDuration := cfg.SeasonalSlotDuration
+	seasonLength := int(cfg.SeasonalPeriod / slotDuration)
+	if seasonLength <= 0 {
+		seasonLength = 1
+	}
+	return &holtWintersPredictModel{
+		slotDuration: slotDuration,
+		state: holtWintersState{
+			Seasonal: make([]float64, seasonLength),
+		},
+	}
+}
+
+func (h *holtWintersPredictModel) AddSample(value float64, weight float64, t time.Time) {
+	s := &h.state
+	slot := h.slotOf(t)
+	if !s.Initialized {
+		s.Level = value
+		s.Initialized = true
+		s.Observed = make([]bool, len(s.Seasonal))
+		s.FirstSlot, s.LastSlot, s.CurrentSlot = slot, slot, slot
+	}
+
+	// update the residual variance with the forecast before smoothing
+	residual := value - h.forecast(slot)
+	s.ResidualVar = (1-holtWintersResidualFactor)*s.ResidualVar + holtWintersResidualFactor*residual*residual
+
+	// smooth the average of the last slot when a new slot begins, the out-of-order samples are counted into the
+	// current slot
+	if slot > s.CurrentSlot {
+		if s.SlotWeight > 0 {
+			h.smooth(s.SlotSum/s.SlotWeight, s.CurrentSlot)
+		}
+		s.CurrentSlot, s.SlotSum, s.SlotWeight = slot, 0, 0
+	}
+	s.SlotSum += value * weight
+	s.SlotWeight += weight
+}
+
+func (h *holtWintersPredictModel) smooth(value float64, slot int64) {
+	s := &h.state
+	idx := h.seasonalIndex(slot)
+	if s.Observed != nil {
+		h.initialize(value, slot)
+		return
+	}
+	lastLevel := s.Level
+	s.Level = holtWintersAlpha*(value-s.Seasonal[idx]) + (1-holtWintersAlpha)*(s.Level+s.Trend)
+	s.Trend = holtWintersBeta*(s.Level-lastLevel) + (1-holtWintersBeta)*s.Trend
+	s.Seasonal[idx] = holtWintersGamma*(value-s.Level) + (1-holtWintersGamma)*s.Seasonal[idx]
+	s.LastSlot = slot
+}
+
+func (h *holtWintersPredictModel) initialize(value float64, slot int64) {
+	s := &h.state
+	idx := h.seasonalIndex(slot)
+	s.Seasonal[idx] = value
+	s.Observed[idx] = true
+	s.InitSlots++
+	s.Level += (value - s.Level) / float64(s.InitSlots)
+	s.LastSlot = slot
+	if slot-s.FirstSlot+1 < int64(len(s.Seasonal)) {
+		return
+	}
+	for i := range s.Seasonal {
+		if s.Observed[i] {
+			s.Seasonal[i] -= s.Level
+		} else {
+			s.Seasonal[i] = 0
+		}
+	}
+	s.Observed = nil
+}
+
+// forecast returns the predicted average of the slot, the trend is extrapolated for one season at most
+func (h *holtWintersPredictModel) forecast(slot int64) float64 {
+	s := &h.state
+	if s.Observed != nil { // not initialized
+		return s.Level
+	}
+	steps := slot - s.LastSlot
+	if steps < 0 {
+		steps = 0
+	} else if steps > int64(len(s.Seasonal)) {
+		steps = int64(len(s.Seasonal))
+	}
+	return s.Level + float64(steps)*s.Trend + s.Seasonal[h.seasonalIndex(slot)]
+}
+
+// Percentile returns the larger prediction of the slot at the given time and the next slot, so the model can prepare
+// for the coming peak of the cycle.
+func (h *holtWintersPredictModel) Percentile(percentile float64, t time.Time) float64 {
+	if !h.state.Initialized {
+		return 0
+	}
+	p := math.Max(math.Min(percentile, maxPredictPercentile), 0.0001)
+	// z-score of the normal distribution
+	z := math.Sqrt2 * math.Erfinv(2*p-1)
+	slot := h.slotOf(t)
+	forecast := math.Max(h.forecast(slot), h.forecast(slot+1))
+	return math.Max(forecast+z*math.Sqrt(h.state.ResidualVar), 0)
+}
+
+func (h *holtWintersPredictModel) SaveToCheckpoint() (*PredictModelCheckpoint, error) {
+	data, err := json.Marshal(&h.state)
+	if err != nil {
+		return nil, err
+	}
+	return &PredictModelCheckpoint{Data: data}, nil
+}
+
+func (h *holtWintersPredictModel) LoadFromCheckpoint(ckpt *PredictModelCheckpoint) error {
+	if ckpt == nil || len(ckpt.Data) <= 0 {
+		return fmt.Errorf("holt-winters checkpoint is empty")
+	}
+	state := holtWintersState{}
+	if err := json.Unmarshal(ckpt.Data, &state); err != nil {
+		return err
+	}
+	if len(state.Seasonal) != len(h.state.Seasonal) {
+		return fmt.Errorf("season length of the checkpoint %d mismatches the model %d", len(state.Seasonal), len(h.state.Seasonal))
+	}
+	if state.Observed != nil && len(state.Observed) != len(state.Seasonal) {
+		return fmt.Errorf("observed slots of the checkpoint %d mismatches the model %d", len(state.Observed), len(state.Seasonal))
+	}
+	h.state = state
+	return nil
+}
+
+func (h *holtWintersPredictModel) slotOf(t time.Time) int64 {
+	return t.UnixNano() / int64(h.slotDuration)
+}
+
+func (h *holtWintersPredictModel) seasonalIndex(slot int64) int {
+	return int(slot % int64(len(h.state.Seasonal)))
+}
@@ -68,6 +68,10 @@ func (m *mockPredictServer) HasSynced() bool {
 	return true
 }
 
+func (m *mockPredictServer) ListPredictions() []PredictionStatus {
+	return nil
+}
+
 func (m *mockPredictServer) GetPrediction(desc MetricDesc) (Result, error) {
 	// Mock implementation of GetPrediction function
 	if m.ResultMap == nil {
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	PredictionPath = "/api/v1/predictions"
+)
+
+type predictionResponse struct {
+	Items []PredictionStatus `json:"items"`
+}
+
+// HttpHandler lists the predictions of the predict server, and the results can be filtered by the uid and kind, e.g.
+// GET /api/v1/predictions?kind=nodeItem
+// GET /api/v1/predictions?uid=xxx
+func HttpHandler(server PredictServer) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		klog.V(4).Infof("handle prediction list client=%v query=%v", r.RemoteAddr, r.URL.RawQuery)
+		if !server.HasSynced() {
+			http.Error(rw, "predict server has not synced", http.StatusServiceUnavailable)
+			return
+		}
+		uid, kind := UIDType(r.FormValue("uid")), r.FormValue("kind")
+		resp := predictionResponse{Items: []PredictionStatus{}}
+		for _, status := range server.ListPredictions() {
+			if (uid != "" && status.UID != uid) || (kind != "" && status.Kind != kind) {
+				continue
+			}
+			resp.Items = append(resp.Items, status)
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(&resp); err != nil {
+			klog.Warningf("failed to encode predictions, err: %v", err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockListPredictServer struct {
+	mockPredictServer
+	hasSynced bool
+	statuses  []PredictionStatus
+}
+
+func (m *mockListPredictServer) HasSynced() bool {
+	return m.hasSynced
+}
+
+func (m *mockListPredictServer) ListPredictions() []PredictionStatus {
+	return m.statuses
+}
+
+func TestHttpHandler(t *testing.T) {
+	statuses := []PredictionStatus{
+		{UID: DefaultNodeID, Kind: PredictionKindNode},
+		{UID: getNodeItemUID(SystemItemID), Kind: PredictionKindNodeItem, Name: SystemItemID},
+		{UID: "pod1", Kind: PredictionKindPod, Name: "default/pod1"},
+	}
+	tests := []struct {
+		name      string
+		hasSynced bool
+		query     string
+		wantCode  int
+		wantUIDs  []UIDType
+	}{
+		{
+			name:     "not synced",
+			wantCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:      "list all",
+			hasSynced: true,
+			wantCode:  http.StatusOK,
+			wantUIDs:  []UIDType{DefaultNodeID, getNodeItemUID(SystemItemID), "pod1"},
+		},
+		{
+			name:      "filter by kind",
+			hasSynced: true,
+			query:     "kind=nodeItem",
+			wantCode:  http.StatusOK,
+			wantUIDs:  []UIDType{getNodeItemUID(SystemItemID)},
+		},
+		{
+			name:      "filter by uid",
+			hasSynced: true,
+			query:     "uid=pod1",
+			wantCode:  http.StatusOK,
+			wantUIDs:  []UIDType{"pod1"},
+		},
+		{
+			name:      "no matched",
+			hasSynced: true,
+			query:     "uid=pod2",
+			wantCode:  http.StatusOK,
+			wantUIDs:  []UIDType{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &mockListPredictServer{hasSynced: tt.hasSynced, statuses: statuses}
+			rw := httptest.NewRecorder()
+			HttpHandler(server)(rw, httptest.NewRequest(http.MethodGet, PredictionPath+"?"+tt.query, nil))
+			assert.Equal(t, tt.wantCode, rw.Code)
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			resp := predictionResponse{}
+			assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+			gotUIDs := []UIDType{}
+			for _, status := range resp.Items {
+				gotUIDs = append(gotUIDs, status.UID)
+			}
+			assert.Equal(t, tt.wantUIDs, gotUIDs)
+		})
+	}
+}
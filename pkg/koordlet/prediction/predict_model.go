@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/histogram"
+)
+
+// PredictModel learns the usage samples of a resource and predicts the percentiles of the usage.
+// Models are registered with RegisterPredictModel and selected by the Config.PredictModel.
+type PredictModel interface {
+	// AddSample adds a usage sample with the weight at the given time.
+	AddSample(value float64, weight float64, t time.Time)
+	// Percentile returns the predicted percentile of the usage around the given time. The percentile is in [0, 1],
+	// and 1.0 means the peak.
+	Percentile(percentile float64, t time.Time) float64
+	// SaveToCheckpoint returns the checkpoint of the model.
+	SaveToCheckpoint() (*PredictModelCheckpoint, error)
+	// LoadFromCheckpoint restores the model from the checkpoint.
+	LoadFromCheckpoint(*PredictModelCheckpoint) error
+}
+
+// PredictModelCheckpoint is the checkpoint of a PredictModel. The histogram model keeps the histogram checkpoint for
+// the compatibility of the existing checkpoints, and other models keep their encoded states in the Data.
+type PredictModelCheckpoint struct {
+	Histogram *histogram.HistogramCheckpoint
+	Data      json.RawMessage
+}
+
+// PredictModelFactory creates a PredictModel of the resource, e.g. cpu, memory.
+type PredictModelFactory func(cfg *Config, resourceName v1.ResourceName) PredictModel
+
+const (
+	// HistogramModelName is the default model based on the decaying histograms
+	HistogramModelName = "histogram"
+)
+
+var (
+	predictModelFactoriesLock sync.RWMutex
+	predictModelFactories     = map[string]PredictModelFactory{}
+)
+
+func init() {
+	RegisterPredictModel(HistogramModelName, newHistogramPredictModel)
+	RegisterPredictModel(HoltWintersModelName, newHoltWintersPredictModel)
+	RegisterPredictModel(DayOfWeekQuantileModelName, newDayOfWeekQuantilePredictModel)
+}
+
+// RegisterPredictModel registers the factory of a PredictModel with the name. The registered model with the same
+// name is overwritten.
+func RegisterPredictModel(name string, factory PredictModelFactory) {
+	predictModelFactoriesLock.Lock()
+	defer predictModelFactoriesLock.Unlock()
+	if _, ok := predictModelFactories[name]; ok {
+		klog.Warningf("predict model %s is already registered, overwrite it", name)
+	}
+	predictModelFactories[name] = factory
+}
+
+// GetPredictModelFactory returns the factory of the registered PredictModel.
+func GetPredictModelFactory(name string) (PredictModelFactory, error) {
+	predictModelFactoriesLock.RLock()
+	defer predictModelFactoriesLock.RUnlock()
+	factory, ok := predictModelFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("predict model %s is not registered", name)
+	}
+	return factory, nil
+}
+
+var _ PredictModel = &histogramPredictModel{}
+
+// histogramPredictModel predicts the percentiles with the histogram of exponentially decaying weights.
+type histogramPredictModel struct {
+	histogram.Histogram
+}
+
+func newHistogramPredictModel(cfg *Config, resourceName v1.ResourceName) PredictModel {
+	if resourceName == v1.ResourceCPU {
+		return &histogramPredictModel{Histogram: newDefaultCPUHistogram(cfg.CPUHistogramDecayHalfLife)}
+	}
+	return &histogramPredictModel{Histogram: newDefaultMemoryHistogram(cfg.MemoryHistogramDecayHalfLife)}
+}
+
+func (h *histogramPredictModel) Percentile(percentile float64, _ time.Time) float64 {
+	return h.Histogram.Percentile(percentile)
+}
+
+func (h *histogramPredictModel) SaveToCheckpoint() (*PredictModelCheckpoint, error) {
+	ckpt, err := h.Histogram.SaveToCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	return &PredictModelCheckpoint{Histogram: ckpt}, nil
+}
+
+func (h *histogramPredictModel) LoadFromCheckpoint(ckpt *PredictModelCheckpoint) error {
+	if ckpt == nil || ckpt.Histogram == nil {
+		return fmt.Errorf("histogram checkpoint is empty")
+	}
+	return h.Histogram.LoadFromCheckpoint(ckpt.Histogram)
+}
+
+// From 0.05 to 1024 cores, maintain the bucket of the CPU histogram at a rate of 5%
+func newDefaultCPUHistogram(halfLife time.Duration) histogram.Histogram {
+	options, err := histogram.NewExponentialHistogramOptions(1024, 0.025, 1.+DefaultHistogramBucketSizeGrowth, epsilon)
+	if err != nil {
+		klog.Fatal("failed to create CPU HistogramOptions")
+	}
+	return histogram.NewDecayingHistogram(options, halfLife)
+}
+
+// From 10M to 2T, maintain the bucket of the Memory histogram at a rate of 5%
+func newDefaultMemoryHistogram(halfLife time.Duration) histogram.Histogram {
+	options, err := histogram.NewExponentialHistogramOptions(1<<31, 5<<20, 1.+DefaultHistogramBucketSizeGrowth, epsilon)
+	if err != nil {
+		klog.Fatal("failed to create Memory HistogramOptions")
+	}
+	return histogram.NewDecayingHistogram(options, halfLife)
+}
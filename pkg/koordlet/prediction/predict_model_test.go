@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGetPredictModelFactory(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelName string
+		wantErr   bool
+	}{
+		{
+			name:      "histogram model",
+			modelName: HistogramModelName,
+		},
+		{
+			name:      "holt-winters model",
+			modelName: HoltWintersModelName,
+		},
+		{
+			name:      "day-of-week quantile model",
+			modelName: DayOfWeekQuantileModelName,
+		},
+		{
+			name:      "unknown model",
+			modelName: "unknown",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory, err := GetPredictModelFactory(tt.modelName)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.NotNil(t, factory(NewDefaultConfig(), v1.ResourceCPU))
+			}
+		})
+	}
+}
+
+func TestNewPeakPredictServerWithUnknownModel(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.PredictModel = "unknown"
+	p := NewPeakPredictServer(cfg).(*peakPredictServer)
+	assert.Equal(t, HistogramModelName, p.modelName)
+	_, ok := p.newResourceModels().CPU.(*histogramPredictModel)
+	assert.True(t, ok)
+}
+
+func TestNewPeakPredictServerWithInvalidHoltWintersConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		period          time.Duration
+		slotDuration    time.Duration
+		wantModelName   string
+		wantHoltWinters bool
+	}{
+		{name: "valid config", period: 24 * time.Hour, slotDuration: 15 * time.Minute, wantModelName: HoltWintersModelName, wantHoltWinters: true},
+		{name: "zero slot duration", period: 24 * time.Hour, slotDuration: 0, wantModelName: HistogramModelName},
+		{name: "negative period", period: -time.Hour, slotDuration: 15 * time.Minute, wantModelName: HistogramModelName},
+		{name: "period shorter than slot", period: time.Minute, slotDuration: 15 * time.Minute, wantModelName: HistogramModelName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			cfg.PredictModel = HoltWintersModelName
+			cfg.SeasonalPeriod = tt.period
+			cfg.SeasonalSlotDuration = tt.slotDuration
+			p := NewPeakPredictServer(cfg).(*peakPredictServer)
+			assert.Equal(t, tt.wantModelName, p.modelName)
+			_, ok := p.newResourceModels().CPU.(*holtWintersPredictModel)
+			assert.Equal(t, tt.wantHoltWinters, ok)
+		})
+	}
+}
+
+// dailyUsage is 2 cores at night and 8 cores in the afternoon
+func dailyUsage(t time.Time) float64 {
+	return 5 - 3*math.Cos(2*math.Pi*float64(t.UTC().Hour()*60+t.UTC().Minute())/(24*60))
+}
+
+func TestHoltWintersPredictModel(t *testing.T) {
+	cfg := NewDefaultConfig()
+	model := newHoltWintersPredictModel(cfg, v1.ResourceCPU)
+	assert.Equal(t, 0.0, model.Percentile(0.9, time.Now()))
+
+	start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(7 * 24 * time.Hour)
+	for now := start; now.Before(end); now = now.Add(time.Minute) {
+		model.AddSample(dailyUsage(now), 1, now)
+	}
+
+	night := end.Add(time.Hour)
+	afternoon := end.Add(12 * time.Hour)
+	assert.InDelta(t, dailyUsage(night), model.Percentile(0.5, night), 0.5)
+	assert.InDelta(t, dailyUsage(afternoon), model.Percentile(0.5, afternoon), 0.5)
+	assert.Greater(t, model.Percentile(0.95, afternoon), model.Percentile(0.95, night))
+	assert.GreaterOrEqual(t, model.Percentile(0.95, night), model.Percentile(0.5, night))
+
+	ckpt, err := model.SaveToCheckpoint()
+	assert.NoError(t, err)
+	restored := newHoltWintersPredictModel(cfg, v1.ResourceCPU)
+	assert.NoError(t, restored.LoadFromCheckpoint(ckpt))
+	assert.Equal(t, model.Percentile(0.95, afternoon), restored.Percentile(0.95, afternoon))
+
+	cfg.SeasonalPeriod = time.Hour
+	assert.Error(t, newHoltWintersPredictModel(cfg, v1.ResourceCPU).LoadFromCheckpoint(ckpt))
+	assert.Error(t, restored.LoadFromCheckpoint(&PredictModelCheckpoint{}))
+}
+
+func TestDayOfWeekQuantilePredictModel(t *testing.T) {
+	model := newDayOfWeekQuantilePredictModel(NewDefaultConfig(), v1.ResourceMemory)
+	assert.Equal(t, 0.0, model.Percentile(0.9, time.Now()))
+
+	// 2023-06-05 is Monday, the usage is 8 GiB on weekdays and 2 GiB on weekends
+	start := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * 7 * 24 * time.Hour)
+	for now := start; now.Before(end); now = now.Add(time.Minute) {
+		usage := float64(8 << 30)
+		if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
+			usage = 2 << 30
+		}
+		model.AddSample(usage, 1, now)
+	}
+
+	wednesday := end.Add(2*24*time.Hour + 10*time.Hour)
+	saturday := end.Add(5*24*time.Hour + 10*time.Hour)
+	assert.InEpsilon(t, float64(8<<30), model.Percentile(0.9, wednesday), 0.1)
+	assert.InEpsilon(t, float64(2<<30), model.Percentile(0.9, saturday), 0.1)
+	// the prediction of sunday night covers the coming monday
+	sundayNight := end.Add(-30 * time.Minute)
+	assert.InEpsilon(t, float64(8<<30), model.Percentile(0.9, sundayNight), 0.1)
+	assert.GreaterOrEqual(t, model.Percentile(1.0, wednesday), model.Percentile(0.5, wednesday))
+
+	ckpt, err := model.SaveToCheckpoint()
+	assert.NoError(t, err)
+	restored := newDayOfWeekQuantilePredictModel(NewDefaultConfig(), v1.ResourceMemory)
+	assert.NoError(t, restored.LoadFromCheckpoint(ckpt))
+	assert.Equal(t, model.Percentile(0.95, saturday), restored.Percentile(0.95, saturday))
+	assert.Error(t, restored.LoadFromCheckpoint(&PredictModelCheckpoint{Data: []byte(`{"slots":[]}`)}))
+}
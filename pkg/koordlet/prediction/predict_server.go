@@ -58,20 +58,44 @@ data are only related to the data it sees. For example, when we need to deal wit
 this business logic should be processed when using the predicted data instead of being coupled
 to the predictive model.
 
-The predictive model currently provides histogram-based statistics with exponentially decaying
-weights over time periods. PredictServer is responsible for storing the intermediate results of
-the model and recovering when the process restarts.
+The predictive model is pluggable (see PredictModel), and it defaults to the histogram-based statistics
+with exponentially decaying weights over time periods. PredictServer is responsible for storing the
+intermediate results of the model and recovering when the process restarts.
 */
 type PredictServer interface {
 	Setup(statesinformer.StatesInformer, metriccache.MetricCache) error
 	Run(stopCh <-chan struct{}) error
 	HasSynced() bool
 	GetPrediction(MetricDesc) (Result, error)
+	// ListPredictions returns the predictions of all models, which is used to audit the predict results.
+	ListPredictions() []PredictionStatus
 }
 
-type PredictModel struct {
-	CPU    histogram.Histogram
-	Memory histogram.Histogram
+const (
+	PredictionKindPod      = "pod"
+	PredictionKindNode     = "node"
+	PredictionKindNodeItem = "nodeItem"
+)
+
+// PredictionStatus is the prediction of a UID with the state of its models.
+type PredictionStatus struct {
+	UID  UIDType `json:"uid"`
+	Kind string  `json:"kind"`
+	// Name is the namespaced name of the pod, or the item ID of the node item
+	Name             string                     `json:"name,omitempty"`
+	Model            string                     `json:"model"`
+	Samples          int64                      `json:"samples"`
+	LastUpdated      time.Time                  `json:"lastUpdated"`
+	LastCheckpointed time.Time                  `json:"lastCheckpointed,omitempty"`
+	Data             map[string]v1.ResourceList `json:"data"`
+}
+
+// ResourceModels keeps the predict models of the resources for a UID.
+type ResourceModels struct {
+	CPU    PredictModel
+	Memory PredictModel
+	// Samples is the number of the samples added into the models
+	Samples int64
 
 	LastUpdated      time.Time
 	LastCheckpointed time.Time
@@ -84,7 +108,9 @@ type peakPredictServer struct {
 	metricServer MetricServer
 
 	uidGenerator UIDGenerator
-	models       map[UIDType]*PredictModel
+	modelName    string
+	modelFactory PredictModelFactory
+	models       map[UIDType]*ResourceModels
 	modelsLock   sync.Mutex
 
 	clock        clock.Clock
@@ -93,13 +119,25 @@ type peakPredictServer struct {
 }
 
 func NewPeakPredictServer(cfg *Config) PredictServer {
+	modelName := cfg.PredictModel
+	modelFactory, err := GetPredictModelFactory(modelName)
+	if err == nil && modelName == HoltWintersModelName {
+		err = validateHoltWintersConfig(cfg)
+	}
+	if err != nil {
+		klog.Errorf("failed to get predict model %s, use the default model %s, err: %v", modelName, HistogramModelName, err)
+		modelName = HistogramModelName
+		modelFactory = newHistogramPredictModel
+	}
 	return &peakPredictServer{
 		cfg:          cfg,
 		uidGenerator: &generator{},
-		models:       make(map[UIDType]*PredictModel),
+		modelName:    modelName,
+		modelFactory: modelFactory,
+		models:       make(map[UIDType]*ResourceModels),
 		clock:        clock.RealClock{},
 		hasSynced:    &atomic.Bool{},
-		checkpointer: NewFileCheckpointer(cfg.CheckpointFilepath),
+		checkpointer: NewCheckpointer(cfg),
 	}
 }
 
@@ -124,6 +162,7 @@ func (p *peakPredictServer) Run(stopCh <-chan struct{}) error {
 			klog.Errorf("remove checkpoint %v failed, err: %v", uid, err)
 		}
 	}
+	p.flushCheckpoints()
 
 	go wait.Until(p.training, p.cfg.TrainingInterval, stopCh)
 	go wait.Until(p.gcModels, time.Minute, stopCh)
@@ -203,22 +242,19 @@ func (p *peakPredictServer) training() {
 	p.hasSynced.Store(true)
 }
 
-// From 0.05 to 1024 cores, maintain the bucket of the CPU histogram at a rate of 5%
 func (p *peakPredictServer) defaultCPUHistogram() histogram.Histogram {
-	options, err := histogram.NewExponentialHistogramOptions(1024, 0.025, 1.+DefaultHistogramBucketSizeGrowth, epsilon)
-	if err != nil {
-		klog.Fatal("failed to create CPU HistogramOptions")
-	}
-	return histogram.NewDecayingHistogram(options, p.cfg.CPUHistogramDecayHalfLife)
+	return newDefaultCPUHistogram(p.cfg.CPUHistogramDecayHalfLife)
 }
 
-// From 10M to 2T, maintain the bucket of the Memory histogram at a rate of 5%
 func (p *peakPredictServer) defaultMemoryHistogram() histogram.Histogram {
-	options, err := histogram.NewExponentialHistogramOptions(1<<31, 5<<20, 1.+DefaultHistogramBucketSizeGrowth, epsilon)
-	if err != nil {
-		klog.Fatal("failed to create Memory HistogramOptions")
+	return newDefaultMemoryHistogram(p.cfg.MemoryHistogramDecayHalfLife)
+}
+
+func (p *peakPredictServer) newResourceModels() *ResourceModels {
+	return &ResourceModels{
+		CPU:    p.modelFactory(p.cfg, v1.ResourceCPU),
+		Memory: p.modelFactory(p.cfg, v1.ResourceMemory),
 	}
-	return histogram.NewDecayingHistogram(options, p.cfg.MemoryHistogramDecayHalfLife)
 }
 
 func (p *peakPredictServer) updateModel(uid UIDType, cpu, memory float64) {
@@ -226,10 +262,7 @@ func (p *peakPredictServer) updateModel(uid UIDType, cpu, memory float64) {
 	defer p.modelsLock.Unlock()
 	model, ok := p.models[uid]
 	if !ok {
-		model = &PredictModel{
-			CPU:    p.defaultCPUHistogram(),
-			Memory: p.defaultMemoryHistogram(),
-		}
+		model = p.newResourceModels()
 		p.models[uid] = model
 	}
 	now := p.clock.Now()
@@ -239,6 +272,7 @@ func (p *peakPredictServer) updateModel(uid UIDType, cpu, memory float64) {
 	// TODO Add adjusted weights
 	model.CPU.AddSample(cpu, 1, now)
 	model.Memory.AddSample(memory, 1, now)
+	model.Samples++
 }
 
 func (p *peakPredictServer) GetPrediction(metric MetricDesc) (Result, error) {
@@ -250,31 +284,76 @@ func (p *peakPredictServer) GetPrediction(metric MetricDesc) (Result, error) {
 	}
 	model.Lock.Lock()
 	defer model.Lock.Unlock()
-	//
+	return model.predict(p.clock.Now()), nil
+}
+
+func (p *peakPredictServer) ListPredictions() []PredictionStatus {
+	podNames := map[UIDType]string{}
+	if p.informer != nil {
+		for _, pod := range p.informer.ListPods() {
+			podNames[p.uidGenerator.Pod(pod)] = util.GetPodKey(pod)
+		}
+	}
+
+	p.modelsLock.Lock()
+	defer p.modelsLock.Unlock()
+	now := p.clock.Now()
+	statuses := make([]PredictionStatus, 0, len(p.models))
+	for uid, model := range p.models {
+		status := PredictionStatus{
+			UID:   uid,
+			Kind:  PredictionKindPod,
+			Name:  podNames[uid],
+			Model: p.modelName,
+		}
+		if uid == p.uidGenerator.Node() {
+			status.Kind, status.Name = PredictionKindNode, ""
+		} else if itemID, ok := parseNodeItemUID(uid); ok {
+			status.Kind, status.Name = PredictionKindNodeItem, itemID
+		}
+		model.Lock.Lock()
+		status.Samples = model.Samples
+		status.LastUpdated = model.LastUpdated
+		status.LastCheckpointed = model.LastCheckpointed
+		status.Data = model.predict(now).Data
+		model.Lock.Unlock()
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Kind != statuses[j].Kind {
+			return statuses[i].Kind < statuses[j].Kind
+		}
+		return statuses[i].UID < statuses[j].UID
+	})
+	return statuses
+}
+
+// predict returns the predict result of the models at the given time, the caller should hold the lock.
+func (m *ResourceModels) predict(now time.Time) Result {
 	return Result{
 		Data: map[string]v1.ResourceList{
 			"p60": {
-				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(model.CPU.Percentile(0.6)*1000.0), resource.DecimalSI),
-				v1.ResourceMemory: *resource.NewQuantity(int64(model.Memory.Percentile(0.6)), resource.BinarySI),
+				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(m.CPU.Percentile(0.6, now)*1000.0), resource.DecimalSI),
+				v1.ResourceMemory: *resource.NewQuantity(int64(m.Memory.Percentile(0.6, now)), resource.BinarySI),
 			},
 			"p90": {
-				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(model.CPU.Percentile(0.9)*1000.0), resource.DecimalSI),
-				v1.ResourceMemory: *resource.NewQuantity(int64(model.Memory.Percentile(0.9)), resource.BinarySI),
+				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(m.CPU.Percentile(0.9, now)*1000.0), resource.DecimalSI),
+				v1.ResourceMemory: *resource.NewQuantity(int64(m.Memory.Percentile(0.9, now)), resource.BinarySI),
 			},
 			"p95": {
-				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(model.CPU.Percentile(0.95)*1000.0), resource.DecimalSI),
-				v1.ResourceMemory: *resource.NewQuantity(int64(model.Memory.Percentile(0.95)), resource.BinarySI),
+				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(m.CPU.Percentile(0.95, now)*1000.0), resource.DecimalSI),
+				v1.ResourceMemory: *resource.NewQuantity(int64(m.Memory.Percentile(0.95, now)), resource.BinarySI),
 			},
 			"p98": {
-				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(model.CPU.Percentile(0.98)*1000.0), resource.DecimalSI),
-				v1.ResourceMemory: *resource.NewQuantity(int64(model.Memory.Percentile(0.98)), resource.BinarySI),
+				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(m.CPU.Percentile(0.98, now)*1000.0), resource.DecimalSI),
+				v1.ResourceMemory: *resource.NewQuantity(int64(m.Memory.Percentile(0.98, now)), resource.BinarySI),
 			},
 			"max": {
-				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(model.CPU.Percentile(1.0)*1000.0), resource.DecimalSI),
-				v1.ResourceMemory: *resource.NewQuantity(int64(model.Memory.Percentile(1.0)), resource.BinarySI),
+				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(m.CPU.Percentile(1.0, now)*1000.0), resource.DecimalSI),
+				v1.ResourceMemory: *resource.NewQuantity(int64(m.Memory.Percentile(1.0, now)), resource.BinarySI),
 			},
 		},
-	}, nil
+	}
 }
 
 func (p *peakPredictServer) gcModels() {
@@ -302,6 +381,7 @@ func (p *peakPredictServer) gcModels() {
 			klog.Errorf("remove checkpoint %v failed, err: %v", uid, err)
 		}
 	}
+	p.flushCheckpoints()
 }
 
 func (p *peakPredictServer) doCheckpoint() {
@@ -312,7 +392,7 @@ func (p *peakPredictServer) doCheckpoint() {
 
 	type pair struct {
 		UID   UIDType
-		Model *PredictModel
+		Model *ResourceModels
 	}
 
 	p.modelsLock.Lock()
@@ -337,11 +417,17 @@ func (p *peakPredictServer) doCheckpoint() {
 		}
 		ckpt := ModelCheckpoint{
 			UID:         pair.UID,
+			Model:       p.modelName,
 			LastUpdated: metav1.NewTime(p.clock.Now()),
 		}
 		pair.Model.Lock.Lock()
-		ckpt.CPU, _ = pair.Model.CPU.SaveToCheckpoint()
-		ckpt.Memory, _ = pair.Model.Memory.SaveToCheckpoint()
+		ckpt.Samples = pair.Model.Samples
+		if cpuCkpt, err := pair.Model.CPU.SaveToCheckpoint(); err == nil {
+			ckpt.CPU, ckpt.CPUData = cpuCkpt.Histogram, cpuCkpt.Data
+		}
+		if memoryCkpt, err := pair.Model.Memory.SaveToCheckpoint(); err == nil {
+			ckpt.Memory, ckpt.MemoryData = memoryCkpt.Histogram, memoryCkpt.Data
+		}
 		pair.Model.Lock.Unlock()
 
 		err := p.checkpointer.Save(ckpt)
@@ -353,6 +439,13 @@ func (p *peakPredictServer) doCheckpoint() {
 		pair.Model.LastCheckpointed = p.clock.Now()
 		checkpointModelsCount++
 	}
+	p.flushCheckpoints()
+}
+
+func (p *peakPredictServer) flushCheckpoints() {
+	if err := p.checkpointer.Flush(); err != nil {
+		klog.Errorf("flush checkpoints failed, err: %v", err)
+	}
 }
 
 func (p *peakPredictServer) restoreModels() (unknownUIDs []UIDType) {
@@ -377,16 +470,26 @@ func (p *peakPredictServer) restoreModels() (unknownUIDs []UIDType) {
 			unknownUIDs = append(unknownUIDs, checkpoint.UID)
 			continue
 		}
-
-		model := &PredictModel{
-			CPU:         p.defaultCPUHistogram(),
-			Memory:      p.defaultMemoryHistogram(),
-			LastUpdated: checkpoint.LastUpdated.Time,
+		// checkpoints without the model name are saved by the histogram model
+		checkpointModelName := checkpoint.Model
+		if checkpointModelName == "" {
+			checkpointModelName = HistogramModelName
 		}
-		if err := model.CPU.LoadFromCheckpoint(checkpoint.CPU); err != nil {
+		if checkpointModelName != p.modelName {
+			klog.InfoS("discard checkpoint of another model", "uid", checkpoint.UID, "model", checkpointModelName)
+			unknownUIDs = append(unknownUIDs, checkpoint.UID)
+			continue
+		}
+
+		model := p.newResourceModels()
+		model.LastUpdated = checkpoint.LastUpdated.Time
+		model.Samples = checkpoint.Samples
+		cpuCkpt := &PredictModelCheckpoint{Histogram: checkpoint.CPU, Data: checkpoint.CPUData}
+		if err := model.CPU.LoadFromCheckpoint(cpuCkpt); err != nil {
 			klog.Errorf("failed to CPU checkpoint %v, err %v", checkpoint.UID, err)
 		}
-		if err := model.Memory.LoadFromCheckpoint(checkpoint.Memory); err != nil {
+		memoryCkpt := &PredictModelCheckpoint{Histogram: checkpoint.Memory, Data: checkpoint.MemoryData}
+		if err := model.Memory.LoadFromCheckpoint(memoryCkpt); err != nil {
 			klog.Errorf("failed to Memory checkpoint %v, err %v", checkpoint.UID, err)
 		}
 		klog.InfoS("restoring checkpoint", "uid", checkpoint.UID, "lastUpdated", checkpoint.LastUpdated)
@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/clock"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/util/histogram"
 )
 
@@ -208,6 +209,51 @@ func TestPredictServerMainLoop(t *testing.T) {
 	}
 }
 
+func TestPredictServerListPredictions(t *testing.T) {
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod1",
+				UID:       "pod1",
+			},
+		},
+	}
+	metricServer := &mockMetricServer{
+		PodUsage: map[UIDType]podUsage{
+			"pod1": {PodCPUUsage: 1, PodMemoryUsage: 128 * 1024 * 1024},
+		},
+		NodeCPUUsage:    2,
+		NodeMemoryUsage: 512 * 1024 * 1024,
+	}
+	peakPrediction := NewPeakPredictServer(NewDefaultConfig())
+	peakPrediction.(*peakPredictServer).informer = &mockInformer{Pods: pods}
+	peakPrediction.(*peakPredictServer).metricServer = metricServer
+	peakPrediction.(*peakPredictServer).training()
+	peakPrediction.(*peakPredictServer).training()
+
+	statuses := peakPrediction.ListPredictions()
+	assert.Equal(t, 8, len(statuses)) // pods(1)+node(1)+priority(5)+sys(1)
+	kinds := map[string]int{}
+	for _, status := range statuses {
+		kinds[status.Kind]++
+		assert.Equal(t, HistogramModelName, status.Model)
+		assert.Equal(t, int64(2), status.Samples)
+		assert.Contains(t, status.Data, "p90")
+	}
+	assert.Equal(t, map[string]int{PredictionKindNode: 1, PredictionKindNodeItem: 6, PredictionKindPod: 1}, kinds)
+	assert.Equal(t, PredictionKindNode, statuses[0].Kind)
+	assert.Equal(t, PredictionKindPod, statuses[7].Kind)
+	assert.Equal(t, "default/pod1", statuses[7].Name)
+	itemIDs := []string{SystemItemID}
+	for _, priorityClass := range extension.KnownPriorityClasses {
+		itemIDs = append(itemIDs, string(priorityClass))
+	}
+	for _, status := range statuses[1:7] {
+		assert.Contains(t, itemIDs, status.Name)
+	}
+}
+
 func TestPredictServerStop(t *testing.T) {
 	informer := &mockInformer{}
 	node := &v1.Node{
@@ -290,9 +336,9 @@ func TestPredictServerGCOldModels(t *testing.T) {
 	}
 }
 
-func makeTestHistogram() histogram.Histogram {
+func makeTestHistogram() PredictModel {
 	options, _ := histogram.NewLinearHistogramOptions(100, 1024, 0.001)
-	return histogram.NewHistogram(options)
+	return &histogramPredictModel{Histogram: histogram.NewHistogram(options)}
 }
 
 type mockCheckpointer struct {
@@ -306,6 +352,10 @@ func (ckpt *mockCheckpointer) Remove(UID UIDType) error {
 	return nil
 }
 
+func (ckpt *mockCheckpointer) Flush() error {
+	return nil
+}
+
 func (ckpt *mockCheckpointer) Restore() ([]*ModelCheckpoint, error) {
 	return nil, nil
 }
@@ -319,7 +369,7 @@ func TestDoCheckpoint(t *testing.T) {
 		metricServer: &mockMetricServer{},
 		uidGenerator: &generator{},
 		clock:        clock.NewFakeClock(now),
-		models: map[UIDType]*PredictModel{
+		models: map[UIDType]*ResourceModels{
 			UIDType("model1"): {
 				CPU:    makeTestHistogram(),
 				Memory: makeTestHistogram(),
@@ -352,7 +402,7 @@ func TestDoCheckpoint_MaxPerStep(t *testing.T) {
 		metricServer: &mockMetricServer{},
 		uidGenerator: &generator{},
 		clock:        clock.NewFakeClock(now),
-		models: map[UIDType]*PredictModel{
+		models: map[UIDType]*ResourceModels{
 			UIDType("model1"): {
 				CPU:    makeTestHistogram(),
 				Memory: makeTestHistogram(),
@@ -375,7 +425,7 @@ func TestDoCheckpoint_MaxPerStep(t *testing.T) {
 	assert.Equal(t, 1, updatedModelCount)
 }
 
-func countModelsCheckpointAtTime(models map[UIDType]*PredictModel, t time.Time) int {
+func countModelsCheckpointAtTime(models map[UIDType]*ResourceModels, t time.Time) int {
 	updatedModelCount := 0
 	for _, model := range models {
 		if model.LastCheckpointed.Equal(t) {
@@ -395,7 +445,7 @@ func TestDoCheckpoint_CheckpointInterval(t *testing.T) {
 		metricServer: &mockMetricServer{},
 		uidGenerator: &generator{},
 		clock:        mockClock,
-		models: map[UIDType]*PredictModel{
+		models: map[UIDType]*ResourceModels{
 			UIDType("model1"): {
 				CPU:    makeTestHistogram(),
 				Memory: makeTestHistogram(),
@@ -468,8 +518,10 @@ func TestDoCheckpoint_RestoreModels(t *testing.T) {
 		informer:     &mockInformer{Pods: pods, Node: node},
 		metricServer: &mockMetricServer{},
 		uidGenerator: &generator{},
+		modelName:    HistogramModelName,
+		modelFactory: newHistogramPredictModel,
 		clock:        mockClock,
-		models: map[UIDType]*PredictModel{
+		models: map[UIDType]*ResourceModels{
 			UIDType("node1"): {
 				CPU:    makeTestHistogram(),
 				Memory: makeTestHistogram(),
@@ -489,12 +541,12 @@ func TestDoCheckpoint_RestoreModels(t *testing.T) {
 	predictServer.doCheckpoint()
 
 	// clear the models in memory and restore it
-	predictServer.models = make(map[UIDType]*PredictModel)
+	predictServer.models = make(map[UIDType]*ResourceModels)
 	predictServer.restoreModels()
 	assert.Equal(t, 3, len(predictServer.models), "restore models from checkpoint")
 
 	// mock another model and restore it to unknownUIDs
-	predictServer.models["unknown"] = &PredictModel{
+	predictServer.models["unknown"] = &ResourceModels{
 		CPU:    makeTestHistogram(),
 		Memory: makeTestHistogram(),
 	}
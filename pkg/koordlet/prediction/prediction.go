@@ -18,6 +18,7 @@ package prediction
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -68,6 +69,16 @@ func getNodeItemUID(itemID string) UIDType {
 	return UIDType(fmt.Sprintf(DefaultNodeItemIDFmt, itemID))
 }
 
+// parseNodeItemUID returns the item ID of the node item UID generated by getNodeItemUID.
+func parseNodeItemUID(uid UIDType) (string, bool) {
+	prefix, suffix := "__node-", "__"
+	s := string(uid)
+	if len(s) < len(prefix)+len(suffix) || !strings.HasPrefix(s, prefix) || !strings.HasSuffix(s, suffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(s, prefix), suffix), true
+}
+
 type Result struct {
 	// Use different quantile type as key, currently support "p60", "p90", "p95" "p98", "max".
 	Data map[string]v1.ResourceList
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -114,3 +115,16 @@ func TestUIDGenerator(t *testing.T) {
 		t.Errorf("Expected node UID to be 'node1', got '%s'", nodeUID)
 	}
 }
+
+func TestParseNodeItemUID(t *testing.T) {
+	itemID, ok := parseNodeItemUID(getNodeItemUID(SystemItemID))
+	assert.True(t, ok)
+	assert.Equal(t, SystemItemID, itemID)
+	itemID, ok = parseNodeItemUID(getNodeItemUID(""))
+	assert.True(t, ok)
+	assert.Equal(t, "", itemID)
+	_, ok = parseNodeItemUID(DefaultNodeID)
+	assert.False(t, ok)
+	_, ok = parseNodeItemUID("pod1")
+	assert.False(t, ok)
+}
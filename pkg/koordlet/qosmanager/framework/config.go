@@ -18,27 +18,60 @@ package framework
 
 import (
 	"flag"
+
+	cliflag "k8s.io/component-base/cli/flag"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 )
 
 type Config struct {
-	ReconcileIntervalSeconds   int
-	CPUSuppressIntervalSeconds int
-	CPUEvictIntervalSeconds    int
-	MemoryEvictIntervalSeconds int
-	MemoryEvictCoolTimeSeconds int
-	CPUEvictCoolTimeSeconds    int
-	QOSExtensionCfg            *QOSExtensionConfig
+	ReconcileIntervalSeconds      int
+	CPUSuppressIntervalSeconds    int
+	CPUEvictIntervalSeconds       int
+	MemoryEvictIntervalSeconds    int
+	MemoryEvictCoolTimeSeconds    int
+	MemorySuppressIntervalSeconds int
+	CPUEvictCoolTimeSeconds       int
+	NetQOSInterfaceName           string
+	QOSExtensionCfg               *QOSExtensionConfig
+	// DryRunStrategies are the names of the strategies running in the dry-run mode, whose decisions are recorded into
+	// the audit events and metrics instead of being applied.
+	DryRunStrategies []string
+
+	// EvictGracePeriodSeconds is the grace period to evict the pods keyed by the priority class (e.g. koord-batch) or
+	// the QoS class (e.g. BE), the priority class takes precedence. The pod's own grace period is used if not set.
+	EvictGracePeriodSeconds map[string]string
+	// EvictRateLimitQPS limits the node-wide eviction rate, zero means no limit.
+	EvictRateLimitQPS   float64
+	EvictRateLimitBurst int
+	// EvictPDBAware skips evicting the pods whose pod disruption budgets do not allow the disruption.
+	EvictPDBAware bool
+
+	InterferenceDetectIntervalSeconds int
+	InterferenceCPIDegradationPercent int
+	InterferencePSIDegradationPercent int
 }
 
 func NewDefaultConfig() *Config {
 	return &Config{
-		ReconcileIntervalSeconds:   1,
-		CPUSuppressIntervalSeconds: 1,
-		CPUEvictIntervalSeconds:    1,
-		MemoryEvictIntervalSeconds: 1,
-		MemoryEvictCoolTimeSeconds: 4,
-		CPUEvictCoolTimeSeconds:    20,
-		QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{}},
+		ReconcileIntervalSeconds:      1,
+		CPUSuppressIntervalSeconds:    1,
+		CPUEvictIntervalSeconds:       1,
+		MemoryEvictIntervalSeconds:    1,
+		MemoryEvictCoolTimeSeconds:    4,
+		MemorySuppressIntervalSeconds: 1,
+		CPUEvictCoolTimeSeconds:       20,
+		QOSExtensionCfg:               &QOSExtensionConfig{FeatureGates: map[string]bool{}},
+		DryRunStrategies:              []string{},
+		EvictGracePeriodSeconds:       map[string]string{},
+		EvictRateLimitQPS:             0,
+		EvictRateLimitBurst:           5,
+		EvictPDBAware:                 false,
+
+		InterferenceDetectIntervalSeconds: 30,
+		InterferenceCPIDegradationPercent: 30,
+		InterferencePSIDegradationPercent: 10,
 	}
 }
 
@@ -48,6 +81,37 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.CPUEvictIntervalSeconds, "cpu-evict-interval-seconds", c.CPUEvictIntervalSeconds, "evict be pod(cpu) interval by seconds")
 	fs.IntVar(&c.MemoryEvictIntervalSeconds, "memory-evict-interval-seconds", c.MemoryEvictIntervalSeconds, "evict be pod(memory) interval by seconds")
 	fs.IntVar(&c.MemoryEvictCoolTimeSeconds, "memory-evict-cool-time-seconds", c.MemoryEvictCoolTimeSeconds, "cooling time: memory next evict time should after lastEvictTime + MemoryEvictCoolTimeSeconds")
+	fs.IntVar(&c.MemorySuppressIntervalSeconds, "memory-suppress-interval-seconds", c.MemorySuppressIntervalSeconds, "suppress be memory interval by seconds")
 	fs.IntVar(&c.CPUEvictCoolTimeSeconds, "cpu-evict-cool-time-seconds", c.CPUEvictCoolTimeSeconds, "cooltime: CPU next evict time should after lastEvictTime + CPUEvictCoolTimeSeconds")
+	fs.StringVar(&c.NetQOSInterfaceName, "net-qos-interface-name", c.NetQOSInterfaceName, "the network interface to shape bandwidth for net qos, use the interface of the default route if empty")
+	fs.IntVar(&c.InterferenceDetectIntervalSeconds, "interference-detect-interval-seconds", c.InterferenceDetectIntervalSeconds, "detect the interference on LS pods interval by seconds")
+	fs.IntVar(&c.InterferenceCPIDegradationPercent, "interference-cpi-degradation-percent", c.InterferenceCPIDegradationPercent, "LS pod is interfered when its CPI exceeds the baseline by the percent, 0 disables the CPI detection")
+	fs.IntVar(&c.InterferencePSIDegradationPercent, "interference-psi-degradation-percent", c.InterferencePSIDegradationPercent, "LS pod is interfered when its cpu some avg10 pressure exceeds the baseline by the percent, 0 disables the PSI detection")
+	fs.Var(cliflag.NewStringSlice(&c.DryRunStrategies), "qos-strategy-dry-run", "names of the qos strategies running in the dry-run mode, which record the decisions into the audit events and metrics without modifying the cgroups or evicting pods, the flag can be specified multiple times")
+	fs.Var(cliflag.NewMapStringString(&c.EvictGracePeriodSeconds), "eviction-grace-period-seconds", "grace period seconds to evict pods keyed by the priority class or QoS class, e.g. koord-batch=30,BE=10, the priority class takes precedence")
+	fs.Float64Var(&c.EvictRateLimitQPS, "eviction-rate-limit-qps", c.EvictRateLimitQPS, "the maximum qps of the node-wide pod evictions, 0 means no limit")
+	fs.IntVar(&c.EvictRateLimitBurst, "eviction-rate-limit-burst", c.EvictRateLimitBurst, "the burst of the node-wide pod evictions, works only when eviction-rate-limit-qps is set")
+	fs.BoolVar(&c.EvictPDBAware, "eviction-pdb-aware", c.EvictPDBAware, "skip evicting the pods whose pod disruption budgets do not allow the disruption")
 	c.QOSExtensionCfg.InitFlags(fs)
 }
+
+// IsDryRun returns whether the strategy runs in the dry-run mode.
+func (c *Config) IsDryRun(strategy string) bool {
+	for _, s := range c.DryRunStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStrategyExecutor returns the resource executor for the strategy. In the dry-run mode, the updates are recorded
+// into the audit events and metrics instead of being applied.
+func (c *Config) NewStrategyExecutor(strategy string) resourceexecutor.ResourceUpdateExecutor {
+	if !c.IsDryRun(strategy) {
+		return resourceexecutor.NewResourceUpdateExecutor()
+	}
+	return resourceexecutor.NewDryRunResourceUpdateExecutor(strategy, func(updater resourceexecutor.ResourceUpdater) {
+		metrics.RecordQOSStrategyDryRunDecision(strategy, metrics.ActionUpdate, string(updater.ResourceType()))
+	})
+}
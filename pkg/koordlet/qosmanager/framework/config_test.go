@@ -21,17 +21,27 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 )
 
 func Test_NewDefaultConfig(t *testing.T) {
 	expectConfig := &Config{
-		ReconcileIntervalSeconds:   1,
-		CPUSuppressIntervalSeconds: 1,
-		CPUEvictIntervalSeconds:    1,
-		MemoryEvictIntervalSeconds: 1,
-		MemoryEvictCoolTimeSeconds: 4,
-		CPUEvictCoolTimeSeconds:    20,
-		QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{}},
+		ReconcileIntervalSeconds:      1,
+		CPUSuppressIntervalSeconds:    1,
+		CPUEvictIntervalSeconds:       1,
+		MemoryEvictIntervalSeconds:    1,
+		MemoryEvictCoolTimeSeconds:    4,
+		MemorySuppressIntervalSeconds: 1,
+		CPUEvictCoolTimeSeconds:       20,
+		QOSExtensionCfg:               &QOSExtensionConfig{FeatureGates: map[string]bool{}},
+		DryRunStrategies:              []string{},
+		EvictGracePeriodSeconds:       map[string]string{},
+		EvictRateLimitBurst:           5,
+
+		InterferenceDetectIntervalSeconds: 30,
+		InterferenceCPIDegradationPercent: 30,
+		InterferencePSIDegradationPercent: 10,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -45,19 +55,41 @@ func Test_InitFlags(t *testing.T) {
 		"--cpu-evict-interval-seconds=2",
 		"--memory-evict-interval-seconds=2",
 		"--memory-evict-cool-time-seconds=8",
+		"--memory-suppress-interval-seconds=2",
 		"--cpu-evict-cool-time-seconds=40",
+		"--net-qos-interface-name=eth1",
 		"--qos-extension-plugins=test-plugin=true",
+		"--interference-detect-interval-seconds=60",
+		"--interference-cpi-degradation-percent=50",
+		"--interference-psi-degradation-percent=20",
+		"--qos-strategy-dry-run=CPUEvict",
+		"--qos-strategy-dry-run=BlkioReconcile",
+		"--eviction-grace-period-seconds=koord-batch=30,BE=10",
+		"--eviction-rate-limit-qps=0.5",
+		"--eviction-rate-limit-burst=2",
+		"--eviction-pdb-aware=true",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
 	type fields struct {
-		ReconcileIntervalSeconds   int
-		CPUSuppressIntervalSeconds int
-		CPUEvictIntervalSeconds    int
-		MemoryEvictIntervalSeconds int
-		MemoryEvictCoolTimeSeconds int
-		CPUEvictCoolTimeSeconds    int
-		QOSExtensionCfg            *QOSExtensionConfig
+		ReconcileIntervalSeconds      int
+		CPUSuppressIntervalSeconds    int
+		CPUEvictIntervalSeconds       int
+		MemoryEvictIntervalSeconds    int
+		MemoryEvictCoolTimeSeconds    int
+		MemorySuppressIntervalSeconds int
+		CPUEvictCoolTimeSeconds       int
+		NetQOSInterfaceName           string
+		QOSExtensionCfg               *QOSExtensionConfig
+		DryRunStrategies              []string
+		EvictGracePeriodSeconds       map[string]string
+		EvictRateLimitQPS             float64
+		EvictRateLimitBurst           int
+		EvictPDBAware                 bool
+
+		InterferenceDetectIntervalSeconds int
+		InterferenceCPIDegradationPercent int
+		InterferencePSIDegradationPercent int
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -70,13 +102,24 @@ func Test_InitFlags(t *testing.T) {
 		{
 			name: "not default",
 			fields: fields{
-				ReconcileIntervalSeconds:   2,
-				CPUSuppressIntervalSeconds: 2,
-				CPUEvictIntervalSeconds:    2,
-				MemoryEvictIntervalSeconds: 2,
-				MemoryEvictCoolTimeSeconds: 8,
-				CPUEvictCoolTimeSeconds:    40,
-				QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{"test-plugin": true}},
+				ReconcileIntervalSeconds:      2,
+				CPUSuppressIntervalSeconds:    2,
+				CPUEvictIntervalSeconds:       2,
+				MemoryEvictIntervalSeconds:    2,
+				MemoryEvictCoolTimeSeconds:    8,
+				MemorySuppressIntervalSeconds: 2,
+				CPUEvictCoolTimeSeconds:       40,
+				NetQOSInterfaceName:           "eth1",
+				QOSExtensionCfg:               &QOSExtensionConfig{FeatureGates: map[string]bool{"test-plugin": true}},
+				DryRunStrategies:              []string{"CPUEvict", "BlkioReconcile"},
+				EvictGracePeriodSeconds:       map[string]string{"koord-batch": "30", "BE": "10"},
+				EvictRateLimitQPS:             0.5,
+				EvictRateLimitBurst:           2,
+				EvictPDBAware:                 true,
+
+				InterferenceDetectIntervalSeconds: 60,
+				InterferenceCPIDegradationPercent: 50,
+				InterferencePSIDegradationPercent: 20,
 			},
 			args: args{fs: fs},
 		},
@@ -84,13 +127,24 @@ func Test_InitFlags(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			raw := &Config{
-				ReconcileIntervalSeconds:   tt.fields.ReconcileIntervalSeconds,
-				CPUSuppressIntervalSeconds: tt.fields.CPUSuppressIntervalSeconds,
-				CPUEvictIntervalSeconds:    tt.fields.CPUEvictIntervalSeconds,
-				MemoryEvictIntervalSeconds: tt.fields.MemoryEvictIntervalSeconds,
-				MemoryEvictCoolTimeSeconds: tt.fields.MemoryEvictCoolTimeSeconds,
-				CPUEvictCoolTimeSeconds:    tt.fields.CPUEvictCoolTimeSeconds,
-				QOSExtensionCfg:            tt.fields.QOSExtensionCfg,
+				ReconcileIntervalSeconds:      tt.fields.ReconcileIntervalSeconds,
+				CPUSuppressIntervalSeconds:    tt.fields.CPUSuppressIntervalSeconds,
+				CPUEvictIntervalSeconds:       tt.fields.CPUEvictIntervalSeconds,
+				MemoryEvictIntervalSeconds:    tt.fields.MemoryEvictIntervalSeconds,
+				MemoryEvictCoolTimeSeconds:    tt.fields.MemoryEvictCoolTimeSeconds,
+				MemorySuppressIntervalSeconds: tt.fields.MemorySuppressIntervalSeconds,
+				CPUEvictCoolTimeSeconds:       tt.fields.CPUEvictCoolTimeSeconds,
+				NetQOSInterfaceName:           tt.fields.NetQOSInterfaceName,
+				QOSExtensionCfg:               tt.fields.QOSExtensionCfg,
+				DryRunStrategies:              tt.fields.DryRunStrategies,
+				EvictGracePeriodSeconds:       tt.fields.EvictGracePeriodSeconds,
+				EvictRateLimitQPS:             tt.fields.EvictRateLimitQPS,
+				EvictRateLimitBurst:           tt.fields.EvictRateLimitBurst,
+				EvictPDBAware:                 tt.fields.EvictPDBAware,
+
+				InterferenceDetectIntervalSeconds: tt.fields.InterferenceDetectIntervalSeconds,
+				InterferenceCPIDegradationPercent: tt.fields.InterferenceCPIDegradationPercent,
+				InterferencePSIDegradationPercent: tt.fields.InterferencePSIDegradationPercent,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
@@ -99,3 +153,15 @@ func Test_InitFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_IsDryRun(t *testing.T) {
+	c := NewDefaultConfig()
+	assert.False(t, c.IsDryRun("CPUEvict"))
+	c.DryRunStrategies = []string{"CPUEvict", "BlkioReconcile"}
+	assert.True(t, c.IsDryRun("CPUEvict"))
+	assert.True(t, c.IsDryRun("BlkioReconcile"))
+	assert.False(t, c.IsDryRun("CPUSuppresss"))
+
+	assert.IsType(t, &resourceexecutor.DryRunResourceUpdateExecutor{}, c.NewStrategyExecutor("CPUEvict"))
+	assert.IsType(t, &resourceexecutor.ResourceUpdateExecutorImpl{}, c.NewStrategyExecutor("CPUSuppresss"))
+}
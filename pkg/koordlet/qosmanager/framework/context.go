@@ -19,12 +19,14 @@ package framework
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
@@ -45,45 +47,119 @@ type Evictor struct {
 	podsEvicted   *expireCache.Cache
 	evictVersion  string
 	started       atomic.Bool
+
+	gracePeriods map[string]int64
+	rateLimiter  flowcontrol.RateLimiter
+	pdbAware     bool
 }
 
 func NewEvictor(kubeClient clientset.Interface, eventRecorder record.EventRecorder, evictVersion string) *Evictor {
-	return &Evictor{
+	return NewEvictorWithConfig(kubeClient, eventRecorder, evictVersion, NewDefaultConfig())
+}
+
+func NewEvictorWithConfig(kubeClient clientset.Interface, eventRecorder record.EventRecorder, evictVersion string, cfg *Config) *Evictor {
+	e := &Evictor{
 		eventRecorder: eventRecorder,
 		kubeClient:    kubeClient,
 		podsEvicted:   expireCache.NewCacheDefault(),
 		evictVersion:  evictVersion,
+		gracePeriods:  parseEvictGracePeriods(cfg.EvictGracePeriodSeconds),
+		pdbAware:      cfg.EvictPDBAware,
+	}
+	if cfg.EvictRateLimitQPS > 0 {
+		burst := cfg.EvictRateLimitBurst
+		if burst < 1 {
+			burst = 1
+		}
+		e.rateLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(cfg.EvictRateLimitQPS), burst)
 	}
+	return e
 }
 
 func (r *Evictor) Start(stopCh <-chan struct{}) error {
 	return r.podsEvicted.Run(stopCh)
 }
 
+// killContainers can be replaced in tests.
+var killContainers = helpers.KillContainers
+
+// IsPodEvicted returns whether the pod has been evicted and is not expired in the evicted records.
+func (r *Evictor) IsPodEvicted(pod *corev1.Pod) bool {
+	_, evicted := r.podsEvicted.Get(string(pod.UID))
+	return evicted
+}
+
+// EvictPodIfNotEvicted evicts the pod if it has not been evicted, and returns whether the pod is evicted in this call.
+func (r *Evictor) EvictPodIfNotEvicted(evictPod *corev1.Pod, node *corev1.Node, reason string, message string) bool {
+	return r.evictPodIfNotEvicted(evictPod, node, reason, message, false)
+}
+
 func (r *Evictor) EvictPodsIfNotEvicted(evictPods []*corev1.Pod, node *corev1.Node, reason string, message string) {
 	for _, evictPod := range evictPods {
-		r.evictPodIfNotEvicted(evictPod, node, reason, message)
+		r.evictPodIfNotEvicted(evictPod, node, reason, message, false)
+	}
+}
+
+// KillAndEvictPodsIfNotEvicted kills the containers of the pods before evicting them, which is used by the strategies
+// relieving the resource pressure urgently. The pressure-driven evictions are not delayed by the grace periods, and
+// are exempted from the pod disruption budgets and the node-wide rate limit.
+func (r *Evictor) KillAndEvictPodsIfNotEvicted(evictPods []*corev1.Pod, node *corev1.Node, reason string, message string) {
+	for _, evictPod := range evictPods {
+		r.evictPodIfNotEvicted(evictPod, node, reason, message, true)
+	}
+}
+
+// DryRunEvictPods records the pods which would be evicted by the strategy into the audit events and metrics, without
+// calling the eviction API.
+func (r *Evictor) DryRunEvictPods(strategy string, evictPods []*corev1.Pod, reason string, message string) {
+	for _, evictPod := range evictPods {
+		_ = audit.V(0).Pod(evictPod.Namespace, evictPod.Name).Reason(reason).DryRun().Message("dry-run evict by %s, %s", strategy, message).Do()
+		metrics.RecordQOSStrategyDryRunDecision(strategy, metrics.ActionEvict, reason)
+		klog.Infof("dry-run evict pod %v/%v by %s, reason: %v, message: %v", evictPod.Namespace, evictPod.Name,
+			strategy, reason, message)
 	}
 }
 
-func (r *Evictor) evictPodIfNotEvicted(evictPod *corev1.Pod, node *corev1.Node, reason string, message string) {
-	_, evicted := r.podsEvicted.Get(string(evictPod.UID))
-	if evicted {
+func (r *Evictor) evictPodIfNotEvicted(evictPod *corev1.Pod, node *corev1.Node, reason string, message string, kill bool) bool {
+	if r.IsPodEvicted(evictPod) {
 		klog.V(5).Infof("Pod has been evicted! podID: %v, evict reason: %s", evictPod.UID, reason)
-		return
+		return false
+	}
+	success := r.evictPod(evictPod, reason, message, kill)
+	if !success {
+		return false
 	}
-	success := r.evictPod(evictPod, reason, message)
-	if success {
+	// keep the evicted record until the pod is terminated gracefully
+	if gracePeriod := r.GetGracePeriodSeconds(evictPod); gracePeriod != nil && *gracePeriod > 0 {
+		_ = r.podsEvicted.Set(string(evictPod.UID), evictPod.UID, evictedPodExpiration+time.Duration(*gracePeriod)*time.Second)
+	} else {
 		_ = r.podsEvicted.SetDefault(string(evictPod.UID), evictPod.UID)
 	}
+	return true
 }
 
-func (r *Evictor) evictPod(evictPod *corev1.Pod, reason string, message string) bool {
+// evictPod evicts the pod in the following stages:
+// 1. if the containers are required to be killed, kill them at once. Otherwise, skip the eviction in this round if the
+// pod disruption budgets (PDB-aware mode) or the node-wide rate limit do not allow it. The pod is not recorded as
+// evicted so that the strategy retries later.
+// 2. mark the pod with the EvictionRequested condition and call the pre-evict hooks to notify the owners.
+// 3. evict the pod with the grace period configured for its priority class or QoS class.
+func (r *Evictor) evictPod(evictPod *corev1.Pod, reason string, message string, kill bool) bool {
 	podEvictMessage := fmt.Sprintf("evict Pod:%s/%s, reason: %s, message: %v", evictPod.Namespace, evictPod.Name, reason, message)
+
+	if kill {
+		killContainers(evictPod, fmt.Sprintf("%s, kill pod: %s", message, util.GetPodKey(evictPod)))
+	} else if !r.admitEviction(evictPod, reason, podEvictMessage) {
+		return false
+	}
+
 	_ = audit.V(0).Pod(evictPod.Namespace, evictPod.Name).Reason(reason).Message(message).Do()
+	r.markEvictionRequested(evictPod, reason, message)
+	runPreEvictHooks(evictPod, reason, message)
 
+	gracePeriod := r.GetGracePeriodSeconds(evictPod)
 	if err := util.EvictPodByVersion(context.TODO(), r.kubeClient, evictPod.Namespace, evictPod.Name, metav1.DeleteOptions{
-		GracePeriodSeconds: nil,
+		GracePeriodSeconds: gracePeriod,
 		Preconditions:      metav1.NewUIDPreconditions(string(evictPod.UID))}, r.evictVersion); err == nil {
 		r.eventRecorder.Eventf(evictPod, corev1.EventTypeWarning, helpers.EvictPodSuccess, podEvictMessage)
 		metrics.RecordPodEviction(evictPod.Namespace, evictPod.Name, reason)
@@ -96,3 +172,22 @@ func (r *Evictor) evictPod(evictPod *corev1.Pod, reason string, message string)
 		return false
 	}
 }
+
+// admitEviction checks whether the pod disruption budgets (PDB-aware mode) and the node-wide rate limit allow the
+// eviction in this round.
+func (r *Evictor) admitEviction(evictPod *corev1.Pod, reason string, podEvictMessage string) bool {
+	if r.pdbAware {
+		if blocked, err := r.isBlockedByPDB(evictPod); err != nil {
+			klog.Warningf("failed to check pod disruption budgets for pod %v/%v, error: %v", evictPod.Namespace, evictPod.Name, err)
+		} else if blocked {
+			r.eventRecorder.Eventf(evictPod, corev1.EventTypeWarning, helpers.EvictPodFail, "%v, blocked by pod disruption budget", podEvictMessage)
+			klog.V(4).Infof("skip evicting pod %v/%v, reason: %v, blocked by pod disruption budget", evictPod.Namespace, evictPod.Name, reason)
+			return false
+		}
+	}
+	if r.rateLimiter != nil && !r.rateLimiter.TryAccept() {
+		klog.V(4).Infof("skip evicting pod %v/%v, reason: %v, node-wide eviction rate limited", evictPod.Namespace, evictPod.Name, reason)
+		return false
+	}
+	return true
+}
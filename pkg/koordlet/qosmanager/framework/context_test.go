@@ -73,6 +73,25 @@ func Test_EvictPodsIfNotEvicted(t *testing.T) {
 	assert.Equal(t, "", fakeRecorder.EventReason, "check evict duplication, no event send!")
 }
 
+func Test_DryRunEvictPods(t *testing.T) {
+	pod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	fakeRecorder := &testutil.FakeRecorder{}
+	client := clientsetfake.NewSimpleClientset()
+	r := NewEvictor(client, fakeRecorder, policyv1beta1.SchemeGroupVersion.Version)
+
+	_, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	// the pod is not evicted
+	r.DryRunEvictPods("testStrategy", []*corev1.Pod{pod}, "evict pod dry-run", "")
+	got, err := client.Tracker().Get(testutil.PodsResource, pod.Namespace, pod.Name)
+	assert.NoError(t, err)
+	assert.IsType(t, &corev1.Pod{}, got)
+	assert.Equal(t, "", fakeRecorder.EventReason)
+	_, found := r.podsEvicted.Get(string(pod.UID))
+	assert.False(t, found)
+}
+
 func setupFakeDiscoveryWithPolicyResource(fake *coretesting.Fake, groupVersion string) {
 	fake.AddReactor("get", "group", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
 		fake.Resources = []*metav1.APIResourceList{
@@ -134,7 +153,7 @@ func Test_evictPod_policy_v1beta1(t *testing.T) {
 	assert.NotNil(t, existPod, "pod exist in k8s!", err)
 
 	// evict success
-	r.evictPod(pod, "evict pod first", "")
+	r.evictPod(pod, "evict pod first", "", false)
 	getEvictObject, err := client.Tracker().Get(testutil.PodsResource, pod.Namespace, pod.Name)
 	assert.NoError(t, err)
 	assert.NotNil(t, getEvictObject, "evictPod Fail", err)
@@ -170,7 +189,7 @@ func Test_evictPod_policy_v1(t *testing.T) {
 	assert.NotNil(t, existPod, "pod exist in k8s!", err)
 
 	// evict success
-	r.evictPod(pod, "evict pod first", "")
+	r.evictPod(pod, "evict pod first", "", false)
 	getEvictObject, err := client.Tracker().Get(testutil.PodsResource, pod.Namespace, pod.Name)
 	assert.NoError(t, err)
 	assert.NotNil(t, getEvictObject, "evictPod Fail", err)
@@ -203,6 +222,6 @@ func Test_evictPod_policy_none(t *testing.T) {
 	assert.NotNil(t, existPod, "pod exist in k8s!", err)
 
 	// evict success
-	evicted := r.evictPod(pod, "evict pod first", "")
+	evicted := r.evictPod(pod, "evict pod first", "", false)
 	assert.False(t, evicted, "pod evicted", err)
 }
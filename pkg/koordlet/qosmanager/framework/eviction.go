@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+)
+
+const (
+	// evictedPodExpiration is how long an evicted pod is remembered to avoid evicting it repeatedly, which is
+	// extended by the eviction grace period.
+	evictedPodExpiration = 2 * time.Minute
+)
+
+// PreEvictHook is called before the Evictor evicts a pod, e.g. to notify the owner of the pod to checkpoint or drain
+// the work. An error of the hook is logged and does not stop the eviction.
+type PreEvictHook interface {
+	Name() string
+	PreEvict(pod *corev1.Pod, reason string, message string) error
+}
+
+var globalPreEvictHooks []PreEvictHook
+
+// RegisterPreEvictHook registers a hook called before each pod eviction.
+func RegisterPreEvictHook(hook PreEvictHook) {
+	globalPreEvictHooks = append(globalPreEvictHooks, hook)
+	klog.V(4).Infof("pre-evict hook %s registered", hook.Name())
+}
+
+func runPreEvictHooks(pod *corev1.Pod, reason string, message string) {
+	for _, hook := range globalPreEvictHooks {
+		if err := hook.PreEvict(pod, reason, message); err != nil {
+			klog.Warningf("failed to run pre-evict hook %s for pod %v/%v, error: %v", hook.Name(), pod.Namespace, pod.Name, err)
+		}
+	}
+}
+
+func parseEvictGracePeriods(gracePeriodSeconds map[string]string) map[string]int64 {
+	gracePeriods := make(map[string]int64, len(gracePeriodSeconds))
+	for key, value := range gracePeriodSeconds {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds < 0 {
+			klog.Warningf("ignore invalid eviction grace period %s=%s", key, value)
+			continue
+		}
+		gracePeriods[key] = seconds
+	}
+	return gracePeriods
+}
+
+// GetGracePeriodSeconds returns the grace period to evict the pod configured by its priority class or QoS class.
+// It returns nil if neither is configured, so that the grace period of the pod itself is used.
+func (r *Evictor) GetGracePeriodSeconds(pod *corev1.Pod) *int64 {
+	if seconds, ok := r.gracePeriods[string(apiext.GetPodPriorityClassWithDefault(pod))]; ok {
+		return &seconds
+	}
+	if seconds, ok := r.gracePeriods[string(apiext.GetPodQoSClassWithDefault(pod))]; ok {
+		return &seconds
+	}
+	return nil
+}
+
+// markEvictionRequested adds the EvictionRequested condition to the pod status. It is best-effort and the eviction
+// continues if the patch fails.
+func (r *Evictor) markEvictionRequested(pod *corev1.Pod, reason string, message string) {
+	if err := r.patchPodCondition(pod, apiext.PodConditionEvictionRequested, reason, message); err != nil {
+		klog.Warningf("failed to mark eviction condition for pod %v/%v, error: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	r.eventRecorder.Eventf(pod, corev1.EventTypeNormal, helpers.EvictPodRequested, "eviction requested, reason: %s, message: %s", reason, message)
+}
+
+// ReportPodsKilled reports the pods whose containers are killed directly by the strategy with the Killed condition,
+// the audit events and the pod events.
+func (r *Evictor) ReportPodsKilled(killedPods []*corev1.Pod, reason string, message string) {
+	for _, pod := range killedPods {
+		_ = audit.V(0).Pod(pod.Namespace, pod.Name).Reason(reason).Message("kill pod, %s", message).Do()
+		if err := r.patchPodCondition(pod, apiext.PodConditionKilled, reason, message); err != nil {
+			klog.Warningf("failed to mark killed condition for pod %v/%v, error: %v", pod.Namespace, pod.Name, err)
+		}
+		r.eventRecorder.Eventf(pod, corev1.EventTypeWarning, helpers.KillPodSuccess, "kill Pod:%s/%s, reason: %s, message: %v", pod.Namespace, pod.Name, reason, message)
+		klog.Infof("kill pod %v/%v, reason: %v", pod.Namespace, pod.Name, reason)
+	}
+}
+
+func (r *Evictor) patchPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType, reason string, message string) error {
+	patch := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []corev1.PodCondition{
+				{
+					Type:               conditionType,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.Now(),
+					Reason:             reason,
+					Message:            message,
+				},
+			},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = r.kubeClient.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.StrategicMergePatchType,
+		patchBytes, metav1.PatchOptions{}, "status")
+	return err
+}
+
+// isBlockedByPDB returns whether any pod disruption budget matching the pod disallows the disruption.
+func (r *Evictor) isBlockedByPDB(pod *corev1.Pod) (bool, error) {
+	type pdbStatus struct {
+		name               string
+		selector           *metav1.LabelSelector
+		disruptionsAllowed int32
+	}
+	var pdbs []pdbStatus
+	if r.evictVersion == "v1beta1" {
+		pdbList, err := r.kubeClient.PolicyV1beta1().PodDisruptionBudgets(pod.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for i := range pdbList.Items {
+			pdb := &pdbList.Items[i]
+			// an empty selector matches no pods in policy/v1beta1
+			if pdb.Spec.Selector == nil || (len(pdb.Spec.Selector.MatchLabels) == 0 && len(pdb.Spec.Selector.MatchExpressions) == 0) {
+				continue
+			}
+			pdbs = append(pdbs, pdbStatus{name: pdb.Name, selector: pdb.Spec.Selector, disruptionsAllowed: pdb.Status.DisruptionsAllowed})
+		}
+	} else {
+		pdbList, err := r.kubeClient.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for i := range pdbList.Items {
+			pdb := &pdbList.Items[i]
+			if pdb.Spec.Selector == nil {
+				continue
+			}
+			pdbs = append(pdbs, pdbStatus{name: pdb.Name, selector: pdb.Spec.Selector, disruptionsAllowed: pdb.Status.DisruptionsAllowed})
+		}
+	}
+
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.selector)
+		if err != nil {
+			klog.V(4).Infof("failed to parse selector of pod disruption budget %s/%s, error: %v", pod.Namespace, pdb.name, err)
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) && pdb.disruptionsAllowed <= 0 {
+			klog.V(5).Infof("pod %v/%v is protected by pod disruption budget %s", pod.Namespace, pod.Name, pdb.name)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+type fakePreEvictHook struct {
+	calledPods []string
+}
+
+func (f *fakePreEvictHook) Name() string { return "fakePreEvictHook" }
+
+func (f *fakePreEvictHook) PreEvict(pod *corev1.Pod, reason string, message string) error {
+	f.calledPods = append(f.calledPods, pod.Name)
+	return nil
+}
+
+func Test_parseEvictGracePeriods(t *testing.T) {
+	got := parseEvictGracePeriods(map[string]string{
+		string(apiext.PriorityBatch): "30",
+		string(apiext.QoSBE):         "10",
+		string(apiext.QoSLS):         "invalid",
+		string(apiext.QoSLSR):        "-1",
+	})
+	assert.Equal(t, map[string]int64{
+		string(apiext.PriorityBatch): 30,
+		string(apiext.QoSBE):         10,
+	}, got)
+}
+
+func TestEvictor_GetGracePeriodSeconds(t *testing.T) {
+	bePod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	batchPod := testutil.MockTestPod(apiext.QoSBE, "test_batch_pod")
+	batchPod.Spec.Priority = pointer.Int32(apiext.PriorityBatchValueMax)
+	lsPod := testutil.MockTestPod(apiext.QoSLS, "test_ls_pod")
+	tests := []struct {
+		name         string
+		gracePeriods map[string]string
+		pod          *corev1.Pod
+		want         *int64
+	}{
+		{
+			name: "not configured",
+			pod:  bePod,
+			want: nil,
+		},
+		{
+			name:         "use qos class",
+			gracePeriods: map[string]string{string(apiext.QoSBE): "10"},
+			pod:          bePod,
+			want:         pointer.Int64(10),
+		},
+		{
+			name: "priority class takes precedence",
+			gracePeriods: map[string]string{
+				string(apiext.PriorityBatch): "30",
+				string(apiext.QoSBE):         "10",
+			},
+			pod:  batchPod,
+			want: pointer.Int64(30),
+		},
+		{
+			name:         "zero grace period",
+			gracePeriods: map[string]string{string(apiext.QoSLS): "0"},
+			pod:          lsPod,
+			want:         pointer.Int64(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			if tt.gracePeriods != nil {
+				cfg.EvictGracePeriodSeconds = tt.gracePeriods
+			}
+			r := NewEvictorWithConfig(clientsetfake.NewSimpleClientset(), &testutil.FakeRecorder{}, "v1", cfg)
+			assert.Equal(t, tt.want, r.GetGracePeriodSeconds(tt.pod))
+		})
+	}
+}
+
+func TestEvictor_evictPodGracefully(t *testing.T) {
+	pod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	client := clientsetfake.NewSimpleClientset()
+	var gotGracePeriod *int64
+	client.PrependReactor("create", "pods", func(action coretesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(coretesting.CreateAction).GetObject().(*policyv1.Eviction)
+		gotGracePeriod = eviction.DeleteOptions.GracePeriodSeconds
+		return true, nil, nil
+	})
+	_, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	hook := &fakePreEvictHook{}
+	oldHooks := globalPreEvictHooks
+	globalPreEvictHooks = nil
+	defer func() { globalPreEvictHooks = oldHooks }()
+	RegisterPreEvictHook(hook)
+
+	cfg := NewDefaultConfig()
+	cfg.EvictGracePeriodSeconds = map[string]string{string(apiext.QoSBE): "30"}
+	fakeRecorder := &testutil.FakeRecorder{}
+	r := NewEvictorWithConfig(client, fakeRecorder, "v1", cfg)
+
+	assert.True(t, r.evictPod(pod, "evict pod gracefully", "test", false))
+	assert.Equal(t, pointer.Int64(30), gotGracePeriod)
+	assert.Equal(t, []string{pod.Name}, hook.calledPods)
+	assert.Equal(t, helpers.EvictPodSuccess, fakeRecorder.EventReason)
+
+	gotPod, err := client.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	var gotCondition *corev1.PodCondition
+	for i := range gotPod.Status.Conditions {
+		if gotPod.Status.Conditions[i].Type == apiext.PodConditionEvictionRequested {
+			gotCondition = &gotPod.Status.Conditions[i]
+		}
+	}
+	assert.NotNil(t, gotCondition)
+	assert.Equal(t, corev1.ConditionTrue, gotCondition.Status)
+	assert.Equal(t, "evict pod gracefully", gotCondition.Reason)
+}
+
+func TestEvictor_evictPodRateLimited(t *testing.T) {
+	pod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	pod1 := testutil.MockTestPod(apiext.QoSBE, "test_be_pod_1")
+	client := clientsetfake.NewSimpleClientset(pod, pod1)
+
+	cfg := NewDefaultConfig()
+	cfg.EvictRateLimitQPS = 0.001
+	cfg.EvictRateLimitBurst = 1
+	r := NewEvictorWithConfig(client, &testutil.FakeRecorder{}, "v1", cfg)
+
+	assert.True(t, r.evictPod(pod, "evict pod", "", false))
+	assert.False(t, r.evictPod(pod1, "evict pod", "", false))
+}
+
+func TestEvictor_KillAndEvictPodsIfNotEvicted(t *testing.T) {
+	pod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	pod1 := testutil.MockTestPod(apiext.QoSBE, "test_be_pod_1")
+	pod2 := testutil.MockTestPod(apiext.QoSBE, "test_be_pod_2")
+	client := clientsetfake.NewSimpleClientset(pod, pod1, pod2)
+
+	var killedPods []string
+	oldKillContainers := killContainers
+	killContainers = func(pod *corev1.Pod, message string) {
+		killedPods = append(killedPods, pod.Name)
+	}
+	defer func() { killContainers = oldKillContainers }()
+
+	cfg := NewDefaultConfig()
+	cfg.EvictGracePeriodSeconds = map[string]string{string(apiext.QoSBE): "30"}
+	cfg.EvictRateLimitQPS = 0.001
+	cfg.EvictRateLimitBurst = 1
+	r := NewEvictorWithConfig(client, &testutil.FakeRecorder{}, "v1", cfg)
+	stop := make(chan struct{})
+	defer close(stop)
+	assert.NoError(t, r.Start(stop))
+
+	r.KillAndEvictPodsIfNotEvicted([]*corev1.Pod{pod, pod1}, testutil.MockTestNode("80", "120G"), "evict pod", "")
+	// the pressure-driven evictions kill the containers regardless of the grace period and the rate limit
+	assert.Equal(t, []string{pod.Name, pod1.Name}, killedPods)
+	_, evicted := r.podsEvicted.Get(string(pod1.UID))
+	assert.True(t, evicted)
+
+	// the other evictions are still rate limited
+	assert.True(t, r.evictPod(pod2, "evict pod", "", false))
+	assert.False(t, r.evictPod(pod2, "evict pod", "", false))
+}
+
+func TestEvictor_evictPodBlockedByPDB(t *testing.T) {
+	pod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	pod.Labels["app"] = "test"
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pdb",
+			Namespace: pod.Namespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "test"},
+			},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			DisruptionsAllowed: 0,
+		},
+	}
+	tests := []struct {
+		name               string
+		pdbAware           bool
+		disruptionsAllowed int32
+		want               bool
+	}{
+		{
+			name:     "pdb not aware",
+			pdbAware: false,
+			want:     true,
+		},
+		{
+			name:     "blocked by pdb",
+			pdbAware: true,
+			want:     false,
+		},
+		{
+			name:               "allowed by pdb",
+			pdbAware:           true,
+			disruptionsAllowed: 1,
+			want:               true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testPDB := pdb.DeepCopy()
+			testPDB.Status.DisruptionsAllowed = tt.disruptionsAllowed
+			client := clientsetfake.NewSimpleClientset(pod.DeepCopy(), testPDB)
+			cfg := NewDefaultConfig()
+			cfg.EvictPDBAware = tt.pdbAware
+			fakeRecorder := &testutil.FakeRecorder{}
+			r := NewEvictorWithConfig(client, fakeRecorder, "v1", cfg)
+
+			assert.Equal(t, tt.want, r.evictPod(pod, "evict pod", "", false))
+			if !tt.want {
+				assert.Equal(t, helpers.EvictPodFail, fakeRecorder.EventReason)
+			}
+		})
+	}
+}
+
+func TestEvictor_ReportPodsKilled(t *testing.T) {
+	pod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	client := clientsetfake.NewSimpleClientset(pod)
+	fakeRecorder := &testutil.FakeRecorder{}
+	r := NewEvictor(client, fakeRecorder, "v1")
+
+	r.ReportPodsKilled([]*corev1.Pod{pod}, "kill pod", "test")
+	assert.Equal(t, helpers.KillPodSuccess, fakeRecorder.EventReason)
+	gotPod, err := client.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, gotPod.Status.Conditions, 1)
+	assert.Equal(t, apiext.PodConditionKilled, gotPod.Status.Conditions[0].Type)
+	assert.Equal(t, "kill pod", gotPod.Status.Conditions[0].Reason)
+}
@@ -23,6 +23,8 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -35,6 +37,10 @@ const (
 
 var globalQOSControlPlugins = map[string]QOSGreyControlPlugin{}
 
+func init() {
+	koordletutil.InjectPodMemoryQOSConfigFunc = injectPodMemoryQOSConfig
+}
+
 func RegisterQOSGreyCtrlPlugin(name string, plugin QOSGreyControlPlugin) error {
 	if _, exist := globalQOSControlPlugins[name]; exist {
 		return fmt.Errorf("qos grep control plugin %v already exist", name)
@@ -68,6 +74,16 @@ func InjectQOSGreyCtrlPlugins(pod *corev1.Pod, policyType QOSPolicyType, policy
 	return injected
 }
 
+// injectPodMemoryQOSConfig returns the memory qos config injected by the qos grey control plugins.
+func injectPodMemoryQOSConfig(pod *corev1.Pod) *slov1alpha1.PodMemoryQOSConfig {
+	var greyCtlMemoryQOSCfgIf interface{} = &slov1alpha1.PodMemoryQOSConfig{}
+	injected := InjectQOSGreyCtrlPlugins(pod, QOSPolicyMemoryQOS, &greyCtlMemoryQOSCfgIf)
+	if greyCtlMemoryQOSCfg, ok := greyCtlMemoryQOSCfgIf.(*slov1alpha1.PodMemoryQOSConfig); ok && injected {
+		return greyCtlMemoryQOSCfg
+	}
+	return nil
+}
+
 func UnregisterQOSGreyCtrlPlugin(name string) {
 	delete(globalQOSControlPlugins, name)
 }
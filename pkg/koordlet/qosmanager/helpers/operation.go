@@ -17,10 +17,14 @@ limitations under the License.
 package helpers
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -52,3 +56,34 @@ func KillContainers(pod *corev1.Pod, message string) {
 		}
 	}
 }
+
+// KillPodByCgroup kills all processes of the pod at once by writing the `cgroup.kill` of cgroups-v2, which is faster
+// than stopping the containers one by one through the runtime.
+func KillPodByCgroup(executor resourceexecutor.ResourceUpdateExecutor, podCgroupDir string) error {
+	r, err := system.GetCgroupResource(system.CgroupKillName)
+	if err != nil { // cgroups-v1
+		return err
+	}
+	// the executor ignores the unsupported errors, so check it before updating
+	if supported, msg := r.IsSupported(podCgroupDir); !supported {
+		return fmt.Errorf("%s is not supported for %s, msg: %s", system.CgroupKillName, podCgroupDir, msg)
+	}
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CgroupKillName, podCgroupDir, "1", nil)
+	if err != nil {
+		return err
+	}
+	_, err = executor.Update(false, updater)
+	return err
+}
+
+// FastKillPod kills the pod through `cgroup.kill` if supported, otherwise it falls back to stop the containers
+// through the runtime without a grace period.
+func FastKillPod(executor resourceexecutor.ResourceUpdateExecutor, pod *corev1.Pod, podCgroupDir string, message string) {
+	err := KillPodByCgroup(executor, podCgroupDir)
+	if err == nil {
+		klog.V(4).Infof("%s, kill pod %s/%s by cgroup", message, pod.Namespace, pod.Name)
+		return
+	}
+	klog.V(5).Infof("failed to kill pod %s/%s by cgroup, fallback to stop containers, err: %v", pod.Namespace, pod.Name, err)
+	KillContainers(pod, message)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestKillPodByCgroup(t *testing.T) {
+	testPodDir := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1.slice"
+	tests := []struct {
+		name      string
+		cgroupsV2 bool
+		prepareFn func(helper *system.FileTestUtil)
+		wantErr   bool
+		wantValue string
+	}{
+		{
+			name:      "cgroups-v1 not supported",
+			cgroupsV2: false,
+			wantErr:   true,
+		},
+		{
+			name:      "cgroup.kill not exist",
+			cgroupsV2: true,
+			wantErr:   true,
+		},
+		{
+			name:      "kill pod by cgroup.kill",
+			cgroupsV2: true,
+			prepareFn: func(helper *system.FileTestUtil) {
+				helper.CreateCgroupFile(testPodDir, system.CgroupKillV2)
+			},
+			wantValue: "1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.cgroupsV2)
+			if tt.prepareFn != nil {
+				tt.prepareFn(helper)
+			}
+			gotErr := KillPodByCgroup(resourceexecutor.NewResourceUpdateExecutor(), testPodDir)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			if !tt.wantErr {
+				assert.Equal(t, tt.wantValue, helper.ReadCgroupFileContents(testPodDir, system.CgroupKillV2))
+			}
+		})
+	}
+}
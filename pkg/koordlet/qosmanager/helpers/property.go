@@ -19,32 +19,12 @@ package helpers
 import (
 	corev1 "k8s.io/api/core/v1"
 
-	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 )
 
 func GetPodResourceQoSByQoSClass(pod *corev1.Pod, strategy *slov1alpha1.ResourceQOSStrategy) *slov1alpha1.ResourceQOS {
-	if strategy == nil {
-		return nil
-	}
-	var resourceQoS *slov1alpha1.ResourceQOS
-	podQoS := apiext.GetPodQoSClassWithDefault(pod)
-	switch podQoS {
-	case apiext.QoSLSE:
-		// currently LSE pods use the same strategy with LSR
-		resourceQoS = strategy.LSRClass
-	case apiext.QoSLSR:
-		resourceQoS = strategy.LSRClass
-	case apiext.QoSLS:
-		resourceQoS = strategy.LSClass
-	case apiext.QoSBE:
-		resourceQoS = strategy.BEClass
-	case apiext.QoSSystem:
-		resourceQoS = strategy.SystemClass
-	default:
-		// should never reach here
-	}
-	return resourceQoS
+	return koordletutil.GetPodResourceQoSByQoSClass(pod, strategy)
 }
 
 // GetKubeQoSResourceQoSByQoSClass gets pod config by mapping kube qos into koordinator qos.
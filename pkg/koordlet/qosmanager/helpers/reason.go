@@ -27,8 +27,11 @@ const (
 	EvictPodByNodeMemoryUsage   = "EvictPodByNodeMemoryUsage"
 	EvictPodByBECPUSatisfaction = "EvictPodByBECPUSatisfaction"
 
-	AdjustBEByNodeCPUUsage = "AdjustBEByNodeCPUUsage"
+	AdjustBEByNodeCPUUsage    = "AdjustBEByNodeCPUUsage"
+	AdjustBEByNodeMemoryUsage = "AdjustBEByNodeMemoryUsage"
 
-	EvictPodSuccess = "evictPodSuccess"
-	EvictPodFail    = "evictPodFail"
+	EvictPodSuccess   = "evictPodSuccess"
+	EvictPodFail      = "evictPodFail"
+	EvictPodRequested = "evictPodRequested"
+	KillPodSuccess    = "killPodSuccess"
 )
@@ -90,7 +90,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
 		statesInformer:    opt.StatesInformer,
 		metricCache:       opt.MetricCache,
-		executor:          resourceexecutor.NewResourceUpdateExecutor(),
+		executor:          opt.Config.NewStrategyExecutor(BlkIOReconcileName),
 	}
 }
 
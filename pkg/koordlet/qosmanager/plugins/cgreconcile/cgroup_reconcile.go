@@ -17,7 +17,6 @@ limitations under the License.
 package cgreconcile
 
 import (
-	"math"
 	"strconv"
 	"time"
 
@@ -37,7 +36,6 @@ import (
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
-	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
 
 const (
@@ -76,7 +74,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 	return &cgroupResourcesReconcile{
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
 		statesInformer:    opt.StatesInformer,
-		executor:          resourceexecutor.NewResourceUpdateExecutor(),
+		executor:          opt.Config.NewStrategyExecutor(CgroupReconcileName),
 	}
 }
 
@@ -290,61 +288,22 @@ func (m *cgroupResourcesReconcile) calculateContainerResources(container *corev1
 	summary := &cgroupResourceSummary{}
 
 	// Mem QoS
-	// resources statically use configured values
 	if podCfg.MemoryQOS != nil {
-		summary.memoryWmarkRatio = podCfg.MemoryQOS.WmarkRatio
-		summary.memoryWmarkScaleFactor = podCfg.MemoryQOS.WmarkScalePermill
-		summary.memoryWmarkMinAdj = podCfg.MemoryQOS.WmarkMinAdj
-		summary.memoryUsePriorityOom = podCfg.MemoryQOS.PriorityEnable
-		summary.memoryPriority = podCfg.MemoryQOS.Priority
-		summary.memoryOomKillGroup = podCfg.MemoryQOS.OomKillGroup
-		// resources calculated with container spec
-		var memRequest int64
-		var memLimit int64
-		if apiext.GetPodQoSClassRaw(pod) != apiext.QoSBE {
-			memRequest = container.Resources.Requests.Memory().Value()
-			memLimit = util.GetContainerMemoryByteLimit(container)
-		} else {
-			memRequest = util.GetContainerBatchMemoryByteRequest(container)
-			memLimit = util.GetContainerBatchMemoryByteLimit(container)
-		}
-		if memRequest < 0 {
-			// when container request not set, memory request is counted as zero but not unlimited(-1)
-			memRequest = 0
-		}
-		// memory.min, memory.low: if container's memory request is not set, just consider it as zero
-		if podCfg.MemoryQOS.MinLimitPercent != nil {
-			summary.memoryMin = pointer.Int64(memRequest * (*podCfg.MemoryQOS.MinLimitPercent) / 100)
-		}
-		if podCfg.MemoryQOS.LowLimitPercent != nil {
-			summary.memoryLow = pointer.Int64(memRequest * (*podCfg.MemoryQOS.LowLimitPercent) / 100)
-		}
-		// memory.high: if container's memory throttling factor is set as zero, disable memory.high by set to maximal;
-		// else if factor is set while container's limit not set, set memory.high with node memory allocatable
-		if podCfg.MemoryQOS.ThrottlingPercent != nil {
-			if *podCfg.MemoryQOS.ThrottlingPercent == 0 { // reset to system default if set 0
-				summary.memoryHigh = pointer.Int64(math.MaxInt64) // writing MaxInt64 is equal to write "max"
-			} else if memLimit > 0 {
-				summary.memoryHigh = pointer.Int64(((memRequest + (memLimit-memRequest)*(*podCfg.MemoryQOS.ThrottlingPercent)/100) / system.PageSize) * system.PageSize)
-			} else {
-				nodeLimit := node.Status.Allocatable.Memory().Value()
-				summary.memoryHigh = pointer.Int64(((memRequest + (nodeLimit-memRequest)*(*podCfg.MemoryQOS.ThrottlingPercent)/100) / system.PageSize) * system.PageSize)
-			}
-		}
-		// values improved: memory.low is no less than memory.min
-		if summary.memoryMin != nil && summary.memoryLow != nil && *summary.memoryLow > 0 &&
-			*summary.memoryLow < *summary.memoryMin {
-			*summary.memoryLow = *summary.memoryMin
-			klog.V(5).Infof("correct calculated memory.low for container since it is lower than memory.min,"+
-				" pod %s, container %s, current value %v", util.GetPodKey(pod), container.Name, *summary.memoryLow)
-		}
-		// values improved: memory.high is no less than memory.min
-		if summary.memoryHigh != nil && summary.memoryMin != nil && *summary.memoryHigh > 0 &&
-			*summary.memoryHigh < *summary.memoryMin {
-			*summary.memoryHigh = *summary.memoryMin
-			klog.V(5).Infof("correct calculated memory.high for container since it is lower than memory.min,"+
-				" pod %s, container %s, current value %v", util.GetPodKey(pod), container.Name, *summary.memoryHigh)
+		var nodeAllocatable int64
+		if node != nil {
+			nodeAllocatable = node.Status.Allocatable.Memory().Value()
 		}
+		containerMemoryQOS := koordletutil.CalculateContainerMemoryQOS(pod, container, &podCfg.MemoryQOS.MemoryQOS,
+			nodeAllocatable)
+		summary.memoryMin = containerMemoryQOS.MemoryMin
+		summary.memoryLow = containerMemoryQOS.MemoryLow
+		summary.memoryHigh = containerMemoryQOS.MemoryHigh
+		summary.memoryWmarkRatio = containerMemoryQOS.WmarkRatio
+		summary.memoryWmarkScaleFactor = containerMemoryQOS.WmarkScaleFactor
+		summary.memoryWmarkMinAdj = containerMemoryQOS.WmarkMinAdj
+		summary.memoryUsePriorityOom = containerMemoryQOS.UsePriorityOom
+		summary.memoryPriority = containerMemoryQOS.Priority
+		summary.memoryOomKillGroup = containerMemoryQOS.OomKillGroup
 	}
 
 	return makeCgroupResources(parentDir, summary)
@@ -367,54 +326,7 @@ func (m *cgroupResourcesReconcile) getMergedPodResourceQoS(pod *corev1.Pod, cfg
 // mergePodResourceQoSForMemoryQoS merges pod-level memory qos config with node-level resource qos config
 // config overwrite: pod-level config > pod policy template > node-level config
 func (m *cgroupResourcesReconcile) mergePodResourceQoSForMemoryQoS(pod *corev1.Pod, cfg *slov1alpha1.ResourceQOS) {
-	// get the pod-level config and determine if the pod is allowed
-	if cfg.MemoryQOS == nil {
-		cfg.MemoryQOS = &slov1alpha1.MemoryQOSCfg{}
-	}
-	policy := slov1alpha1.PodMemoryQOSPolicyDefault
-
-	// get pod-level config
-	podCfg, err := slov1alpha1.GetPodMemoryQoSConfig(pod)
-	if err != nil { // ignore pod-level memory qos config when parse error
-		klog.Errorf("failed to parse memory qos config, pod %s, err: %s", util.GetPodKey(pod), err)
-		podCfg = nil
-	}
-
-	if podCfg == nil {
-		var greyCtlMemoryQOSCfgIf interface{} = &slov1alpha1.PodMemoryQOSConfig{}
-		injected := framework.InjectQOSGreyCtrlPlugins(pod, framework.QOSPolicyMemoryQOS, &greyCtlMemoryQOSCfgIf)
-		if greyCtlMemoryQOSCfg, ok := greyCtlMemoryQOSCfgIf.(*slov1alpha1.PodMemoryQOSConfig); ok && injected {
-			podCfg = greyCtlMemoryQOSCfg
-		}
-	}
-
-	if podCfg != nil {
-		policy = podCfg.Policy // policy="" is equal to policy="default"
-	}
-	klog.V(5).Infof("memory qos podPolicy=%s for pod %s", policy, util.GetPodKey(pod))
-
-	// if policy is not default, replace memory qos config with the policy template
-	if policy == slov1alpha1.PodMemoryQOSPolicyNone { // fully disable memory qos for policy=None
-		cfg.MemoryQOS.MemoryQOS = *sloconfig.NoneMemoryQOS()
-		cfg.MemoryQOS.Enable = pointer.Bool(false)
-		return
-	} else if policy == slov1alpha1.PodMemoryQOSPolicyAuto { // qos=None would be set with kubeQoS for policy=Auto
-		cfg.MemoryQOS.MemoryQOS = helpers.GetPodResourceQoSByQoSClass(pod, sloconfig.DefaultResourceQOSStrategy()).MemoryQOS.MemoryQOS
-	}
-
-	// no need to merge config if pod-level config is nil
-	if podCfg == nil {
-		return
-	}
-	// otherwise detailed pod-level config is specified, merge with node-level config for the pod
-	merged, err := util.MergeCfg(&cfg.MemoryQOS.MemoryQOS, &podCfg.MemoryQOS) // node config has been deep-copied
-	if err != nil {
-		// not change memory qos config if merge error
-		klog.Errorf("failed to merge memory qos config with node config, pod %s, err: %s", util.GetPodKey(pod), err)
-		return
-	}
-	cfg.MemoryQOS.MemoryQOS = *merged.(*slov1alpha1.MemoryQOS)
-	klog.V(6).Infof("get merged memory qos %v", util.DumpJSON(cfg.MemoryQOS))
+	koordletutil.MergePodMemoryQOS(pod, cfg, koordletutil.GetPodMemoryQOSConfig(pod))
 }
 
 // updateCgroupSummaryForQoS updates qos cgroup summary by pod to summarize qos-level cgroup according to belonging pods
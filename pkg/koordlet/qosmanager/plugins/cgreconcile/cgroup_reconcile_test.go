@@ -32,6 +32,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
@@ -45,6 +46,7 @@ import (
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/cache"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
@@ -461,6 +463,76 @@ func Test_calculateAndUpdateResources(t *testing.T) {
 	}
 }
 
+func Test_calculateAndUpdateResources_partialFailure(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+	}
+	qosStrategy := testutil.DefaultQOSStrategy()
+	qosStrategy.LSClass.MemoryQOS.MinLimitPercent = pointer.Int64(100)
+	newTestPod := func(name string) *statesinformer.PodMeta {
+		podMeta := testutil.MockTestPodWithQOS(corev1.PodQOSBurstable, apiext.QoSLS)
+		podMeta.Pod.Name = name
+		podMeta.Pod.UID = types.UID(name)
+		for i := range podMeta.Pod.Status.ContainerStatuses {
+			podMeta.Pod.Status.ContainerStatuses[i].ContainerID = fmt.Sprintf("docker://%s-%s", name, podMeta.Pod.Status.ContainerStatuses[i].Name)
+		}
+		podMeta.CgroupDir = koordletutil.GetPodCgroupParentDir(podMeta.Pod)
+		return podMeta
+	}
+	getContainerDir := func(podMeta *statesinformer.PodMeta, containerName string) string {
+		_, containerStatus, err := util.FindContainerIdAndStatusByName(&podMeta.Pod.Status, containerName)
+		assert.NoError(t, err)
+		containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStatus)
+		assert.NoError(t, err)
+		return containerDir
+	}
+	podA, podB := newTestPod("pod-a"), newTestPod("pod-b")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetNode().Return(testingNode).AnyTimes()
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{podA, podB}).AnyTimes()
+	reconciler := newTestCgroupResourcesReconcile(&framework.Options{
+		StatesInformer: statesInformer,
+		Config:         framework.NewDefaultConfig(),
+	})
+	stop := make(chan struct{})
+	defer close(stop)
+	reconciler.init(stop)
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetAnolisOSResourcesSupported(true)
+	initQOSCgroupFile(testutil.DefaultQOSStrategy(), helper)
+	for _, podMeta := range []*statesinformer.PodMeta{podA, podB} {
+		writeMemToCgroupFile(podMeta.CgroupDir, qosStrategy.LSClass, helper)
+		for _, containerName := range []string{"test", "main"} {
+			writeMemToCgroupFile(getContainerDir(podMeta, containerName), qosStrategy.LSClass, helper)
+		}
+	}
+	// the memory.min of a container in pod-b cannot be written
+	failedPath := system.MemoryMin.Path(getContainerDir(podB, "main"))
+	assert.NoError(t, os.Remove(failedPath))
+	assert.NoError(t, os.MkdirAll(failedPath, 0755))
+
+	reconciler.calculateAndUpdateResources(createNodeSLOWithQOSStrategy(qosStrategy))
+
+	// the failure in pod-b does not roll back the updates of the other pods and containers
+	wantMemoryMin := strconv.FormatInt(testingPodMemRequestLimitBytes, 10)
+	assert.Equal(t, wantMemoryMin, helper.ReadCgroupFileContents(podA.CgroupDir, system.MemoryMin))
+	assert.Equal(t, wantMemoryMin, helper.ReadCgroupFileContents(getContainerDir(podA, "main"), system.MemoryMin))
+	assert.Equal(t, wantMemoryMin, helper.ReadCgroupFileContents(podB.CgroupDir, system.MemoryMin))
+}
+
 func TestCgroupResourceReconcile_calculateResources(t *testing.T) {
 	testingPodLS := testutil.MockTestPodWithQOS(corev1.PodQOSBurstable, apiext.QoSLS)
 	podParentDirLS := testingPodLS.CgroupDir
@@ -115,6 +115,8 @@ func (s nodeStateForBurst) String() string {
 type burstLimiter struct {
 	bucketCapacity int64
 	currentToken   int64
+	// usedToken is the burst actually consumed and not yet saved back, which is not bounded by the bucket capacity
+	usedToken      int64
 	lastUpdateTime time.Time
 	expireDuration time.Duration
 }
@@ -132,6 +134,7 @@ func (l *burstLimiter) init(burstPeriodSec, maxScalePercent int64) {
 	initSize := float64(capacity) * randomInitRatio
 	l.bucketCapacity = capacity
 	l.currentToken = int64(initSize)
+	l.usedToken = 0
 	l.lastUpdateTime = time.Now()
 	l.expireDuration = time.Duration(2*burstPeriodSec) * time.Second
 }
@@ -141,9 +144,11 @@ func (l *burstLimiter) Allow(now time.Time, currentUsageScalePercent int64) (boo
 	if currentUsageScalePercent >= cpuThresholdPercentForLimiterConsumeTokens {
 		needToken := (currentUsageScalePercent - 100) * int64(timePastSec)
 		l.currentToken -= needToken
+		l.usedToken += needToken
 	} else if currentUsageScalePercent < cpuThresholdPercentForLimiterSavingTokens {
 		saveToken := (100 - currentUsageScalePercent) * int64(timePastSec)
 		l.currentToken += saveToken
+		l.usedToken = util.MaxInt64(l.usedToken-saveToken, 0)
 	}
 	l.currentToken = util.MaxInt64(util.MinInt64(l.currentToken, l.bucketCapacity), -l.bucketCapacity)
 	l.lastUpdateTime = now
@@ -162,6 +167,13 @@ func (l *burstLimiter) Expire() bool {
 	return time.Since(l.lastUpdateTime) > l.expireDuration
 }
 
+// Budget returns the burst budget used and allowed in seconds, where one second of budget means running at twice the
+// cpu limit for one second. The used budget is accounted from the burst consumed since the limiter is initialized,
+// so it can exceed the allowed when the container keeps bursting after the limiter disallows it.
+func (l *burstLimiter) Budget() (used, allowed float64) {
+	return float64(l.usedToken) / 100, float64(l.bucketCapacity) / 100
+}
+
 var _ framework.QOSStrategy = &cpuBurst{}
 
 type cpuBurst struct {
@@ -173,6 +185,8 @@ type cpuBurst struct {
 	cgroupReader          resourceexecutor.CgroupReader
 	nodeCPUBurstStrategy  *slov1alpha1.CPUBurstStrategy
 	containerLimiter      map[string]*burstLimiter
+	// budgetPods records the pods whose burst budget is reported, so that the metrics are deleted when they are gone
+	budgetPods map[string]*corev1.Pod
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -181,9 +195,10 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
 		statesInformer:        opt.StatesInformer,
 		metricCache:           opt.MetricCache,
-		executor:              resourceexecutor.NewResourceUpdateExecutor(),
+		executor:              opt.Config.NewStrategyExecutor(CPUBurstName),
 		cgroupReader:          opt.CgroupReader,
 		containerLimiter:      make(map[string]*burstLimiter),
+		budgetPods:            make(map[string]*corev1.Pod),
 	}
 }
 
@@ -216,6 +231,8 @@ func (b *cpuBurst) start() {
 	}
 	b.nodeCPUBurstStrategy = nodeSLO.Spec.CPUBurstStrategy
 	podsMeta := b.statesInformer.GetAllPods()
+	budgetSamples := make([]metriccache.MetricSample, 0)
+	budgetPods := make(map[string]*corev1.Pod)
 
 	// get node state by node share pool usage
 	nodeState := b.getNodeStateForBurst(*b.nodeCPUBurstStrategy.SharePoolThresholdPercent, podsMeta)
@@ -250,10 +267,81 @@ func (b *cpuBurst) start() {
 		b.applyCPUBurst(cpuBurstCfg, podMeta)
 		// scale cpu.cfs_quota_us for pod and containers
 		b.applyCFSQuotaBurst(cpuBurstCfg, podMeta, nodeState)
+		// account the burst budget consumed by the pod
+		if samples := b.collectPodBurstBudget(podMeta.Pod); len(samples) > 0 {
+			budgetSamples = append(budgetSamples, samples...)
+			budgetPods[string(podMeta.Pod.UID)] = podMeta.Pod
+		}
 	}
+	b.appendBurstBudgetMetrics(budgetSamples)
+	b.deleteStaleBurstBudget(budgetPods)
 	b.Recycle()
 }
 
+// deleteStaleBurstBudget deletes the burst budget metrics of the pods which are reported in the last round but not in
+// the current one, e.g. the pods are deleted or no longer limited.
+func (b *cpuBurst) deleteStaleBurstBudget(budgetPods map[string]*corev1.Pod) {
+	for uid, pod := range b.budgetPods {
+		if _, ok := budgetPods[uid]; !ok {
+			metrics.DeletePodCPUBurstBudgetSeconds(pod.Namespace, pod.Name, uid)
+		}
+	}
+	b.budgetPods = budgetPods
+}
+
+// collectPodBurstBudget sums up the burst budget of containers limited by the cfs quota burst limiter,
+// records it and returns the metric samples. Nothing is returned if no container of the pod is limited.
+func (b *cpuBurst) collectPodBurstBudget(pod *corev1.Pod) []metriccache.MetricSample {
+	var podUsed, podAllowed float64
+	limited := false
+	for i := range pod.Status.ContainerStatuses {
+		limiter, exist := b.containerLimiter[pod.Status.ContainerStatuses[i].ContainerID]
+		if !exist {
+			continue
+		}
+		used, allowed := limiter.Budget()
+		podUsed += used
+		podAllowed += allowed
+		limited = true
+	}
+	if !limited {
+		return nil
+	}
+	klog.V(5).Infof("pod %s/%s cpu burst budget used %v seconds, allowed %v seconds",
+		pod.Namespace, pod.Name, podUsed, podAllowed)
+	metrics.RecordPodCPUBurstBudgetSeconds(pod.Namespace, pod.Name, string(pod.UID), podUsed, podAllowed)
+
+	now := time.Now()
+	usedSample, err := metriccache.PodCPUBurstUsedSecondsMetric.GenerateSample(
+		metriccache.MetricPropertiesFunc.Pod(string(pod.UID)), now, podUsed)
+	if err != nil {
+		klog.Warningf("generate pod %s/%s cpu burst used metric failed, error: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+	allowedSample, err := metriccache.PodCPUBurstAllowedSecondsMetric.GenerateSample(
+		metriccache.MetricPropertiesFunc.Pod(string(pod.UID)), now, podAllowed)
+	if err != nil {
+		klog.Warningf("generate pod %s/%s cpu burst allowed metric failed, error: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+	return []metriccache.MetricSample{usedSample, allowedSample}
+}
+
+func (b *cpuBurst) appendBurstBudgetMetrics(samples []metriccache.MetricSample) {
+	if len(samples) <= 0 {
+		return
+	}
+	appender := b.metricCache.Appender()
+	if err := appender.Append(samples); err != nil {
+		klog.Warningf("append cpu burst budget metrics failed, error: %v", err)
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("commit cpu burst budget metrics failed, error: %v", err)
+		return
+	}
+}
+
 // getNodeStateForBurst checks whether node share pool cpu usage beyonds the threshold
 // return isOverload, share pool usage ratio and message detail
 func (b *cpuBurst) getNodeStateForBurst(sharePoolThresholdPercent int64,
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -37,6 +38,7 @@ import (
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
@@ -700,6 +702,37 @@ func TestCPUBurst_applyCPUBurst(t *testing.T) {
 	}
 }
 
+func TestCPUBurst_applyCPUBurstCgroupV2(t *testing.T) {
+	testHelper := system.NewFileTestUtil(t)
+	defer testHelper.Cleanup()
+	testHelper.WriteCgroupFileContents(system.CgroupPathFormatter.ParentDir, system.CPUBurstV2, "0")
+
+	b := &cpuBurst{
+		executor: newTestExecutor(),
+	}
+	stop := make(chan struct{})
+	b.init(stop)
+	defer func() { stop <- struct{}{} }()
+
+	podMeta := createPodMetaByResource("test-pod-1", map[string]corev1.ResourceRequirements{
+		"test-container-1": {
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewMilliQuantity(2000, resource.DecimalSI),
+			},
+		},
+	})
+	containerStat := &podMeta.Pod.Status.ContainerStatuses[0]
+	containerPath, _ := util.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+	testHelper.WriteCgroupFileContents(podMeta.CgroupDir, system.CPUBurstV2, "0")
+	testHelper.WriteCgroupFileContents(containerPath, system.CPUBurstV2, "0")
+
+	b.applyCPUBurst(&defaultAutoBurstCfg, podMeta)
+
+	wantBurst := strconv.FormatInt(2*10*system.CFSBasePeriodValue, 10)
+	assert.Equal(t, wantBurst, testHelper.ReadCgroupFileContents(containerPath, system.CPUBurstV2))
+	assert.Equal(t, wantBurst, testHelper.ReadCgroupFileContents(podMeta.CgroupDir, system.CPUBurstV2))
+}
+
 func TestCPUBurst_applyCFSQuotaBurst(t *testing.T) {
 	testPodName1 := "test-pod-1"
 	testContainerName1 := "test-container-1"
@@ -1453,6 +1486,96 @@ func Test_burstLimiter_UpdateIfChanged(t *testing.T) {
 	}
 }
 
+func Test_burstLimiter_Budget(t *testing.T) {
+	now := time.Now()
+	l := &burstLimiter{
+		bucketCapacity: 6000,
+		currentToken:   3000,
+		lastUpdateTime: now,
+	}
+	// never burst
+	gotUsed, gotAllowed := l.Budget()
+	assert.Equal(t, float64(0), gotUsed)
+	assert.Equal(t, float64(60), gotAllowed)
+
+	// burst at 150% for 60 seconds
+	now = now.Add(60 * time.Second)
+	l.Allow(now, 150)
+	gotUsed, _ = l.Budget()
+	assert.Equal(t, float64(30), gotUsed)
+
+	// keep bursting after the bucket is exhausted, the debt is still accounted
+	now = now.Add(60 * time.Second)
+	l.Allow(now, 200)
+	gotUsed, _ = l.Budget()
+	assert.Equal(t, float64(90), gotUsed)
+
+	// save tokens at 50% for 60 seconds
+	now = now.Add(60 * time.Second)
+	l.Allow(now, 50)
+	gotUsed, _ = l.Budget()
+	assert.Equal(t, float64(60), gotUsed)
+
+	// saved back completely
+	now = now.Add(300 * time.Second)
+	l.Allow(now, 0)
+	gotUsed, _ = l.Budget()
+	assert.Equal(t, float64(0), gotUsed)
+}
+
+func TestCPUBurst_collectPodBurstBudget(t *testing.T) {
+	pod := newTestPodWithQOS("test-pod-1", apiext.QoSLS, 2000, 2000)
+	containerID := genTestDefaultContainerIDByPod("test-pod-1")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	mockAppender := mock_metriccache.NewMockAppender(ctrl)
+	mockMetricCache.EXPECT().Appender().Return(mockAppender).Times(1)
+	mockAppender.EXPECT().Append(gomock.Any()).DoAndReturn(func(samples []metriccache.MetricSample) error {
+		assert.Equal(t, 2, len(samples))
+		assert.Equal(t, string(metriccache.PodMetricCPUBurstUsedSeconds), samples[0].GetKind())
+		assert.Equal(t, string(metriccache.PodMetricCPUBurstAllowedSeconds), samples[1].GetKind())
+		assert.Equal(t, string(pod.UID), samples[0].GetProperties()[string(metriccache.MetricPropertyPodUID)])
+		return nil
+	}).Times(1)
+	mockAppender.EXPECT().Commit().Return(nil).Times(1)
+
+	b := &cpuBurst{
+		metricCache:      mockMetricCache,
+		containerLimiter: map[string]*burstLimiter{},
+	}
+	// no container is limited
+	samples := b.collectPodBurstBudget(pod)
+	assert.Nil(t, samples)
+	b.appendBurstBudgetMetrics(samples)
+
+	b.containerLimiter[containerID] = &burstLimiter{
+		bucketCapacity: 6000,
+		currentToken:   5000,
+		usedToken:      1000,
+	}
+	samples = b.collectPodBurstBudget(pod)
+	b.appendBurstBudgetMetrics(samples)
+}
+
+func TestCPUBurst_deleteStaleBurstBudget(t *testing.T) {
+	testingNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+	metrics.Register(testingNode)
+	defer metrics.Register(nil)
+	pod := newTestPodWithQOS("test-pod-1", apiext.QoSLS, 2000, 2000)
+	metrics.RecordPodCPUBurstBudgetSeconds(pod.Namespace, pod.Name, string(pod.UID), 10, 60)
+
+	b := &cpuBurst{}
+	b.deleteStaleBurstBudget(map[string]*corev1.Pod{string(pod.UID): pod})
+	assert.Equal(t, 1, promtestutil.CollectAndCount(metrics.PodCPUBurstUsedSeconds))
+
+	// the pod is gone
+	b.deleteStaleBurstBudget(map[string]*corev1.Pod{})
+	assert.Equal(t, 0, promtestutil.CollectAndCount(metrics.PodCPUBurstUsedSeconds))
+	assert.Equal(t, 0, promtestutil.CollectAndCount(metrics.PodCPUBurstAllowedSeconds))
+}
+
 func TestCPUBurst_start(t *testing.T) {
 	lsrPodName := "lsr-pod-1"
 	lsPodName := "ls-pod-2"
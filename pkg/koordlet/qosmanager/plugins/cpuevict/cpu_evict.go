@@ -57,6 +57,7 @@ type cpuEvictor struct {
 	statesInformer        statesinformer.StatesInformer
 	metricCache           metriccache.MetricCache
 	evictor               *framework.Evictor
+	dryRun                bool
 	lastEvictTime         time.Time
 }
 
@@ -68,6 +69,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		statesInformer:        opt.StatesInformer,
 		metricCache:           opt.MetricCache,
 		lastEvictTime:         time.Now(),
+		dryRun:                opt.Config.IsDryRun(CPUEvictName),
 	}
 }
 
@@ -298,16 +300,17 @@ func (c *cpuEvictor) killAndEvictBEPodsRelease(node *corev1.Node, bePodInfos []*
 			break
 		}
 
-		podKillMsg := fmt.Sprintf("%s, kill pod: %s", message, util.GetPodKey(bePod.pod))
-		helpers.KillContainers(bePod.pod, podKillMsg)
-
 		killedPods = append(killedPods, bePod.pod)
 		cpuMilliReleased = cpuMilliReleased + bePod.milliRequest
 
 		klog.V(5).Infof("cpuEvict pick pod %s/%s to evict", util.GetPodKey(bePod.pod))
 	}
 
-	c.evictor.EvictPodsIfNotEvicted(killedPods, node, resourceexecutor.EvictPodByBECPUSatisfaction, message)
+	if c.dryRun {
+		c.evictor.DryRunEvictPods(CPUEvictName, killedPods, resourceexecutor.EvictPodByBECPUSatisfaction, message)
+	} else {
+		c.evictor.KillAndEvictPodsIfNotEvicted(killedPods, node, resourceexecutor.EvictPodByBECPUSatisfaction, message)
+	}
 
 	if len(killedPods) > 0 {
 		c.lastEvictTime = time.Now()
@@ -589,6 +589,36 @@ func Test_killAndEvictBEPodsRelease(t *testing.T) {
 
 }
 
+func Test_killAndEvictBEPodsRelease_dryRun(t *testing.T) {
+	podEvictInfosSorted := []*podEvictCPUInfo{
+		{
+			pod:          mockBEPodForCPUEvict("pod_be_1_priority100", 16*1000, 100),
+			milliRequest: 16 * 1000,
+		},
+	}
+	fakeRecorder := &testutil.FakeRecorder{}
+	client := clientsetfake.NewSimpleClientset()
+	stop := make(chan struct{})
+	evictor := framework.NewEvictor(client, fakeRecorder, policyv1beta1.SchemeGroupVersion.Version)
+	evictor.Start(stop)
+	defer func() { stop <- struct{}{} }()
+	pod := podEvictInfosSorted[0].pod
+	_, _ = client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+
+	cpuEvictor := &cpuEvictor{
+		evictor:       evictor,
+		lastEvictTime: time.Now().Add(-5 * time.Minute),
+		dryRun:        true,
+	}
+	cpuEvictor.killAndEvictBEPodsRelease(testutil.MockTestNode("100", "500G"), podEvictInfosSorted, 8*1000)
+
+	got, err := client.Tracker().Get(testutil.PodsResource, pod.Namespace, pod.Name)
+	assert.NoError(t, err)
+	assert.IsType(t, &corev1.Pod{}, got, "pod should not be evicted in dry-run mode")
+	// the cooling time still applies
+	assert.True(t, cpuEvictor.lastEvictTime.After(time.Now().Add(-5*time.Second)), "checkLastTime")
+}
+
 func Test_isSatisfactionConfigValid(t *testing.T) {
 	tests := []struct {
 		name            string
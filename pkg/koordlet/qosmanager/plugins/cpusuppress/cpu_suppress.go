@@ -20,9 +20,11 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -77,6 +79,16 @@ type CPUSuppress struct {
 	executor               resourceexecutor.ResourceUpdateExecutor
 	cgroupReader           resourceexecutor.CgroupReader
 	suppressPolicyStatuses map[string]suppressPolicyStatus
+
+	beCPULimitLock sync.RWMutex
+	beCPULimit     *beCPULimit
+}
+
+// beCPULimit is the limit of BE cpus set by other strategies, e.g. the interference detection, which is applied in
+// addition to the suppression of the node usage threshold.
+type beCPULimit struct {
+	quantity resource.Quantity
+	policy   slov1alpha1.CPUSuppressPolicy
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -85,7 +97,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		metricCollectInterval:  opt.MetricAdvisorConfig.CollectResUsedInterval,
 		statesInformer:         opt.StatesInformer,
 		metricCache:            opt.MetricCache,
-		executor:               resourceexecutor.NewResourceUpdateExecutor(),
+		executor:               opt.Config.NewStrategyExecutor(CPUSuppressName),
 		cgroupReader:           opt.CgroupReader,
 		suppressPolicyStatuses: map[string]suppressPolicyStatus{},
 	}
@@ -104,6 +116,39 @@ func (r *CPUSuppress) Run(stopCh <-chan struct{}) {
 	go wait.Until(r.suppressBECPU, r.interval, stopCh)
 }
 
+// SetBECPULimit limits the cpus of BE pods no more than the quantity with the suppress policy until it is cleared.
+func (r *CPUSuppress) SetBECPULimit(quantity resource.Quantity, policy slov1alpha1.CPUSuppressPolicy) {
+	r.beCPULimitLock.Lock()
+	defer r.beCPULimitLock.Unlock()
+	r.beCPULimit = &beCPULimit{quantity: quantity, policy: policy}
+}
+
+// ClearBECPULimit clears the limit set by SetBECPULimit.
+func (r *CPUSuppress) ClearBECPULimit() {
+	r.beCPULimitLock.Lock()
+	defer r.beCPULimitLock.Unlock()
+	r.beCPULimit = nil
+}
+
+// applyBECPULimit returns the less one of the suppress quantity and the limit, and the policy of the limit if set.
+func (r *CPUSuppress) applyBECPULimit(suppressCPUQuantity *resource.Quantity, policy slov1alpha1.CPUSuppressPolicy) (*resource.Quantity, slov1alpha1.CPUSuppressPolicy) {
+	r.beCPULimitLock.RLock()
+	defer r.beCPULimitLock.RUnlock()
+	if r.beCPULimit == nil {
+		return suppressCPUQuantity, policy
+	}
+	if r.beCPULimit.quantity.Cmp(*suppressCPUQuantity) < 0 {
+		limitQuantity := r.beCPULimit.quantity.DeepCopy()
+		klog.V(4).Infof("suppressBECPU: limit be cpu from %v to %v by policy %v",
+			suppressCPUQuantity.String(), limitQuantity.String(), r.beCPULimit.policy)
+		suppressCPUQuantity = &limitQuantity
+	}
+	if r.beCPULimit.policy != "" {
+		policy = r.beCPULimit.policy
+	}
+	return suppressCPUQuantity, policy
+}
+
 func (r *CPUSuppress) init(stopCh <-chan struct{}) {
 	r.executor.Run(stopCh)
 }
@@ -136,6 +181,52 @@ func (r *CPUSuppress) writeBECgroupsCPUSet(paths []string, cpusetStr string, isR
 	r.executor.UpdateBatch(true, updaters...)
 }
 
+// writeBECgroupsCPUSetByPod writes the be cgroups cpuset, where the first path is the be qos-level cgroup and the
+// others are the pod-level and container-level cgroups. The qos-level cgroup is loosened before the pods and tightened
+// after them, while each pod and its containers are updated in a transaction, so a failed pod does not roll back the
+// others.
+func (r *CPUSuppress) writeBECgroupsCPUSetByPod(paths []string, cpusetStr string, mergedCPUSetStr string) error {
+	if len(paths) <= 0 {
+		return nil
+	}
+	qosPath := paths[0]
+	r.writeBECgroupsCPUSet([]string{qosPath}, mergedCPUSetStr, false)
+
+	eventHelper := audit.V(3).Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cpuset: %v", cpusetStr)
+	var podPaths []string
+	podUpdaters := map[string][][]resourceexecutor.ResourceUpdater{}
+	for _, path := range paths[1:] {
+		u, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUSetCPUSName, path, cpusetStr, eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to get cpuset updater: path %s, err %s", path, err)
+			continue
+		}
+		podPath, level := path, 0
+		if parentDir := filepath.Dir(path); parentDir != qosPath {
+			podPath, level = parentDir, 1
+		}
+		if _, ok := podUpdaters[podPath]; !ok {
+			podUpdaters[podPath] = make([][]resourceexecutor.ResourceUpdater, 2)
+			podPaths = append(podPaths, podPath)
+		}
+		podUpdaters[podPath][level] = append(podUpdaters[podPath][level], u)
+	}
+
+	var lastErr error
+	for _, podPath := range podPaths {
+		if err := r.executor.UpdateTransaction(podUpdaters[podPath]); err != nil {
+			klog.V(4).Infof("failed to update cpuset for be pod %s, err: %s", podPath, err)
+			lastErr = fmt.Errorf("update cpuset for be pod %s failed, err: %w", podPath, err)
+		}
+	}
+	if lastErr != nil {
+		// keep the qos-level cpuset loosened since the failed pods may still use the old cpuset
+		return lastErr
+	}
+	r.writeBECgroupsCPUSet([]string{qosPath}, cpusetStr, false)
+	return nil
+}
+
 // calculateBESuppressCPU calculates the quantity of cpuset cpus for suppressing be pods
 func (r *CPUSuppress) calculateBESuppressCPU(node *corev1.Node, nodeMetric float64,
 	podMetrics map[string]float64, podMetas []*statesinformer.PodMeta, beCPUUsedThreshold int64) *resource.Quantity {
@@ -219,6 +310,8 @@ func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32)
 	// 1. get current be cgroups cpuset
 	// 2. temporarily write with a union of old cpuset and new cpuset from upper to lower, to avoid cgroup conflicts
 	// 3. write with the new cpuset from lower to upper to apply the real policy
+	// the cpuset of each pod and its containers are updated in a transaction, which rolls back the pod if any write
+	// fails (e.g. EBUSY), so the cpuset of the containers keeps a subset of the pod's
 	if len(cpus) <= 0 {
 		klog.Warningf("applyCPUSetWithNonePolicy skipped due to the empty cpuset")
 		return nil
@@ -230,18 +323,14 @@ func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32)
 		return fmt.Errorf("apply be suppress policy failed, err: %s", err)
 	}
 
-	// write a loose cpuset for all be cgroups before applying the real policy
-	mergedCPUSet := cpuset.MergeCPUSet(oldCPUSet, cpus)
-	mergedCPUSetStr := cpuset.GenerateCPUSetStr(mergedCPUSet)
-	klog.V(6).Infof("applyCPUSetWithNonePolicy temporarily writes cpuset from upper cgroup to lower, cpuset %v",
-		mergedCPUSet)
-	r.writeBECgroupsCPUSet(cpusetCgroupPaths, mergedCPUSetStr, false)
-
-	// apply the suppress policy from lower to upper
 	cpusetStr := cpuset.GenerateCPUSetStr(cpus)
-	klog.V(6).Infof("applyCPUSetWithNonePolicy writes suppressed cpuset from lower cgroup to upper, cpuset %v",
-		cpus)
-	r.writeBECgroupsCPUSet(cpusetCgroupPaths, cpusetStr, true)
+	mergedCPUSetStr := cpuset.GenerateCPUSetStr(cpuset.MergeCPUSet(oldCPUSet, cpus))
+	klog.V(6).Infof("applyCPUSetWithNonePolicy writes suppressed cpuset by pods, cpuset %v, old %v",
+		cpus, oldCPUSet)
+	if err = r.writeBECgroupsCPUSetByPod(cpusetCgroupPaths, cpusetStr, mergedCPUSetStr); err != nil {
+		klog.Warningf("applyCPUSetWithNonePolicy failed to write be cgroups cpuset, err: %s", err)
+		return fmt.Errorf("apply be suppress policy failed, err: %w", err)
+	}
 	metrics.RecordBESuppressCores(string(slov1alpha1.CPUSetPolicy), float64(len(cpus)))
 	return nil
 }
@@ -336,7 +425,8 @@ func (r *CPUSuppress) suppressBECPU() {
 	if !ok {
 		klog.Fatalf("type error, expect %T， but got %T", metriccache.NodeCPUInfo{}, nodeCPUInfoRaw)
 	}
-	if nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
+	suppressCPUQuantity, suppressPolicy := r.applyBECPULimit(suppressCPUQuantity, nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy)
+	if suppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
 		r.adjustByCfsQuota(suppressCPUQuantity, node)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyUsing
 		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
@@ -18,6 +18,7 @@ package cpusuppress
 
 import (
 	"encoding/json"
+	"os"
 	"path/filepath"
 	"strconv"
 	"testing"
@@ -1345,6 +1346,33 @@ func Test_cpuSuppress_applyCPUSetWithNonePolicy(t *testing.T) {
 	}
 }
 
+func Test_cpuSuppress_applyCPUSetWithNonePolicy_podFailed(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	podDirs := []string{"pod1", "pod2", "pod3"}
+	testingPrepareBECgroupData(helper, podDirs, "1,2")
+	// the cpuset of pod2 cannot be written
+	beQOSDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	failedPath := system.CPUSet.Path(filepath.Join(beQOSDir, "pod2"))
+	assert.NoError(t, os.Remove(failedPath))
+	assert.NoError(t, os.MkdirAll(failedPath, 0755))
+
+	opt := &framework.Options{
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}
+	r := newTestCPUSuppress(opt)
+	stop := make(chan struct{})
+	defer close(stop)
+	r.init(stop)
+
+	err := r.applyCPUSetWithNonePolicy([]int32{3, 2}, []int32{1, 2})
+	assert.Error(t, err)
+	// the other pods are updated, while the qos-level cpuset keeps loosened for the failed pod
+	assert.Equal(t, "1-3", helper.ReadCgroupFileContents(beQOSDir, system.CPUSet))
+	assert.Equal(t, "2-3", helper.ReadCgroupFileContents(filepath.Join(beQOSDir, "pod1"), system.CPUSet))
+	assert.Equal(t, "2-3", helper.ReadCgroupFileContents(filepath.Join(beQOSDir, "pod3"), system.CPUSet))
+}
+
 func Test_getBECgroupCPUSetPathsRecursive(t *testing.T) {
 	// prepare testing files
 	helper := system.NewFileTestUtil(t)
@@ -1994,3 +2022,54 @@ func TestCPUSuppress_applyBESuppressCPUSet(t *testing.T) {
 		})
 	}
 }
+
+func TestCPUSuppress_applyBECPULimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        *beCPULimit
+		quantity     resource.Quantity
+		policy       slov1alpha1.CPUSuppressPolicy
+		wantQuantity resource.Quantity
+		wantPolicy   slov1alpha1.CPUSuppressPolicy
+	}{
+		{
+			name:         "no limit",
+			quantity:     resource.MustParse("10"),
+			policy:       slov1alpha1.CPUSetPolicy,
+			wantQuantity: resource.MustParse("10"),
+			wantPolicy:   slov1alpha1.CPUSetPolicy,
+		},
+		{
+			name:         "limit less than suppress quantity",
+			limit:        &beCPULimit{quantity: resource.MustParse("4"), policy: slov1alpha1.CPUCfsQuotaPolicy},
+			quantity:     resource.MustParse("10"),
+			policy:       slov1alpha1.CPUSetPolicy,
+			wantQuantity: resource.MustParse("4"),
+			wantPolicy:   slov1alpha1.CPUCfsQuotaPolicy,
+		},
+		{
+			name:         "limit greater than suppress quantity",
+			limit:        &beCPULimit{quantity: resource.MustParse("12"), policy: slov1alpha1.CPUSetPolicy},
+			quantity:     resource.MustParse("10"),
+			policy:       slov1alpha1.CPUCfsQuotaPolicy,
+			wantQuantity: resource.MustParse("10"),
+			wantPolicy:   slov1alpha1.CPUSetPolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &CPUSuppress{}
+			if tt.limit != nil {
+				r.SetBECPULimit(tt.limit.quantity, tt.limit.policy)
+			}
+			gotQuantity, gotPolicy := r.applyBECPULimit(&tt.quantity, tt.policy)
+			assert.Equal(t, tt.wantQuantity.MilliValue(), gotQuantity.MilliValue())
+			assert.Equal(t, tt.wantPolicy, gotPolicy)
+
+			r.ClearBECPULimit()
+			gotQuantity, gotPolicy = r.applyBECPULimit(&tt.quantity, tt.policy)
+			assert.Equal(t, tt.quantity.MilliValue(), gotQuantity.MilliValue())
+			assert.Equal(t, tt.policy, gotPolicy)
+		})
+	}
+}
@@ -0,0 +1,439 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interference
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpusuppress"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	InterferenceDetectName = "InterferenceDetect"
+
+	// baselineSmoothingFactor is the factor of the exponentially weighted moving average of the baselines
+	baselineSmoothingFactor = 0.1
+	// baselineMinSamples is the number of the samples to learn before detecting the degradation of a pod
+	baselineMinSamples = 10
+	// recoverHealthyRounds is the number of the consecutive healthy rounds to lower the suppress level
+	recoverHealthyRounds = 3
+	// beCPUSuppressRatio is the ratio of the current BE cpu usage to keep when suppressing BE pods
+	beCPUSuppressRatio = 0.5
+	// beMinCPUMilli is the minimal cpus to keep for BE pods when suppressing
+	beMinCPUMilli = 2000
+)
+
+var (
+	timeNow = time.Now
+)
+
+// suppressLevel is the level of the suppression on BE pods, which is raised progressively when the interference
+// lasts, and lowered after the interference disappears.
+type suppressLevel int
+
+const (
+	suppressLevelNone suppressLevel = iota
+	suppressLevelCfsQuota
+	suppressLevelCPUSet
+	suppressLevelEvict
+)
+
+func (l suppressLevel) String() string {
+	switch l {
+	case suppressLevelNone:
+		return "none"
+	case suppressLevelCfsQuota:
+		return "cfsQuota"
+	case suppressLevelCPUSet:
+		return "cpuset"
+	case suppressLevelEvict:
+		return "evict"
+	}
+	return fmt.Sprintf("unknown(%d)", int(l))
+}
+
+// beCPULimiter limits the cpus of BE pods, which is implemented by the CPUSuppress strategy.
+type beCPULimiter interface {
+	SetBECPULimit(quantity resource.Quantity, policy slov1alpha1.CPUSuppressPolicy)
+	ClearBECPULimit()
+}
+
+var _ beCPULimiter = &cpusuppress.CPUSuppress{}
+
+var _ framework.QOSStrategy = &interferenceDetector{}
+
+// podBaseline is the learned CPI and CPU PSI of a pod without interference.
+type podBaseline struct {
+	cpi     float64
+	psi     float64
+	samples int
+}
+
+// podInterference is the latest CPI and CPU PSI of a pod, and the metric is missing if its value is negative.
+type podInterference struct {
+	cpi float64
+	psi float64
+}
+
+// interferenceDetector compares the CPI and CPU PSI of LS pods with their baselines learned in the healthy rounds.
+// When any LS pod degrades, it suppresses BE pods progressively by the cfs quota, cpuset and eviction.
+type interferenceDetector struct {
+	interval              time.Duration
+	cpiWindow             time.Duration
+	psiWindow             time.Duration
+	metricCollectInterval time.Duration
+	cpiDegradationRatio   float64
+	psiDegradationPercent float64
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	evictor               *framework.Evictor
+	dryRun                bool
+	cpuLimiter            beCPULimiter
+	// settleDuration is the time to wait after raising the level before raising it again, so the metrics windows
+	// can reflect the effect of the current level
+	settleDuration time.Duration
+
+	baselines        map[string]*podBaseline
+	level            suppressLevel
+	levelChangedTime time.Time
+	healthyRounds    int
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	cpiWindow := 2 * opt.MetricAdvisorConfig.CPICollectorInterval
+	psiWindow := 2 * opt.MetricAdvisorConfig.PSICollectorInterval
+	settleDuration := cpiWindow
+	if psiWindow > settleDuration {
+		settleDuration = psiWindow
+	}
+	return &interferenceDetector{
+		interval:              time.Duration(opt.Config.InterferenceDetectIntervalSeconds) * time.Second,
+		cpiWindow:             cpiWindow,
+		psiWindow:             psiWindow,
+		settleDuration:        settleDuration,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		cpiDegradationRatio:   float64(opt.Config.InterferenceCPIDegradationPercent) / 100,
+		psiDegradationPercent: float64(opt.Config.InterferencePSIDegradationPercent),
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+		baselines:             map[string]*podBaseline{},
+		dryRun:                opt.Config.IsDryRun(InterferenceDetectName),
+	}
+}
+
+func (d *interferenceDetector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.InterferenceDetect) && d.interval > 0
+}
+
+func (d *interferenceDetector) Setup(ctx *framework.Context) {
+	d.evictor = ctx.Evictor
+	// the limit takes effect only when the CPUSuppress strategy is running
+	strategy, ok := ctx.Strategies[cpusuppress.CPUSuppressName]
+	if !ok || !strategy.Enabled() {
+		return
+	}
+	if limiter, ok := strategy.(beCPULimiter); ok {
+		d.cpuLimiter = limiter
+	}
+}
+
+func (d *interferenceDetector) Run(stopCh <-chan struct{}) {
+	go wait.Until(d.detect, d.interval, stopCh)
+}
+
+func (d *interferenceDetector) detect() {
+	klog.V(5).Infof("starting interference detect process")
+	defer klog.V(5).Infof("interference detect process completed")
+
+	nodeSLO := d.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.InterferenceDetect); err != nil || disabled {
+		klog.V(4).Infof("skip interference detect, disabled in NodeSLO, err: %v", err)
+		d.setLevel(suppressLevelNone, nil)
+		return
+	}
+	node := d.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip interference detect, Node is nil")
+		return
+	}
+
+	podMetas := d.statesInformer.GetAllPods()
+	degradedPods := d.detectDegradedPods(podMetas)
+	if len(degradedPods) <= 0 {
+		d.healthyRounds++
+		if d.level > suppressLevelNone && d.healthyRounds >= recoverHealthyRounds {
+			d.healthyRounds = 0
+			d.setLevel(d.level-1, podMetas)
+		}
+		return
+	}
+
+	d.healthyRounds = 0
+	klog.Infof("interference detected on LS pods %v, current suppress level %v", degradedPods, d.level)
+	if d.level < suppressLevelEvict {
+		if d.level > suppressLevelNone && timeNow().Before(d.levelChangedTime.Add(d.settleDuration)) {
+			klog.V(4).Infof("wait for the suppress level %v to settle before raising it", d.level)
+			return
+		}
+		d.setLevel(d.level+1, podMetas)
+	}
+	if d.level == suppressLevelEvict {
+		d.evictBEPod(node, podMetas, degradedPods)
+	}
+}
+
+// detectDegradedPods returns the LS pods whose CPI or CPU PSI exceeds the baselines, and it learns the baselines of the
+// healthy pods when BE pods are not suppressed.
+func (d *interferenceDetector) detectDegradedPods(podMetas []*statesinformer.PodMeta) []string {
+	var degradedPods []string
+	alivePods := map[string]bool{}
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		if !isLSPod(pod) {
+			continue
+		}
+		podUID := string(pod.UID)
+		alivePods[podUID] = true
+		current := d.getPodInterference(podMeta)
+		if current.cpi < 0 && current.psi < 0 {
+			continue
+		}
+		baseline, ok := d.baselines[podUID]
+		if !ok {
+			baseline = &podBaseline{cpi: current.cpi, psi: current.psi}
+			d.baselines[podUID] = baseline
+		}
+		if baseline.samples >= baselineMinSamples && d.isDegraded(baseline, current) {
+			degradedPods = append(degradedPods, util.GetPodKey(pod))
+			continue
+		}
+		// learn the baseline without the suppression, so the baseline is not lowered by the suppression
+		if d.level == suppressLevelNone {
+			baseline.update(current)
+		}
+	}
+	for podUID := range d.baselines {
+		if !alivePods[podUID] {
+			delete(d.baselines, podUID)
+		}
+	}
+	sort.Strings(degradedPods)
+	return degradedPods
+}
+
+func (d *interferenceDetector) isDegraded(baseline *podBaseline, current podInterference) bool {
+	if current.cpi > 0 && baseline.cpi > 0 && d.cpiDegradationRatio > 0 &&
+		current.cpi > baseline.cpi*(1+d.cpiDegradationRatio) {
+		return true
+	}
+	if current.psi >= 0 && baseline.psi >= 0 && d.psiDegradationPercent > 0 &&
+		current.psi > baseline.psi+d.psiDegradationPercent {
+		return true
+	}
+	return false
+}
+
+func (b *podBaseline) update(current podInterference) {
+	if current.cpi >= 0 {
+		if b.cpi < 0 {
+			b.cpi = current.cpi
+		}
+		b.cpi = (1-baselineSmoothingFactor)*b.cpi + baselineSmoothingFactor*current.cpi
+	}
+	if current.psi >= 0 {
+		if b.psi < 0 {
+			b.psi = current.psi
+		}
+		b.psi = (1-baselineSmoothingFactor)*b.psi + baselineSmoothingFactor*current.psi
+	}
+	b.samples++
+}
+
+// getPodInterference returns the CPI summed over the containers and the cpu some avg10 pressure of the pod.
+func (d *interferenceDetector) getPodInterference(podMeta *statesinformer.PodMeta) podInterference {
+	pod := podMeta.Pod
+	result := podInterference{cpi: -1, psi: -1}
+
+	end := time.Now()
+	cycles, instructions := 0.0, 0.0
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		cycle, err := d.queryMetric(metriccache.ContainerCPI, metriccache.MetricPropertiesFunc.ContainerCPI(string(pod.UID),
+			containerStatus.ContainerID, string(metriccache.CPIResourceCycle)), end.Add(-d.cpiWindow), end)
+		if err != nil {
+			klog.V(5).Infof("query container %s/%s/%s cycles failed, err: %v", pod.Namespace, pod.Name, containerStatus.Name, err)
+			continue
+		}
+		instruction, err := d.queryMetric(metriccache.ContainerCPI, metriccache.MetricPropertiesFunc.ContainerCPI(string(pod.UID),
+			containerStatus.ContainerID, string(metriccache.CPIResourceInstruction)), end.Add(-d.cpiWindow), end)
+		if err != nil {
+			klog.V(5).Infof("query container %s/%s/%s instructions failed, err: %v", pod.Namespace, pod.Name, containerStatus.Name, err)
+			continue
+		}
+		cycles += cycle
+		instructions += instruction
+	}
+	if instructions > 0 {
+		result.cpi = cycles / instructions
+	}
+
+	psi, err := d.queryMetric(metriccache.PodPSIMetric, metriccache.MetricPropertiesFunc.PodPSI(string(pod.UID),
+		string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), end.Add(-d.psiWindow), end)
+	if err != nil {
+		klog.V(5).Infof("query pod %s/%s cpu psi failed, err: %v", pod.Namespace, pod.Name, err)
+	} else {
+		result.psi = psi
+	}
+	return result
+}
+
+func (d *interferenceDetector) queryMetric(resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string,
+	start, end time.Time) (float64, error) {
+	queryMeta, err := resource.BuildQueryMeta(properties)
+	if err != nil {
+		return 0, err
+	}
+	result, err := helpers.CollectPodMetric(d.metricCache, queryMeta, start, end)
+	if err != nil {
+		return 0, err
+	}
+	if result.Count() == 0 {
+		return 0, fmt.Errorf("metric is empty")
+	}
+	return result.Value(metriccache.AggregationTypeAVG)
+}
+
+// setLevel changes the suppress level, and limits the cpus of BE pods by the cfs quota or cpuset policy of CPUSuppress.
+func (d *interferenceDetector) setLevel(level suppressLevel, podMetas []*statesinformer.PodMeta) {
+	if level != d.level {
+		klog.Infof("interference suppress level changed from %v to %v", d.level, level)
+		d.levelChangedTime = timeNow()
+	}
+	d.level = level
+	if d.cpuLimiter == nil {
+		if level > suppressLevelNone && level < suppressLevelEvict {
+			klog.Warningf("interference suppress level %v takes no effect, strategy %v is not enabled, check the feature-gate %v",
+				level, cpusuppress.CPUSuppressName, features.BECPUSuppress)
+		}
+		return
+	}
+	switch level {
+	case suppressLevelNone:
+		if !d.dryRun {
+			d.cpuLimiter.ClearBECPULimit()
+		}
+	case suppressLevelCfsQuota:
+		d.setBECPULimit(d.calculateBECPULimit(podMetas), slov1alpha1.CPUCfsQuotaPolicy)
+	case suppressLevelCPUSet:
+		d.setBECPULimit(d.calculateBECPULimit(podMetas), slov1alpha1.CPUSetPolicy)
+	}
+}
+
+// setBECPULimit limits the cpus of BE pods, or records the decision in the dry-run mode.
+func (d *interferenceDetector) setBECPULimit(quantity resource.Quantity, policy slov1alpha1.CPUSuppressPolicy) {
+	if d.dryRun {
+		_ = audit.V(3).Node().Reason(InterferenceDetectName).DryRun().Message("dry-run limit BE cpus to %v by %v", quantity.String(), policy).Do()
+		metrics.RecordQOSStrategyDryRunDecision(InterferenceDetectName, metrics.ActionUpdate, string(policy))
+		klog.Infof("dry-run limit BE cpus to %v by %v for %s", quantity.String(), policy, InterferenceDetectName)
+		return
+	}
+	d.cpuLimiter.SetBECPULimit(quantity, policy)
+}
+
+// calculateBECPULimit returns beCPUSuppressRatio of the current cpu usage of BE pods, and no less than beMinCPUMilli.
+func (d *interferenceDetector) calculateBECPULimit(podMetas []*statesinformer.PodMeta) resource.Quantity {
+	podMetrics := helpers.CollectAllPodMetricsLast(d.statesInformer, d.metricCache, metriccache.PodCPUUsageMetric, d.metricCollectInterval)
+	beUsed := 0.0
+	for _, podMeta := range podMetas {
+		if extension.GetPodQoSClassRaw(podMeta.Pod) == extension.QoSBE {
+			beUsed += podMetrics[string(podMeta.Pod.UID)]
+		}
+	}
+	limitMilli := int64(beUsed * beCPUSuppressRatio * 1000)
+	if limitMilli < beMinCPUMilli {
+		limitMilli = beMinCPUMilli
+	}
+	return *resource.NewMilliQuantity(limitMilli, resource.DecimalSI)
+}
+
+// evictBEPod evicts the BE pod with the lowest priority and the highest cpu usage each round. The pods terminating or
+// evicted are skipped, and the next candidate is tried if the evictor declines one, e.g. blocked by the PDB.
+func (d *interferenceDetector) evictBEPod(node *corev1.Node, podMetas []*statesinformer.PodMeta, degradedPods []string) {
+	if d.evictor == nil {
+		return
+	}
+	podMetrics := helpers.CollectAllPodMetricsLast(d.statesInformer, d.metricCache, metriccache.PodCPUUsageMetric, d.metricCollectInterval)
+	var bePods []*corev1.Pod
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassRaw(pod) != extension.QoSBE || pod.DeletionTimestamp != nil || d.evictor.IsPodEvicted(pod) {
+			continue
+		}
+		bePods = append(bePods, pod)
+	}
+	if len(bePods) <= 0 {
+		klog.V(4).Infof("skip interference eviction, no BE pod found")
+		return
+	}
+	sort.Slice(bePods, func(i, j int) bool {
+		pi, pj := bePods[i].Spec.Priority, bePods[j].Spec.Priority
+		if pi != nil && pj != nil && *pi != *pj {
+			return *pi < *pj
+		}
+		ui, uj := podMetrics[string(bePods[i].UID)], podMetrics[string(bePods[j].UID)]
+		if ui != uj {
+			return ui > uj
+		}
+		return bePods[i].Name < bePods[j].Name
+	})
+	message := fmt.Sprintf("evict BE pod for the interference on LS pods %v", degradedPods)
+	if d.dryRun {
+		d.evictor.DryRunEvictPods(InterferenceDetectName, bePods[:1], resourceexecutor.EvictPodByInterference, message)
+		return
+	}
+	for _, pod := range bePods {
+		if d.evictor.EvictPodIfNotEvicted(pod, node, resourceexecutor.EvictPodByInterference, message) {
+			return
+		}
+	}
+	klog.V(4).Infof("no BE pod is evicted for the interference, candidates %d", len(bePods))
+}
+
+func isLSPod(pod *corev1.Pod) bool {
+	switch extension.GetPodQoSClassWithDefault(pod) {
+	case extension.QoSLSE, extension.QoSLSR, extension.QoSLS:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interference
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpusuppress"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+type fakeBECPULimiter struct {
+	quantity *resource.Quantity
+	policy   slov1alpha1.CPUSuppressPolicy
+}
+
+func (f *fakeBECPULimiter) SetBECPULimit(quantity resource.Quantity, policy slov1alpha1.CPUSuppressPolicy) {
+	f.quantity = &quantity
+	f.policy = policy
+}
+
+func (f *fakeBECPULimiter) ClearBECPULimit() {
+	f.quantity = nil
+	f.policy = ""
+}
+
+func Test_interferenceDetector_isDegraded(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline *podBaseline
+		current  podInterference
+		want     bool
+	}{
+		{
+			name:     "healthy",
+			baseline: &podBaseline{cpi: 1, psi: 2},
+			current:  podInterference{cpi: 1.2, psi: 10},
+			want:     false,
+		},
+		{
+			name:     "cpi degraded",
+			baseline: &podBaseline{cpi: 1, psi: 2},
+			current:  podInterference{cpi: 1.5, psi: 2},
+			want:     true,
+		},
+		{
+			name:     "psi degraded",
+			baseline: &podBaseline{cpi: 1, psi: 2},
+			current:  podInterference{cpi: 1, psi: 15},
+			want:     true,
+		},
+		{
+			name:     "metrics missing",
+			baseline: &podBaseline{cpi: 1, psi: 2},
+			current:  podInterference{cpi: -1, psi: -1},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &interferenceDetector{cpiDegradationRatio: 0.3, psiDegradationPercent: 10}
+			assert.Equal(t, tt.want, d.isDegraded(tt.baseline, tt.current))
+		})
+	}
+}
+
+func Test_interferenceDetector_detect(t *testing.T) {
+	lsPod := createInterferenceTestPod("test_ls_pod", apiext.QoSLS, 9000)
+	bePods := []*corev1.Pod{
+		createInterferenceTestPod("test_be_pod_1", apiext.QoSBE, 5000),
+		createInterferenceTestPod("test_be_pod_2", apiext.QoSBE, 5000),
+	}
+	pods := append([]*corev1.Pod{lsPod}, bePods...)
+	podCPUUsed := map[string]float64{
+		"test_ls_pod":   4,
+		"test_be_pod_1": 6,
+		"test_be_pod_2": 8,
+	}
+	// the metrics of the ls pod changed in each round
+	lsCPI, lsPSI := 1.0, 2.0
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(testutil.GetPodMetas(pods)).AnyTimes()
+	mockStatesInformer.EXPECT().GetNode().Return(testutil.MockTestNode("80", "120G")).AnyTimes()
+	mockStatesInformer.EXPECT().GetNodeSLO().Return(testutil.GetNodeSLOByThreshold(&slov1alpha1.ResourceThresholdStrategy{
+		Enable: pointer.Bool(true),
+	})).AnyTimes()
+
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctl)
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	mockQuerier := mock_metriccache.NewMockQuerier(ctl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	mockQueryResult := func(resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string, value func() float64) {
+		result := mock_metriccache.NewMockAggregateResult(ctl)
+		result.EXPECT().Value(gomock.Any()).DoAndReturn(func(metriccache.AggregationType) (float64, error) {
+			return value(), nil
+		}).AnyTimes()
+		result.EXPECT().Count().Return(1).AnyTimes()
+		queryMeta, err := resource.BuildQueryMeta(properties)
+		assert.NoError(t, err)
+		mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+		mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	}
+	for uid, cpuUsed := range podCPUUsed {
+		used := cpuUsed
+		mockQueryResult(metriccache.PodCPUUsageMetric, metriccache.MetricPropertiesFunc.Pod(uid), func() float64 { return used })
+	}
+	containerID := lsPod.Status.ContainerStatuses[0].ContainerID
+	mockQueryResult(metriccache.ContainerCPI, metriccache.MetricPropertiesFunc.ContainerCPI(string(lsPod.UID), containerID,
+		string(metriccache.CPIResourceCycle)), func() float64 { return lsCPI * 1000 })
+	mockQueryResult(metriccache.ContainerCPI, metriccache.MetricPropertiesFunc.ContainerCPI(string(lsPod.UID), containerID,
+		string(metriccache.CPIResourceInstruction)), func() float64 { return 1000 })
+	mockQueryResult(metriccache.PodPSIMetric, metriccache.MetricPropertiesFunc.PodPSI(string(lsPod.UID), string(metriccache.PSIResourceCPU),
+		string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), func() float64 { return lsPSI })
+
+	client := clientsetfake.NewSimpleClientset()
+	for _, pod := range pods {
+		_, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	stop := make(chan struct{})
+	evictor := framework.NewEvictor(client, &testutil.FakeRecorder{}, policyv1beta1.SchemeGroupVersion.Version)
+	evictor.Start(stop)
+	defer func() { stop <- struct{}{} }()
+
+	d := New(&framework.Options{
+		StatesInformer:      mockStatesInformer,
+		MetricCache:         mockMetricCache,
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}).(*interferenceDetector)
+	limiter := &fakeBECPULimiter{}
+	d.Setup(&framework.Context{Evictor: evictor})
+	d.cpuLimiter = limiter
+	now := time.Now()
+	timeNow = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	// learn the baseline
+	for i := 0; i < baselineMinSamples; i++ {
+		d.detect()
+	}
+	assert.Equal(t, suppressLevelNone, d.level)
+	baseline := d.baselines[string(lsPod.UID)]
+	assert.NotNil(t, baseline)
+	assert.Equal(t, baselineMinSamples, baseline.samples)
+	assert.InDelta(t, 1.0, baseline.cpi, 1e-6)
+	assert.InDelta(t, 2.0, baseline.psi, 1e-6)
+
+	// cpi degraded, suppress be pods by cfs quota with half of the be usage
+	lsCPI = 2.0
+	d.detect()
+	assert.Equal(t, suppressLevelCfsQuota, d.level)
+	assert.Equal(t, int64(7000), limiter.quantity.MilliValue())
+	assert.Equal(t, slov1alpha1.CPUCfsQuotaPolicy, limiter.policy)
+
+	// psi degraded, wait for the level to settle
+	lsCPI, lsPSI = 1.0, 20
+	d.detect()
+	assert.Equal(t, suppressLevelCfsQuota, d.level)
+
+	// still degraded after settled, suppress be pods by cpuset
+	now = now.Add(d.settleDuration)
+	d.detect()
+	assert.Equal(t, suppressLevelCPUSet, d.level)
+	assert.Equal(t, slov1alpha1.CPUSetPolicy, limiter.policy)
+	// the baseline is not learned during the suppression
+	assert.Equal(t, baselineMinSamples, d.baselines[string(lsPod.UID)].samples)
+
+	// still degraded, evict the be pod with the highest usage
+	now = now.Add(d.settleDuration)
+	d.detect()
+	assert.Equal(t, suppressLevelEvict, d.level)
+	getEvictObject, err := client.Tracker().Get(testutil.PodsResource, bePods[1].Namespace, bePods[1].Name)
+	assert.NoError(t, err)
+	assert.IsType(t, &policyv1beta1.Eviction{}, getEvictObject)
+	getObject, _ := client.Tracker().Get(testutil.PodsResource, bePods[0].Namespace, bePods[0].Name)
+	assert.IsType(t, &corev1.Pod{}, getObject)
+
+	// recover the level step by step
+	lsPSI = 2.0
+	for i := 0; i < recoverHealthyRounds-1; i++ {
+		d.detect()
+	}
+	assert.Equal(t, suppressLevelEvict, d.level)
+	d.detect()
+	assert.Equal(t, suppressLevelCPUSet, d.level)
+	for i := 0; i < 2*recoverHealthyRounds; i++ {
+		d.detect()
+	}
+	assert.Equal(t, suppressLevelNone, d.level)
+	assert.Nil(t, limiter.quantity)
+}
+
+func Test_interferenceDetector_evictBEPod(t *testing.T) {
+	terminatingPod := createInterferenceTestPod("test_be_pod_1", apiext.QoSBE, 5000)
+	terminatingPod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	evictedPod := createInterferenceTestPod("test_be_pod_2", apiext.QoSBE, 5001)
+	declinedPod := createInterferenceTestPod("test_be_pod_3", apiext.QoSBE, 5002)
+	candidatePod := createInterferenceTestPod("test_be_pod_4", apiext.QoSBE, 5003)
+	pods := []*corev1.Pod{terminatingPod, evictedPod, declinedPod, candidatePod}
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(testutil.GetPodMetas(pods)).AnyTimes()
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("no metric")).AnyTimes()
+
+	// the declined pod is not found by the eviction API
+	client := clientsetfake.NewSimpleClientset(terminatingPod, evictedPod, candidatePod)
+	stop := make(chan struct{})
+	defer close(stop)
+	evictor := framework.NewEvictor(client, &testutil.FakeRecorder{}, policyv1beta1.SchemeGroupVersion.Version)
+	assert.NoError(t, evictor.Start(stop))
+	node := testutil.MockTestNode("80", "120G")
+	assert.True(t, evictor.EvictPodIfNotEvicted(evictedPod, node, "test", ""))
+
+	d := New(&framework.Options{
+		StatesInformer:      mockStatesInformer,
+		MetricCache:         mockMetricCache,
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}).(*interferenceDetector)
+	d.evictor = evictor
+	d.evictBEPod(node, testutil.GetPodMetas(pods), nil)
+
+	assert.False(t, evictor.IsPodEvicted(terminatingPod))
+	assert.False(t, evictor.IsPodEvicted(declinedPod))
+	assert.True(t, evictor.IsPodEvicted(candidatePod))
+}
+
+func Test_interferenceDetector_setLevel_dryRun(t *testing.T) {
+	limiter := &fakeBECPULimiter{}
+	d := &interferenceDetector{cpuLimiter: limiter, dryRun: true}
+	d.setBECPULimit(resource.MustParse("2"), slov1alpha1.CPUSetPolicy)
+	assert.Nil(t, limiter.quantity, "the limit should not be set in the dry-run mode")
+	d.dryRun = false
+	d.setBECPULimit(resource.MustParse("2"), slov1alpha1.CPUSetPolicy)
+	assert.Equal(t, int64(2000), limiter.quantity.MilliValue())
+	d.dryRun = true
+	d.setLevel(suppressLevelNone, nil)
+	assert.NotNil(t, limiter.quantity, "the limit should not be cleared in the dry-run mode")
+}
+
+func Test_interferenceDetector_Setup(t *testing.T) {
+	opt := &framework.Options{
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}
+	d := New(opt).(*interferenceDetector)
+	d.Setup(&framework.Context{Strategies: map[string]framework.QOSStrategy{cpusuppress.CPUSuppressName: cpusuppress.New(opt)}})
+	assert.NotNil(t, d.cpuLimiter)
+
+	assert.NoError(t, features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{string(features.BECPUSuppress): false}))
+	defer func() {
+		assert.NoError(t, features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{string(features.BECPUSuppress): true}))
+	}()
+	d = New(opt).(*interferenceDetector)
+	d.Setup(&framework.Context{Strategies: map[string]framework.QOSStrategy{cpusuppress.CPUSuppressName: cpusuppress.New(opt)}})
+	assert.Nil(t, d.cpuLimiter, "the limiter of the disabled strategy should not be used")
+}
+
+func createInterferenceTestPod(name string, qosClass apiext.QoSClass, priority int32) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  types.UID(name),
+			Labels: map[string]string{
+				apiext.LabelPodQoS: string(qosClass),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: fmt.Sprintf("%s_%s", name, "main"),
+				},
+			},
+			Priority: &priority,
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        fmt.Sprintf("%s_%s", name, "main"),
+					ContainerID: fmt.Sprintf("containerd://%s_%s", name, "main"),
+				},
+			},
+		},
+	}
+}
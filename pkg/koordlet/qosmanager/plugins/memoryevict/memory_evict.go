@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
@@ -38,6 +39,9 @@ const (
 	MemoryEvictName = "memoryEvict"
 
 	memoryReleaseBufferPercent = 2
+
+	defaultMemoryEvictPSIFullAvg10ThresholdPercent = 5
+	defaultMemoryEvictPSISomeAvg10ThresholdPercent = 20
 )
 
 var _ framework.QOSStrategy = &memoryEvictor{}
@@ -49,12 +53,15 @@ type memoryEvictor struct {
 	statesInformer        statesinformer.StatesInformer
 	metricCache           metriccache.MetricCache
 	evictor               *framework.Evictor
+	executor              resourceexecutor.ResourceUpdateExecutor
+	dryRun                bool
 	lastEvictTime         time.Time
 }
 
 type podInfo struct {
-	pod     *corev1.Pod
-	memUsed float64
+	pod       *corev1.Pod
+	cgroupDir string
+	memUsed   float64
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -64,6 +71,8 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
 		statesInformer:        opt.StatesInformer,
 		metricCache:           opt.MetricCache,
+		executor:              opt.Config.NewStrategyExecutor(MemoryEvictName),
+		dryRun:                opt.Config.IsDryRun(MemoryEvictName),
 	}
 }
 
@@ -98,6 +107,11 @@ func (m *memoryEvictor) memoryEvict() {
 	}
 
 	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	if thresholdConfig != nil && thresholdConfig.MemoryEvictPolicy == slov1alpha1.EvictByMemoryPSIPolicy {
+		m.memoryEvictByPSI(thresholdConfig)
+		return
+	}
+
 	thresholdPercent := thresholdConfig.MemoryEvictThresholdPercent
 	if thresholdPercent == nil {
 		klog.Warningf("skip memory evict, threshold percent is nil")
@@ -157,12 +171,112 @@ func (m *memoryEvictor) memoryEvict() {
 	)
 
 	memoryNeedRelease := memoryCapacity * (nodeMemoryUsage - lowerPercent) / 100
-	m.killAndEvictBEPods(node, podMetrics, memoryNeedRelease)
+	killPercent := thresholdConfig.MemoryEvictKillThresholdPercent
+	if killPercent != nil && *killPercent <= *thresholdPercent {
+		klog.Warningf("ignore the memory evict kill threshold(%v), it should be larger than the evict threshold(%v)",
+			*killPercent, *thresholdPercent)
+		killPercent = nil
+	}
+	if killPercent != nil && nodeMemoryUsage >= *killPercent {
+		klog.Infof("node memory usage(%v) exceeds the kill threshold(%v), kill BE pods directly",
+			float64(nodeMemoryUsage)/100, float64(*killPercent)/100)
+		m.fastKillAndEvictBEPods(node, podMetrics, memoryNeedRelease)
+		return
+	}
+	m.killAndEvictBEPods(node, podMetrics, memoryNeedRelease, resourceexecutor.EvictPodByNodeMemoryUsage)
 }
 
-func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[string]float64, memoryNeedRelease int64) {
+// memoryEvictByPSI evicts BE pods when any prod pod is stalled by the memory pressure, since the reclaim stalls can
+// happen before the node memory usage reaches the threshold. It releases memoryReleaseBufferPercent of the node
+// memory each time, and waits for the cooling time to check the pressure again.
+func (m *memoryEvictor) memoryEvictByPSI(thresholdConfig *slov1alpha1.ResourceThresholdStrategy) {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.PSICollector) {
+		klog.Warningf("skip memory evict by psi, feature-gate %s is disabled so the psi is not collected", features.PSICollector)
+		return
+	}
+	fullThreshold := int64(defaultMemoryEvictPSIFullAvg10ThresholdPercent)
+	if thresholdConfig.MemoryEvictPSIFullAvg10ThresholdPercent != nil {
+		fullThreshold = *thresholdConfig.MemoryEvictPSIFullAvg10ThresholdPercent
+	}
+	someThreshold := int64(defaultMemoryEvictPSISomeAvg10ThresholdPercent)
+	if thresholdConfig.MemoryEvictPSISomeAvg10ThresholdPercent != nil {
+		someThreshold = *thresholdConfig.MemoryEvictPSISomeAvg10ThresholdPercent
+	}
+	if fullThreshold <= 0 && someThreshold <= 0 {
+		klog.Warningf("skip memory evict by psi, full threshold(%v) or some threshold(%v) should greater than 0",
+			fullThreshold, someThreshold)
+		return
+	}
+	windowSeconds := int64(m.metricCollectInterval.Seconds() * 2)
+	if thresholdConfig.MemoryEvictPSITimeWindowSeconds != nil && *thresholdConfig.MemoryEvictPSITimeWindowSeconds > int64(m.metricCollectInterval.Seconds()) {
+		windowSeconds = *thresholdConfig.MemoryEvictPSITimeWindowSeconds
+	}
+
+	node := m.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip memory evict by psi, Node is nil")
+		return
+	}
+	memoryCapacity := node.Status.Capacity.Memory().Value()
+	if memoryCapacity <= 0 {
+		klog.Warningf("skip memory evict by psi, memory capacity(%v) should greater than 0", memoryCapacity)
+		return
+	}
+
+	stalledPod, fullPressure, somePressure := m.getStalledProdPod(windowSeconds, fullThreshold, someThreshold)
+	if stalledPod == nil {
+		klog.V(5).Infof("skip memory evict by psi, no prod pod is stalled by memory pressure")
+		return
+	}
+	klog.Infof("prod pod %s/%s is stalled by memory pressure, full avg10: %.2f, threshold: %v, some avg10: %.2f, threshold: %v",
+		stalledPod.Namespace, stalledPod.Name, fullPressure, fullThreshold, somePressure, someThreshold)
+
+	podMetrics := helpers.CollectAllPodMetricsLast(m.statesInformer, m.metricCache, metriccache.PodMemUsageMetric, m.metricCollectInterval)
+	memoryNeedRelease := memoryCapacity * memoryReleaseBufferPercent / 100
+	m.killAndEvictBEPods(node, podMetrics, memoryNeedRelease, resourceexecutor.EvictPodByMemoryPressure)
+}
+
+// getStalledProdPod returns the first prod pod whose average memory pressure in the window exceeds the thresholds.
+func (m *memoryEvictor) getStalledProdPod(windowSeconds, fullThreshold, someThreshold int64) (*corev1.Pod, float64, float64) {
+	queryParam := helpers.GenerateQueryParamsAvg(windowSeconds)
+	queryPressure := func(pod *corev1.Pod, degree metriccache.MetricPropertyValue) (float64, bool) {
+		queryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodPSI(string(pod.UID),
+			string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(degree)))
+		if err != nil {
+			klog.Warningf("build pod %s/%s psi query meta failed, error: %v", pod.Namespace, pod.Name, err)
+			return 0, false
+		}
+		result, err := helpers.CollectPodMetric(m.metricCache, queryMeta, *queryParam.Start, *queryParam.End)
+		if err != nil || result.Count() == 0 {
+			klog.V(5).Infof("query pod %s/%s memory %s psi failed or empty, error: %v", pod.Namespace, pod.Name, degree, err)
+			return 0, false
+		}
+		value, err := result.Value(queryParam.Aggregate)
+		if err != nil {
+			klog.Warningf("aggregate pod %s/%s memory %s psi failed, error: %v", pod.Namespace, pod.Name, degree, err)
+			return 0, false
+		}
+		return value, true
+	}
+
+	for _, podMeta := range m.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if extension.GetPodPriorityClassWithDefault(pod) != extension.PriorityProd {
+			continue
+		}
+		fullPressure, fullOK := queryPressure(pod, metriccache.PSIDegreeFull)
+		somePressure, someOK := queryPressure(pod, metriccache.PSIDegreeSome)
+		if (fullOK && fullThreshold > 0 && fullPressure >= float64(fullThreshold)) ||
+			(someOK && someThreshold > 0 && somePressure >= float64(someThreshold)) {
+			return pod, fullPressure, somePressure
+		}
+	}
+	return nil, 0, 0
+}
+
+func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[string]float64, memoryNeedRelease int64, reason string) {
 	bePodInfos := m.getSortedBEPodInfos(podMetrics)
-	message := fmt.Sprintf("killAndEvictBEPods for node, need to release memory: %v", memoryNeedRelease)
+	message := fmt.Sprintf("killAndEvictBEPods for node, reason: %v, need to release memory: %v", reason, memoryNeedRelease)
 	memoryReleased := int64(0)
 
 	var killedPods []*corev1.Pod
@@ -171,20 +285,56 @@ func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[str
 			break
 		}
 
-		killMsg := fmt.Sprintf("%v, kill pod: %v", message, bePod.pod.Name)
-		helpers.KillContainers(bePod.pod, killMsg)
 		killedPods = append(killedPods, bePod.pod)
 		if bePod.memUsed != 0 {
 			memoryReleased += int64(bePod.memUsed)
 		}
 	}
 
-	m.evictor.EvictPodsIfNotEvicted(killedPods, node, resourceexecutor.EvictPodByNodeMemoryUsage, message)
+	if m.dryRun {
+		m.evictor.DryRunEvictPods(MemoryEvictName, killedPods, reason, message)
+	} else {
+		m.evictor.KillAndEvictPodsIfNotEvicted(killedPods, node, reason, message)
+	}
 
 	m.lastEvictTime = time.Now()
 	klog.Infof("killAndEvictBEPods completed, memoryNeedRelease(%v) memoryReleased(%v)", memoryNeedRelease, memoryReleased)
 }
 
+// fastKillAndEvictBEPods kills the BE pods through cgroup.kill or the runtime without waiting for the eviction API when
+// the node is close to the global OOM. The killed pods are still evicted afterwards, so that they are not restarted.
+func (m *memoryEvictor) fastKillAndEvictBEPods(node *corev1.Node, podMetrics map[string]float64, memoryNeedRelease int64) {
+	reason := resourceexecutor.KillPodByNodeMemoryCritical
+	bePodInfos := m.getSortedBEPodInfos(podMetrics)
+	message := fmt.Sprintf("fastKillAndEvictBEPods for node, reason: %v, need to release memory: %v", reason, memoryNeedRelease)
+	memoryReleased := int64(0)
+
+	var killedPods []*corev1.Pod
+	for _, bePod := range bePodInfos {
+		if memoryReleased >= memoryNeedRelease {
+			break
+		}
+		if !m.dryRun {
+			killMsg := fmt.Sprintf("%v, kill pod: %v", message, bePod.pod.Name)
+			helpers.FastKillPod(m.executor, bePod.pod, bePod.cgroupDir, killMsg)
+		}
+		killedPods = append(killedPods, bePod.pod)
+		if bePod.memUsed != 0 {
+			memoryReleased += int64(bePod.memUsed)
+		}
+	}
+
+	if m.dryRun {
+		m.evictor.DryRunEvictPods(MemoryEvictName, killedPods, reason, message)
+	} else {
+		m.evictor.ReportPodsKilled(killedPods, reason, message)
+		m.evictor.EvictPodsIfNotEvicted(killedPods, node, reason, message)
+	}
+
+	m.lastEvictTime = time.Now()
+	klog.Infof("fastKillAndEvictBEPods completed, memoryNeedRelease(%v) memoryReleased(%v)", memoryNeedRelease, memoryReleased)
+}
+
 func (m *memoryEvictor) getSortedBEPodInfos(podMetricMap map[string]float64) []*podInfo {
 
 	var bePodInfos []*podInfo
@@ -192,8 +342,9 @@ func (m *memoryEvictor) getSortedBEPodInfos(podMetricMap map[string]float64) []*
 		pod := podMeta.Pod
 		if extension.GetPodQoSClassRaw(pod) == extension.QoSBE {
 			info := &podInfo{
-				pod:     pod,
-				memUsed: podMetricMap[string(pod.UID)],
+				pod:       pod,
+				cgroupDir: podMeta.CgroupDir,
+				memUsed:   podMetricMap[string(pod.UID)],
 			}
 			bePodInfos = append(bePodInfos, info)
 		}
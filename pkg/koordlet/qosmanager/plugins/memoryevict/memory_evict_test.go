@@ -36,6 +36,7 @@ import (
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
 	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
@@ -227,6 +228,42 @@ func Test_memoryEvict(t *testing.T) {
 				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
 			},
 		},
+		{
+			name: "test_memoryevict_MemoryEvictKillThresholdPercent_90",
+			node: testutil.MockTestNode("80", "120G"),
+			pods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+			nodeMemUsed: resource.MustParse("115G"),
+			podMetrics: []podMemSample{
+				{UID: "test_lsr_pod", MemUsed: resource.MustParse("40G")},
+				{UID: "test_ls_pod", MemUsed: resource.MustParse("30G")},
+				{UID: "test_noqos_pod", MemUsed: resource.MustParse("10G")},
+				{UID: "test_be_pod_priority100_1", MemUsed: resource.MustParse("5G")},
+				{UID: "test_be_pod_priority100_2", MemUsed: resource.MustParse("20G")},
+				{UID: "test_be_pod_priority120", MemUsed: resource.MustParse("10G")},
+			},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                          pointer.Bool(true),
+				MemoryEvictThresholdPercent:     pointer.Int64(80),
+				MemoryEvictKillThresholdPercent: pointer.Int64(90),
+			}, // >20.4G, killed directly
+			expectEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+			},
+			expectNotEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+		},
 		{
 			name: "test_memoryevict_MemoryEvictLowerPercent_80",
 			node: testutil.MockTestNode("80", "120G"),
@@ -426,6 +463,237 @@ func Test_memoryEvict(t *testing.T) {
 	}
 }
 
+func Test_memoryEvictByPSI(t *testing.T) {
+	type podPSISample struct {
+		UID          string
+		FullPressure float64
+		SomePressure float64
+	}
+	type args struct {
+		name               string
+		node               *corev1.Node
+		pods               []*corev1.Pod
+		podMemUsed         map[string]resource.Quantity
+		podPSIs            []podPSISample
+		psiDisabled        bool
+		thresholdConfig    *slov1alpha1.ResourceThresholdStrategy
+		expectEvictPods    []*corev1.Pod
+		expectNotEvictPods []*corev1.Pod
+	}
+
+	testPods := []*corev1.Pod{
+		createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+		createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+		createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+		createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+		createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+	}
+	testPodMemUsed := map[string]resource.Quantity{
+		"test_lsr_pod":              resource.MustParse("40G"),
+		"test_ls_pod":               resource.MustParse("30G"),
+		"test_be_pod_priority100_1": resource.MustParse("1G"),
+		"test_be_pod_priority100_2": resource.MustParse("2G"),
+		"test_be_pod_priority120":   resource.MustParse("8G"),
+	}
+	tests := []args{
+		{
+			name:       "prod pods not stalled",
+			node:       testutil.MockTestNode("80", "120G"),
+			pods:       testPods,
+			podMemUsed: testPodMemUsed,
+			podPSIs: []podPSISample{
+				{UID: "test_lsr_pod", FullPressure: 1, SomePressure: 5},
+				{UID: "test_ls_pod", FullPressure: 2, SomePressure: 10},
+				{UID: "test_be_pod_priority100_1", FullPressure: 50, SomePressure: 80},
+			},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:            pointer.Bool(true),
+				MemoryEvictPolicy: slov1alpha1.EvictByMemoryPSIPolicy,
+			},
+			expectNotEvictPods: testPods,
+		},
+		{
+			name:       "prod pod stalled by full pressure",
+			node:       testutil.MockTestNode("80", "120G"),
+			pods:       testPods,
+			podMemUsed: testPodMemUsed,
+			podPSIs: []podPSISample{
+				{UID: "test_lsr_pod", FullPressure: 1, SomePressure: 5},
+				{UID: "test_ls_pod", FullPressure: 6, SomePressure: 10},
+			},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:            pointer.Bool(true),
+				MemoryEvictPolicy: slov1alpha1.EvictByMemoryPSIPolicy,
+			}, // release 2.4G
+			expectEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+			},
+			expectNotEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+		},
+		{
+			name:       "prod pod stalled by some pressure with custom threshold",
+			node:       testutil.MockTestNode("80", "120G"),
+			pods:       testPods,
+			podMemUsed: testPodMemUsed,
+			podPSIs: []podPSISample{
+				{UID: "test_lsr_pod", FullPressure: 1, SomePressure: 12},
+			},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                                  pointer.Bool(true),
+				MemoryEvictPolicy:                       slov1alpha1.EvictByMemoryPSIPolicy,
+				MemoryEvictPSISomeAvg10ThresholdPercent: pointer.Int64(10),
+				MemoryEvictPSITimeWindowSeconds:         pointer.Int64(60),
+			},
+			expectEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+			},
+			expectNotEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+		},
+		{
+			name:       "thresholds disabled",
+			node:       testutil.MockTestNode("80", "120G"),
+			pods:       testPods,
+			podMemUsed: testPodMemUsed,
+			podPSIs: []podPSISample{
+				{UID: "test_lsr_pod", FullPressure: 50, SomePressure: 80},
+			},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                                  pointer.Bool(true),
+				MemoryEvictPolicy:                       slov1alpha1.EvictByMemoryPSIPolicy,
+				MemoryEvictPSIFullAvg10ThresholdPercent: pointer.Int64(0),
+				MemoryEvictPSISomeAvg10ThresholdPercent: pointer.Int64(0),
+			},
+			expectNotEvictPods: testPods,
+		},
+		{
+			name:       "psi collector disabled",
+			node:       testutil.MockTestNode("80", "120G"),
+			pods:       testPods,
+			podMemUsed: testPodMemUsed,
+			podPSIs: []podPSISample{
+				{UID: "test_lsr_pod", FullPressure: 1, SomePressure: 5},
+				{UID: "test_ls_pod", FullPressure: 6, SomePressure: 10},
+			},
+			psiDisabled: true,
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:            pointer.Bool(true),
+				MemoryEvictPolicy: slov1alpha1.EvictByMemoryPSIPolicy,
+			},
+			expectNotEvictPods: testPods,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{
+				string(features.PSICollector): !tt.psiDisabled}))
+			defer func() {
+				assert.NoError(t, features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{
+					string(features.PSICollector): false}))
+			}()
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+			mockStatesInformer.EXPECT().GetAllPods().Return(testutil.GetPodMetas(tt.pods)).AnyTimes()
+			mockStatesInformer.EXPECT().GetNode().Return(tt.node).AnyTimes()
+			mockStatesInformer.EXPECT().GetNodeSLO().Return(testutil.GetNodeSLOByThreshold(tt.thresholdConfig)).AnyTimes()
+
+			mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+			mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctl)
+			metriccache.DefaultAggregateResultFactory = mockResultFactory
+			mockQuerier := mock_metriccache.NewMockQuerier(ctl)
+			mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+			mockQueryResult := func(resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string, value float64) {
+				result := mock_metriccache.NewMockAggregateResult(ctl)
+				result.EXPECT().Value(gomock.Any()).Return(value, nil).AnyTimes()
+				result.EXPECT().Count().Return(1).AnyTimes()
+				queryMeta, err := resource.BuildQueryMeta(properties)
+				assert.NoError(t, err)
+				mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+				mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			}
+			for uid, memUsed := range tt.podMemUsed {
+				mockQueryResult(metriccache.PodMemUsageMetric, metriccache.MetricPropertiesFunc.Pod(uid), float64(memUsed.Value()))
+			}
+			psiUIDs := map[string]bool{}
+			for _, podPSI := range tt.podPSIs {
+				psiUIDs[podPSI.UID] = true
+				mockQueryResult(metriccache.PodPSIMetric, metriccache.MetricPropertiesFunc.PodPSI(podPSI.UID, string(metriccache.PSIResourceMem),
+					string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)), podPSI.FullPressure)
+				mockQueryResult(metriccache.PodPSIMetric, metriccache.MetricPropertiesFunc.PodPSI(podPSI.UID, string(metriccache.PSIResourceMem),
+					string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), podPSI.SomePressure)
+			}
+			// the pods without psi samples return empty results
+			emptyResult := mock_metriccache.NewMockAggregateResult(ctl)
+			emptyResult.EXPECT().Count().Return(0).AnyTimes()
+			for _, pod := range tt.pods {
+				if psiUIDs[string(pod.UID)] {
+					continue
+				}
+				for _, degree := range []metriccache.MetricPropertyValue{metriccache.PSIDegreeFull, metriccache.PSIDegreeSome} {
+					queryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodPSI(string(pod.UID),
+						string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(degree)))
+					assert.NoError(t, err)
+					mockResultFactory.EXPECT().New(queryMeta).Return(emptyResult).AnyTimes()
+					mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				}
+			}
+
+			fakeRecorder := &testutil.FakeRecorder{}
+			client := clientsetfake.NewSimpleClientset()
+			stop := make(chan struct{})
+			evictor := framework.NewEvictor(client, fakeRecorder, policyv1beta1.SchemeGroupVersion.Version)
+			evictor.Start(stop)
+			defer func() { stop <- struct{}{} }()
+
+			runtime.DockerHandler = handler.NewFakeRuntimeHandler()
+			var containers []*critesting.FakeContainer
+			for _, pod := range tt.pods {
+				_, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+				assert.NoError(t, err, "createPod ERROR!")
+				for _, containerStatus := range pod.Status.ContainerStatuses {
+					_, containerId, _ := util.ParseContainerId(containerStatus.ContainerID)
+					containers = append(containers, &critesting.FakeContainer{
+						SandboxID:       string(pod.UID),
+						ContainerStatus: runtimeapi.ContainerStatus{Id: containerId},
+					})
+				}
+			}
+			runtime.DockerHandler.(*handler.FakeRuntimeHandler).SetFakeContainers(containers)
+
+			opt := &framework.Options{
+				StatesInformer:      mockStatesInformer,
+				MetricCache:         mockMetricCache,
+				Config:              framework.NewDefaultConfig(),
+				MetricAdvisorConfig: maframework.NewDefaultConfig(),
+			}
+			memoryEvictor := New(opt).(*memoryEvictor)
+			memoryEvictor.Setup(&framework.Context{Evictor: evictor})
+			memoryEvictor.memoryEvict()
+
+			for _, pod := range tt.expectEvictPods {
+				getEvictObject, err := client.Tracker().Get(testutil.PodsResource, pod.Namespace, pod.Name)
+				assert.NotNil(t, getEvictObject, "evictPod Fail", err)
+				assert.IsType(t, &policyv1beta1.Eviction{}, getEvictObject, "evictPod Fail", pod.Name)
+			}
+			for _, pod := range tt.expectNotEvictPods {
+				getObject, _ := client.Tracker().Get(testutil.PodsResource, pod.Namespace, pod.Name)
+				assert.IsType(t, &corev1.Pod{}, getObject, "no need evict", pod.Name)
+			}
+		})
+	}
+}
+
 func createMemoryEvictTestPod(name string, qosClass apiext.QoSClass, priority int32) *corev1.Pod {
 	return &corev1.Pod{
 		TypeMeta: metav1.TypeMeta{Kind: "Pod"},
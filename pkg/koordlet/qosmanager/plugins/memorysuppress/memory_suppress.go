@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorysuppress
+
+import (
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	MemorySuppressName = "MemorySuppress"
+
+	// beMinMemoryLimitBytes is the lower bound of the BE memory limit, to avoid the BE pods being OOM killed in bulk.
+	beMinMemoryLimitBytes int64 = 256 * 1024 * 1024
+	// beMaxDecreaseMemoryRatio is the max ratio of the BE memory usage to reclaim in a round, scale down slowly
+	beMaxDecreaseMemoryRatio = 0.1
+)
+
+var _ framework.QOSStrategy = &memorySuppress{}
+
+type memorySuppress struct {
+	interval              time.Duration
+	metricCollectInterval time.Duration
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	executor              resourceexecutor.ResourceUpdateExecutor
+	// suppressed indicates whether the BE memory limit may be set by the strategy and needs to recover when disabled
+	suppressed bool
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &memorySuppress{
+		interval:              time.Duration(opt.Config.MemorySuppressIntervalSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+		executor:              opt.Config.NewStrategyExecutor(MemorySuppressName),
+		// the BE memory limit can be left suppressed by the last run, so recover it once if disabled after a restart
+		suppressed: true,
+	}
+}
+
+func (m *memorySuppress) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BEMemorySuppress) && m.interval > 0
+}
+
+func (m *memorySuppress) Setup(*framework.Context) {
+}
+
+func (m *memorySuppress) Run(stopCh <-chan struct{}) {
+	m.executor.Run(stopCh)
+	go wait.Until(m.suppressBEMemory, m.interval, stopCh)
+}
+
+// suppressBEMemory limits the memory of the BE QoS cgroup to keep the node memory usage under the suppress threshold,
+// so that BE pods are reclaimed before the prod pods and the memory eviction.
+func (m *memorySuppress) suppressBEMemory() {
+	nodeSLO := m.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEMemorySuppress); err != nil {
+		klog.Warningf("suppressBEMemory failed, cannot check the featuregate, err: %s", err)
+		return
+	} else if disabled {
+		m.recoverIfNeed()
+		klog.V(5).Infof("suppressBEMemory skipped, nodeSLO disable the featuregate")
+		return
+	}
+	thresholdPercent := nodeSLO.Spec.ResourceUsedThresholdWithBE.MemorySuppressThresholdPercent
+	if thresholdPercent == nil || *thresholdPercent <= 0 {
+		m.recoverIfNeed()
+		klog.V(5).Infof("suppressBEMemory skipped, threshold percent is not set")
+		return
+	}
+
+	node := m.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("suppressBEMemory failed, got nil node")
+		return
+	}
+	if node.Status.Capacity.Memory().Value() <= 0 {
+		klog.Warningf("suppressBEMemory failed, memory capacity(%v) should greater than 0", node.Status.Capacity.Memory().Value())
+		return
+	}
+	podMetas := m.statesInformer.GetAllPods()
+	podMetrics := helpers.CollectAllPodMetricsLast(m.statesInformer, m.metricCache, metriccache.PodMemUsageMetric, m.metricCollectInterval)
+	queryMeta, err := metriccache.NodeMemoryUsageMetric.BuildQueryMeta(nil)
+	if err != nil {
+		klog.Warningf("suppressBEMemory failed, build node query meta failed, error: %v", err)
+		return
+	}
+	nodeUsed, err := helpers.CollectorNodeMetricLast(m.metricCache, queryMeta, m.metricCollectInterval)
+	if err != nil {
+		klog.Warningf("suppressBEMemory failed, query node memory metrics failed, error: %v", err)
+		return
+	}
+
+	limit, ok := calculateBESuppressMemory(node, nodeUsed, podMetrics, podMetas, *thresholdPercent)
+	if !ok {
+		return
+	}
+	if m.adjustBEMemoryLimit(strconv.FormatInt(limit, 10)) {
+		m.suppressed = true
+	}
+	metrics.RecordBESuppressMemoryBytes(float64(limit))
+}
+
+// calculateBESuppressMemory calculates the memory limit of BE pods in bytes. It returns false if the memory usage of
+// BE pods is missing, since the BE memory cannot be reclaimed progressively without it.
+func calculateBESuppressMemory(node *corev1.Node, nodeUsed float64, podMetrics map[string]float64,
+	podMetas []*statesinformer.PodMeta, thresholdPercent int64) (int64, bool) {
+	podMetaMap := map[string]*statesinformer.PodMeta{}
+	for _, podMeta := range podMetas {
+		podMetaMap[string(podMeta.Pod.UID)] = podMeta
+	}
+
+	var podAllUsed, podNonBEUsed, podBEUsed int64
+	for podUID, podMetric := range podMetrics {
+		podAllUsed += int64(podMetric)
+		podMeta, ok := podMetaMap[podUID]
+		if !ok || (apiext.GetPodQoSClassRaw(podMeta.Pod) != apiext.QoSBE && util.GetKubeQosClass(podMeta.Pod) != corev1.PodQOSBestEffort) {
+			// NOTE: consider non-BE pods and podMeta-missing pods as LS
+			podNonBEUsed += int64(podMetric)
+		} else {
+			podBEUsed += int64(podMetric)
+		}
+	}
+	if podBEUsed <= 0 {
+		klog.V(5).Infof("suppressBEMemory skipped, memory usage of BE pods is missing")
+		return 0, false
+	}
+
+	systemUsed := int64(nodeUsed) - podAllUsed
+	if systemUsed < 0 {
+		systemUsed = 0
+	}
+	systemUsedList := quotav1.Max(quotav1.Max(corev1.ResourceList{
+		corev1.ResourceMemory: *resource.NewQuantity(systemUsed, resource.BinarySI),
+	}, util.GetNodeReservationFromAnnotation(node.Annotations)), util.GetNodeReservationFromKubelet(node))
+	systemUsed = systemUsedList.Memory().Value()
+
+	// suppress(BE) := node.Capacity * SLOPercent - pod(non-be).Used - max(system.Used, node.anno.reserved, node.kubelet.reserved)
+	suppress := node.Status.Capacity.Memory().Value()*thresholdPercent/100 - podNonBEUsed - systemUsed
+	// reclaim the BE memory progressively to avoid the OOM
+	if lowerBound := int64(float64(podBEUsed) * (1 - beMaxDecreaseMemoryRatio)); suppress < lowerBound {
+		suppress = lowerBound
+	}
+	if suppress < beMinMemoryLimitBytes {
+		suppress = beMinMemoryLimitBytes
+	}
+
+	metrics.RecordBESuppressLSUsedMemoryBytes(float64(podNonBEUsed))
+	klog.V(4).Infof("nodeSuppressBE[Memory(Bytes)]:%v = node.Total:%v * SLOPercent:%v%% - systemUsage:%v - podLSUsed:%v, podBEUsed:%v",
+		suppress, node.Status.Capacity.Memory().Value(), thresholdPercent, systemUsed, podNonBEUsed, podBEUsed)
+	return suppress, true
+}
+
+// adjustBEMemoryLimit writes the memory.high on cgroups-v2 to throttle and reclaim BE pods without the OOM, or the
+// memory.limit_in_bytes on cgroups-v1. It returns whether the cgroup is updated.
+func (m *memorySuppress) adjustBEMemoryLimit(value string) bool {
+	var resourceType system.ResourceType = system.MemoryLimitName
+	if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+		resourceType = system.MemoryHighName
+	}
+	beCgroupDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByNodeMemoryUsage).Message("update BE group to %s: %v", resourceType, value)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(resourceType, beCgroupDir, value, eventHelper)
+	if err != nil {
+		klog.Warningf("suppressBEMemory failed to get updater for %s, err: %v", resourceType, err)
+		return false
+	}
+	if _, err = m.executor.Update(true, updater); err != nil {
+		klog.Warningf("suppressBEMemory failed to update %s to %v, err: %v", resourceType, value, err)
+		return false
+	}
+	return true
+}
+
+func (m *memorySuppress) recoverIfNeed() {
+	if !m.suppressed {
+		return
+	}
+	value := "-1"
+	if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+		value = system.CgroupMaxSymbolStr
+	}
+	if !m.adjustBEMemoryLimit(value) {
+		return
+	}
+	m.suppressed = false
+	klog.V(4).Infof("recover BE memory limit to %s", value)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorysuppress
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+const (
+	mib int64 = 1024 * 1024
+	gib int64 = 1024 * mib
+)
+
+func Test_calculateBESuppressMemory(t *testing.T) {
+	node := testutil.MockTestNode("80", "100Gi")
+	lsPod := testutil.MockTestPod(apiext.QoSLS, "test_ls_pod")
+	lsPod.Status.QOSClass = corev1.PodQOSBurstable
+	bePod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{lsPod, bePod})
+	tests := []struct {
+		name             string
+		nodeUsed         float64
+		podMetrics       map[string]float64
+		thresholdPercent int64
+		want             int64
+		wantOK           bool
+	}{
+		{
+			name:     "suppress by threshold",
+			nodeUsed: float64(60 * gib),
+			podMetrics: map[string]float64{
+				string(lsPod.UID): float64(30 * gib),
+				string(bePod.UID): float64(20 * gib),
+			},
+			thresholdPercent: 80,
+			want:             40 * gib, // 80 - 30 - 10
+			wantOK:           true,
+		},
+		{
+			name:     "reclaim be memory progressively",
+			nodeUsed: float64(60 * gib),
+			podMetrics: map[string]float64{
+				string(lsPod.UID): float64(30 * gib),
+				string(bePod.UID): float64(20 * gib),
+			},
+			thresholdPercent: 50,
+			want:             18 * gib, // 20 * (1 - 0.1) > 50 - 30 - 10
+			wantOK:           true,
+		},
+		{
+			name:     "no less than the min limit",
+			nodeUsed: float64(60 * gib),
+			podMetrics: map[string]float64{
+				string(lsPod.UID): float64(50 * gib),
+				string(bePod.UID): float64(100 * mib),
+			},
+			thresholdPercent: 50,
+			want:             beMinMemoryLimitBytes,
+			wantOK:           true,
+		},
+		{
+			name:     "skip when the be memory usage is missing",
+			nodeUsed: float64(60 * gib),
+			podMetrics: map[string]float64{
+				string(lsPod.UID): float64(50 * gib),
+			},
+			thresholdPercent: 50,
+			want:             0,
+			wantOK:           false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOK := calculateBESuppressMemory(node, tt.nodeUsed, tt.podMetrics, podMetas, tt.thresholdPercent)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOK, gotOK)
+		})
+	}
+}
+
+func Test_memorySuppress_suppressBEMemory(t *testing.T) {
+	node := testutil.MockTestNode("80", "100Gi")
+	lsPod := testutil.MockTestPod(apiext.QoSLS, "test_ls_pod")
+	lsPod.Status.QOSClass = corev1.PodQOSBurstable
+	bePod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	tests := []struct {
+		name          string
+		useCgroupsV2  bool
+		resource      system.Resource
+		wantSuppress  string
+		wantRecovered string
+	}{
+		{
+			name:          "suppress memory.limit_in_bytes on cgroups-v1",
+			resource:      system.MemoryLimit,
+			wantSuppress:  "42949672960",
+			wantRecovered: "-1",
+		},
+		{
+			name:          "suppress memory.high on cgroups-v2",
+			useCgroupsV2:  true,
+			resource:      system.MemoryHighV2,
+			wantSuppress:  "42949672960",
+			wantRecovered: "max",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.useCgroupsV2)
+			beCgroupDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+			helper.WriteCgroupFileContents(beCgroupDir, tt.resource, "100")
+
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			thresholdConfig := &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                         pointer.Bool(true),
+				MemorySuppressThresholdPercent: pointer.Int64(80),
+			}
+			mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+			mockStatesInformer.EXPECT().GetAllPods().Return(testutil.GetPodMetas([]*corev1.Pod{lsPod, bePod})).AnyTimes()
+			mockStatesInformer.EXPECT().GetNode().Return(node).AnyTimes()
+			mockStatesInformer.EXPECT().GetNodeSLO().Return(testutil.GetNodeSLOByThreshold(thresholdConfig)).AnyTimes()
+
+			mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+			mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctl)
+			metriccache.DefaultAggregateResultFactory = mockResultFactory
+			mockQuerier := mock_metriccache.NewMockQuerier(ctl)
+			mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+			mockQueryResult := func(queryMeta metriccache.MetricMeta, value float64) {
+				result := mock_metriccache.NewMockAggregateResult(ctl)
+				result.EXPECT().Value(gomock.Any()).Return(value, nil).AnyTimes()
+				result.EXPECT().Count().Return(1).AnyTimes()
+				mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+				mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), gomock.Any()).SetArg(2, *result).Return(nil).AnyTimes()
+			}
+			nodeQueryMeta, err := metriccache.NodeMemoryUsageMetric.BuildQueryMeta(nil)
+			assert.NoError(t, err)
+			mockQueryResult(nodeQueryMeta, float64(60*gib))
+			for uid, used := range map[string]int64{string(lsPod.UID): 30 * gib, string(bePod.UID): 20 * gib} {
+				podQueryMeta, err := metriccache.PodMemUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod(uid))
+				assert.NoError(t, err)
+				mockQueryResult(podQueryMeta, float64(used))
+			}
+
+			m := New(&framework.Options{
+				StatesInformer:      mockStatesInformer,
+				MetricCache:         mockMetricCache,
+				Config:              framework.NewDefaultConfig(),
+				MetricAdvisorConfig: maframework.NewDefaultConfig(),
+			}).(*memorySuppress)
+			m.executor = resourceexecutor.NewTestResourceExecutor()
+			stop := make(chan struct{})
+			defer close(stop)
+			m.executor.Run(stop)
+
+			// recover the limit left by the last run if the threshold is unset after a restart
+			thresholdConfig.MemorySuppressThresholdPercent = nil
+			m.suppressBEMemory()
+			assert.Equal(t, tt.wantRecovered, helper.ReadCgroupFileContents(beCgroupDir, tt.resource))
+			assert.False(t, m.suppressed)
+
+			thresholdConfig.MemorySuppressThresholdPercent = pointer.Int64(80)
+			m.suppressBEMemory()
+			assert.Equal(t, tt.wantSuppress, helper.ReadCgroupFileContents(beCgroupDir, tt.resource))
+			assert.True(t, m.suppressed)
+
+			// recover if the threshold is unset
+			thresholdConfig.MemorySuppressThresholdPercent = nil
+			m.suppressBEMemory()
+			assert.Equal(t, tt.wantRecovered, helper.ReadCgroupFileContents(beCgroupDir, tt.resource))
+			assert.False(t, m.suppressed)
+		})
+	}
+}
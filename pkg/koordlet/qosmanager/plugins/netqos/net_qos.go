@@ -0,0 +1,529 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	NetQOSName = "NetQOS"
+
+	// IfbInterfaceName is the name of the ifb device which the ingress traffic is redirected to.
+	IfbInterfaceName = "koord-ifb0"
+
+	// htb handles and classes
+	// 1:    root qdisc
+	// 1:1   root class, rate = ceil = total bandwidth
+	// 1:2   LSR class
+	// 1:3   LS class (default class for unclassified traffic)
+	// 1:4   BE class
+	htbMajor       = 1
+	htbRootMinor   = 1
+	lsrClassMinor  = 2
+	lsClassMinor   = 3
+	beClassMinor   = 4
+	ingressHandle  = "ffff:"
+	filterPriority = "10"
+
+	// minClassRateBps is the minimum rate of a htb class since htb requires a positive rate.
+	minClassRateBps = 8 * 1000
+)
+
+var _ framework.QOSStrategy = &netQOS{}
+
+// commandRunner runs the command and returns the combined output.
+type commandRunner func(name string, args ...string) ([]byte, error)
+
+func runCommand(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).CombinedOutput()
+}
+
+// dryRunCommand records the command into the audit events and metrics without running it.
+func dryRunCommand(name string, args ...string) ([]byte, error) {
+	cmd := strings.Join(append([]string{name}, args...), " ")
+	_ = audit.V(3).Node().Reason(NetQOSName).DryRun().Message("dry-run command: %s", cmd).Do()
+	metrics.RecordQOSStrategyDryRunDecision(NetQOSName, metrics.ActionUpdate, name)
+	klog.V(4).Infof("%s: dry-run command %s", NetQOSName, cmd)
+	return nil, nil
+}
+
+// classRule is the htb class config of a qos class.
+type classRule struct {
+	Minor          int
+	IngressRateBps int64
+	IngressCeilBps int64
+	EgressRateBps  int64
+	EgressCeilBps  int64
+}
+
+// netQOSRules is the desired traffic control config of the node.
+type netQOSRules struct {
+	InterfaceName string
+	TotalBps      int64
+	Classes       []classRule
+}
+
+type netQOS struct {
+	reconcileInterval time.Duration
+	interfaceName     string
+	statesInformer    statesinformer.StatesInformer
+	executor          resourceexecutor.ResourceUpdateExecutor
+	runCommand        commandRunner
+
+	// appliedRules records the last applied tc config, tc is only re-configured when the rules change
+	appliedRules *netQOSRules
+	// appliedIngressFilters records the pod ip -> class minor of the filters on the ifb device
+	appliedIngressFilters map[string]int
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	runner := runCommand
+	if opt.Config.IsDryRun(NetQOSName) {
+		runner = dryRunCommand
+	}
+	return &netQOS{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		interfaceName:     opt.Config.NetQOSInterfaceName,
+		statesInformer:    opt.StatesInformer,
+		executor:          opt.Config.NewStrategyExecutor(NetQOSName),
+		runCommand:        runner,
+	}
+}
+
+func (n *netQOS) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.NetQOS) && n.reconcileInterval > 0
+}
+
+func (n *netQOS) Setup(context *framework.Context) {
+}
+
+func (n *netQOS) Run(stopCh <-chan struct{}) {
+	n.init(stopCh)
+	go wait.Until(n.reconcile, n.reconcileInterval, stopCh)
+}
+
+func (n *netQOS) init(stopCh <-chan struct{}) {
+	n.executor.Run(stopCh)
+	n.recoverAppliedRules()
+}
+
+// recoverAppliedRules finds the interface whose ingress is redirected to the ifb device by a previous koordlet, so
+// the leftover tc config can be cleaned up or re-configured after a restart.
+func (n *netQOS) recoverAppliedRules() {
+	out, err := n.runCommand("tc", "qdisc", "show")
+	if err != nil {
+		klog.V(4).Infof("%s: failed to show qdiscs, output %s, err: %v", NetQOSName, string(out), err)
+		return
+	}
+	for _, ifName := range parseIngressQdiscDevices(string(out)) {
+		filterOut, err := n.runCommand("tc", "filter", "show", "dev", ifName, "parent", ingressHandle)
+		if err != nil {
+			klog.V(4).Infof("%s: failed to show ingress filters of interface %s, output %s, err: %v",
+				NetQOSName, ifName, string(filterOut), err)
+			continue
+		}
+		if strings.Contains(string(filterOut), IfbInterfaceName) {
+			klog.V(4).Infof("%s: found traffic control on interface %s applied before", NetQOSName, ifName)
+			n.appliedRules = &netQOSRules{InterfaceName: ifName}
+			return
+		}
+	}
+}
+
+// parseIngressQdiscDevices returns the devices having an ingress qdisc in the output of `tc qdisc show`.
+// e.g.
+// qdisc htb 1: dev eth0 root refcnt 2 r2q 10 default 0x3 direct_packets_stat 0
+// qdisc ingress ffff: dev eth0 parent ffff:fff1 ----------------
+func parseIngressQdiscDevices(output string) []string {
+	var devices []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "qdisc" || fields[1] != "ingress" || fields[3] != "dev" {
+			continue
+		}
+		if fields[4] != IfbInterfaceName {
+			devices = append(devices, fields[4])
+		}
+	}
+	return devices
+}
+
+func (n *netQOS) reconcile() {
+	nodeSLO := n.statesInformer.GetNodeSLO()
+	if nodeSLO == nil || nodeSLO.Spec.ResourceQOSStrategy == nil {
+		klog.Warningf("%s: nodeSLO or resourceQOSStrategy is nil, skip reconcile net qos", NetQOSName)
+		n.cleanupTrafficControl()
+		return
+	}
+	if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+		klog.V(4).Infof("%s: net_cls is not supported on cgroups-v2, skip reconcile net qos", NetQOSName)
+		n.cleanupTrafficControl()
+		return
+	}
+
+	rules, err := n.calculateRules(nodeSLO.Spec.ResourceQOSStrategy)
+	if err != nil {
+		klog.Warningf("%s: failed to calculate net qos rules, err: %v", NetQOSName, err)
+		return
+	}
+	if rules == nil {
+		n.cleanupTrafficControl()
+		return
+	}
+
+	if !reflect.DeepEqual(rules, n.appliedRules) {
+		// the interface is changed, remove the config on the previous one
+		if n.appliedRules != nil && n.appliedRules.InterfaceName != rules.InterfaceName {
+			n.cleanupTrafficControl()
+		}
+		// the filters are re-added since the qdiscs may be re-created
+		n.appliedIngressFilters = nil
+		if err = n.applyTrafficControl(rules); err != nil {
+			klog.Warningf("%s: failed to apply traffic control on interface %s, err: %v",
+				NetQOSName, rules.InterfaceName, err)
+			return
+		}
+		klog.V(4).Infof("%s: apply traffic control on interface %s successfully, rules %+v",
+			NetQOSName, rules.InterfaceName, rules)
+		n.appliedRules = rules
+	}
+
+	podMetas := n.statesInformer.GetAllPods()
+	n.reconcileClassIDs(podMetas)
+	n.reconcileIngressFilters(podMetas)
+}
+
+// calculateRules returns the desired tc config according to the NetworkQOS of each qos class.
+// It returns nil when no qos class enables the network qos.
+func (n *netQOS) calculateRules(strategy *slov1alpha1.ResourceQOSStrategy) (*netQOSRules, error) {
+	classCfgs := []struct {
+		minor int
+		cfg   *slov1alpha1.ResourceQOS
+	}{
+		{minor: lsrClassMinor, cfg: strategy.LSRClass},
+		{minor: lsClassMinor, cfg: strategy.LSClass},
+		{minor: beClassMinor, cfg: strategy.BEClass},
+	}
+	enabled := false
+	for _, c := range classCfgs {
+		if isNetworkQOSEnabled(c.cfg) {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	ifName := n.interfaceName
+	if len(ifName) <= 0 {
+		defaultIfName, err := system.GetDefaultRouteInterface()
+		if err != nil {
+			return nil, fmt.Errorf("get default route interface failed, err: %w", err)
+		}
+		ifName = defaultIfName
+	}
+	speedMbps, err := system.GetNetInterfaceSpeedMbps(ifName)
+	if err != nil {
+		return nil, err
+	}
+	totalBps := speedMbps * 1000 * 1000
+
+	rules := &netQOSRules{
+		InterfaceName: ifName,
+		TotalBps:      totalBps,
+	}
+	for _, c := range classCfgs {
+		// the disabled qos class can use the whole bandwidth
+		networkQOS := &slov1alpha1.NetworkQOS{}
+		if isNetworkQOSEnabled(c.cfg) {
+			networkQOS = &c.cfg.NetworkQOS.NetworkQOS
+		}
+		ingressRate, ingressCeil := getRateAndCeil(totalBps, networkQOS.IngressRequestPercent, networkQOS.IngressLimitPercent)
+		egressRate, egressCeil := getRateAndCeil(totalBps, networkQOS.EgressRequestPercent, networkQOS.EgressLimitPercent)
+		rules.Classes = append(rules.Classes, classRule{
+			Minor:          c.minor,
+			IngressRateBps: ingressRate,
+			IngressCeilBps: ingressCeil,
+			EgressRateBps:  egressRate,
+			EgressCeilBps:  egressCeil,
+		})
+	}
+	return rules, nil
+}
+
+func isNetworkQOSEnabled(cfg *slov1alpha1.ResourceQOS) bool {
+	return cfg != nil && cfg.NetworkQOS != nil && cfg.NetworkQOS.Enable != nil && *cfg.NetworkQOS.Enable
+}
+
+// getRateAndCeil calculates the htb rate and ceil in bps by the request and limit percentages.
+func getRateAndCeil(totalBps int64, requestPercent, limitPercent *int64) (int64, int64) {
+	rate := int64(minClassRateBps)
+	if requestPercent != nil && *requestPercent > 0 {
+		rate = totalBps * *requestPercent / 100
+	}
+	ceil := totalBps
+	if limitPercent != nil && *limitPercent > 0 {
+		ceil = totalBps * *limitPercent / 100
+	}
+	if ceil < rate {
+		ceil = rate
+	}
+	return rate, ceil
+}
+
+// applyTrafficControl configures htb on the egress of the interface and the ifb device which the ingress traffic of
+// the interface redirected to. The egress packets are classified by the cgroup classifier according to the
+// net_cls.classid, while the ingress packets are classified by the destination pod ips since the socket is unknown
+// on the ifb device.
+// NOTE: The ingress traffic to the host network pods or encapsulated by the overlay network falls into the default
+// class (LS).
+func (n *netQOS) applyTrafficControl(rules *netQOSRules) error {
+	// egress
+	if err := n.applyHTB(rules.InterfaceName, rules.TotalBps, rules.Classes, false); err != nil {
+		return err
+	}
+
+	// ingress: redirect to the ifb device, and then shape on its egress
+	if _, err := n.runCommand("ip", "link", "show", IfbInterfaceName); err != nil {
+		if out, err := n.runCommand("ip", "link", "add", IfbInterfaceName, "type", "ifb"); err != nil {
+			return fmt.Errorf("add ifb device failed, output %s, err: %w", string(out), err)
+		}
+	}
+	cmds := [][]string{
+		{"ip", "link", "set", IfbInterfaceName, "up"},
+		{"tc", "qdisc", "replace", "dev", rules.InterfaceName, "handle", ingressHandle, "ingress"},
+		{"tc", "filter", "replace", "dev", rules.InterfaceName, "parent", ingressHandle, "protocol", "all",
+			"prio", filterPriority, "u32", "match", "u32", "0", "0",
+			"action", "mirred", "egress", "redirect", "dev", IfbInterfaceName},
+	}
+	if err := n.runCommands(cmds); err != nil {
+		return err
+	}
+	return n.applyHTB(IfbInterfaceName, rules.TotalBps, rules.Classes, true)
+}
+
+func (n *netQOS) applyHTB(ifName string, totalBps int64, classes []classRule, isIngress bool) error {
+	rootClassID := getHTBClassID(htbRootMinor)
+	cmds := [][]string{
+		{"tc", "qdisc", "replace", "dev", ifName, "root", "handle", fmt.Sprintf("%d:", htbMajor),
+			"htb", "default", strconv.Itoa(lsClassMinor)},
+		{"tc", "class", "replace", "dev", ifName, "parent", fmt.Sprintf("%d:", htbMajor), "classid", rootClassID,
+			"htb", "rate", formatBps(totalBps), "ceil", formatBps(totalBps)},
+	}
+	for _, c := range classes {
+		rate, ceil := c.EgressRateBps, c.EgressCeilBps
+		if isIngress {
+			rate, ceil = c.IngressRateBps, c.IngressCeilBps
+		}
+		cmds = append(cmds, []string{"tc", "class", "replace", "dev", ifName, "parent", rootClassID,
+			"classid", getHTBClassID(c.Minor), "htb", "rate", formatBps(rate), "ceil", formatBps(ceil)})
+	}
+	// the ingress filters are reconciled by the pod ips
+	if !isIngress {
+		cmds = append(cmds, []string{"tc", "filter", "replace", "dev", ifName, "parent", fmt.Sprintf("%d:", htbMajor),
+			"protocol", "all", "prio", filterPriority, "handle", fmt.Sprintf("%d:", htbMajor), "cgroup"})
+	}
+	return n.runCommands(cmds)
+}
+
+// reconcileIngressFilters classifies the ingress traffic on the ifb device by the destination pod ips.
+// The filters are re-added only when the pod ips or their classes change.
+func (n *netQOS) reconcileIngressFilters(podMetas []*statesinformer.PodMeta) {
+	if n.appliedRules == nil {
+		return
+	}
+	desired := getPodIPClassMinors(podMetas)
+	if n.appliedIngressFilters != nil && reflect.DeepEqual(desired, n.appliedIngressFilters) {
+		return
+	}
+
+	// remove all the pod ip filters, it fails if there is no filter
+	if out, err := n.runCommand("tc", "filter", "del", "dev", IfbInterfaceName, "parent", fmt.Sprintf("%d:", htbMajor),
+		"prio", filterPriority); err != nil {
+		klog.V(5).Infof("%s: failed to delete ingress filters, output %s, err: %v", NetQOSName, string(out), err)
+	}
+	ips := make([]string, 0, len(desired))
+	for ip := range desired {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	var cmds [][]string
+	for _, ip := range ips {
+		protocol, match, prefix := "ip", "ip", "/32"
+		if net.ParseIP(ip).To4() == nil {
+			protocol, match, prefix = "ipv6", "ip6", "/128"
+		}
+		cmds = append(cmds, []string{"tc", "filter", "add", "dev", IfbInterfaceName, "parent", fmt.Sprintf("%d:", htbMajor),
+			"protocol", protocol, "prio", filterPriority, "u32", "match", match, "dst", ip + prefix,
+			"flowid", getHTBClassID(desired[ip])})
+	}
+	if err := n.runCommands(cmds); err != nil {
+		klog.Warningf("%s: failed to add ingress filters, err: %v", NetQOSName, err)
+		n.appliedIngressFilters = nil
+		return
+	}
+	klog.V(5).Infof("%s: reconcile %v ingress filters successfully", NetQOSName, len(ips))
+	n.appliedIngressFilters = desired
+}
+
+// getPodIPClassMinors returns the class minors of the pod ips. The host network pods are skipped since they share
+// the node ips.
+func getPodIPClassMinors(podMetas []*statesinformer.PodMeta) map[string]int {
+	podIPs := map[string]int{}
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		if pod == nil || pod.Spec.HostNetwork {
+			continue
+		}
+		minor := getPodClassMinor(pod)
+		if minor <= 0 {
+			continue
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			if net.ParseIP(podIP.IP) != nil {
+				podIPs[podIP.IP] = minor
+			}
+		}
+		if net.ParseIP(pod.Status.PodIP) != nil {
+			podIPs[pod.Status.PodIP] = minor
+		}
+	}
+	return podIPs
+}
+
+// cleanupTrafficControl removes the tc config applied by the koordlet.
+func (n *netQOS) cleanupTrafficControl() {
+	if n.appliedRules == nil {
+		return
+	}
+	ifName := n.appliedRules.InterfaceName
+	cmds := [][]string{
+		{"tc", "qdisc", "del", "dev", ifName, "root"},
+		{"tc", "qdisc", "del", "dev", ifName, "handle", ingressHandle, "ingress"},
+		{"ip", "link", "del", IfbInterfaceName},
+	}
+	for _, cmd := range cmds {
+		if out, err := n.runCommand(cmd[0], cmd[1:]...); err != nil {
+			klog.V(4).Infof("%s: failed to run %v, output %s, err: %v", NetQOSName, cmd, string(out), err)
+		}
+	}
+	klog.V(4).Infof("%s: cleanup traffic control on interface %s", NetQOSName, ifName)
+	n.appliedRules = nil
+	n.appliedIngressFilters = nil
+}
+
+func (n *netQOS) runCommands(cmds [][]string) error {
+	for _, cmd := range cmds {
+		if out, err := n.runCommand(cmd[0], cmd[1:]...); err != nil {
+			return fmt.Errorf("run %v failed, output %s, err: %w", cmd, string(out), err)
+		}
+	}
+	return nil
+}
+
+// reconcileClassIDs sets the net_cls.classid of the pods and containers according to their qos classes.
+func (n *netQOS) reconcileClassIDs(podMetas []*statesinformer.PodMeta) {
+	var updaters []resourceexecutor.ResourceUpdater
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		minor := getPodClassMinor(pod)
+		if minor <= 0 {
+			continue
+		}
+		classID := strconv.FormatInt(getNetClsClassID(minor), 10)
+
+		podUpdater, err := newNetClsUpdater(podMeta.CgroupDir, classID, pod)
+		if err != nil {
+			klog.V(4).Infof("%s: failed to get net_cls updater for pod %s, err: %v", NetQOSName, util.GetPodKey(pod), err)
+			continue
+		}
+		updaters = append(updaters, podUpdater)
+
+		for i := range pod.Status.ContainerStatuses {
+			containerStat := &pod.Status.ContainerStatuses[i]
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+			if err != nil {
+				klog.V(5).Infof("%s: failed to get cgroup dir of container %s/%s, err: %v",
+					NetQOSName, util.GetPodKey(pod), containerStat.Name, err)
+				continue
+			}
+			containerUpdater, err := newNetClsUpdater(containerDir, classID, pod)
+			if err != nil {
+				continue
+			}
+			updaters = append(updaters, containerUpdater)
+		}
+	}
+	n.executor.UpdateBatch(true, updaters...)
+}
+
+func newNetClsUpdater(cgroupDir string, classID string, pod *corev1.Pod) (resourceexecutor.ResourceUpdater, error) {
+	eventHelper := audit.V(3).Pod(pod.Namespace, pod.Name).Reason("NetQOS").Message("update %s/%s to %s",
+		cgroupDir, system.NetClsClassIdName, classID)
+	return resourceexecutor.DefaultCgroupUpdaterFactory.New(system.NetClsClassIdName, cgroupDir, classID, eventHelper)
+}
+
+func getPodClassMinor(pod *corev1.Pod) int {
+	switch extension.GetPodQoSClassWithDefault(pod) {
+	case extension.QoSLSE, extension.QoSLSR:
+		return lsrClassMinor
+	case extension.QoSLS:
+		return lsClassMinor
+	case extension.QoSBE:
+		return beClassMinor
+	}
+	return -1
+}
+
+// getNetClsClassID returns the net_cls.classid of the htb class, whose format is 0xAAAABBBB, AAAA is the major
+// handle and BBBB is the minor handle.
+func getNetClsClassID(minor int) int64 {
+	return int64(htbMajor)<<16 | int64(minor)
+}
+
+func getHTBClassID(minor int) string {
+	return fmt.Sprintf("%d:%d", htbMajor, minor)
+}
+
+func formatBps(bps int64) string {
+	return fmt.Sprintf("%dbit", bps)
+}
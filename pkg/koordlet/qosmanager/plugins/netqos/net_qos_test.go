@@ -0,0 +1,368 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_getRateAndCeil(t *testing.T) {
+	tests := []struct {
+		name           string
+		totalBps       int64
+		requestPercent *int64
+		limitPercent   *int64
+		wantRate       int64
+		wantCeil       int64
+	}{
+		{
+			name:     "use default",
+			totalBps: 1000000000,
+			wantRate: minClassRateBps,
+			wantCeil: 1000000000,
+		},
+		{
+			name:           "calculate by percent",
+			totalBps:       1000000000,
+			requestPercent: pointer.Int64(20),
+			limitPercent:   pointer.Int64(50),
+			wantRate:       200000000,
+			wantCeil:       500000000,
+		},
+		{
+			name:           "ceil no less than rate",
+			totalBps:       1000000000,
+			requestPercent: pointer.Int64(60),
+			limitPercent:   pointer.Int64(50),
+			wantRate:       600000000,
+			wantCeil:       600000000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRate, gotCeil := getRateAndCeil(tt.totalBps, tt.requestPercent, tt.limitPercent)
+			assert.Equal(t, tt.wantRate, gotRate)
+			assert.Equal(t, tt.wantCeil, gotCeil)
+		})
+	}
+}
+
+func Test_netQOS_calculateRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifName   string
+		strategy *slov1alpha1.ResourceQOSStrategy
+		want     *netQOSRules
+		wantErr  bool
+	}{
+		{
+			name: "network qos not enabled",
+			strategy: &slov1alpha1.ResourceQOSStrategy{
+				BEClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable: pointer.Bool(false),
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name:   "interface speed unknown",
+			ifName: "eth1",
+			strategy: &slov1alpha1.ResourceQOSStrategy{
+				BEClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable: pointer.Bool(true),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "limit be class on the default route interface",
+			strategy: &slov1alpha1.ResourceQOSStrategy{
+				LSClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable: pointer.Bool(false),
+					},
+				},
+				BEClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable: pointer.Bool(true),
+						NetworkQOS: slov1alpha1.NetworkQOS{
+							IngressRequestPercent: pointer.Int64(10),
+							IngressLimitPercent:   pointer.Int64(40),
+							EgressRequestPercent:  pointer.Int64(20),
+							EgressLimitPercent:    pointer.Int64(50),
+						},
+					},
+				},
+			},
+			want: &netQOSRules{
+				InterfaceName: "eth0",
+				TotalBps:      10000000000,
+				Classes: []classRule{
+					{
+						Minor:          lsrClassMinor,
+						IngressRateBps: minClassRateBps,
+						IngressCeilBps: 10000000000,
+						EgressRateBps:  minClassRateBps,
+						EgressCeilBps:  10000000000,
+					},
+					{
+						Minor:          lsClassMinor,
+						IngressRateBps: minClassRateBps,
+						IngressCeilBps: 10000000000,
+						EgressRateBps:  minClassRateBps,
+						EgressCeilBps:  10000000000,
+					},
+					{
+						Minor:          beClassMinor,
+						IngressRateBps: 1000000000,
+						IngressCeilBps: 4000000000,
+						EgressRateBps:  2000000000,
+						EgressCeilBps:  5000000000,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.WriteProcSubFileContents(system.ProcNetRouteName, `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	FDFFA8C0	0003	0	0	0	00000000	0	0	0
+`)
+			helper.WriteFileContents(filepath.Join(system.SysNetSubDir, "eth0", system.SysNetSpeedSubPath), "10000\n")
+
+			n := &netQOS{interfaceName: tt.ifName}
+			got, gotErr := n.calculateRules(tt.strategy)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_netQOS_reconcile(t *testing.T) {
+	testingNodeSLO := &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				BEClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable: pointer.Bool(true),
+						NetworkQOS: slov1alpha1.NetworkQOS{
+							EgressLimitPercent: pointer.Int64(50),
+						},
+					},
+				},
+			},
+		},
+	}
+	testingPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod0",
+				Namespace: "default",
+				UID:       "p0",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSBE),
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				PodIP: "10.0.0.2",
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "container0",
+						ContainerID: "containerd://c0",
+					},
+				},
+			},
+		},
+		CgroupDir: "kubepods.slice/kubepods-besteffort.slice/p0",
+	}
+	testingContainerDir := "kubepods.slice/kubepods-besteffort.slice/p0/cri-containerd-c0.scope"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testingPodMeta}).AnyTimes()
+	statesInformer.EXPECT().GetNodeSLO().Return(testingNodeSLO).AnyTimes()
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(false)
+	helper.SetResourcesSupported(true, system.NetClsClassId)
+	helper.WriteCgroupFileContents(testingPodMeta.CgroupDir, system.NetClsClassId, "0")
+	helper.WriteCgroupFileContents(testingContainerDir, system.NetClsClassId, "0")
+	helper.WriteProcSubFileContents(system.ProcNetRouteName, `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	FDFFA8C0	0003	0	0	0	00000000	0	0	0
+`)
+	helper.WriteFileContents(filepath.Join(system.SysNetSubDir, "eth0", system.SysNetSpeedSubPath), "1000\n")
+
+	var gotCmds []string
+	n := New(&framework.Options{
+		StatesInformer: statesInformer,
+		Config:         framework.NewDefaultConfig(),
+	}).(*netQOS)
+	n.executor = resourceexecutor.NewTestResourceExecutor()
+	n.runCommand = func(name string, args ...string) ([]byte, error) {
+		gotCmds = append(gotCmds, strings.Join(append([]string{name}, args...), " "))
+		return nil, nil
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	n.init(stop)
+
+	n.reconcile()
+	assert.Contains(t, gotCmds, "tc qdisc replace dev eth0 root handle 1: htb default 3")
+	assert.Contains(t, gotCmds, "tc class replace dev eth0 parent 1:1 classid 1:4 htb rate 8000bit ceil 500000000bit")
+	assert.Contains(t, gotCmds, "tc class replace dev koord-ifb0 parent 1:1 classid 1:4 htb rate 8000bit ceil 1000000000bit")
+	assert.Contains(t, gotCmds, "tc filter replace dev eth0 parent 1: protocol all prio 10 handle 1: cgroup")
+	assert.NotContains(t, gotCmds, "tc filter replace dev koord-ifb0 parent 1: protocol all prio 10 handle 1: cgroup")
+	assert.Contains(t, gotCmds, "tc filter add dev koord-ifb0 parent 1: protocol ip prio 10 u32 match ip dst 10.0.0.2/32 flowid 1:4")
+	assert.Equal(t, "65540", helper.ReadCgroupFileContents(testingPodMeta.CgroupDir, system.NetClsClassId))
+	assert.Equal(t, "65540", helper.ReadCgroupFileContents(testingContainerDir, system.NetClsClassId))
+
+	// tc is not re-configured if rules are unchanged
+	gotCmds = nil
+	n.reconcile()
+	assert.Nil(t, gotCmds)
+
+	// cleanup if network qos is disabled
+	testingNodeSLO.Spec.ResourceQOSStrategy.BEClass.NetworkQOS.Enable = pointer.Bool(false)
+	n.reconcile()
+	assert.Contains(t, gotCmds, "tc qdisc del dev eth0 root")
+	assert.Contains(t, gotCmds, "ip link del koord-ifb0")
+	assert.Nil(t, n.appliedRules)
+	assert.Nil(t, n.appliedIngressFilters)
+
+	// cleanup if the resource qos strategy is removed
+	testingNodeSLO.Spec.ResourceQOSStrategy.BEClass.NetworkQOS.Enable = pointer.Bool(true)
+	n.reconcile()
+	assert.NotNil(t, n.appliedRules)
+	gotCmds = nil
+	testingNodeSLO.Spec.ResourceQOSStrategy = nil
+	n.reconcile()
+	assert.Contains(t, gotCmds, "tc qdisc del dev eth0 root")
+	assert.Contains(t, gotCmds, "ip link del koord-ifb0")
+	assert.Nil(t, n.appliedRules)
+}
+
+func Test_netQOS_recoverAppliedRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs map[string]string
+		want    *netQOSRules
+	}{
+		{
+			name: "no traffic control applied",
+			outputs: map[string]string{
+				"tc qdisc show": "qdisc noqueue 0: dev lo root refcnt 2\nqdisc mq 0: dev eth0 root\n",
+			},
+			want: nil,
+		},
+		{
+			name: "ingress qdisc of other tools",
+			outputs: map[string]string{
+				"tc qdisc show":                        "qdisc ingress ffff: dev eth0 parent ffff:fff1 ----------------\n",
+				"tc filter show dev eth0 parent ffff:": "filter protocol all pref 49152 bpf chain 0\n",
+			},
+			want: nil,
+		},
+		{
+			name: "traffic control applied before",
+			outputs: map[string]string{
+				"tc qdisc show": `qdisc htb 1: dev eth0 root refcnt 2 r2q 10 default 0x3 direct_packets_stat 0
+qdisc ingress ffff: dev eth0 parent ffff:fff1 ----------------
+qdisc htb 1: dev koord-ifb0 root refcnt 2 r2q 10 default 0x3 direct_packets_stat 0
+`,
+				"tc filter show dev eth0 parent ffff:": `filter protocol all pref 10 u32 chain 0
+filter protocol all pref 10 u32 chain 0 fh 800::800 order 2048 key ht 800 bkt 0 terminal flowid ??? not_in_hw
+  match 00000000/00000000 at 0
+	action order 1: mirred (Egress Redirect to device koord-ifb0) stolen
+`,
+			},
+			want: &netQOSRules{InterfaceName: "eth0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &netQOS{
+				runCommand: func(name string, args ...string) ([]byte, error) {
+					return []byte(tt.outputs[strings.Join(append([]string{name}, args...), " ")]), nil
+				},
+			}
+			n.recoverAppliedRules()
+			assert.Equal(t, tt.want, n.appliedRules)
+		})
+	}
+}
+
+func Test_getPodIPClassMinors(t *testing.T) {
+	podMetas := []*statesinformer.PodMeta{
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{extension.LabelPodQoS: string(extension.QoSBE)}},
+				Status: corev1.PodStatus{
+					PodIP:  "10.0.0.2",
+					PodIPs: []corev1.PodIP{{IP: "10.0.0.2"}, {IP: "fd00::2"}},
+				},
+			},
+		},
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{extension.LabelPodQoS: string(extension.QoSLS)}},
+				Status:     corev1.PodStatus{PodIP: "10.0.0.3"},
+			},
+		},
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{extension.LabelPodQoS: string(extension.QoSBE)}},
+				Spec:       corev1.PodSpec{HostNetwork: true},
+				Status:     corev1.PodStatus{PodIP: "192.168.0.1"},
+			},
+		},
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{extension.LabelPodQoS: string(extension.QoSBE)}},
+			},
+		},
+	}
+	assert.Equal(t, map[string]int{
+		"10.0.0.2": beClassMinor,
+		"fd00::2":  beClassMinor,
+		"10.0.0.3": lsClassMinor,
+	}, getPodIPClassMinors(podMetas))
+}
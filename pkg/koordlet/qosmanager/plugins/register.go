@@ -23,8 +23,12 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuburst"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuevict"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpusuppress"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/interference"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memoryevict"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memorysuppress"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/netqos"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/resctrl"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/swaphugepage"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/sysreconcile"
 )
 
@@ -35,8 +39,12 @@ var (
 		cpuburst.CPUBurstName:                  cpuburst.New,
 		cpuevict.CPUEvictName:                  cpuevict.New,
 		cpusuppress.CPUSuppressName:            cpusuppress.New,
+		interference.InterferenceDetectName:    interference.New,
 		memoryevict.MemoryEvictName:            memoryevict.New,
+		memorysuppress.MemorySuppressName:      memorysuppress.New,
+		netqos.NetQOSName:                      netqos.New,
 		resctrl.ResctrlReconcileName:           resctrl.New,
+		swaphugepage.SwapHugepageQOSName:       swaphugepage.New,
 		sysreconcile.SystemConfigReconcileName: sysreconcile.New,
 	}
 )
@@ -0,0 +1,275 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// PodResctrlGroupPrefix is the prefix of the resctrl groups created for the pods with the resctrl annotation.
+	// The groups created by koordlet get removed when no pod uses them.
+	PodResctrlGroupPrefix = "koord-"
+
+	podResctrlGroupPodPrefix      = PodResctrlGroupPrefix + "pod-"
+	podResctrlGroupWorkloadPrefix = PodResctrlGroupPrefix + "workload-"
+)
+
+// podResctrlGroup is a resctrl group dedicated to a pod or the pods of a workload.
+type podResctrlGroup struct {
+	name       string
+	resctrlQOS *slov1alpha1.ResctrlQOS
+	// pod is the owner pod whose annotation decides the policy of the group
+	pod *corev1.Pod
+}
+
+func getPodResctrlGroupName(pod *corev1.Pod, cfg *slov1alpha1.PodResctrlQOSConfig) (string, error) {
+	if cfg.Group == "" {
+		return podResctrlGroupPodPrefix + string(pod.UID), nil
+	}
+	if errs := validation.IsDNS1123Label(cfg.Group); len(errs) > 0 {
+		return "", fmt.Errorf("invalid group %s, err: %s", cfg.Group, strings.Join(errs, ";"))
+	}
+	return podResctrlGroupWorkloadPrefix + cfg.Group, nil
+}
+
+// getDesiredPodResctrlGroups returns the resctrl groups required by the pods and the group name of each pod UID.
+// The number of the groups is limited by maxGroups, where the existing groups are kept first and then the groups of
+// the older pods. The pods beyond the limit fall back to the resctrl groups of their QoS classes.
+func getDesiredPodResctrlGroups(podMetas []*statesinformer.PodMeta, existingGroups map[string]struct{},
+	maxGroups int) (map[string]*podResctrlGroup, map[string]string) {
+	candidates := map[string]*podResctrlGroup{}
+	podGroupNames := map[string]string{}
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		// only Running and Pending pods are considered
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		cfg, err := slov1alpha1.GetPodResctrlQoSConfig(pod)
+		if err != nil {
+			klog.Warningf("failed to parse resctrl config of pod %s, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		if cfg == nil {
+			continue
+		}
+		name, err := getPodResctrlGroupName(pod, cfg)
+		if err != nil {
+			klog.Warningf("failed to get resctrl group of pod %s, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		podGroupNames[string(pod.UID)] = name
+
+		group, ok := candidates[name]
+		if !ok || isOlderPod(pod, group.pod) {
+			if ok && !reflect.DeepEqual(&cfg.ResctrlQOS, group.resctrlQOS) {
+				klog.V(4).Infof("resctrl config of group %s is decided by the older pod %s instead of pod %s",
+					name, util.GetPodKey(pod), util.GetPodKey(group.pod))
+			}
+			candidates[name] = &podResctrlGroup{name: name, resctrlQOS: &cfg.ResctrlQOS, pod: pod}
+		}
+	}
+
+	groupList := make([]*podResctrlGroup, 0, len(candidates))
+	for _, group := range candidates {
+		groupList = append(groupList, group)
+	}
+	sort.Slice(groupList, func(i, j int) bool {
+		_, existI := existingGroups[groupList[i].name]
+		_, existJ := existingGroups[groupList[j].name]
+		if existI != existJ {
+			return existI
+		}
+		if isOlderPod(groupList[i].pod, groupList[j].pod) {
+			return true
+		}
+		if isOlderPod(groupList[j].pod, groupList[i].pod) {
+			return false
+		}
+		return groupList[i].name < groupList[j].name
+	})
+
+	groups := map[string]*podResctrlGroup{}
+	for i, group := range groupList {
+		if i >= maxGroups {
+			klog.Warningf("skip resctrl group %s for pod %s, the number of groups exceeds the limit %d",
+				group.name, util.GetPodKey(group.pod), maxGroups)
+			continue
+		}
+		groups[group.name] = group
+	}
+	for podUID, name := range podGroupNames {
+		if _, ok := groups[name]; !ok {
+			delete(podGroupNames, podUID)
+		}
+	}
+	return groups, podGroupNames
+}
+
+func isOlderPod(a, b *corev1.Pod) bool {
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
+
+// getMaxPodResctrlGroups returns the number of the CLOSIDs left for the pod resctrl groups, excluding the resctrl
+// root and the groups of the QoS classes.
+func getMaxPodResctrlGroups() (int, error) {
+	numClosids, err := system.ReadCatL3NumClosids()
+	if err != nil {
+		return 0, err
+	}
+	maxGroups := numClosids - 1 - len(resctrlGroupList)
+	if maxGroups < 0 {
+		maxGroups = 0
+	}
+	return maxGroups, nil
+}
+
+// listPodResctrlGroups returns the existing resctrl groups created for pods.
+func listPodResctrlGroups() (map[string]struct{}, error) {
+	entries, err := os.ReadDir(system.GetResctrlSubsystemDirPath())
+	if err != nil {
+		return nil, err
+	}
+	groups := map[string]struct{}{}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), PodResctrlGroupPrefix) {
+			groups[entry.Name()] = struct{}{}
+		}
+	}
+	return groups, nil
+}
+
+// reconcilePodResctrlGroups creates the resctrl groups for the pods with the resctrl annotation, applies their
+// policies, and removes the groups no longer used. Only the groups created or used by koordlet are removed, so the
+// groups left by others are kept. The tasks of the pods are reconciled in reconcileResctrlGroups.
+func (r *resctrlReconcile) reconcilePodResctrlGroups() {
+	existingGroups, err := listPodResctrlGroups()
+	if err != nil {
+		klog.Warningf("failed to list pod resctrl groups, err: %v", err)
+		return
+	}
+	maxGroups, err := getMaxPodResctrlGroups()
+	if err != nil {
+		klog.Warningf("failed to get the max number of pod resctrl groups, err: %v", err)
+		return
+	}
+	groups, podGroupNames := getDesiredPodResctrlGroups(r.statesInformer.GetAllPods(), existingGroups, maxGroups)
+
+	// remove the groups no longer used, the tasks are moved to the resctrl root by the kernel
+	for name := range existingGroups {
+		if _, ok := groups[name]; ok {
+			continue
+		}
+		if _, ok := r.ownedPodResctrlGroups[name]; !ok {
+			klog.V(5).Infof("skip removing pod resctrl group %s, it is not created by koordlet", name)
+			continue
+		}
+		if r.dryRun {
+			klog.V(4).Infof("dry-run remove pod resctrl group %s", name)
+			continue
+		}
+		if err = os.RemoveAll(system.GetResctrlGroupRootDirPath(name)); err != nil {
+			klog.Warningf("failed to remove pod resctrl group %s, err: %v", name, err)
+			continue
+		}
+		delete(r.ownedPodResctrlGroups, name)
+		klog.V(4).Infof("remove pod resctrl group %s successfully", name)
+	}
+
+	var groupNames []string
+	for name := range groups {
+		if r.dryRun {
+			groupNames = append(groupNames, name)
+			continue
+		}
+		if updated, err := initCatGroupIfNotExist(name); err != nil {
+			klog.Warningf("failed to init pod resctrl group %s, err: %v", name, err)
+			delete(groups, name)
+			continue
+		} else if updated {
+			klog.V(4).Infof("create pod resctrl group %s for pod %s successfully", name, util.GetPodKey(groups[name].pod))
+		}
+		r.ownedPodResctrlGroups[name] = struct{}{}
+		groupNames = append(groupNames, name)
+	}
+	for podUID, name := range podGroupNames {
+		if _, ok := groups[name]; !ok {
+			delete(podGroupNames, podUID)
+		}
+	}
+	sort.Strings(groupNames)
+	r.podResctrlGroups = podGroupNames
+	if len(groupNames) <= 0 {
+		return
+	}
+
+	nodeCPUInfo, cbm, l3Num, err := r.getCatL3Info()
+	if err != nil {
+		klog.Warningf("failed to apply policies for pod resctrl groups, err: %v", err)
+		return
+	}
+	for _, name := range groupNames {
+		if err = r.calculateAndApplyPodResctrlGroupPolicy(groups[name], cbm, l3Num, nodeCPUInfo.BasicInfo); err != nil {
+			klog.Warningf("failed to apply policy for pod resctrl group %s, err: %v", name, err)
+		}
+	}
+}
+
+func (r *resctrlReconcile) calculateAndApplyPodResctrlGroupPolicy(group *podResctrlGroup, cbm uint, l3Num int,
+	cpuBasicInfo extension.CPUBasicInfo) error {
+	var updaters []resourceexecutor.ResourceUpdater
+	cfg := group.resctrlQOS
+	if cfg.CATRangeStartPercent != nil && cfg.CATRangeEndPercent != nil {
+		l3MaskValue, err := system.CalculateCatL3MaskValue(cbm, *cfg.CATRangeStartPercent, *cfg.CATRangeEndPercent)
+		if err != nil {
+			return fmt.Errorf("failed to calculate l3 cat schemata, err: %w", err)
+		}
+		updaters = append(updaters, resourceexecutor.NewResctrlL3SchemataResource(group.name, l3MaskValue, l3Num))
+	}
+	if cfg.MBAPercent != nil {
+		if memBwPercent := calculateMbaPercentForGroup(group.name, cfg.MBAPercent, cpuBasicInfo); memBwPercent != "" {
+			updaters = append(updaters, resourceexecutor.NewResctrlMbSchemataResource(group.name, memBwPercent, l3Num))
+		}
+	}
+
+	// NOTE: the updates should not be cacheable, since a removed group can be created again with the same name
+	for _, updater := range updaters {
+		isUpdated, err := r.executor.Update(false, updater)
+		if err != nil {
+			return fmt.Errorf("failed to write schemata, err: %w", err)
+		}
+		klog.V(5).Infof("apply policy for pod resctrl group %s finished, schemata %v, isUpdated %v",
+			group.name, updater.Value(), isUpdated)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func newTestResctrlPodMeta(name string, qosClass extension.QoSClass, resctrlAnno string, createTime time.Time) *statesinformer.PodMeta {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(createTime),
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(qosClass),
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "container0",
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "container0",
+					ContainerID: "containerd://" + name + "-c0",
+				},
+			},
+		},
+	}
+	if resctrlAnno != "" {
+		pod.Annotations[slov1alpha1.AnnotationPodResctrlQoS] = resctrlAnno
+	}
+	return &statesinformer.PodMeta{
+		Pod:       pod,
+		CgroupDir: "kubepods.slice/" + name,
+	}
+}
+
+func Test_getDesiredPodResctrlGroups(t *testing.T) {
+	now := time.Now()
+	testPodWithoutAnno := newTestResctrlPodMeta("pod0", extension.QoSLS, "", now)
+	testPodDedicated := newTestResctrlPodMeta("pod1", extension.QoSLS, `{"catRangeStartPercent":0,"catRangeEndPercent":50}`, now.Add(-time.Hour))
+	testPodWorkload := newTestResctrlPodMeta("pod2", extension.QoSLS, `{"group":"db","mbaPercent":50}`, now.Add(-2*time.Hour))
+	testPodWorkload1 := newTestResctrlPodMeta("pod3", extension.QoSLSR, `{"group":"db","mbaPercent":80}`, now)
+	testPodInvalidGroup := newTestResctrlPodMeta("pod4", extension.QoSLS, `{"group":"Invalid_Group"}`, now)
+	testPodInvalidAnno := newTestResctrlPodMeta("pod5", extension.QoSLS, `{"group":`, now)
+	testPodTerminated := newTestResctrlPodMeta("pod6", extension.QoSLS, `{"mbaPercent":50}`, now)
+	testPodTerminated.Pod.Status.Phase = corev1.PodSucceeded
+	testPodNew := newTestResctrlPodMeta("pod7", extension.QoSLS, `{"mbaPercent":50}`, now.Add(time.Hour))
+	testPods := []*statesinformer.PodMeta{
+		testPodWithoutAnno,
+		testPodDedicated,
+		testPodWorkload,
+		testPodWorkload1,
+		testPodInvalidGroup,
+		testPodInvalidAnno,
+		testPodTerminated,
+		testPodNew,
+	}
+
+	tests := []struct {
+		name              string
+		existingGroups    map[string]struct{}
+		maxGroups         int
+		wantGroups        map[string]*slov1alpha1.ResctrlQOS
+		wantPodGroupNames map[string]string
+	}{
+		{
+			name:              "no group allowed",
+			maxGroups:         0,
+			wantGroups:        map[string]*slov1alpha1.ResctrlQOS{},
+			wantPodGroupNames: map[string]string{},
+		},
+		{
+			name:      "create groups for all pods",
+			maxGroups: 10,
+			wantGroups: map[string]*slov1alpha1.ResctrlQOS{
+				"koord-pod-pod1": {CATRangeStartPercent: pointer.Int64(0), CATRangeEndPercent: pointer.Int64(50)},
+				// the config is decided by the oldest pod
+				"koord-workload-db": {MBAPercent: pointer.Int64(50)},
+				"koord-pod-pod7":    {MBAPercent: pointer.Int64(50)},
+			},
+			wantPodGroupNames: map[string]string{
+				"pod1": "koord-pod-pod1",
+				"pod2": "koord-workload-db",
+				"pod3": "koord-workload-db",
+				"pod7": "koord-pod-pod7",
+			},
+		},
+		{
+			name:      "limit groups by pod creation time",
+			maxGroups: 2,
+			wantGroups: map[string]*slov1alpha1.ResctrlQOS{
+				"koord-pod-pod1":    {CATRangeStartPercent: pointer.Int64(0), CATRangeEndPercent: pointer.Int64(50)},
+				"koord-workload-db": {MBAPercent: pointer.Int64(50)},
+			},
+			wantPodGroupNames: map[string]string{
+				"pod1": "koord-pod-pod1",
+				"pod2": "koord-workload-db",
+				"pod3": "koord-workload-db",
+			},
+		},
+		{
+			name:           "keep existing groups first",
+			existingGroups: map[string]struct{}{"koord-pod-pod7": {}},
+			maxGroups:      2,
+			wantGroups: map[string]*slov1alpha1.ResctrlQOS{
+				"koord-workload-db": {MBAPercent: pointer.Int64(50)},
+				"koord-pod-pod7":    {MBAPercent: pointer.Int64(50)},
+			},
+			wantPodGroupNames: map[string]string{
+				"pod2": "koord-workload-db",
+				"pod3": "koord-workload-db",
+				"pod7": "koord-pod-pod7",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotGroups, gotPodGroupNames := getDesiredPodResctrlGroups(testPods, tt.existingGroups, tt.maxGroups)
+			got := map[string]*slov1alpha1.ResctrlQOS{}
+			for name, group := range gotGroups {
+				assert.Equal(t, name, group.name)
+				got[name] = group.resctrlQOS
+			}
+			assert.Equal(t, tt.wantGroups, got)
+			assert.Equal(t, tt.wantPodGroupNames, gotPodGroupNames)
+		})
+	}
+}
+
+func TestResctrlReconcile_reconcilePodResctrlGroups(t *testing.T) {
+	now := time.Now()
+	testPodDedicated := newTestResctrlPodMeta("pod0", extension.QoSLS, `{"catRangeStartPercent":0,"catRangeEndPercent":50,"mbaPercent":40}`, now)
+	testPodWorkload := newTestResctrlPodMeta("pod1", extension.QoSBE, `{"group":"db","catRangeStartPercent":50,"catRangeEndPercent":100}`, now)
+	testPodBE := newTestResctrlPodMeta("pod2", extension.QoSBE, "", now)
+	testQOSStrategy := sloconfig.DefaultResourceQOSStrategy()
+	testQOSStrategy.BEClass.ResctrlQOS.Enable = pointer.Bool(true)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testPodDedicated, testPodWorkload, testPodBE}).AnyTimes()
+	metricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(&metriccache.NodeCPUInfo{
+		BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "ff"},
+		TotalInfo: koordletutil.CPUTotalInfo{L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}, 1: {}}},
+	}, true).AnyTimes()
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	sysFSRootDirName := "reconcilePodResctrlGroups"
+	helper.MkDirAll(sysFSRootDirName)
+	system.Conf.SysFSRootDir = filepath.Join(helper.TempDir, sysFSRootDirName)
+	system.CommonRootDir = ""
+	testingPrepareResctrlL3CatGroups(t, "ff", "L3:0=ff;1=ff\n")
+	resctrlDir := filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir)
+	// 1 for root, 3 for qos classes and 2 for pods
+	numClosidsPath := filepath.Join(resctrlDir, system.RdtInfoDir, system.L3CatDir, system.ResctrlNumClosidsName)
+	assert.NoError(t, os.WriteFile(numClosidsPath, []byte("6\n"), 0666))
+	// the group not created by koordlet is kept
+	helper.MkDirAll(filepath.Join(sysFSRootDirName, system.ResctrlDir, "koord-pod-stale"))
+	// the group of pod0 is already created by kernel with the default schemata
+	pod0GroupDir := filepath.Join(resctrlDir, "koord-pod-pod0")
+	assert.NoError(t, os.MkdirAll(pod0GroupDir, 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(pod0GroupDir, system.ResctrlSchemataName), []byte("L3:0=ff;1=ff\nMB:0=100;1=100\n"), 0666))
+	assert.NoError(t, os.WriteFile(filepath.Join(pod0GroupDir, system.ResctrlTasksName), []byte{}, 0666))
+	testingPrepareContainerCgroupCPUTasks(t, helper, "kubepods.slice/pod0/cri-containerd-pod0-c0.scope", "100\n101")
+	testingPrepareContainerCgroupCPUTasks(t, helper, "kubepods.slice/pod2/cri-containerd-pod2-c0.scope", "200")
+
+	r := newTestResctrlReconcile(&framework.Options{
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		Config:         framework.NewDefaultConfig(),
+	})
+	stop := make(chan struct{})
+	r.init(stop)
+	defer func() { stop <- struct{}{} }()
+
+	r.reconcilePodResctrlGroups()
+	assert.Equal(t, map[string]string{
+		"pod0": "koord-pod-pod0",
+		"pod1": "koord-workload-db",
+	}, r.podResctrlGroups)
+	assert.DirExists(t, filepath.Join(resctrlDir, "koord-pod-stale"))
+	assert.DirExists(t, filepath.Join(resctrlDir, "koord-workload-db"))
+	got, err := os.ReadFile(filepath.Join(pod0GroupDir, system.ResctrlSchemataName))
+	assert.NoError(t, err)
+	assert.Equal(t, "MB:0=40;1=40;\n", string(got))
+
+	// the tasks of pods with dedicated groups are not added into the groups of qos classes
+	r.reconcileResctrlGroups(testQOSStrategy)
+	got, err = os.ReadFile(filepath.Join(pod0GroupDir, system.ResctrlTasksName))
+	assert.NoError(t, err)
+	assert.Equal(t, "100101", string(got))
+	got, err = os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
+	assert.NoError(t, err)
+	assert.Equal(t, "200", string(got))
+
+	// remove the groups when the pods exit
+	statesInformer1 := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer1.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testPodBE}).AnyTimes()
+	r.statesInformer = statesInformer1
+	r.reconcilePodResctrlGroups()
+	assert.Empty(t, r.podResctrlGroups)
+	assert.NoDirExists(t, pod0GroupDir)
+	assert.NoDirExists(t, filepath.Join(resctrlDir, "koord-workload-db"))
+	assert.DirExists(t, filepath.Join(resctrlDir, "koord-pod-stale"))
+	assert.Empty(t, r.ownedPodResctrlGroups)
+
+	// keep the groups when failed to get the number of closids
+	r.statesInformer = statesInformer
+	r.reconcilePodResctrlGroups()
+	assert.DirExists(t, pod0GroupDir)
+	assert.NoError(t, os.Remove(numClosidsPath))
+	r.statesInformer = statesInformer1
+	r.reconcilePodResctrlGroups()
+	assert.DirExists(t, pod0GroupDir)
+	assert.DirExists(t, filepath.Join(resctrlDir, "koord-workload-db"))
+}
@@ -19,6 +19,7 @@ package resctrl
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"time"
 
@@ -77,6 +78,12 @@ type resctrlReconcile struct {
 	metricCache       metriccache.MetricCache
 	cgroupReader      resourceexecutor.CgroupReader
 	eventRecorder     record.EventRecorder
+	// dryRun records the updates without creating or removing the resctrl groups
+	dryRun bool
+	// podResctrlGroups is the resctrl group of each pod UID which has a dedicated group
+	podResctrlGroups map[string]string
+	// ownedPodResctrlGroups are the pod resctrl groups created or used by koordlet, only which can be removed
+	ownedPodResctrlGroups map[string]struct{}
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -84,9 +91,12 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
 		statesInformer:    opt.StatesInformer,
 		metricCache:       opt.MetricCache,
-		executor:          resourceexecutor.NewResourceUpdateExecutor(),
+		executor:          opt.Config.NewStrategyExecutor(ResctrlReconcileName),
 		cgroupReader:      opt.CgroupReader,
 		eventRecorder:     opt.EventRecorder,
+		dryRun:            opt.Config.IsDryRun(ResctrlReconcileName),
+
+		ownedPodResctrlGroups: map[string]struct{}{},
 	}
 }
 
@@ -384,43 +394,46 @@ func (r *resctrlReconcile) calculateAndApplyCatL3GroupTasks(group string, taskId
 	return nil
 }
 
-func (r *resctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
-	// 1. retrieve rdt configs from nodeSLOSpec
-	// 2.1 get cbm and l3 numbers, which are general for all resctrl groups
-	// 2.2 calculate applying resctrl policies, like cat policy and so on, with each rdt config
-	// 3. apply the policies onto resctrl groups
-
-	// read cat l3 cbm
-
+// getCatL3Info returns the node cpu info, the cat l3 cbm and the number of l3 caches.
+func (r *resctrlReconcile) getCatL3Info() (*metriccache.NodeCPUInfo, uint, int, error) {
 	nodeCPUInfoRaw, exist := r.metricCache.Get(metriccache.NodeCPUInfoKey)
 	if !exist {
-		klog.Warning("failed to get nodeCPUInfo, not exist")
-		return
+		return nil, 0, 0, fmt.Errorf("nodeCPUInfo not exist")
 	}
 	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
 	if !ok {
 		klog.Fatalf("type error, expect %T， but got %T", metriccache.NodeCPUInfo{}, nodeCPUInfoRaw)
 	}
 	if nodeCPUInfo == nil {
-		klog.Warning("failed to get nodeCPUInfo, the value is nil")
-		return
+		return nil, 0, 0, fmt.Errorf("nodeCPUInfo is nil")
 	}
 	cbmStr := nodeCPUInfo.BasicInfo.CatL3CbmMask
 	if len(cbmStr) <= 0 {
-		klog.Warning("failed to get cat l3 cbm, cbm is empty")
-		return
+		return nil, 0, 0, fmt.Errorf("cat l3 cbm is empty")
 	}
 	cbmValue, err := strconv.ParseUint(cbmStr, 16, 32)
 	if err != nil {
-		klog.Warningf("failed to parse cat l3 cbm %s, err: %v", cbmStr, err)
-		return
+		return nil, 0, 0, fmt.Errorf("failed to parse cat l3 cbm %s, err: %v", cbmStr, err)
 	}
-	cbm := uint(cbmValue)
 
 	// get the number of l3 caches; it is larger than 0
 	l3Num := len(nodeCPUInfo.TotalInfo.L3ToCPU)
 	if l3Num <= 0 {
-		klog.Warningf("failed to get the number of l3 caches, invalid value %v", l3Num)
+		return nil, 0, 0, fmt.Errorf("invalid number of l3 caches %v", l3Num)
+	}
+	return nodeCPUInfo, uint(cbmValue), l3Num, nil
+}
+
+func (r *resctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
+	// 1. retrieve rdt configs from nodeSLOSpec
+	// 2.1 get cbm and l3 numbers, which are general for all resctrl groups
+	// 2.2 calculate applying resctrl policies, like cat policy and so on, with each rdt config
+	// 3. apply the policies onto resctrl groups
+
+	// read cat l3 cbm
+	nodeCPUInfo, cbm, l3Num, err := r.getCatL3Info()
+	if err != nil {
+		klog.Warningf("failed to get cat l3 info, err: %v", err)
 		return
 	}
 
@@ -454,6 +467,15 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 			klog.Warningf("failed to read Cat L3 tasks for resctrl group %s, err: %s", group, err)
 		}
 	}
+	for _, group := range r.podResctrlGroups {
+		if _, ok := curTaskMaps[group]; ok {
+			continue
+		}
+		curTaskMaps[group], err = system.ReadResctrlTasksMap(group)
+		if err != nil {
+			klog.Warningf("failed to read Cat L3 tasks for pod resctrl group %s, err: %s", group, err)
+		}
+	}
 
 	taskIds := map[string][]int32{}
 	podsMeta := r.statesInformer.GetAllPods()
@@ -464,6 +486,14 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 			continue
 		}
 
+		// the pods with dedicated resctrl groups ignore the config of their QoS classes
+		if group, ok := r.podResctrlGroups[string(pod.UID)]; ok {
+			ids := r.getPodCgroupNewTaskIds(podMeta, curTaskMaps[group])
+			taskIds[group] = append(taskIds[group], ids...)
+			klog.V(6).Infof("pod %v apply to pod resctrl group %s with %v tasks", util.GetPodKey(pod), group, len(ids))
+			continue
+		}
+
 		// only extension-QoS-specified pod are considered
 		podQoSCfg := helpers.GetPodResourceQoSByQoSClass(pod, qosStrategy)
 		if podQoSCfg.ResctrlQOS.Enable == nil || !(*podQoSCfg.ResctrlQOS.Enable) {
@@ -480,7 +510,16 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 	}
 
 	// write Cat L3 tasks for each resctrl group
-	for _, group := range resctrlGroupList {
+	groups := append([]string{}, resctrlGroupList...)
+	podGroups := map[string]struct{}{}
+	for _, group := range r.podResctrlGroups {
+		podGroups[group] = struct{}{}
+	}
+	for group := range podGroups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups[len(resctrlGroupList):])
+	for _, group := range groups {
 		err = r.calculateAndApplyCatL3GroupTasks(group, taskIds[group])
 		if err != nil {
 			klog.Warningf("failed to apply l3 cat tasks for group %s, err %s", group, err)
@@ -514,10 +553,13 @@ func (r *resctrlReconcile) reconcile() {
 		return
 	}
 
-	if err := initCatResctrl(); err != nil {
+	if r.dryRun {
+		klog.V(5).Infof("resctrlReconcile runs in the dry-run mode, skip initializing cat resctrl groups")
+	} else if err := initCatResctrl(); err != nil {
 		klog.V(4).Infof("resctrlReconcile failed, cannot initialize cat resctrl group, err: %s", err)
 		return
 	}
 	r.reconcileCatResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+	r.reconcilePodResctrlGroups()
 	r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy)
 }
@@ -52,7 +52,8 @@ func newTestResctrlReconcile(opt *framework.Options) *resctrlReconcile {
 			Config:        resourceexecutor.NewDefaultConfig(),
 			ResourceCache: cache.NewCacheDefault(),
 		},
-		cgroupReader: resourceexecutor.NewCgroupReader(),
+		cgroupReader:          resourceexecutor.NewCgroupReader(),
+		ownedPodResctrlGroups: map[string]struct{}{},
 	}
 }
 
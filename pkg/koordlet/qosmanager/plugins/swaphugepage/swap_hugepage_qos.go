@@ -0,0 +1,279 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swaphugepage
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	SwapHugepageQOSName = "SwapHugepageQOS"
+
+	// defaultSwapLimitPercent is used when the swap qos is enabled without a SwapLimitPercent.
+	defaultSwapLimitPercent = 100
+)
+
+var (
+	// hugepageLimitResources maps the supported hugepage sizes in bytes to the cgroup resources of the hugetlb limits.
+	hugepageLimitResources = map[int64]system.ResourceType{
+		2 << 20: system.HugetlbLimit2MBName,
+		1 << 30: system.HugetlbLimit1GBName,
+	}
+)
+
+var _ framework.QOSStrategy = &swapHugepageQOS{}
+
+type swapHugepageQOS struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	executor          resourceexecutor.ResourceUpdateExecutor
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &swapHugepageQOS{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		executor:          opt.Config.NewStrategyExecutor(SwapHugepageQOSName),
+	}
+}
+
+func (s *swapHugepageQOS) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.SwapHugepageQOS) && s.reconcileInterval > 0
+}
+
+func (s *swapHugepageQOS) Setup(context *framework.Context) {
+}
+
+func (s *swapHugepageQOS) Run(stopCh <-chan struct{}) {
+	s.init(stopCh)
+	go wait.Until(s.reconcile, s.reconcileInterval, stopCh)
+}
+
+func (s *swapHugepageQOS) init(stopCh <-chan struct{}) {
+	s.executor.Run(stopCh)
+}
+
+func (s *swapHugepageQOS) reconcile() {
+	nodeSLO := s.statesInformer.GetNodeSLO()
+	if nodeSLO == nil || nodeSLO.Spec.ResourceQOSStrategy == nil {
+		klog.Warningf("%s: nodeSLO or resourceQOSStrategy is nil, skip reconcile swap and hugepage qos", SwapHugepageQOSName)
+		return
+	}
+	strategy := nodeSLO.Spec.ResourceQOSStrategy
+	warnUnsupportedClasses(strategy)
+	podMetas := s.statesInformer.GetAllPods()
+
+	var updaters []resourceexecutor.ResourceUpdater
+	// hugepage limits are applied on the qos-level cgroups of the LS (burstable) and BE (besteffort) classes, while the
+	// pod-level cgroups are owned by kubelet which limits the hugepages of pods according to their requests
+	for _, qosClass := range []corev1.PodQOSClass{corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
+		qosDir := koordletutil.GetPodQoSRelativePath(qosClass)
+		cfg := helpers.GetKubeQoSResourceQoSByQoSClass(qosClass, strategy)
+		updaters = append(updaters, getHugepageUpdaters(qosDir, cfg, audit.V(3).Group(string(qosClass)))...)
+	}
+
+	if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+		swapUpdaters, err := getSwapUpdaters(strategy.BEClass, podMetas)
+		if err != nil {
+			klog.Warningf("%s: failed to get swap updaters, err: %v", SwapHugepageQOSName, err)
+		} else {
+			updaters = append(updaters, swapUpdaters...)
+		}
+	} else if isSwapQOSEnabled(strategy.BEClass) {
+		klog.V(4).Infof("%s: swap qos relies on cgroups-v2, skip reconcile swap qos", SwapHugepageQOSName)
+	}
+
+	s.executor.UpdateBatch(true, updaters...)
+	klog.V(5).Infof("%s: reconcile finished, updaters %d", SwapHugepageQOSName, len(updaters))
+}
+
+func warnUnsupportedClasses(strategy *slov1alpha1.ResourceQOSStrategy) {
+	for name, cfg := range map[string]*slov1alpha1.ResourceQOS{
+		"LSRClass":    strategy.LSRClass,
+		"LSClass":     strategy.LSClass,
+		"SystemClass": strategy.SystemClass,
+		"CgroupRoot":  strategy.CgroupRoot,
+	} {
+		if isSwapQOSEnabled(cfg) {
+			klog.Warningf("%s: configuring swap of %s is not supported!", SwapHugepageQOSName, name)
+		}
+	}
+	for name, cfg := range map[string]*slov1alpha1.ResourceQOS{
+		"LSRClass":    strategy.LSRClass,
+		"SystemClass": strategy.SystemClass,
+		"CgroupRoot":  strategy.CgroupRoot,
+	} {
+		if isHugepageQOSEnabled(cfg) {
+			klog.Warningf("%s: configuring hugepage of %s is not supported!", SwapHugepageQOSName, name)
+		}
+	}
+}
+
+func isSwapQOSEnabled(cfg *slov1alpha1.ResourceQOS) bool {
+	return cfg != nil && cfg.SwapQOS != nil && cfg.SwapQOS.Enable != nil && *cfg.SwapQOS.Enable
+}
+
+func isHugepageQOSEnabled(cfg *slov1alpha1.ResourceQOS) bool {
+	return cfg != nil && cfg.HugepageQOS != nil && cfg.HugepageQOS.Enable != nil && *cfg.HugepageQOS.Enable
+}
+
+// calculateHugepageLimits returns the hugetlb limits of each supported page size in bytes when the hugepage qos is
+// enabled. The page sizes not limited are unlimited ("-1").
+func calculateHugepageLimits(cfg *slov1alpha1.ResourceQOS) map[system.ResourceType]string {
+	if !isHugepageQOSEnabled(cfg) {
+		return nil
+	}
+	limits := map[system.ResourceType]string{}
+	for _, resourceType := range hugepageLimitResources {
+		limits[resourceType] = system.CgroupUnlimitedSymbolStr
+	}
+	for name, quantity := range cfg.HugepageQOS.Limits {
+		pageSize, err := v1helper.HugePageSizeFromResourceName(name)
+		if err != nil {
+			klog.Warningf("%s: invalid hugepage resource %s, err: %v", SwapHugepageQOSName, name, err)
+			continue
+		}
+		resourceType, ok := hugepageLimitResources[pageSize.Value()]
+		if !ok {
+			klog.Warningf("%s: hugepage size %s is not supported", SwapHugepageQOSName, pageSize.String())
+			continue
+		}
+		limits[resourceType] = strconv.FormatInt(quantity.Value(), 10)
+	}
+	return limits
+}
+
+// getHugepageUpdaters returns the updaters of the hugetlb limits on the qos-level cgroup dir. Nothing is returned if the
+// hugepage qos is not enabled, so that the limits set by kubelet or other tools are kept.
+func getHugepageUpdaters(cgroupDir string, cfg *slov1alpha1.ResourceQOS, event *audit.EventHelper) []resourceexecutor.ResourceUpdater {
+	var updaters []resourceexecutor.ResourceUpdater
+	for resourceType, value := range calculateHugepageLimits(cfg) {
+		eventHelper := event.Reason(SwapHugepageQOSName).Message("update %s/%s to %s", cgroupDir, resourceType, value)
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(resourceType, cgroupDir, value, eventHelper)
+		if err != nil {
+			klog.V(4).Infof("%s: failed to get hugetlb updater for %s, err: %v", SwapHugepageQOSName, cgroupDir, err)
+			continue
+		}
+		updaters = append(updaters, updater)
+	}
+	return updaters
+}
+
+// calculateSwapBudget returns the swap budget of the BE class in bytes according to the node swap capacity.
+func calculateSwapBudget(cfg *slov1alpha1.ResourceQOS) (int64, error) {
+	memInfo, err := koordletutil.GetMemInfo()
+	if err != nil {
+		return 0, fmt.Errorf("get meminfo failed, err: %w", err)
+	}
+	percent := int64(defaultSwapLimitPercent)
+	if cfg.SwapQOS.SwapLimitPercent != nil {
+		percent = *cfg.SwapQOS.SwapLimitPercent
+	}
+	// SwapTotal is in kB
+	return int64(memInfo.SwapTotal) * 1024 * percent / 100, nil
+}
+
+// getSwapUpdaters returns the updaters of the memory.swap.max for the BE class.
+// When the swap qos is enabled, the BE qos-level cgroup is limited by the swap budget, and the BE pods and containers
+// in it are unlimited to share the budget. When it is disabled, the BE qos-level cgroup is forbidden to swap. The BE
+// pods out of the BE qos-level cgroup (e.g. the burstable ones) are set with the value of the BE qos-level cgroup on
+// their pod-level cgroups. Nothing is returned if the swap qos is not configured.
+func getSwapUpdaters(cfg *slov1alpha1.ResourceQOS, podMetas []*statesinformer.PodMeta) ([]resourceexecutor.ResourceUpdater, error) {
+	if cfg == nil || cfg.SwapQOS == nil {
+		return nil, nil
+	}
+	enabled := isSwapQOSEnabled(cfg)
+	qosValue := "0"
+	if enabled {
+		budget, err := calculateSwapBudget(cfg)
+		if err != nil {
+			return nil, err
+		}
+		qosValue = strconv.FormatInt(budget, 10)
+	}
+	qosDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	updater, err := newSwapUpdater(qosDir, qosValue, audit.V(3).Group(string(corev1.PodQOSBestEffort)))
+	if err != nil {
+		return nil, err
+	}
+	updaters := []resourceexecutor.ResourceUpdater{updater}
+
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassWithDefault(pod) != extension.QoSBE {
+			continue
+		}
+		inBEQoSDir := extension.GetKubeQosClass(pod) == corev1.PodQOSBestEffort
+		if inBEQoSDir && !enabled {
+			// limited by the BE qos-level cgroup
+			continue
+		}
+		podValue := system.CgroupMaxSymbolStr
+		if !inBEQoSDir {
+			podValue = qosValue
+		}
+		podUpdater, err := newSwapUpdater(podMeta.CgroupDir, podValue, audit.V(3).Pod(pod.Namespace, pod.Name))
+		if err != nil {
+			klog.V(4).Infof("%s: failed to get swap updater for pod %s, err: %v", SwapHugepageQOSName, util.GetPodKey(pod), err)
+			continue
+		}
+		updaters = append(updaters, podUpdater)
+		if !enabled {
+			continue
+		}
+
+		for i := range pod.Status.ContainerStatuses {
+			containerStat := &pod.Status.ContainerStatuses[i]
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+			if err != nil {
+				klog.V(5).Infof("%s: failed to get cgroup dir of container %s/%s, err: %v",
+					SwapHugepageQOSName, util.GetPodKey(pod), containerStat.Name, err)
+				continue
+			}
+			containerUpdater, err := newSwapUpdater(containerDir, system.CgroupMaxSymbolStr, audit.V(3).Pod(pod.Namespace, pod.Name))
+			if err != nil {
+				continue
+			}
+			updaters = append(updaters, containerUpdater)
+		}
+	}
+	return updaters, nil
+}
+
+func newSwapUpdater(cgroupDir string, value string, event *audit.EventHelper) (resourceexecutor.ResourceUpdater, error) {
+	eventHelper := event.Reason(SwapHugepageQOSName).Message("update %s/%s to %s", cgroupDir, system.MemorySwapMaxName, value)
+	return resourceexecutor.DefaultCgroupUpdaterFactory.New(system.MemorySwapMaxName, cgroupDir, value, eventHelper)
+}
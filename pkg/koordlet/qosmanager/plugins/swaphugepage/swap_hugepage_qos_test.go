@@ -0,0 +1,278 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package swaphugepage
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const testingMemInfo = `MemTotal:       263432804 kB
+MemFree:        254391744 kB
+MemAvailable:   256703236 kB
+SwapTotal:        8388608 kB
+SwapFree:         8388608 kB
+`
+
+func Test_calculateHugepageLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *slov1alpha1.ResourceQOS
+		want map[system.ResourceType]string
+	}{
+		{
+			name: "hugepage qos not configured",
+			cfg:  nil,
+			want: nil,
+		},
+		{
+			name: "hugepage qos disabled",
+			cfg: &slov1alpha1.ResourceQOS{
+				HugepageQOS: &slov1alpha1.HugepageQOSCfg{
+					Enable: pointer.Bool(false),
+					HugepageQOS: slov1alpha1.HugepageQOS{
+						Limits: corev1.ResourceList{
+							corev1.ResourceName("hugepages-2Mi"): resource.MustParse("1Gi"),
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "limit the specified page sizes",
+			cfg: &slov1alpha1.ResourceQOS{
+				HugepageQOS: &slov1alpha1.HugepageQOSCfg{
+					Enable: pointer.Bool(true),
+					HugepageQOS: slov1alpha1.HugepageQOS{
+						Limits: corev1.ResourceList{
+							corev1.ResourceName("hugepages-2Mi"):  resource.MustParse("1Gi"),
+							corev1.ResourceName("hugepages-64Ki"): resource.MustParse("1Gi"),
+							corev1.ResourceCPU:                    resource.MustParse("1"),
+						},
+					},
+				},
+			},
+			want: map[system.ResourceType]string{
+				system.HugetlbLimit2MBName: "1073741824",
+				system.HugetlbLimit1GBName: "-1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateHugepageLimits(tt.cfg)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_swapHugepageQOS_reconcile(t *testing.T) {
+	testingNodeSLO := &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				LSRClass: &slov1alpha1.ResourceQOS{
+					HugepageQOS: &slov1alpha1.HugepageQOSCfg{
+						Enable: pointer.Bool(true),
+						HugepageQOS: slov1alpha1.HugepageQOS{
+							Limits: corev1.ResourceList{
+								corev1.ResourceName("hugepages-2Mi"): resource.MustParse("2Gi"),
+							},
+						},
+					},
+				},
+				LSClass: &slov1alpha1.ResourceQOS{
+					HugepageQOS: &slov1alpha1.HugepageQOSCfg{
+						Enable: pointer.Bool(true),
+						HugepageQOS: slov1alpha1.HugepageQOS{
+							Limits: corev1.ResourceList{
+								corev1.ResourceName("hugepages-1Gi"): resource.MustParse("4Gi"),
+							},
+						},
+					},
+				},
+				BEClass: &slov1alpha1.ResourceQOS{
+					SwapQOS: &slov1alpha1.SwapQOSCfg{
+						Enable: pointer.Bool(true),
+						SwapQOS: slov1alpha1.SwapQOS{
+							SwapLimitPercent: pointer.Int64(50),
+						},
+					},
+					HugepageQOS: &slov1alpha1.HugepageQOSCfg{
+						Enable: pointer.Bool(true),
+						HugepageQOS: slov1alpha1.HugepageQOS{
+							Limits: corev1.ResourceList{
+								corev1.ResourceName("hugepages-2Mi"): resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	testingBEPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod0",
+				Namespace: "default",
+				UID:       "p0",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSBE),
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "container0",
+						ContainerID: "containerd://c0",
+					},
+				},
+			},
+		},
+		CgroupDir: "kubepods.slice/kubepods-besteffort.slice/p0",
+	}
+	testingContainerDir := "kubepods.slice/kubepods-besteffort.slice/p0/cri-containerd-c0.scope"
+	testingBurstableBEPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod3",
+				Namespace: "default",
+				UID:       "p3",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSBE),
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:    corev1.PodRunning,
+				QOSClass: corev1.PodQOSBurstable,
+			},
+		},
+		CgroupDir: "kubepods.slice/kubepods-burstable.slice/p3",
+	}
+	testingLSPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod1",
+				Namespace: "default",
+				UID:       "p1",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSLS),
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+		CgroupDir: "kubepods.slice/kubepods-burstable.slice/p1",
+	}
+	testingLSRPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod2",
+				Namespace: "default",
+				UID:       "p2",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSLSR),
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:    corev1.PodRunning,
+				QOSClass: corev1.PodQOSGuaranteed,
+			},
+		},
+		CgroupDir: "kubepods.slice/p2",
+	}
+	beQOSDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	lsQOSDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBurstable)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testingBEPodMeta, testingBurstableBEPodMeta, testingLSPodMeta, testingLSRPodMeta}).AnyTimes()
+	statesInformer.EXPECT().GetNodeSLO().Return(testingNodeSLO).AnyTimes()
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetResourcesSupported(true, system.MemorySwapMaxV2, system.HugetlbLimit2MBV2, system.HugetlbLimit1GBV2)
+	for _, dir := range []string{beQOSDir, lsQOSDir, testingLSRPodMeta.CgroupDir} {
+		helper.WriteCgroupFileContents(dir, system.HugetlbLimit2MBV2, "max")
+		helper.WriteCgroupFileContents(dir, system.HugetlbLimit1GBV2, "max")
+	}
+	for _, dir := range []string{beQOSDir, testingBEPodMeta.CgroupDir, testingContainerDir, testingBurstableBEPodMeta.CgroupDir, testingLSPodMeta.CgroupDir} {
+		helper.WriteCgroupFileContents(dir, system.MemorySwapMaxV2, "0")
+	}
+	helper.WriteProcSubFileContents(system.ProcMemInfoName, testingMemInfo)
+
+	s := New(&framework.Options{
+		StatesInformer: statesInformer,
+		Config:         framework.NewDefaultConfig(),
+	}).(*swapHugepageQOS)
+	s.executor = resourceexecutor.NewTestResourceExecutor()
+	stop := make(chan struct{})
+	defer close(stop)
+	s.init(stop)
+
+	s.reconcile()
+	assert.Equal(t, "4294967296", helper.ReadCgroupFileContents(lsQOSDir, system.HugetlbLimit1GBV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(lsQOSDir, system.HugetlbLimit2MBV2))
+	assert.Equal(t, "1073741824", helper.ReadCgroupFileContents(beQOSDir, system.HugetlbLimit2MBV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(beQOSDir, system.HugetlbLimit1GBV2))
+	// the pod-level limits are owned by kubelet
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(testingLSRPodMeta.CgroupDir, system.HugetlbLimit2MBV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(testingLSRPodMeta.CgroupDir, system.HugetlbLimit1GBV2))
+	// 50% of 8GiB swap
+	assert.Equal(t, "4294967296", helper.ReadCgroupFileContents(beQOSDir, system.MemorySwapMaxV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(testingBEPodMeta.CgroupDir, system.MemorySwapMaxV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(testingContainerDir, system.MemorySwapMaxV2))
+	// the BE pod out of the BE qos-level cgroup is limited by the BE budget
+	assert.Equal(t, "4294967296", helper.ReadCgroupFileContents(testingBurstableBEPodMeta.CgroupDir, system.MemorySwapMaxV2))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(testingLSPodMeta.CgroupDir, system.MemorySwapMaxV2))
+
+	// forbid swap when the swap qos is disabled, while the hugepage limits are left to kubelet when the hugepage qos is
+	// disabled
+	testingNodeSLO.Spec.ResourceQOSStrategy.LSClass.HugepageQOS.Enable = pointer.Bool(false)
+	testingNodeSLO.Spec.ResourceQOSStrategy.BEClass.SwapQOS.Enable = pointer.Bool(false)
+	helper.WriteCgroupFileContents(lsQOSDir, system.HugetlbLimit1GBV2, "1073741824")
+	s.reconcile()
+	assert.Equal(t, "1073741824", helper.ReadCgroupFileContents(lsQOSDir, system.HugetlbLimit1GBV2))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(beQOSDir, system.MemorySwapMaxV2))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(testingBurstableBEPodMeta.CgroupDir, system.MemorySwapMaxV2))
+
+	// keep the settings of other tools when the qos is not configured
+	testingNodeSLO.Spec.ResourceQOSStrategy.BEClass = nil
+	helper.WriteCgroupFileContents(beQOSDir, system.MemorySwapMaxV2, "1048576")
+	helper.WriteCgroupFileContents(beQOSDir, system.HugetlbLimit2MBV2, "2097152")
+	s.reconcile()
+	assert.Equal(t, "1048576", helper.ReadCgroupFileContents(beQOSDir, system.MemorySwapMaxV2))
+	assert.Equal(t, "2097152", helper.ReadCgroupFileContents(beQOSDir, system.HugetlbLimit2MBV2))
+}
@@ -48,7 +48,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 	return &systemConfig{
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
 		statesInformer:    opt.StatesInformer,
-		executor:          resourceexecutor.NewResourceUpdateExecutor(),
+		executor:          opt.Config.NewStrategyExecutor(SystemConfigReconcileName),
 	}
 }
 
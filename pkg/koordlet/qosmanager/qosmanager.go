@@ -54,7 +54,7 @@ func NewQOSManager(cfg *framework.Config, schema *apiruntime.Scheme, kubeClient
 	eventBroadcaster.StartRecordingToSink(&clientcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(schema, corev1.EventSource{Component: "koordlet-qosManager", Host: nodeName})
 	cgroupReader := resourceexecutor.NewCgroupReader()
-	evictor := framework.NewEvictor(kubeClient, recorder, evictVersion)
+	evictor := framework.NewEvictorWithConfig(kubeClient, recorder, evictVersion, cfg)
 
 	opt := &framework.Options{
 		CgroupReader:        cgroupReader,
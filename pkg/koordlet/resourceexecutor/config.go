@@ -24,9 +24,13 @@ const (
 	ReasonUpdateResctrl      = "UpdateResctrl" // update resctrl tasks, schemata
 
 	EvictPodByNodeMemoryUsage   = "EvictPodByNodeMemoryUsage"
+	EvictPodByMemoryPressure    = "EvictPodByMemoryPressure"
+	EvictPodByInterference      = "EvictPodByInterference"
 	EvictPodByBECPUSatisfaction = "EvictPodByBECPUSatisfaction"
+	KillPodByNodeMemoryCritical = "KillPodByNodeMemoryCritical"
 
-	AdjustBEByNodeCPUUsage = "AdjustBEByNodeCPUUsage"
+	AdjustBEByNodeCPUUsage    = "AdjustBEByNodeCPUUsage"
+	AdjustBEByNodeMemoryUsage = "AdjustBEByNodeMemoryUsage"
 )
 
 var Conf = NewDefaultConfig()
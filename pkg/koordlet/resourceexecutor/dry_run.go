@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+var _ ResourceUpdateExecutor = &DryRunResourceUpdateExecutor{}
+
+// DryRunResourceUpdateExecutor records the resource updates into the audit events and metrics instead of applying
+// them, so the decisions of an owner (e.g. a qos strategy) can be verified before they take effect.
+type DryRunResourceUpdateExecutor struct {
+	owner string
+	// recordFn is called for each recorded update, e.g. to export the metrics
+	recordFn func(updater ResourceUpdater)
+
+	lock sync.Mutex
+	// resource key -> last recorded value, which expires like the cache of the ResourceUpdateExecutorImpl, so the
+	// values of the removed resources are not kept forever
+	lastValues *cache.Cache
+	onceRun    sync.Once
+}
+
+func NewDryRunResourceUpdateExecutor(owner string, recordFn func(updater ResourceUpdater)) ResourceUpdateExecutor {
+	return &DryRunResourceUpdateExecutor{
+		owner:      owner,
+		recordFn:   recordFn,
+		lastValues: cache.NewCacheDefault(),
+	}
+}
+
+// Update records the update of the resource. A cacheable update is recorded only if the value is changed since the
+// last record.
+func (e *DryRunResourceUpdateExecutor) Update(cacheable bool, updater ResourceUpdater) (bool, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if lastValue, ok := e.lastValues.Get(updater.Key()); cacheable && ok && lastValue == updater.Value() {
+		klog.V(6).Infof("dry-run skip resource %s for %s, value %v is unchanged", updater.Key(), e.owner, updater.Value())
+		return false, nil
+	}
+	if err := e.lastValues.SetDefault(updater.Key(), updater.Value()); err != nil {
+		klog.V(5).Infof("failed to cache the dry-run value of resource %s for %s, err: %v", updater.Key(), e.owner, err)
+	}
+
+	_ = audit.V(3).Unknown(updater.Key()).Reason(e.owner).DryRun().Message("dry-run update to %v", updater.Value()).Do()
+	if e.recordFn != nil {
+		e.recordFn(updater)
+	}
+	klog.V(4).Infof("dry-run update resource %s to %v for %s", updater.Key(), updater.Value(), e.owner)
+	return true, nil
+}
+
+func (e *DryRunResourceUpdateExecutor) UpdateBatch(cacheable bool, updaters ...ResourceUpdater) {
+	for _, updater := range updaters {
+		_, _ = e.Update(cacheable, updater)
+	}
+}
+
+func (e *DryRunResourceUpdateExecutor) LeveledUpdateBatch(updaters [][]ResourceUpdater) {
+	for i := range updaters {
+		e.UpdateBatch(true, updaters[i]...)
+	}
+}
+
+func (e *DryRunResourceUpdateExecutor) UpdateTransaction(updaters [][]ResourceUpdater) error {
+	e.LeveledUpdateBatch(updaters)
+	return nil
+}
+
+func (e *DryRunResourceUpdateExecutor) Run(stopCh <-chan struct{}) {
+	e.onceRun.Do(func() {
+		_ = e.lastValues.Run(stopCh)
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func TestDryRunResourceUpdateExecutor(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	testParentDir := "kubepods.slice/kubepods-besteffort.slice"
+	helper.WriteCgroupFileContents(testParentDir, sysutil.CPUCFSQuota, "-1")
+
+	var recorded []string
+	e := NewDryRunResourceUpdateExecutor("testStrategy", func(updater ResourceUpdater) {
+		recorded = append(recorded, updater.Value())
+	})
+	stop := make(chan struct{})
+	defer close(stop)
+	e.Run(stop)
+
+	updater, err := NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, testParentDir, "10000", nil)
+	assert.NoError(t, err)
+	updated, err := e.Update(true, updater)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	// the cgroup is not modified
+	assert.Equal(t, "-1", helper.ReadCgroupFileContents(testParentDir, sysutil.CPUCFSQuota))
+
+	// unchanged value is not recorded again for the cacheable update
+	updated, err = e.Update(true, updater)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	updated, err = e.Update(false, updater)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	updater1, err := NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, testParentDir, "20000", nil)
+	assert.NoError(t, err)
+	e.UpdateBatch(true, updater1)
+	updater2, err := NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, testParentDir, "30000", nil)
+	assert.NoError(t, err)
+	e.LeveledUpdateBatch([][]ResourceUpdater{{updater2}})
+
+	assert.Equal(t, []string{"10000", "10000", "20000", "30000"}, recorded)
+	assert.Equal(t, "-1", helper.ReadCgroupFileContents(testParentDir, sysutil.CPUCFSQuota))
+
+	// the recorded values expire
+	e.(*DryRunResourceUpdateExecutor).lastValues = cache.NewCache(time.Millisecond, time.Minute)
+	_ = e.(*DryRunResourceUpdateExecutor).lastValues.Run(stop)
+	updated, err = e.Update(true, updater)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	time.Sleep(5 * time.Millisecond)
+	updated, err = e.Update(true, updater)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+}
@@ -39,6 +39,9 @@ type ResourceUpdateExecutor interface {
 	// 2. update each cgroup resource by the order of layers: firstly update resources from upper to lower by merging
 	//    the new value with old value; then update resources from lower to upper with the new value.
 	LeveledUpdateBatch(updaters [][]ResourceUpdater)
+	// UpdateTransaction updates the resources by the order of levels like LeveledUpdateBatch, and it rolls back the
+	// written resources to their prior values if any update fails.
+	UpdateTransaction(updaters [][]ResourceUpdater) error
 	Run(stopCh <-chan struct{})
 }
 
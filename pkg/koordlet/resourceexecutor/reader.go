@@ -39,6 +39,7 @@ type CgroupReader interface {
 	ReadCPUTasks(parentDir string) ([]int32, error)
 	ReadPSI(parentDir string) (*PSIByResource, error)
 	ReadMemoryColdPageUsage(parentDir string) (uint64, error)
+	ReadBlkIOStat(parentDir string) (*sysutil.BlkIOStatRaw, error)
 }
 
 var _ CgroupReader = &CgroupV1Reader{}
@@ -215,6 +216,37 @@ func (r *CgroupV1Reader) ReadMemoryColdPageUsage(parentDir string) (uint64, erro
 	return v.GetColdPageTotalBytes(), nil
 }
 
+func (r *CgroupV1Reader) ReadBlkIOStat(parentDir string) (*sysutil.BlkIOStatRaw, error) {
+	bytesResource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV1, sysutil.BlkioIOServiceBytesName)
+	if !ok {
+		return nil, ErrResourceNotRegistered
+	}
+	iosResource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV1, sysutil.BlkioIOServicedName)
+	if !ok {
+		return nil, ErrResourceNotRegistered
+	}
+	stat := &sysutil.BlkIOStatRaw{}
+	for _, t := range []struct {
+		resource sysutil.Resource
+		read     *uint64
+		write    *uint64
+	}{
+		{resource: bytesResource, read: &stat.ReadBytes, write: &stat.WriteBytes},
+		{resource: iosResource, read: &stat.ReadIOs, write: &stat.WriteIOs},
+	} {
+		s, err := cgroupFileRead(parentDir, t.resource)
+		if err != nil {
+			return nil, err
+		}
+		// content: `8:0 Read 4096\n8:0 Write 8192\n...\nTotal 12288\n`
+		*t.read, *t.write, err = sysutil.ParseBlkIOThrottleStat(s)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse cgroup value %s, err: %v", s, err)
+		}
+	}
+	return stat, nil
+}
+
 var _ CgroupReader = &CgroupV2Reader{}
 
 type CgroupV2Reader struct{}
@@ -407,6 +439,23 @@ func (r *CgroupV2Reader) ReadMemoryColdPageUsage(parentDir string) (uint64, erro
 	return 0, ErrResourceNotRegistered
 }
 
+func (r *CgroupV2Reader) ReadBlkIOStat(parentDir string) (*sysutil.BlkIOStatRaw, error) {
+	resource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV2, sysutil.BlkioIOServiceBytesName)
+	if !ok {
+		return nil, ErrResourceNotRegistered
+	}
+	s, err := cgroupFileRead(parentDir, resource)
+	if err != nil {
+		return nil, err
+	}
+	// content: `8:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0\n...`
+	v, err := sysutil.ParseIOStatV2(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse cgroup value %s, err: %v", s, err)
+	}
+	return v, nil
+}
+
 func NewCgroupReader() CgroupReader {
 	if sysutil.GetCurrentCgroupVersion() == sysutil.CgroupVersionV2 {
 		return &CgroupV2Reader{}
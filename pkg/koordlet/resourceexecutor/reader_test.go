@@ -1446,3 +1446,98 @@ func TestCgroupReader_ReadColdPageUsage(t *testing.T) {
 		})
 	}
 }
+
+func TestCgroupReader_ReadBlkIOStat(t *testing.T) {
+	type fields struct {
+		UseCgroupsV2        bool
+		IOServiceBytesValue string
+		IOServicedValue     string
+		IOStatV2Value       string
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		parentDir string
+		want      *sysutil.BlkIOStatRaw
+		wantErr   bool
+	}{
+		{
+			name:      "v1 path not exist",
+			parentDir: "/kubepods.slice",
+			wantErr:   true,
+		},
+		{
+			name: "v1 io_serviced not exist",
+			fields: fields{
+				IOServiceBytesValue: "8:0 Read 4096\n8:0 Write 8192\nTotal 12288\n",
+			},
+			parentDir: "/kubepods.slice",
+			wantErr:   true,
+		},
+		{
+			name: "parse v1 value successfully",
+			fields: fields{
+				IOServiceBytesValue: "8:0 Read 4096\n8:0 Write 8192\n8:0 Sync 0\n8:0 Async 12288\n8:0 Total 12288\nTotal 12288\n",
+				IOServicedValue:     "8:0 Read 1\n8:0 Write 2\n8:0 Sync 0\n8:0 Async 3\n8:0 Total 3\nTotal 3\n",
+			},
+			parentDir: "/kubepods.slice",
+			want: &sysutil.BlkIOStatRaw{
+				ReadBytes:  4096,
+				WriteBytes: 8192,
+				ReadIOs:    1,
+				WriteIOs:   2,
+			},
+		},
+		{
+			name: "v2 path not exist",
+			fields: fields{
+				UseCgroupsV2: true,
+			},
+			parentDir: "/kubepods.slice",
+			wantErr:   true,
+		},
+		{
+			name: "parse v2 value successfully",
+			fields: fields{
+				UseCgroupsV2:  true,
+				IOStatV2Value: "8:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0\n",
+			},
+			parentDir: "/kubepods.slice",
+			want: &sysutil.BlkIOStatRaw{
+				ReadBytes:  4096,
+				WriteBytes: 8192,
+				ReadIOs:    1,
+				WriteIOs:   2,
+			},
+		},
+		{
+			name: "parse v2 value failed",
+			fields: fields{
+				UseCgroupsV2:  true,
+				IOStatV2Value: "8:0 rbytes=invalid\n",
+			},
+			parentDir: "/kubepods.slice",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.fields.IOServiceBytesValue != "" {
+				helper.WriteCgroupFileContents(tt.parentDir, sysutil.BlkioIOServiceBytes, tt.fields.IOServiceBytesValue)
+			}
+			if tt.fields.IOServicedValue != "" {
+				helper.WriteCgroupFileContents(tt.parentDir, sysutil.BlkioIOServiced, tt.fields.IOServicedValue)
+			}
+			if tt.fields.IOStatV2Value != "" {
+				helper.WriteCgroupFileContents(tt.parentDir, sysutil.BlkioIOServiceBytesV2, tt.fields.IOStatV2Value)
+			}
+			helper.SetCgroupsV2(tt.fields.UseCgroupsV2)
+
+			got, gotErr := NewCgroupReader().ReadBlkIOStat(tt.parentDir)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
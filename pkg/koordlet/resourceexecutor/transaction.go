@@ -0,0 +1,284 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	TransactionStatusCommitted      = "committed"
+	TransactionStatusAborted        = "aborted"
+	TransactionStatusRolledBack     = "rolled_back"
+	TransactionStatusRollbackFailed = "rollback_failed"
+)
+
+var (
+	// RecordTransactionFunc records the status of each update transaction.
+	// It is set by the caller to export the metrics since the metrics package depends on the resourceexecutor.
+	RecordTransactionFunc = func(status string) {}
+	// RecordRollbackFunc records the rollback of each resource in the failed transactions.
+	RecordRollbackFunc = func(resourceType string, err error) {}
+)
+
+// the resources cannot be restored by writing the prior values
+var nonRollbackableResources = map[sysutil.ResourceType]bool{
+	sysutil.CPUTasksName:   true,
+	sysutil.CPUProcsName:   true,
+	sysutil.CgroupKillName: true,
+}
+
+type transactionRecord struct {
+	updater    ResourceUpdater
+	priorValue string
+}
+
+// updateTransaction records the prior values of the resources before the first writes, so the resources can be
+// restored in the reverse order of the writes.
+type updateTransaction struct {
+	// resource key -> record
+	records map[string]*transactionRecord
+	// the written resource keys in order
+	journal []string
+	// the resources skipped for the ignored errors, e.g. the cgroup dir does not exist
+	skipped map[string]bool
+}
+
+func newUpdateTransaction() *updateTransaction {
+	return &updateTransaction{
+		records: map[string]*transactionRecord{},
+		skipped: map[string]bool{},
+	}
+}
+
+// UpdateTransaction updates the resources by the order of levels like the LeveledUpdateBatch, but it applies all or
+// none of the updates. Before the first write of each resource, its prior value is recorded. If any update fails
+// with an error not ignored, the written resources are restored to the prior values in the reverse order, so the
+// lower levels are restored before the upper ones and e.g. `cpuset.cpus` of the container is always a subset of the
+// pod's.
+// The resources are considered dependent, so all of them are updated if any of them needs update. The resource cache
+// is set only when the transaction commits.
+func (e *ResourceUpdateExecutorImpl) UpdateTransaction(updaters [][]ResourceUpdater) error {
+	e.LeveledUpdateLock.Lock()
+	defer e.LeveledUpdateLock.Unlock()
+	if !e.gcStarted {
+		return fmt.Errorf("cache GC is not started")
+	}
+
+	needUpdate := false
+	for i := range updaters {
+		for _, updater := range updaters[i] {
+			if e.needUpdate(updater) {
+				needUpdate = true
+				break
+			}
+		}
+	}
+	if !needUpdate {
+		return nil
+	}
+
+	tx := newUpdateTransaction()
+	if err := e.prepareTransaction(tx, updaters); err != nil {
+		RecordTransactionFunc(TransactionStatusAborted)
+		return fmt.Errorf("failed to prepare the update transaction, err: %w", err)
+	}
+
+	err := e.applyTransaction(tx, updaters)
+	if err == nil {
+		now := time.Now()
+		for i := range updaters {
+			for _, updater := range updaters[i] {
+				if tx.skipped[updater.Key()] {
+					continue
+				}
+				updater.UpdateLastUpdateTimestamp(now)
+				if err := e.ResourceCache.SetDefault(updater.Key(), updater); err != nil {
+					klog.V(4).Infof("failed to SetDefault in resourceCache for resource %s, err: %v",
+						updater.Key(), err)
+				}
+			}
+		}
+		RecordTransactionFunc(TransactionStatusCommitted)
+		klog.V(6).Infof("successfully commit the update transaction, written %v", len(tx.journal))
+		return nil
+	}
+
+	klog.V(4).Infof("failed to apply the update transaction, rollback %v resources, err: %v", len(tx.journal), err)
+	if rollbackErr := e.rollbackTransaction(tx); rollbackErr != nil {
+		RecordTransactionFunc(TransactionStatusRollbackFailed)
+		return fmt.Errorf("failed to update resources, err: %v, rollback err: %w", err, rollbackErr)
+	}
+	RecordTransactionFunc(TransactionStatusRolledBack)
+	return fmt.Errorf("failed to update resources and rolled back, err: %w", err)
+}
+
+// prepareTransaction records the prior values of the resources.
+func (e *ResourceUpdateExecutorImpl) prepareTransaction(tx *updateTransaction, updaters [][]ResourceUpdater) error {
+	for i := range updaters {
+		for _, updater := range updaters[i] {
+			if _, ok := tx.records[updater.Key()]; ok || tx.skipped[updater.Key()] {
+				continue
+			}
+			if nonRollbackableResources[updater.ResourceType()] {
+				return fmt.Errorf("resource %s cannot be rolled back", updater.Key())
+			}
+			priorValue, err := readResourceValue(updater)
+			if err != nil && e.isUpdateErrIgnored(err) {
+				klog.V(5).Infof("skip resource %s in the update transaction, ignored err: %v", updater.Key(), err)
+				tx.skipped[updater.Key()] = true
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("read resource %s failed, err: %w", updater.Key(), err)
+			}
+			tx.records[updater.Key()] = &transactionRecord{
+				updater:    updater,
+				priorValue: priorValue,
+			}
+		}
+	}
+	return nil
+}
+
+// applyTransaction firstly updates resources from upper to lower by merging the new value with old value, then
+// updates resources from lower to upper with the new value.
+func (e *ResourceUpdateExecutorImpl) applyTransaction(tx *updateTransaction, updaters [][]ResourceUpdater) error {
+	skipMerge := map[string]bool{}
+	for i := 0; i < len(updaters); i++ {
+		for _, updater := range updaters[i] {
+			if tx.skipped[updater.Key()] {
+				continue
+			}
+			tx.write(updater.Key())
+			mergedUpdater, err := updater.MergeUpdate()
+			if err != nil && e.isUpdateErrIgnored(err) {
+				klog.V(5).Infof("failed to merge update resource %s to %v, ignored err: %v",
+					updater.Key(), updater.Value(), err)
+				tx.skipped[updater.Key()] = true
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("merge update resource %s to %v failed, err: %w", updater.Key(), updater.Value(), err)
+			}
+			if mergedUpdater == nil {
+				skipMerge[updater.Key()] = true
+			}
+		}
+	}
+
+	for i := len(updaters) - 1; i >= 0; i-- {
+		for _, updater := range updaters[i] {
+			if tx.skipped[updater.Key()] || skipMerge[updater.Key()] {
+				continue
+			}
+			tx.write(updater.Key())
+			err := updater.update()
+			if err != nil && e.isUpdateErrIgnored(err) {
+				klog.V(5).Infof("failed to update resource %s to %v, ignored err: %v", updater.Key(), updater.Value(), err)
+				tx.skipped[updater.Key()] = true
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("update resource %s to %v failed, err: %w", updater.Key(), updater.Value(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// rollbackTransaction restores the written resources to the prior values in the reverse order of the writes.
+// It continues on failures to restore as many resources as possible.
+func (e *ResourceUpdateExecutorImpl) rollbackTransaction(tx *updateTransaction) error {
+	var lastErr error
+	for i := len(tx.journal) - 1; i >= 0; i-- {
+		record := tx.records[tx.journal[i]]
+		err := restoreResourceValue(record.updater, record.priorValue)
+		if err != nil && e.isUpdateErrIgnored(err) {
+			klog.V(5).Infof("skip rollback resource %s, ignored err: %v", record.updater.Key(), err)
+			continue
+		}
+		RecordRollbackFunc(string(record.updater.ResourceType()), err)
+		if err != nil {
+			klog.Warningf("failed to rollback resource %s to %v, err: %v", record.updater.Key(), record.priorValue, err)
+			lastErr = err
+			continue
+		}
+		klog.V(5).Infof("successfully rollback resource %s to %v", record.updater.Key(), record.priorValue)
+	}
+	return lastErr
+}
+
+// write journals the first write of the resource.
+func (tx *updateTransaction) write(key string) {
+	for _, k := range tx.journal {
+		if k == key {
+			return
+		}
+	}
+	tx.journal = append(tx.journal, key)
+}
+
+func readResourceValue(updater ResourceUpdater) (string, error) {
+	switch u := updater.(type) {
+	case *CgroupResourceUpdater:
+		return cgroupFileRead(u.parentDir, u.file)
+	case *DefaultResourceUpdater:
+		return sysutil.CommonFileRead(u.Path())
+	default:
+		return "", fmt.Errorf("rollback is not supported for the updater %T", updater)
+	}
+}
+
+// restoreResourceValue writes the prior value as is, since it is read from the file and may not be valid for the
+// update functions, e.g. `cpu.max` contains both the quota and the period in cgroups-v2.
+func restoreResourceValue(updater ResourceUpdater, value string) error {
+	switch u := updater.(type) {
+	case *CgroupResourceUpdater:
+		if exist, msg := IsCgroupPathExist(u.parentDir, u.file); !exist {
+			return ResourceCgroupDirErr(fmt.Sprintf("rollback cgroup %s failed, msg: %s", u.file.ResourceType(), msg))
+		}
+		currentValue, err := cgroupFileRead(u.parentDir, u.file)
+		if err == nil && currentValue == value {
+			return nil
+		}
+		if err = os.WriteFile(u.Path(), []byte(value), 0644); err != nil {
+			return err
+		}
+		_ = audit.V(3).Reason(ReasonUpdateCgroups).Message("rollback %v to %v", u.Path(), value).Do()
+		return nil
+	case *DefaultResourceUpdater:
+		currentValue, err := sysutil.CommonFileRead(u.Path())
+		if err == nil && currentValue == value {
+			return nil
+		}
+		if err = sysutil.CommonFileWrite(u.Path(), value); err != nil {
+			return err
+		}
+		_ = audit.V(3).Reason(ReasonUpdateSystemConfig).Message("rollback %v to %v", u.Path(), value).Do()
+		return nil
+	default:
+		return fmt.Errorf("rollback is not supported for the updater %T", updater)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func TestResourceUpdateExecutor_UpdateTransaction(t *testing.T) {
+	testPodDir := "kubepods/pod1"
+	testContainerDir := "kubepods/pod1/container1"
+	// the kernel rejects shrinking the cpuset of a parent cgroup with EBUSY when the child still uses the cpus
+	failedUpdateFunc := func(resource ResourceUpdater) error {
+		return &os.PathError{Op: "write", Path: resource.Path(), Err: syscall.EBUSY}
+	}
+	newCPUSetUpdater := func(dir, value string, fail bool) ResourceUpdater {
+		if fail {
+			u, _ := NewMergeableCgroupUpdaterWithCondition(sysutil.CPUSetCPUSName, dir, value, failedUpdateFunc, MergeConditionIfCPUSetIsLooser, nil)
+			return u
+		}
+		u, _ := DefaultCgroupUpdaterFactory.New(sysutil.CPUSetCPUSName, dir, value, nil)
+		return u
+	}
+	type fields struct {
+		notStarted       bool
+		noContainerDir   bool
+		cachedPodUpdater ResourceUpdater
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		updaters         func() [][]ResourceUpdater
+		wantErr          bool
+		wantEBUSY        bool
+		wantPodCPUSet    string
+		wantContainerSet string
+		wantCached       bool
+		wantStatuses     []string
+		wantRollbacks    int
+	}{
+		{
+			name:   "abort when GC is not started",
+			fields: fields{notStarted: true},
+			updaters: func() [][]ResourceUpdater {
+				return [][]ResourceUpdater{
+					{newCPUSetUpdater(testPodDir, "4-7", false)},
+					{newCPUSetUpdater(testContainerDir, "4-7", false)},
+				}
+			},
+			wantErr:          true,
+			wantPodCPUSet:    "0-3",
+			wantContainerSet: "0-3",
+		},
+		{
+			name: "commit the updates",
+			updaters: func() [][]ResourceUpdater {
+				return [][]ResourceUpdater{
+					{newCPUSetUpdater(testPodDir, "4-7", false)},
+					{newCPUSetUpdater(testContainerDir, "4-7", false)},
+				}
+			},
+			wantPodCPUSet:    "4-7",
+			wantContainerSet: "4-7",
+			wantCached:       true,
+			wantStatuses:     []string{TransactionStatusCommitted},
+		},
+		{
+			name: "update all resources if any needs update",
+			fields: fields{
+				cachedPodUpdater: newCPUSetUpdater(testPodDir, "4-7", false),
+			},
+			updaters: func() [][]ResourceUpdater {
+				return [][]ResourceUpdater{
+					{newCPUSetUpdater(testPodDir, "4-7", false)},
+					{newCPUSetUpdater(testContainerDir, "4-7", false)},
+				}
+			},
+			wantPodCPUSet:    "4-7",
+			wantContainerSet: "4-7",
+			wantCached:       true,
+			wantStatuses:     []string{TransactionStatusCommitted},
+		},
+		{
+			name:   "skip the resources whose cgroup dir does not exist",
+			fields: fields{noContainerDir: true},
+			updaters: func() [][]ResourceUpdater {
+				return [][]ResourceUpdater{
+					{newCPUSetUpdater(testPodDir, "4-7", false)},
+					{newCPUSetUpdater(testContainerDir, "4-7", false)},
+				}
+			},
+			wantPodCPUSet: "4-7",
+			wantCached:    true,
+			wantStatuses:  []string{TransactionStatusCommitted},
+		},
+		{
+			name: "rollback when the container update fails",
+			updaters: func() [][]ResourceUpdater {
+				return [][]ResourceUpdater{
+					{newCPUSetUpdater(testPodDir, "4-7", false)},
+					{newCPUSetUpdater(testContainerDir, "4-7", true)},
+				}
+			},
+			wantErr:          true,
+			wantEBUSY:        true,
+			wantPodCPUSet:    "0-3",
+			wantContainerSet: "0-3",
+			wantStatuses:     []string{TransactionStatusRolledBack},
+			wantRollbacks:    2,
+		},
+		{
+			name: "rollback when the pod update fails",
+			updaters: func() [][]ResourceUpdater {
+				return [][]ResourceUpdater{
+					{newCPUSetUpdater(testPodDir, "4-7", true)},
+					{newCPUSetUpdater(testContainerDir, "4-7", false)},
+				}
+			},
+			wantErr:          true,
+			wantEBUSY:        true,
+			wantPodCPUSet:    "0-3",
+			wantContainerSet: "0-3",
+			wantStatuses:     []string{TransactionStatusRolledBack},
+			wantRollbacks:    2,
+		},
+		{
+			name: "abort for the resources cannot be rolled back",
+			updaters: func() [][]ResourceUpdater {
+				u, _ := DefaultCgroupUpdaterFactory.New(sysutil.CPUProcsName, testPodDir, "1", nil)
+				return [][]ResourceUpdater{
+					{newCPUSetUpdater(testPodDir, "4-7", false), u},
+				}
+			},
+			wantErr:          true,
+			wantPodCPUSet:    "0-3",
+			wantContainerSet: "0-3",
+			wantStatuses:     []string{TransactionStatusAborted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(false)
+			helper.WriteCgroupFileContents(testPodDir, sysutil.CPUSet, "0-3")
+			helper.WriteCgroupFileContents(testPodDir, sysutil.CPUProcs, "")
+			if !tt.fields.noContainerDir {
+				helper.WriteCgroupFileContents(testContainerDir, sysutil.CPUSet, "0-3")
+			}
+
+			var gotStatuses []string
+			gotRollbacks := 0
+			RecordTransactionFunc = func(status string) {
+				gotStatuses = append(gotStatuses, status)
+			}
+			RecordRollbackFunc = func(resourceType string, err error) {
+				assert.Equal(t, sysutil.CPUSetCPUSName, resourceType)
+				assert.NoError(t, err)
+				gotRollbacks++
+			}
+			defer func() {
+				RecordTransactionFunc = func(status string) {}
+				RecordRollbackFunc = func(resourceType string, err error) {}
+			}()
+
+			e := &ResourceUpdateExecutorImpl{
+				ResourceCache: cache.NewCacheDefault(),
+				Config:        NewDefaultConfig(),
+			}
+			if !tt.fields.notStarted {
+				stop := make(chan struct{})
+				defer close(stop)
+				e.Run(stop)
+			}
+			if tt.fields.cachedPodUpdater != nil {
+				assert.NoError(t, e.ResourceCache.SetDefault(tt.fields.cachedPodUpdater.Key(), tt.fields.cachedPodUpdater))
+			}
+
+			// EBUSY is not ignored, so the transaction is rolled back instead of treating the write as done
+			assert.False(t, e.isUpdateErrIgnored(failedUpdateFunc(newCPUSetUpdater(testPodDir, "4-7", false))))
+
+			updaters := tt.updaters()
+			gotErr := e.UpdateTransaction(updaters)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.wantEBUSY, errors.Is(gotErr, syscall.EBUSY), gotErr)
+			assert.Equal(t, tt.wantPodCPUSet, helper.ReadCgroupFileContents(testPodDir, sysutil.CPUSet))
+			if !tt.fields.noContainerDir {
+				assert.Equal(t, tt.wantContainerSet, helper.ReadCgroupFileContents(testContainerDir, sysutil.CPUSet))
+			}
+			_, gotCached := e.ResourceCache.Get(updaters[0][0].Key())
+			assert.Equal(t, tt.wantCached, gotCached)
+			assert.Equal(t, tt.wantStatuses, gotStatuses)
+			assert.Equal(t, tt.wantRollbacks, gotRollbacks)
+
+			// nothing to do if the resources are unchanged, while the skipped ones are retried
+			if tt.wantCached && !tt.fields.noContainerDir {
+				gotStatuses = nil
+				assert.NoError(t, e.UpdateTransaction(tt.updaters()))
+				assert.Nil(t, gotStatuses)
+			}
+		})
+	}
+}
+
+func Test_restoreResourceValue(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	helper.WriteCgroupFileContents("kubepods", sysutil.CPUCFSQuotaV2, "max 100000")
+
+	u, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, "kubepods", "200000", nil)
+	assert.NoError(t, err)
+	prior, err := readResourceValue(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "max 100000", prior)
+	helper.WriteCgroupFileContents("kubepods", sysutil.CPUCFSQuotaV2, "200000 100000")
+	// the prior value is restored as is
+	assert.NoError(t, restoreResourceValue(u, prior))
+	assert.Equal(t, "max 100000", helper.ReadCgroupFileContents("kubepods", sysutil.CPUCFSQuotaV2))
+
+	// not exist
+	u, err = DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, "kubepods/pod1", "200000", nil)
+	assert.NoError(t, err)
+	assert.True(t, IsCgroupDirErr(restoreResourceValue(u, prior)))
+
+	// unsupported updater
+	_, err = readResourceValue(NewResctrlL3SchemataResource("BE", "f", 1))
+	assert.Error(t, err)
+}
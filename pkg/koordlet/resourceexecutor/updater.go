@@ -42,6 +42,9 @@ func init() {
 	DefaultCgroupUpdaterFactory.Register(NewCgroupUpdaterWithUpdateFunc(CgroupUpdateWithUnlimitedFunc),
 		sysutil.CPUCFSPeriodName,
 		sysutil.MemoryLimitName,
+		sysutil.MemorySwapMaxName,
+		sysutil.HugetlbLimit2MBName,
+		sysutil.HugetlbLimit1GBName,
 	)
 	DefaultCgroupUpdaterFactory.Register(NewCommonCgroupUpdater,
 		sysutil.CPUBurstName,
@@ -54,9 +57,11 @@ func init() {
 		sysutil.MemoryPriorityName,
 		sysutil.MemoryUsePriorityOomName,
 		sysutil.MemoryOomGroupName,
+		sysutil.NetClsClassIdName,
 	)
 	// special cases
 	DefaultCgroupUpdaterFactory.Register(NewCgroupUpdaterWithUpdateFunc(CgroupUpdateCPUSharesFunc), sysutil.CPUSharesName)
+	DefaultCgroupUpdaterFactory.Register(NewCgroupUpdaterWithUpdateFunc(CgroupWriteOnlyUpdateFunc), sysutil.CgroupKillName)
 	DefaultCgroupUpdaterFactory.Register(NewMergeableCgroupUpdaterWithConditionFunc(CgroupUpdateWithUnlimitedFunc, MergeConditionIfValueIsLarger),
 		sysutil.CPUCFSQuotaName,
 	)
@@ -360,6 +365,20 @@ func CommonCgroupUpdateFunc(resource ResourceUpdater) error {
 	return cgroupWriteIfDifferentWithLog(c)
 }
 
+// CgroupWriteOnlyUpdateFunc writes the write-only cgroup files (e.g. `cgroup.kill`) without comparing the current value.
+func CgroupWriteOnlyUpdateFunc(resource ResourceUpdater) error {
+	c := resource.(*CgroupResourceUpdater)
+	if err := cgroupFileWrite(c.parentDir, c.file, c.value); err != nil {
+		return err
+	}
+	if c.eventHelper != nil {
+		_ = c.eventHelper.Do()
+	} else {
+		_ = audit.V(3).Reason(ReasonUpdateCgroups).Message("update %v to %v", c.Path(), c.Value()).Do()
+	}
+	return nil
+}
+
 func CommonDefaultUpdateFunc(resource ResourceUpdater) error {
 	c := resource.(*DefaultResourceUpdater)
 	return commonWriteIfDifferentWithLog(c)
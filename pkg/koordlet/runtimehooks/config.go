@@ -26,10 +26,12 @@ import (
 
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/batchresource"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/coresched"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/cpunormalization"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/cpuset"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/gpu"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/groupidentity"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/memoryqos"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -66,6 +68,20 @@ const (
 	// owner: @saintube @zwzhang0107
 	// alpha: v1.4
 	CPUNormalization featuregate.Feature = "CPUNormalization"
+
+	// MemoryQOS sets memory qos cgroups (e.g. memory.min, memory.low, memory.high) according to NodeSLO at the
+	// container creation.
+	//
+	// owner: @saintube @zwzhang0107
+	// alpha: v1.5
+	MemoryQOS featuregate.Feature = "MemoryQOS"
+
+	// CoreSched assigns core scheduling cookies to container tasks according to the QoS class, so that the tasks of
+	// different cookies never run concurrently on the SMT siblings of a physical core.
+	//
+	// owner: @saintube @zwzhang0107
+	// alpha: v1.5
+	CoreSched featuregate.Feature = "CoreSched"
 )
 
 var (
@@ -75,6 +91,8 @@ var (
 		GPUEnvInject:     {Default: false, PreRelease: featuregate.Alpha},
 		BatchResource:    {Default: true, PreRelease: featuregate.Beta},
 		CPUNormalization: {Default: false, PreRelease: featuregate.Alpha},
+		MemoryQOS:        {Default: false, PreRelease: featuregate.Alpha},
+		CoreSched:        {Default: false, PreRelease: featuregate.Alpha},
 	}
 
 	runtimeHookPlugins = map[featuregate.Feature]HookPlugin{
@@ -83,6 +101,8 @@ var (
 		GPUEnvInject:     gpu.Object(),
 		BatchResource:    batchresource.Object(),
 		CPUNormalization: cpunormalization.Object(),
+		MemoryQOS:        memoryqos.Object(),
+		CoreSched:        coresched.Object(),
 	}
 )
 
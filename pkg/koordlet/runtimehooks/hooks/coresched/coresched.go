@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coresched
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+)
+
+const (
+	name        = "CoreSched"
+	description = "set core scheduling cookie for container tasks by qos class"
+
+	podGroupPrefix = "pod-"
+	qosGroupPrefix = "qos-"
+)
+
+// cookieGroup is a group of tasks sharing the same core scheduling cookie.
+// The cookie has no handle in the kernel, so a leader task of the group is recorded to share the cookie from.
+type cookieGroup struct {
+	leaderPID uint32
+	cookie    uint64
+}
+
+// plugin assigns the core scheduling cookies to the tasks of the containers, so that the tasks of different cookies
+// (e.g. LS and BE pods) never run concurrently on the SMT siblings of a physical core.
+type plugin struct {
+	rule        *coreSchedRule
+	ruleRWMutex sync.RWMutex
+	// sysSupported is checked once since the hooks are called concurrently
+	sysSupportedOnce sync.Once
+	sysSupported     bool
+
+	// group id -> cookie group
+	groups     map[string]*cookieGroup
+	groupMutex sync.Mutex
+
+	coreSched    sysutil.CoreSchedInterface
+	cgroupReader resourceexecutor.CgroupReader
+}
+
+func (p *plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	rule.Register(name, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeSLOSpec, p.parseRule),
+		rule.WithUpdateCallback(p.ruleUpdateCb),
+		rule.WithSystemSupported(p.SystemSupported))
+	// the tasks of the container only exist after it starts
+	hooks.Register(rmconfig.PostStartContainer, name, description, p.SetContainerCookie)
+	reconciler.RegisterCgroupReconciler(reconciler.ContainerLevel, sysutil.CPUTasks, "reconcile container core sched cookie",
+		p.SetContainerCookie, reconciler.NoneFilter())
+}
+
+func (p *plugin) SystemSupported() bool {
+	p.sysSupportedOnce.Do(func() {
+		// the prctl returns EINVAL if the kernel is not built with CONFIG_SCHED_CORE
+		_, err := p.coreSched.Get(sysutil.CoreSchedScopeThread, 0)
+		p.sysSupported = err == nil
+		klog.Infof("update system supported info to %v for plugin %v, err: %v", p.sysSupported, name, err)
+	})
+	return p.sysSupported
+}
+
+func (p *plugin) SetContainerCookie(proto protocol.HooksProtocol) error {
+	containerCtx := proto.(*protocol.ContainerContext)
+	if containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin %v", name)
+	}
+	if !p.SystemSupported() {
+		klog.V(5).Infof("plugin %s is not supported by system", name)
+		return nil
+	}
+	r := p.getRule()
+	if r == nil {
+		klog.V(5).Infof("hook plugin rule is nil, nothing to do for plugin %v", name)
+		return nil
+	}
+	containerReq := containerCtx.Request
+	if containerReq.CgroupParent == "" {
+		klog.V(5).Infof("cgroup parent is empty for container %s/%s, skip", containerReq.PodMeta.String(),
+			containerReq.ContainerMeta.Name)
+		return nil
+	}
+
+	tasks, err := p.cgroupReader.ReadCPUTasks(containerReq.CgroupParent)
+	if err != nil {
+		return fmt.Errorf("failed to read tasks of container %s/%s, err: %w", containerReq.PodMeta.String(),
+			containerReq.ContainerMeta.Name, err)
+	}
+	pids := make([]uint32, 0, len(tasks))
+	for _, t := range tasks {
+		pids = append(pids, uint32(t))
+	}
+	if len(pids) <= 0 {
+		return nil
+	}
+
+	podQOS := apiext.GetQoSClassByAttrs(containerReq.PodLabels, containerReq.PodAnnotations)
+	groupID := getCookieGroupID(r.getPolicy(podQOS), podQOS, containerReq.PodMeta.UID)
+	if groupID == "" {
+		return p.clearCookie(pids)
+	}
+	return p.assignCookie(groupID, pids)
+}
+
+// assignCookie assigns the cookie of the group to the tasks. A new cookie is created if the group does not exist or
+// the leader task of the group has exited.
+func (p *plugin) assignCookie(groupID string, pids []uint32) error {
+	p.groupMutex.Lock()
+	defer p.groupMutex.Unlock()
+
+	group := p.groups[groupID]
+	if group != nil {
+		cookie, err := p.coreSched.Get(sysutil.CoreSchedScopeThread, group.leaderPID)
+		if err != nil || cookie != group.cookie {
+			// the leader has exited or been reused by another task, the remaining tasks of the group are
+			// reassigned to the new cookie in the later reconciliations
+			klog.V(5).Infof("leader %v of core sched group %s is expired, err: %v", group.leaderPID, groupID, err)
+			group = nil
+		}
+	}
+
+	var toAssign []uint32
+	if group == nil {
+		leaderPID := pids[0]
+		if err := p.coreSched.Create(sysutil.CoreSchedScopeThread, leaderPID); err != nil {
+			return fmt.Errorf("failed to create cookie for group %s on pid %v, err: %w", groupID, leaderPID, err)
+		}
+		cookie, err := p.coreSched.Get(sysutil.CoreSchedScopeThread, leaderPID)
+		if err != nil {
+			return fmt.Errorf("failed to get cookie for group %s on pid %v, err: %w", groupID, leaderPID, err)
+		}
+		group = &cookieGroup{leaderPID: leaderPID, cookie: cookie}
+		p.groups[groupID] = group
+		klog.V(4).Infof("create core sched cookie %v for group %s, leader pid %v", cookie, groupID, leaderPID)
+		pids = pids[1:]
+	}
+
+	for _, pid := range pids {
+		cookie, err := p.coreSched.Get(sysutil.CoreSchedScopeThread, pid)
+		if err != nil { // task may have exited
+			klog.V(6).Infof("failed to get cookie of pid %v, err: %v", pid, err)
+			continue
+		}
+		if cookie != group.cookie {
+			toAssign = append(toAssign, pid)
+		}
+	}
+	if len(toAssign) <= 0 {
+		return nil
+	}
+	failed, err := p.coreSched.Assign(group.leaderPID, sysutil.CoreSchedScopeThread, toAssign...)
+	if err != nil {
+		klog.V(5).Infof("failed to assign cookie of group %s to pids %v, err: %v", groupID, failed, err)
+	}
+	klog.V(5).Infof("assign cookie %v of group %s to %v pids", group.cookie, groupID, len(toAssign)-len(failed))
+	return nil
+}
+
+// clearCookie clears the cookies of the tasks which have been assigned.
+func (p *plugin) clearCookie(pids []uint32) error {
+	var toClear []uint32
+	for _, pid := range pids {
+		cookie, err := p.coreSched.Get(sysutil.CoreSchedScopeThread, pid)
+		if err != nil {
+			klog.V(6).Infof("failed to get cookie of pid %v, err: %v", pid, err)
+			continue
+		}
+		if cookie != 0 {
+			toClear = append(toClear, pid)
+		}
+	}
+	if len(toClear) <= 0 {
+		return nil
+	}
+	failed, err := p.coreSched.Clear(sysutil.CoreSchedScopeThread, toClear...)
+	if err != nil {
+		klog.V(5).Infof("failed to clear cookie of pids %v, err: %v", failed, err)
+	}
+	klog.V(5).Infof("clear cookie of %v pids", len(toClear)-len(failed))
+	return nil
+}
+
+// cleanupPodGroups removes the cookie groups of the pods not running on the node.
+func (p *plugin) cleanupPodGroups(podUIDs map[string]struct{}) {
+	p.groupMutex.Lock()
+	defer p.groupMutex.Unlock()
+	for groupID := range p.groups {
+		if !strings.HasPrefix(groupID, podGroupPrefix) {
+			continue
+		}
+		if _, ok := podUIDs[strings.TrimPrefix(groupID, podGroupPrefix)]; !ok {
+			delete(p.groups, groupID)
+			klog.V(5).Infof("cleanup core sched group %s", groupID)
+		}
+	}
+}
+
+// getCookieGroupID returns the id of the cookie group which the tasks belong to.
+// An empty id means the tasks should not have a cookie.
+func getCookieGroupID(policy slov1alpha1.CoreSchedPolicy, qos apiext.QoSClass, podUID string) string {
+	switch policy {
+	case slov1alpha1.CoreSchedPolicyPod:
+		return podGroupPrefix + podUID
+	case slov1alpha1.CoreSchedPolicyGroup:
+		return qosGroupPrefix + string(qos)
+	default:
+		return ""
+	}
+}
+
+var singleton *plugin
+
+func Object() *plugin {
+	if singleton == nil {
+		singleton = newPlugin()
+	}
+	return singleton
+}
+
+func newPlugin() *plugin {
+	return &plugin{
+		groups:       map[string]*cookieGroup{},
+		coreSched:    sysutil.NewCoreSched(),
+		cgroupReader: resourceexecutor.NewCgroupReader(),
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coresched
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		p := Object()
+		assert.NotNil(t, p)
+	})
+}
+
+func TestPlugin_Register(t *testing.T) {
+	t.Run("test not panic", func(t *testing.T) {
+		p := newPlugin()
+		p.Register(hooks.Options{})
+	})
+}
+
+func Test_plugin_SystemSupported(t *testing.T) {
+	fakeCoreSched := sysutil.NewFakeCoreSched()
+	p := newPlugin()
+	p.coreSched = fakeCoreSched
+	assert.False(t, p.SystemSupported())
+
+	fakeCoreSched.SetTask(0, 0)
+	assert.False(t, p.SystemSupported(), "the result is cached")
+	p.sysSupportedOnce = sync.Once{}
+	assert.True(t, p.SystemSupported())
+}
+
+func Test_plugin_parseRule(t *testing.T) {
+	strategy := sloconfig.DefaultResourceQOSStrategy()
+	strategy.LSClass.CPUQOS.Enable = pointer.Bool(true)
+	strategy.LSClass.CPUQOS.CoreSchedPolicy = corePolicyPtr(slov1alpha1.CoreSchedPolicyPod)
+	strategy.BEClass.CPUQOS.Enable = pointer.Bool(true)
+	strategy.BEClass.CPUQOS.CoreSchedPolicy = corePolicyPtr(slov1alpha1.CoreSchedPolicyGroup)
+	// not take effect since cpu qos is disabled
+	strategy.LSRClass.CPUQOS.Enable = pointer.Bool(false)
+	strategy.LSRClass.CPUQOS.CoreSchedPolicy = corePolicyPtr(slov1alpha1.CoreSchedPolicyPod)
+
+	p := newPlugin()
+	updated, err := p.parseRule(&slov1alpha1.NodeSLOSpec{ResourceQOSStrategy: strategy})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	r := p.getRule()
+	assert.Equal(t, slov1alpha1.CoreSchedPolicyNone, r.getPolicy(apiext.QoSLSE))
+	assert.Equal(t, slov1alpha1.CoreSchedPolicyNone, r.getPolicy(apiext.QoSLSR))
+	assert.Equal(t, slov1alpha1.CoreSchedPolicyPod, r.getPolicy(apiext.QoSLS))
+	assert.Equal(t, slov1alpha1.CoreSchedPolicyGroup, r.getPolicy(apiext.QoSBE))
+	assert.Equal(t, slov1alpha1.CoreSchedPolicyNone, r.getPolicy(apiext.QoSSystem))
+
+	updated, err = p.parseRule(&slov1alpha1.NodeSLOSpec{ResourceQOSStrategy: strategy})
+	assert.NoError(t, err)
+	assert.False(t, updated)
+}
+
+func Test_plugin_SetContainerCookie(t *testing.T) {
+	lsPodParent := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod-ls.slice"
+	lsContainerParent := lsPodParent + "/cri-containerd-ls-c0.scope"
+	bePodParent0 := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod-be0.slice"
+	beContainerParent0 := bePodParent0 + "/cri-containerd-be0-c0.scope"
+	bePodParent1 := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod-be1.slice"
+	beContainerParent1 := bePodParent1 + "/cri-containerd-be1-c0.scope"
+	newContainerCtx := func(uid string, qos apiext.QoSClass, cgroupParent string) *protocol.ContainerContext {
+		return &protocol.ContainerContext{
+			Request: protocol.ContainerRequest{
+				PodMeta:       protocol.PodMeta{Namespace: "test-ns", Name: uid, UID: uid},
+				ContainerMeta: protocol.ContainerMeta{Name: "c0"},
+				PodLabels:     map[string]string{apiext.LabelPodQoS: string(qos)},
+				CgroupParent:  cgroupParent,
+			},
+		}
+	}
+
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteCgroupFileContents(lsContainerParent, sysutil.CPUTasks, "100\n101\n")
+	helper.WriteCgroupFileContents(beContainerParent0, sysutil.CPUTasks, "200\n201\n")
+	helper.WriteCgroupFileContents(beContainerParent1, sysutil.CPUTasks, "300\n")
+
+	fakeCoreSched := sysutil.NewFakeCoreSched()
+	for _, pid := range []uint32{100, 101, 200, 201, 300} {
+		fakeCoreSched.SetTask(pid, 0)
+	}
+	p := newPlugin()
+	p.coreSched = fakeCoreSched
+	p.sysSupportedOnce.Do(func() {
+		p.sysSupported = true
+	})
+
+	// no rule
+	assert.NoError(t, p.SetContainerCookie(newContainerCtx("ls", apiext.QoSLS, lsContainerParent)))
+	assert.Equal(t, 0, len(p.groups))
+
+	strategy := sloconfig.DefaultResourceQOSStrategy()
+	strategy.LSClass.CPUQOS.Enable = pointer.Bool(true)
+	strategy.LSClass.CPUQOS.CoreSchedPolicy = corePolicyPtr(slov1alpha1.CoreSchedPolicyPod)
+	strategy.BEClass.CPUQOS.Enable = pointer.Bool(true)
+	strategy.BEClass.CPUQOS.CoreSchedPolicy = corePolicyPtr(slov1alpha1.CoreSchedPolicyGroup)
+	_, err := p.parseRule(&slov1alpha1.NodeSLOSpec{ResourceQOSStrategy: strategy})
+	assert.NoError(t, err)
+
+	// ls pod gets its own cookie
+	assert.NoError(t, p.SetContainerCookie(newContainerCtx("ls", apiext.QoSLS, lsContainerParent)))
+	lsCookie := getCookie(t, fakeCoreSched, 100)
+	assert.NotEqual(t, uint64(0), lsCookie)
+	assert.Equal(t, lsCookie, getCookie(t, fakeCoreSched, 101))
+
+	// be pods share the group cookie
+	assert.NoError(t, p.SetContainerCookie(newContainerCtx("be0", apiext.QoSBE, beContainerParent0)))
+	assert.NoError(t, p.SetContainerCookie(newContainerCtx("be1", apiext.QoSBE, beContainerParent1)))
+	beCookie := getCookie(t, fakeCoreSched, 200)
+	assert.NotEqual(t, uint64(0), beCookie)
+	assert.NotEqual(t, lsCookie, beCookie)
+	assert.Equal(t, beCookie, getCookie(t, fakeCoreSched, 201))
+	assert.Equal(t, beCookie, getCookie(t, fakeCoreSched, 300))
+
+	// the leader exits, a new cookie is created for the group
+	fakeCoreSched.DeleteTask(200)
+	helper.WriteCgroupFileContents(beContainerParent0, sysutil.CPUTasks, "201\n")
+	assert.NoError(t, p.SetContainerCookie(newContainerCtx("be0", apiext.QoSBE, beContainerParent0)))
+	newBECookie := getCookie(t, fakeCoreSched, 201)
+	assert.NotEqual(t, beCookie, newBECookie)
+	assert.NoError(t, p.SetContainerCookie(newContainerCtx("be1", apiext.QoSBE, beContainerParent1)))
+	assert.Equal(t, newBECookie, getCookie(t, fakeCoreSched, 300))
+
+	// cookies are cleared when the policy is none
+	strategy.LSClass.CPUQOS.CoreSchedPolicy = corePolicyPtr(slov1alpha1.CoreSchedPolicyNone)
+	_, err = p.parseRule(&slov1alpha1.NodeSLOSpec{ResourceQOSStrategy: strategy})
+	assert.NoError(t, err)
+	assert.NoError(t, p.SetContainerCookie(newContainerCtx("ls", apiext.QoSLS, lsContainerParent)))
+	assert.Equal(t, uint64(0), getCookie(t, fakeCoreSched, 100))
+	assert.Equal(t, uint64(0), getCookie(t, fakeCoreSched, 101))
+
+	// tasks not found
+	assert.Error(t, p.SetContainerCookie(newContainerCtx("unknown", apiext.QoSBE, bePodParent0+"/unknown")))
+}
+
+func Test_plugin_ruleUpdateCb(t *testing.T) {
+	p := newPlugin()
+	p.coreSched = sysutil.NewFakeCoreSched()
+	p.sysSupportedOnce.Do(func() {
+		p.sysSupported = true
+	})
+	p.groups = map[string]*cookieGroup{
+		podGroupPrefix + "running-pod": {leaderPID: 1, cookie: 1},
+		podGroupPrefix + "deleted-pod": {leaderPID: 2, cookie: 2},
+		qosGroupPrefix + "BE":          {leaderPID: 3, cookie: 3},
+	}
+	assert.Error(t, p.ruleUpdateCb(nil))
+
+	err := p.ruleUpdateCb(&statesinformer.CallbackTarget{
+		Pods: []*statesinformer.PodMeta{
+			{
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "running-pod", UID: "running-pod"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*cookieGroup{
+		podGroupPrefix + "running-pod": {leaderPID: 1, cookie: 1},
+		qosGroupPrefix + "BE":          {leaderPID: 3, cookie: 3},
+	}, p.groups)
+}
+
+func corePolicyPtr(policy slov1alpha1.CoreSchedPolicy) *slov1alpha1.CoreSchedPolicy {
+	return &policy
+}
+
+func getCookie(t *testing.T, coreSched sysutil.CoreSchedInterface, pid uint32) uint64 {
+	cookie, err := coreSched.Get(sysutil.CoreSchedScopeThread, pid)
+	assert.NoError(t, err)
+	return cookie
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coresched
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+type coreSchedRule struct {
+	podQOSPolicies map[apiext.QoSClass]slov1alpha1.CoreSchedPolicy
+}
+
+func (r *coreSchedRule) getPolicy(qos apiext.QoSClass) slov1alpha1.CoreSchedPolicy {
+	if policy, ok := r.podQOSPolicies[qos]; ok {
+		return policy
+	}
+	return slov1alpha1.CoreSchedPolicyNone
+}
+
+func (p *plugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
+	mergedNodeSLO := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
+	qosStrategy := mergedNodeSLO.ResourceQOSStrategy
+
+	lsrPolicy := getCPUQOSCoreSchedPolicy(qosStrategy.LSRClass)
+	newRule := &coreSchedRule{
+		podQOSPolicies: map[apiext.QoSClass]slov1alpha1.CoreSchedPolicy{
+			apiext.QoSLSE: lsrPolicy,
+			apiext.QoSLSR: lsrPolicy,
+			apiext.QoSLS:  getCPUQOSCoreSchedPolicy(qosStrategy.LSClass),
+			apiext.QoSBE:  getCPUQOSCoreSchedPolicy(qosStrategy.BEClass),
+		},
+	}
+
+	updated := p.updateRule(newRule)
+	klog.Infof("runtime hook plugin %s update rule %v, new rule %v", name, updated, newRule)
+	return updated, nil
+}
+
+// getCPUQOSCoreSchedPolicy returns the core sched policy of the qos class, which takes effect only if the cpu qos of
+// the class is enabled.
+func getCPUQOSCoreSchedPolicy(resourceQOS *slov1alpha1.ResourceQOS) slov1alpha1.CoreSchedPolicy {
+	if resourceQOS == nil || resourceQOS.CPUQOS == nil || resourceQOS.CPUQOS.Enable == nil ||
+		!*resourceQOS.CPUQOS.Enable || resourceQOS.CPUQOS.CoreSchedPolicy == nil {
+		return slov1alpha1.CoreSchedPolicyNone
+	}
+	return *resourceQOS.CPUQOS.CoreSchedPolicy
+}
+
+func (p *plugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
+	if !p.SystemSupported() {
+		klog.V(5).Infof("plugin %s is not supported by system", name)
+		return nil
+	}
+	if target == nil {
+		return fmt.Errorf("callback target is nil")
+	}
+	podUIDs := make(map[string]struct{}, len(target.Pods))
+	for _, podMeta := range target.Pods {
+		podUIDs[string(podMeta.Pod.UID)] = struct{}{}
+		for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
+			containerCtx := &protocol.ContainerContext{}
+			containerCtx.FromReconciler(podMeta, containerStat.Name, false)
+			if err := p.SetContainerCookie(containerCtx); err != nil {
+				klog.V(4).Infof("failed to set core sched cookie for container %s/%s, err: %v",
+					util.GetPodKey(podMeta.Pod), containerStat.Name, err)
+			}
+		}
+	}
+	p.cleanupPodGroups(podUIDs)
+	return nil
+}
+
+func (p *plugin) getRule() *coreSchedRule {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	if p.rule == nil {
+		return nil
+	}
+	rule := *p.rule
+	return &rule
+}
+
+func (p *plugin) updateRule(newRule *coreSchedRule) bool {
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	if !reflect.DeepEqual(newRule, p.rule) {
+		p.rule = newRule
+		return true
+	}
+	return false
+}
@@ -28,6 +28,7 @@ import (
 
 	ext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
@@ -188,6 +189,9 @@ func (p *cpusetPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error
 		return nil
 	}
 	for _, podMeta := range target.Pods {
+		// update the cpuset of the sandbox and containers in a transaction, so that a failure in the middle will not
+		// leave the cpuset of the containers inconsistent
+		var updaters []resourceexecutor.ResourceUpdater
 		for _, containerStat := range podMeta.Pod.Status.ContainerStatuses {
 			containerCtx := &protocol.ContainerContext{}
 			containerCtx.FromReconciler(podMeta, containerStat.Name, false)
@@ -195,19 +199,26 @@ func (p *cpusetPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error
 				klog.V(4).Infof("parse cpuset from pod annotation failed during callback, error: %v", err)
 				continue
 			}
-			containerCtx.ReconcilerDone(p.executor)
+			containerCtx.ReconcilerProcess(p.executor)
+			updaters = append(updaters, containerCtx.GetUpdaters()...)
 		}
-
 		sandboxContainerCtx := &protocol.ContainerContext{}
 		sandboxContainerCtx.FromReconciler(podMeta, "", true)
 		if err := p.SetContainerCPUSet(sandboxContainerCtx); err != nil {
 			klog.Warningf("set cpuset for failed for pod sandbox %v/%v, error %v",
 				sandboxContainerCtx.Request.PodMeta.String(), sandboxContainerCtx.Request.ContainerMeta.ID, err)
+		} else {
+			sandboxContainerCtx.ReconcilerProcess(p.executor)
+			updaters = append(updaters, sandboxContainerCtx.GetUpdaters()...)
+		}
+		if len(updaters) <= 0 {
+			continue
+		}
+		if err := p.executor.UpdateTransaction([][]resourceexecutor.ResourceUpdater{updaters}); err != nil {
+			klog.Warningf("set cpuset failed for pod %v, error %v", podMeta.Key(), err)
 			continue
 		}
-		sandboxContainerCtx.ReconcilerDone(p.executor)
-		klog.V(5).Infof("set cpuset finished pod sandbox %v/%v",
-			sandboxContainerCtx.Request.PodMeta.String(), sandboxContainerCtx.Request.ContainerMeta.ID)
+		klog.V(5).Infof("set cpuset finished for pod %v", podMeta.Key())
 	}
 	for _, hostApp := range target.HostApplications {
 		hostCtx := protocol.HooksProtocolBuilder.HostApp(&hostApp)
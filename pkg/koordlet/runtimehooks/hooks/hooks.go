@@ -23,6 +23,7 @@ import (
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
 )
 
@@ -34,7 +35,8 @@ type Hook struct {
 }
 
 type Options struct {
-	Executor resourceexecutor.ResourceUpdateExecutor
+	Executor       resourceexecutor.ResourceUpdateExecutor
+	StatesInformer statesinformer.StatesInformer
 }
 
 type HookFn func(protocol.HooksProtocol) error
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryqos
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	name        = "MemoryQOS"
+	description = "set memory qos cgroups value for container"
+)
+
+// plugin sets the memory qos cgroups (e.g. memory.min, memory.low, memory.high and memory.wmark_ratio) of the
+// container when the container is created or updated, so that the memory protection is in place before the workload
+// starts allocating. The cgroups are still reconciled periodically by the CgroupReconcile strategy of the qosmanager.
+type plugin struct {
+	rule           *memoryQOSRule
+	ruleRWMutex    sync.RWMutex
+	statesInformer statesinformer.StatesInformer
+	executor       resourceexecutor.ResourceUpdateExecutor
+}
+
+func (p *plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	rule.Register(name, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeSLOSpec, p.parseRule))
+	hooks.Register(rmconfig.PreCreateContainer, name, description, p.SetContainerMemoryQOS)
+	hooks.Register(rmconfig.PreUpdateContainerResources, name, description, p.SetContainerMemoryQOS)
+	p.statesInformer = op.StatesInformer
+	p.executor = op.Executor
+}
+
+var singleton *plugin
+
+func Object() *plugin {
+	if singleton == nil {
+		singleton = newPlugin()
+	}
+	return singleton
+}
+
+func newPlugin() *plugin {
+	return &plugin{}
+}
+
+func (p *plugin) SetContainerMemoryQOS(proto protocol.HooksProtocol) error {
+	containerCtx := proto.(*protocol.ContainerContext)
+	if containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin %v", name)
+	}
+	r := p.getRule()
+	if r == nil || r.strategy == nil {
+		klog.V(5).Infof("hook plugin rule is nil, nothing to do for plugin %v", name)
+		return nil
+	}
+	if p.statesInformer == nil {
+		return fmt.Errorf("states informer is nil for plugin %v", name)
+	}
+
+	// the memory requests are not passed in the runtime requests, so retrieve the pod spec from the states informer
+	// if possible, otherwise the pod may be not synced yet at the container creation, and the spec is built from the
+	// runtime request
+	var container *corev1.Container
+	pod := p.getPod(containerCtx.Request.PodMeta.UID)
+	if pod != nil {
+		container = getContainerSpec(pod, containerCtx.Request.ContainerMeta.Name)
+	}
+	if container == nil {
+		klog.V(5).Infof("container %s/%s not found in states informer, build it from the runtime request",
+			containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
+		pod, container = getPodFromRequest(&containerCtx.Request)
+	}
+	memoryQOS := getPodMemoryQOS(pod, r.strategy)
+	if memoryQOS == nil {
+		klog.V(6).Infof("memory qos is disabled for pod %s, skip plugin %v", util.GetPodKey(pod), name)
+		return nil
+	}
+
+	var nodeAllocatable int64
+	if node := p.statesInformer.GetNode(); node != nil {
+		nodeAllocatable = node.Status.Allocatable.Memory().Value()
+	}
+	setContainerMemoryQOS(&containerCtx.Response.Resources,
+		koordletutil.CalculateContainerMemoryQOS(pod, container, memoryQOS, nodeAllocatable))
+	klog.V(5).Infof("plugin %s set container %s/%s memory qos, min %v, low %v, high %v, wmark ratio %v",
+		name, util.GetPodKey(pod), container.Name, util.DumpJSON(containerCtx.Response.Resources.MemoryMin),
+		util.DumpJSON(containerCtx.Response.Resources.MemoryLow), util.DumpJSON(containerCtx.Response.Resources.MemoryHigh),
+		util.DumpJSON(containerCtx.Response.Resources.MemoryWmarkRatio))
+	return nil
+}
+
+func (p *plugin) getPod(uid string) *corev1.Pod {
+	for _, podMeta := range p.statesInformer.GetAllPods() {
+		if podMeta != nil && podMeta.Pod != nil && string(podMeta.Pod.UID) == uid {
+			return podMeta.Pod
+		}
+	}
+	return nil
+}
+
+// getPodFromRequest builds the pod and container spec from the runtime request. The memory requests of the non-BE
+// containers are unknown in the request, so only the limits take effect.
+func getPodFromRequest(request *protocol.ContainerRequest) (*corev1.Pod, *corev1.Container) {
+	container := corev1.Container{
+		Name: request.ContainerMeta.Name,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{},
+			Limits:   corev1.ResourceList{},
+		},
+	}
+	if request.Resources != nil && request.Resources.MemoryLimit != nil && *request.Resources.MemoryLimit > 0 {
+		container.Resources.Limits[corev1.ResourceMemory] = *resource.NewQuantity(*request.Resources.MemoryLimit, resource.BinarySI)
+	}
+	if request.ExtendedResources != nil {
+		for name, q := range request.ExtendedResources.Requests {
+			container.Resources.Requests[name] = q
+		}
+		for name, q := range request.ExtendedResources.Limits {
+			container.Resources.Limits[name] = q
+		}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   request.PodMeta.Namespace,
+			Name:        request.PodMeta.Name,
+			UID:         types.UID(request.PodMeta.UID),
+			Labels:      request.PodLabels,
+			Annotations: request.PodAnnotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{container},
+		},
+	}
+	return pod, &pod.Spec.Containers[0]
+}
+
+func getContainerSpec(pod *corev1.Pod, containerName string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == containerName {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// getPodMemoryQOS returns the memory qos config of the pod merged with the pod-level config, nil if it is disabled.
+// config overwrite: pod-level config > pod policy template > node-level config
+func getPodMemoryQOS(pod *corev1.Pod, strategy *slov1alpha1.ResourceQOSStrategy) *slov1alpha1.MemoryQOS {
+	podCfg := koordletutil.GetPodMemoryQOSConfig(pod)
+	if podCfg != nil && podCfg.Policy == slov1alpha1.PodMemoryQOSPolicyNone {
+		return nil
+	}
+	podQOSCfg := koordletutil.GetPodResourceQoSByQoSClass(pod, strategy)
+	nodeEnabled := podQOSCfg != nil && podQOSCfg.MemoryQOS != nil && podQOSCfg.MemoryQOS.Enable != nil &&
+		*podQOSCfg.MemoryQOS.Enable
+	if !nodeEnabled && podCfg == nil { // neither node-level nor pod-level config enables the memory qos
+		return nil
+	}
+
+	mergedCfg := &slov1alpha1.ResourceQOS{}
+	if podQOSCfg != nil {
+		mergedCfg = podQOSCfg.DeepCopy()
+	}
+	koordletutil.MergePodMemoryQOS(pod, mergedCfg, podCfg)
+	return &mergedCfg.MemoryQOS.MemoryQOS
+}
+
+// setContainerMemoryQOS sets the memory qos resources of the container in the same way as the CgroupReconcile
+// strategy.
+func setContainerMemoryQOS(resources *protocol.Resources, containerMemoryQOS *koordletutil.ContainerMemoryQOS) {
+	resources.MemoryMin = containerMemoryQOS.MemoryMin
+	resources.MemoryLow = containerMemoryQOS.MemoryLow
+	resources.MemoryHigh = containerMemoryQOS.MemoryHigh
+	resources.MemoryWmarkRatio = containerMemoryQOS.WmarkRatio
+	resources.MemoryWmarkScaleFactor = containerMemoryQOS.WmarkScaleFactor
+	resources.MemoryWmarkMinAdj = containerMemoryQOS.WmarkMinAdj
+	resources.MemoryUsePriorityOom = containerMemoryQOS.UsePriorityOom
+	resources.MemoryPriority = containerMemoryQOS.Priority
+	resources.MemoryOomKillGroup = containerMemoryQOS.OomKillGroup
+}
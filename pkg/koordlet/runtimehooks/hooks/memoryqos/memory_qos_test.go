@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryqos
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		p := Object()
+		assert.NotNil(t, p)
+	})
+}
+
+func TestPlugin_Register(t *testing.T) {
+	t.Run("test not panic", func(t *testing.T) {
+		p := newPlugin()
+		p.Register(hooks.Options{})
+	})
+}
+
+func Test_plugin_parseRule(t *testing.T) {
+	p := newPlugin()
+	strategy := sloconfig.DefaultResourceQOSStrategy()
+	updated, err := p.parseRule(&slov1alpha1.NodeSLOSpec{ResourceQOSStrategy: strategy})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, strategy, p.getRule().strategy)
+
+	updated, err = p.parseRule(&slov1alpha1.NodeSLOSpec{ResourceQOSStrategy: strategy})
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	_, err = p.parseRule(&slov1alpha1.NodeSLOSpec{})
+	assert.Error(t, err)
+}
+
+func Test_getPodMemoryQOS(t *testing.T) {
+	enabledStrategy := sloconfig.DefaultResourceQOSStrategy()
+	enabledStrategy.LSClass.MemoryQOS.Enable = pointer.Bool(true)
+	enabledStrategy.LSClass.MemoryQOS.MinLimitPercent = pointer.Int64(100)
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		strategy *slov1alpha1.ResourceQOSStrategy
+		want     *slov1alpha1.MemoryQOS
+	}{
+		{
+			name: "disabled on node",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+				},
+			},
+			strategy: sloconfig.DefaultResourceQOSStrategy(),
+			want:     nil,
+		},
+		{
+			name: "enabled on node",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+				},
+			},
+			strategy: enabledStrategy,
+			want:     &enabledStrategy.LSClass.MemoryQOS.MemoryQOS,
+		},
+		{
+			name: "disabled by pod policy",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+					Annotations: map[string]string{slov1alpha1.AnnotationPodMemoryQoS: `{"policy":"none"}`},
+				},
+			},
+			strategy: enabledStrategy,
+			want:     nil,
+		},
+		{
+			name: "enabled by pod config",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+					Annotations: map[string]string{slov1alpha1.AnnotationPodMemoryQoS: `{"minLimitPercent":50}`},
+				},
+			},
+			strategy: sloconfig.DefaultResourceQOSStrategy(),
+			want: func() *slov1alpha1.MemoryQOS {
+				m := sloconfig.DefaultResourceQOSStrategy().LSClass.MemoryQOS.MemoryQOS.DeepCopy()
+				m.MinLimitPercent = pointer.Int64(50)
+				return m
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getPodMemoryQOS(tt.pod, tt.strategy)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_plugin_SetContainerMemoryQOS(t *testing.T) {
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "test-ns",
+			UID:       "test-pod-uid",
+			Labels:    map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test-container",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+					},
+				},
+			},
+		},
+	}
+	strategy := sloconfig.DefaultResourceQOSStrategy()
+	strategy.LSClass.MemoryQOS.Enable = pointer.Bool(true)
+	strategy.LSClass.MemoryQOS.MinLimitPercent = pointer.Int64(100)
+	strategy.BEClass.MemoryQOS.Enable = pointer.Bool(true)
+	strategy.BEClass.MemoryQOS.MinLimitPercent = pointer.Int64(100)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: testPod}}).AnyTimes()
+	si.EXPECT().GetNode().Return(&corev1.Node{}).AnyTimes()
+
+	p := newPlugin()
+	p.statesInformer = si
+	// no rule
+	containerCtx := &protocol.ContainerContext{}
+	assert.NoError(t, p.SetContainerMemoryQOS(containerCtx))
+	assert.False(t, containerCtx.Response.Resources.IsMemoryQOSSet())
+
+	_, err := p.parseRule(&slov1alpha1.NodeSLOSpec{ResourceQOSStrategy: strategy})
+	assert.NoError(t, err)
+	// pod not synced yet, build the spec from the request
+	containerCtx = &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodMeta:       protocol.PodMeta{Namespace: "test-ns", Name: "new-pod", UID: "new-pod-uid"},
+			ContainerMeta: protocol.ContainerMeta{Name: "test-container"},
+			PodLabels:     map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+			Resources:     &protocol.Resources{MemoryLimit: pointer.Int64(2 << 30)},
+		},
+	}
+	assert.NoError(t, p.SetContainerMemoryQOS(containerCtx))
+	assert.Equal(t, pointer.Int64(0), containerCtx.Response.Resources.MemoryMin)
+	assert.Equal(t, pointer.Int64(95), containerCtx.Response.Resources.MemoryWmarkRatio)
+	containerCtx = &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodMeta:       protocol.PodMeta{Namespace: "test-ns", Name: "new-be-pod", UID: "new-be-pod-uid"},
+			ContainerMeta: protocol.ContainerMeta{Name: "test-container"},
+			PodLabels:     map[string]string{apiext.LabelPodQoS: string(apiext.QoSBE)},
+			ExtendedResources: &apiext.ExtendedResourceContainerSpec{
+				Requests: corev1.ResourceList{apiext.BatchMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{apiext.BatchMemory: resource.MustParse("2Gi")},
+			},
+		},
+	}
+	assert.NoError(t, p.SetContainerMemoryQOS(containerCtx))
+	assert.Equal(t, pointer.Int64(1<<30), containerCtx.Response.Resources.MemoryMin)
+
+	containerCtx = &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodMeta:       protocol.PodMeta{Namespace: "test-ns", Name: "test-pod", UID: "test-pod-uid"},
+			ContainerMeta: protocol.ContainerMeta{Name: "test-container"},
+		},
+	}
+	assert.NoError(t, p.SetContainerMemoryQOS(containerCtx))
+	assert.Equal(t, pointer.Int64(1<<30), containerCtx.Response.Resources.MemoryMin)
+	assert.Equal(t, pointer.Int64(0), containerCtx.Response.Resources.MemoryLow)
+	assert.Equal(t, pointer.Int64(math.MaxInt64), containerCtx.Response.Resources.MemoryHigh)
+	assert.Equal(t, pointer.Int64(95), containerCtx.Response.Resources.MemoryWmarkRatio)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryqos
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+type memoryQOSRule struct {
+	strategy *slov1alpha1.ResourceQOSStrategy
+}
+
+func (p *plugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
+	mergedNodeSLO, ok := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
+	if !ok {
+		return false, fmt.Errorf("type input %T is not *NodeSLOSpec", mergedNodeSLOIf)
+	}
+	if mergedNodeSLO == nil || mergedNodeSLO.ResourceQOSStrategy == nil {
+		return false, fmt.Errorf("got nil resource qos strategy")
+	}
+
+	newRule := &memoryQOSRule{
+		strategy: mergedNodeSLO.ResourceQOSStrategy.DeepCopy(),
+	}
+	updated := p.updateRule(newRule)
+	klog.V(4).Infof("runtime hook plugin %s update rule %v", name, updated)
+	return updated, nil
+}
+
+func (p *plugin) getRule() *memoryQOSRule {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	if p.rule == nil {
+		return nil
+	}
+	rule := *p.rule
+	return &rule
+}
+
+func (p *plugin) updateRule(newRule *memoryQOSRule) bool {
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	if !reflect.DeepEqual(newRule, p.rule) {
+		p.rule = newRule
+		return true
+	}
+	return false
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -200,6 +201,18 @@ func (c *ContainerResponse) ProxyDone(resp *runtimeapi.ContainerResourceHookResp
 	if c.Resources.MemoryLimit != nil {
 		resp.ContainerResources.MemoryLimitInBytes = *c.Resources.MemoryLimit
 	}
+	// memory qos can be set by the runtime at the container creation only on cgroups-v2
+	if c.Resources.IsMemoryQOSSet() && sysutil.GetCurrentCgroupVersion() == sysutil.CgroupVersionV2 {
+		if resp.ContainerResources == nil {
+			resp.ContainerResources = &runtimeapi.LinuxContainerResources{}
+		}
+		if resp.ContainerResources.Unified == nil {
+			resp.ContainerResources.Unified = map[string]string{}
+		}
+		for k, v := range c.Resources.GetUnifiedMemoryQOS() {
+			resp.ContainerResources.Unified[k] = v
+		}
+	}
 	if c.AddContainerEnvs != nil {
 		if resp.ContainerEnvs == nil {
 			resp.ContainerEnvs = make(map[string]string)
@@ -225,7 +238,10 @@ func (c *ContainerContext) FromProxy(req *runtimeapi.ContainerResourceHookReques
 	c.Request.FromProxy(req)
 }
 
-func (c *ContainerContext) ProxyDone(resp *runtimeapi.ContainerResourceHookResponse) {
+func (c *ContainerContext) ProxyDone(resp *runtimeapi.ContainerResourceHookResponse, executor resourceexecutor.ResourceUpdateExecutor) {
+	if c.executor == nil {
+		c.executor = executor
+	}
 	c.injectForExt()
 	c.Response.ProxyDone(resp)
 	c.Update()
@@ -259,6 +275,13 @@ func (c *ContainerContext) NriDone(executor resourceexecutor.ResourceUpdateExecu
 		update.SetLinuxMemoryLimit(*c.Response.Resources.MemoryLimit)
 	}
 
+	if c.Response.Resources.IsMemoryQOSSet() && sysutil.GetCurrentCgroupVersion() == sysutil.CgroupVersionV2 {
+		for k, v := range c.Response.Resources.GetUnifiedMemoryQOS() {
+			adjust.AddLinuxUnified(k, v)
+			update.AddLinuxUnified(k, v)
+		}
+	}
+
 	if c.Response.AddContainerEnvs != nil {
 		for k, v := range c.Response.AddContainerEnvs {
 			adjust.AddEnv(k, v)
@@ -368,7 +391,41 @@ func (c *ContainerContext) injectForOrigin() {
 }
 
 func (c *ContainerContext) injectForExt() {
-	// TODO
+	// the container cgroup does not exist before the container is created, where the memory qos is set by the runtime
+	// on cgroups-v2, so here only updates the cgroups of the existing container
+	if len(c.Request.CgroupParent) == 0 || !c.Response.Resources.IsMemoryQOSSet() {
+		return
+	}
+	for _, t := range []struct {
+		resourceType sysutil.ResourceType
+		value        *int64
+	}{
+		{resourceType: sysutil.MemoryMinName, value: c.Response.Resources.MemoryMin},
+		{resourceType: sysutil.MemoryLowName, value: c.Response.Resources.MemoryLow},
+		{resourceType: sysutil.MemoryHighName, value: c.Response.Resources.MemoryHigh},
+		{resourceType: sysutil.MemoryWmarkRatioName, value: c.Response.Resources.MemoryWmarkRatio},
+		{resourceType: sysutil.MemoryWmarkScaleFactorName, value: c.Response.Resources.MemoryWmarkScaleFactor},
+		{resourceType: sysutil.MemoryWmarkMinAdjName, value: c.Response.Resources.MemoryWmarkMinAdj},
+		{resourceType: sysutil.MemoryUsePriorityOomName, value: c.Response.Resources.MemoryUsePriorityOom},
+		{resourceType: sysutil.MemoryPriorityName, value: c.Response.Resources.MemoryPriority},
+		{resourceType: sysutil.MemoryOomGroupName, value: c.Response.Resources.MemoryOomKillGroup},
+	} {
+		if t.value == nil {
+			continue
+		}
+		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message(
+			"set container %v to %v", t.resourceType, *t.value)
+		updater, err := injectMemoryQOS(t.resourceType, c.Request.CgroupParent, *t.value, eventHelper)
+		if err != nil {
+			klog.V(4).Infof("set container %v/%v/%v %v %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
+				c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, t.resourceType, *t.value, c.Request.CgroupParent, err)
+			continue
+		}
+		c.updaters = append(c.updaters, updater)
+		klog.V(5).Infof("set container %v/%v/%v %v %v on cgroup parent %v",
+			c.Request.PodMeta.Namespace, c.Request.PodMeta.Name, c.Request.ContainerMeta.Name,
+			t.resourceType, *t.value, c.Request.CgroupParent)
+	}
 }
 
 func getContainerID(podAnnotations map[string]string, containerUID string) string {
@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"math"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +31,7 @@ import (
 )
 
 type HooksProtocol interface {
+	ReconcilerProcess(executor resourceexecutor.ResourceUpdateExecutor)
 	ReconcilerDone(executor resourceexecutor.ResourceUpdateExecutor)
 	Update()
 	GetUpdaters() []resourceexecutor.ResourceUpdater
@@ -80,6 +82,47 @@ type Resources struct {
 
 	// extended resources
 	CPUBvt *int64
+	// memory qos resources, which are memory.min/low/high on cgroups-v2 or anolis os
+	MemoryMin              *int64
+	MemoryLow              *int64
+	MemoryHigh             *int64
+	MemoryWmarkRatio       *int64
+	MemoryWmarkScaleFactor *int64
+	MemoryWmarkMinAdj      *int64
+	MemoryUsePriorityOom   *int64
+	MemoryPriority         *int64
+	MemoryOomKillGroup     *int64
+}
+
+// IsMemoryQOSSet returns whether any of the memory qos resources is injected.
+func (r *Resources) IsMemoryQOSSet() bool {
+	return r.MemoryMin != nil || r.MemoryLow != nil || r.MemoryHigh != nil || r.MemoryWmarkRatio != nil ||
+		r.MemoryWmarkScaleFactor != nil || r.MemoryWmarkMinAdj != nil || r.MemoryUsePriorityOom != nil ||
+		r.MemoryPriority != nil || r.MemoryOomKillGroup != nil
+}
+
+// GetUnifiedMemoryQOS returns the cgroups-v2 unified resources of memory qos, which can be set by the runtime at the
+// container creation. Anolis-specific resources like memory.wmark_ratio are excluded since they may be unsupported.
+func (r *Resources) GetUnifiedMemoryQOS() map[string]string {
+	unified := map[string]string{}
+	for _, t := range []struct {
+		name  string
+		value *int64
+	}{
+		{name: sysutil.MemoryMinName, value: r.MemoryMin},
+		{name: sysutil.MemoryLowName, value: r.MemoryLow},
+		{name: sysutil.MemoryHighName, value: r.MemoryHigh},
+	} {
+		if t.value == nil {
+			continue
+		}
+		if *t.value == math.MaxInt64 { // writing MaxInt64 is equal to write "max"
+			unified[t.name] = sysutil.CgroupMaxSymbolStr
+		} else {
+			unified[t.name] = strconv.FormatInt(*t.value, 10)
+		}
+	}
+	return unified
 }
 
 func (r *Resources) IsOriginResSet() bool {
@@ -158,6 +201,15 @@ func injectMemoryLimit(cgroupParent string, memoryLimit int64, a *audit.EventHel
 	return updater, nil
 }
 
+func injectMemoryQOS(resourceType sysutil.ResourceType, cgroupParent string, value int64, a *audit.EventHelper) (resourceexecutor.ResourceUpdater, error) {
+	valueStr := strconv.FormatInt(value, 10)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(resourceType, cgroupParent, valueStr, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
 func injectCPUBvt(cgroupParent string, bvtValue int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
 	bvtValueStr := strconv.FormatInt(bvtValue, 10)
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUBVTWarpNsName, cgroupParent, bvtValueStr, a)
@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"math"
 	"sync"
 	"testing"
 
@@ -28,6 +29,7 @@ import (
 
 	runtimeapi "github.com/koordinator-sh/koordinator/apis/runtime/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 func TestResources_IsOriginResSet(t *testing.T) {
@@ -325,6 +327,47 @@ func TestContainerResponse_ProxyDone(t *testing.T) {
 	}
 }
 
+func TestResources_GetUnifiedMemoryQOS(t *testing.T) {
+	r := &Resources{}
+	assert.False(t, r.IsMemoryQOSSet())
+	assert.Equal(t, map[string]string{}, r.GetUnifiedMemoryQOS())
+
+	r = &Resources{
+		MemoryMin:        pointer.Int64(1048576),
+		MemoryHigh:       pointer.Int64(math.MaxInt64),
+		MemoryWmarkRatio: pointer.Int64(95),
+	}
+	assert.True(t, r.IsMemoryQOSSet())
+	assert.Equal(t, map[string]string{
+		sysutil.MemoryMinName:  "1048576",
+		sysutil.MemoryHighName: "max",
+	}, r.GetUnifiedMemoryQOS())
+}
+
+func TestContainerResponse_ProxyDoneMemoryQOS(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	c := &ContainerResponse{
+		Resources: Resources{
+			MemoryMin: pointer.Int64(1048576),
+			MemoryLow: pointer.Int64(2097152),
+		},
+	}
+	// not set on cgroups-v1
+	helper.SetCgroupsV2(false)
+	resp := &runtimeapi.ContainerResourceHookResponse{}
+	c.ProxyDone(resp)
+	assert.Nil(t, resp.ContainerResources)
+
+	helper.SetCgroupsV2(true)
+	resp = &runtimeapi.ContainerResourceHookResponse{}
+	c.ProxyDone(resp)
+	assert.Equal(t, map[string]string{
+		sysutil.MemoryMinName: "1048576",
+		sysutil.MemoryLowName: "2097152",
+	}, resp.ContainerResources.Unified)
+}
+
 func TestPodResponse_ProxyDone(t *testing.T) {
 	type fields struct {
 		Resources Resources
@@ -279,6 +279,7 @@ func (c *reconciler) reconcileKubeQOSCgroup(stopCh <-chan struct{}) {
 			timer.Reset(c.reconcileInterval)
 		case <-stopCh:
 			klog.V(1).Infof("stop reconcile kube qos cgroup")
+			return
 		}
 	}
 }
@@ -331,6 +332,8 @@ func (c *reconciler) reconcilePodCgroup(stopCh <-chan struct{}) {
 					}
 				}
 
+				// the cpuset of the sandbox and containers are updated in a transaction
+				var cpusetUpdaters []resourceexecutor.ResourceUpdater
 				for _, r := range globalCgroupReconcilers.sandboxContainerLevel {
 					reconcileFn, ok := r.fn[r.filter.Filter(podMeta)]
 					if !ok {
@@ -342,7 +345,8 @@ func (c *reconciler) reconcilePodCgroup(stopCh <-chan struct{}) {
 					if err := reconcileFn(sandboxContainerCtx); err != nil {
 						klog.Warningf("calling reconcile function %v failed for sandbox, error %v", r.description, err)
 					} else {
-						sandboxContainerCtx.ReconcilerDone(c.executor)
+						sandboxContainerCtx.ReconcilerProcess(c.executor)
+						cpusetUpdaters = append(cpusetUpdaters, updateExceptCPUSet(c.executor, sandboxContainerCtx.GetUpdaters())...)
 						klog.V(5).Infof("calling reconcile function %v for pod sandbox %v finished",
 							r.description, util.GetPodKey(podMeta.Pod))
 					}
@@ -361,12 +365,18 @@ func (c *reconciler) reconcilePodCgroup(stopCh <-chan struct{}) {
 						if err := reconcileFn(containerCtx); err != nil {
 							klog.Warningf("calling reconcile function %v failed, error %v", r.description, err)
 						} else {
-							containerCtx.ReconcilerDone(c.executor)
+							containerCtx.ReconcilerProcess(c.executor)
+							cpusetUpdaters = append(cpusetUpdaters, updateExceptCPUSet(c.executor, containerCtx.GetUpdaters())...)
 							klog.V(5).Infof("calling reconcile function %v for container %v/%v finish",
 								r.description, util.GetPodKey(podMeta.Pod), containerStat.Name)
 						}
 					}
 				}
+				if len(cpusetUpdaters) > 0 {
+					if err := c.executor.UpdateTransaction([][]resourceexecutor.ResourceUpdater{cpusetUpdaters}); err != nil {
+						klog.Warningf("failed to update cpuset for pod %v, error %v", util.GetPodKey(podMeta.Pod), err)
+					}
+				}
 			}
 		case <-stopCh:
 			klog.V(1).Infof("stop reconcile pod cgroup")
@@ -374,3 +384,18 @@ func (c *reconciler) reconcilePodCgroup(stopCh <-chan struct{}) {
 		}
 	}
 }
+
+// updateExceptCPUSet updates the resources except the cpuset.cpus, and returns the cpuset updaters, which are updated
+// in a transaction for all containers of the pod to avoid the inconsistent cpuset after a partial failure.
+func updateExceptCPUSet(e resourceexecutor.ResourceUpdateExecutor, updaters []resourceexecutor.ResourceUpdater) []resourceexecutor.ResourceUpdater {
+	var cpusetUpdaters, otherUpdaters []resourceexecutor.ResourceUpdater
+	for _, updater := range updaters {
+		if updater.ResourceType() == system.CPUSetCPUSName {
+			cpusetUpdaters = append(cpusetUpdaters, updater)
+		} else {
+			otherUpdaters = append(otherUpdaters, updater)
+		}
+	}
+	e.UpdateBatch(true, otherUpdaters...)
+	return cpusetUpdaters
+}
@@ -19,6 +19,7 @@ package reconciler
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -90,6 +91,25 @@ func Test_doKubeQOSCgroup(t *testing.T) {
 	}
 }
 
+func Test_reconciler_reconcileKubeQOSCgroup(t *testing.T) {
+	c := &reconciler{
+		executor:          resourceexecutor.NewTestResourceExecutor(),
+		reconcileInterval: time.Hour,
+	}
+	stopCh := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		c.reconcileKubeQOSCgroup(stopCh)
+		close(exited)
+	}()
+	close(stopCh)
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconcile kube qos cgroup is not exited after stopped")
+	}
+}
+
 func Test_reconciler_reconcilePodCgroup(t *testing.T) {
 	stopCh := make(chan struct{}, 1)
 	tryStopFn := func() {
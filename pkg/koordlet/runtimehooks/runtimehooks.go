@@ -123,7 +123,8 @@ func NewRuntimeHook(si statesinformer.StatesInformer, cfg *Config) (RuntimeHook,
 	}
 
 	newPluginOptions := hooks.Options{
-		Executor: e,
+		Executor:       e,
+		StatesInformer: si,
 	}
 
 	if err != nil {
@@ -27,12 +27,21 @@ import (
 type PodMeta struct {
 	Pod       *corev1.Pod
 	CgroupDir string
+	// ContainerAllocations is the resources assigned by the kubelet to the containers, keyed by the container name.
+	// It is only set when the kubelet pod-resources API is enabled.
+	ContainerAllocations map[string]*ContainerAllocation
 }
 
 func (in *PodMeta) DeepCopy() *PodMeta {
 	out := new(PodMeta)
 	out.Pod = in.Pod.DeepCopy()
 	out.CgroupDir = in.CgroupDir
+	if in.ContainerAllocations != nil {
+		out.ContainerAllocations = make(map[string]*ContainerAllocation, len(in.ContainerAllocations))
+		for name, allocation := range in.ContainerAllocations {
+			out.ContainerAllocations[name] = allocation.DeepCopy()
+		}
+	}
 	return out
 }
 
@@ -51,6 +60,31 @@ func (in *PodMeta) IsRunningOrPending() bool {
 	return phase == corev1.PodRunning || phase == corev1.PodPending
 }
 
+// ContainerAllocation is the resources assigned to a container by the kubelet resource managers, e.g. the exclusive
+// cpus of the static cpu manager policy and the devices allocated by the device plugins.
+type ContainerAllocation struct {
+	// CPUSet is the exclusive cpus assigned to the container, e.g. "0-3". It is empty if the container uses the shared pool.
+	CPUSet string
+	// Devices is the device ids allocated to the container, keyed by the resource name.
+	Devices map[corev1.ResourceName][]string
+}
+
+func (in *ContainerAllocation) DeepCopy() *ContainerAllocation {
+	if in == nil {
+		return nil
+	}
+	out := &ContainerAllocation{
+		CPUSet: in.CPUSet,
+	}
+	if in.Devices != nil {
+		out.Devices = make(map[corev1.ResourceName][]string, len(in.Devices))
+		for resourceName, ids := range in.Devices {
+			out.Devices[resourceName] = append([]string{}, ids...)
+		}
+	}
+	return out
+}
+
 type RegisterType int64
 
 const (
@@ -23,6 +23,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+const (
+	// PodSourceKubelet lists pods from the kubelet /pods endpoint.
+	PodSourceKubelet = "kubelet"
+	// PodSourceCRI lists pods from the container runtime via the CRI ListPodSandbox and ListContainers.
+	PodSourceCRI = "cri"
+	// PodSourceAuto lists pods from the kubelet, and falls back to the container runtime when the kubelet is unhealthy.
+	PodSourceAuto = "auto"
+)
+
 type Config struct {
 	KubeletPreferredAddressType string
 	KubeletSyncInterval         time.Duration
@@ -33,6 +42,9 @@ type Config struct {
 	DisableQueryKubeletConfig   bool
 	EnableNodeMetricReport      bool
 	MetricReportInterval        time.Duration // Deprecated
+	PodSourceMode               string
+	CRIRuntimeEndpoint          string
+	KubeletPodResourcesEndpoint string
 }
 
 func NewDefaultConfig() *Config {
@@ -45,6 +57,9 @@ func NewDefaultConfig() *Config {
 		NodeTopologySyncInterval:    3 * time.Second,
 		DisableQueryKubeletConfig:   false,
 		EnableNodeMetricReport:      true,
+		PodSourceMode:               PodSourceKubelet,
+		CRIRuntimeEndpoint:          "",
+		KubeletPodResourcesEndpoint: "",
 	}
 }
 
@@ -58,4 +73,7 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.DisableQueryKubeletConfig, "disable-query-kubelet-config", c.DisableQueryKubeletConfig, "Disables querying the kubelet configuration from kubelet. Flag must be set to true if kubelet-insecure-tls=true is configured")
 	fs.DurationVar(&c.MetricReportInterval, "report-interval", c.MetricReportInterval, "Deprecated since v1.1, use ColocationStrategy.MetricReportIntervalSeconds in config map of slo-controller")
 	fs.BoolVar(&c.EnableNodeMetricReport, "enable-node-metric-report", c.EnableNodeMetricReport, "Enable status update of node metric crd.")
+	fs.StringVar(&c.PodSourceMode, "pod-source-mode", c.PodSourceMode, "The source which Koordlet lists pods from. Options: kubelet, cri, auto. The auto mode lists pods from kubelet and falls back to the container runtime when kubelet is unhealthy.")
+	fs.StringVar(&c.CRIRuntimeEndpoint, "cri-runtime-endpoint", c.CRIRuntimeEndpoint, "The CRI endpoint of the container runtime to list pods from, e.g. unix:///var/run/containerd/containerd.sock. The containerd endpoint is detected if not specified.")
+	fs.StringVar(&c.KubeletPodResourcesEndpoint, "kubelet-pod-resources-endpoint", c.KubeletPodResourcesEndpoint, "The endpoint of the kubelet pod-resources API to get the cpuset and device assignments of containers, e.g. unix:///var/lib/kubelet/pod-resources/kubelet.sock. Disabled if not specified.")
 }
@@ -42,6 +42,7 @@ func TestNewDefaultConfig(t *testing.T) {
 				DisableQueryKubeletConfig:   false,
 				EnableNodeMetricReport:      true,
 				MetricReportInterval:        0,
+				PodSourceMode:               PodSourceKubelet,
 			},
 		},
 	}
@@ -64,6 +65,9 @@ func TestConfig_InitFlags(t *testing.T) {
 		"--node-topology-sync-interval=10s",
 		"--disable-query-kubelet-config=true",
 		"--enable-node-metric-report=false",
+		"--pod-source-mode=auto",
+		"--cri-runtime-endpoint=unix:///var/run/containerd/containerd.sock",
+		"--kubelet-pod-resources-endpoint=unix:///var/lib/kubelet/pod-resources/kubelet.sock",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -76,6 +80,9 @@ func TestConfig_InitFlags(t *testing.T) {
 		NodeTopologySyncInterval    time.Duration
 		DisableQueryKubeletConfig   bool
 		EnableNodeMetricReport      bool
+		PodSourceMode               string
+		CRIRuntimeEndpoint          string
+		KubeletPodResourcesEndpoint string
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -96,6 +103,9 @@ func TestConfig_InitFlags(t *testing.T) {
 				NodeTopologySyncInterval:    10 * time.Second,
 				DisableQueryKubeletConfig:   true,
 				EnableNodeMetricReport:      false,
+				PodSourceMode:               PodSourceAuto,
+				CRIRuntimeEndpoint:          "unix:///var/run/containerd/containerd.sock",
+				KubeletPodResourcesEndpoint: "unix:///var/lib/kubelet/pod-resources/kubelet.sock",
 			},
 			args: args{fs: fs},
 		},
@@ -111,6 +121,9 @@ func TestConfig_InitFlags(t *testing.T) {
 				NodeTopologySyncInterval:    tt.fields.NodeTopologySyncInterval,
 				DisableQueryKubeletConfig:   tt.fields.DisableQueryKubeletConfig,
 				EnableNodeMetricReport:      tt.fields.EnableNodeMetricReport,
+				PodSourceMode:               tt.fields.PodSourceMode,
+				CRIRuntimeEndpoint:          tt.fields.CRIRuntimeEndpoint,
+				KubeletPodResourcesEndpoint: tt.fields.KubeletPodResourcesEndpoint,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
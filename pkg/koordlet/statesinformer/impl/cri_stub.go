@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubeletutil "k8s.io/kubernetes/pkg/kubelet/util"
+
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	// kubeletLabelPrefix is the prefix of the labels which kubelet adds to the sandboxes and containers.
+	kubeletLabelPrefix = "io.kubernetes."
+)
+
+// CRIStub lists pods from the container runtime. It is used as a pod source when the kubelet is unavailable.
+// The pods are rebuilt from the sandboxes and containers, so only the metadata and the status are filled, while the
+// container resources in the spec are unknown.
+type CRIStub interface {
+	GetAllPods() (corev1.PodList, error)
+}
+
+type criStub struct {
+	runtimeName   string
+	runtimeClient runtimeapi.RuntimeServiceClient
+	timeout       time.Duration
+}
+
+func NewCRIStub(endpoint string, timeout time.Duration) (CRIStub, error) {
+	addr, dialer, err := kubeletutil.GetAddressAndDialer(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithContextDialer(dialer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect cri endpoint %s, err: %v", endpoint, err)
+	}
+	runtimeClient := runtimeapi.NewRuntimeServiceClient(conn)
+
+	versionCtx, versionCancel := context.WithTimeout(context.Background(), timeout)
+	defer versionCancel()
+	version, err := runtimeClient.Version(versionCtx, &runtimeapi.VersionRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runtime version of cri endpoint %s, err: %v", endpoint, err)
+	}
+
+	return &criStub{
+		runtimeName:   version.GetRuntimeName(),
+		runtimeClient: runtimeClient,
+		timeout:       timeout,
+	}, nil
+}
+
+func (c *criStub) GetAllPods() (corev1.PodList, error) {
+	podList := corev1.PodList{}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	sandboxRsp, err := c.runtimeClient.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{})
+	if err != nil {
+		return podList, fmt.Errorf("list pod sandboxes failed, err: %v", err)
+	}
+	containerRsp, err := c.runtimeClient.ListContainers(ctx, &runtimeapi.ListContainersRequest{})
+	if err != nil {
+		return podList, fmt.Errorf("list containers failed, err: %v", err)
+	}
+
+	podList.Items = buildPodsFromRuntime(c.runtimeName, sandboxRsp.GetItems(), containerRsp.GetContainers())
+	return podList, nil
+}
+
+// newCRIStubFromConfig creates the cri stub with the configured endpoint, or the detected containerd endpoint.
+func newCRIStubFromConfig(cfg *Config) (CRIStub, error) {
+	endpoint := cfg.CRIRuntimeEndpoint
+	if endpoint == "" {
+		for _, ep := range []string{handler.GetContainerdEndpoint(), handler.GetContainerdEndpoint2()} {
+			if system.FileExists(ep) {
+				endpoint = fmt.Sprintf("unix://%s", ep)
+				break
+			}
+		}
+	}
+	if endpoint == "" {
+		return nil, fmt.Errorf("cri endpoint is not specified and containerd endpoint does not exist")
+	}
+	return NewCRIStub(endpoint, cfg.KubeletSyncTimeout)
+}
+
+// buildPodsFromRuntime rebuilds the pods from the sandboxes and containers. If a pod has multiple sandboxes, the
+// latest one is used. If a container has been restarted, the latest attempt is used.
+func buildPodsFromRuntime(runtimeName string, sandboxes []*runtimeapi.PodSandbox, containers []*runtimeapi.Container) []corev1.Pod {
+	latestSandboxes := map[string]*runtimeapi.PodSandbox{}
+	for _, sandbox := range sandboxes {
+		if sandbox == nil || sandbox.Metadata == nil || sandbox.Metadata.Uid == "" {
+			continue
+		}
+		last, ok := latestSandboxes[sandbox.Metadata.Uid]
+		if !ok || last.CreatedAt < sandbox.CreatedAt {
+			latestSandboxes[sandbox.Metadata.Uid] = sandbox
+		}
+	}
+
+	sandboxContainers := map[string]map[string]*runtimeapi.Container{}
+	for _, container := range containers {
+		if container == nil || container.Metadata == nil {
+			continue
+		}
+		podContainers, ok := sandboxContainers[container.PodSandboxId]
+		if !ok {
+			podContainers = map[string]*runtimeapi.Container{}
+			sandboxContainers[container.PodSandboxId] = podContainers
+		}
+		last, ok := podContainers[container.Metadata.Name]
+		if !ok || last.CreatedAt < container.CreatedAt {
+			podContainers[container.Metadata.Name] = container
+		}
+	}
+
+	pods := make([]corev1.Pod, 0, len(latestSandboxes))
+	for _, sandbox := range latestSandboxes {
+		pods = append(pods, buildPodFromRuntime(runtimeName, sandbox, sandboxContainers[sandbox.Id]))
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].UID < pods[j].UID
+	})
+	return pods
+}
+
+func buildPodFromRuntime(runtimeName string, sandbox *runtimeapi.PodSandbox, containers map[string]*runtimeapi.Container) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              sandbox.Metadata.Name,
+			Namespace:         sandbox.Metadata.Namespace,
+			UID:               types.UID(sandbox.Metadata.Uid),
+			Labels:            map[string]string{},
+			Annotations:       map[string]string{},
+			CreationTimestamp: metav1.NewTime(time.Unix(0, sandbox.CreatedAt)),
+		},
+	}
+	for k, v := range sandbox.Labels {
+		if !strings.HasPrefix(k, kubeletLabelPrefix) {
+			pod.Labels[k] = v
+		}
+	}
+	for k, v := range sandbox.Annotations {
+		pod.Annotations[k] = v
+	}
+
+	containerNames := make([]string, 0, len(containers))
+	for name := range containers {
+		containerNames = append(containerNames, name)
+	}
+	sort.Strings(containerNames)
+
+	hasRunning := false
+	for _, name := range containerNames {
+		container := containers[name]
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+			Name:  name,
+			Image: container.GetImage().GetImage(),
+		})
+		containerStatus := corev1.ContainerStatus{
+			Name:        name,
+			Image:       container.GetImage().GetImage(),
+			ImageID:     container.ImageRef,
+			ContainerID: fmt.Sprintf("%s://%s", runtimeName, container.Id),
+		}
+		switch container.State {
+		case runtimeapi.ContainerState_CONTAINER_RUNNING:
+			hasRunning = true
+			containerStatus.Ready = true
+			containerStatus.State.Running = &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Unix(0, container.CreatedAt))}
+		case runtimeapi.ContainerState_CONTAINER_EXITED:
+			containerStatus.State.Terminated = &corev1.ContainerStateTerminated{ContainerID: containerStatus.ContainerID}
+		default:
+			containerStatus.State.Waiting = &corev1.ContainerStateWaiting{}
+		}
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, containerStatus)
+	}
+
+	switch {
+	case sandbox.State != runtimeapi.PodSandboxState_SANDBOX_READY:
+		// the exit codes are not listed, so the terminated pods cannot be told whether succeeded or failed
+		pod.Status.Phase = corev1.PodUnknown
+	case hasRunning:
+		pod.Status.Phase = corev1.PodRunning
+	default:
+		pod.Status.Phase = corev1.PodPending
+	}
+	pod.Status.QOSClass = probePodQOSClass(pod.UID)
+	return pod
+}
+
+// probePodQOSClass finds the kube qos class of the pod by the existence of the pod cgroup dir, since the container
+// resources are not listed by the container runtime.
+func probePodQOSClass(podUID types.UID) corev1.PodQOSClass {
+	procsResource, err := system.GetCgroupResource(system.CPUProcsName)
+	if err != nil {
+		return ""
+	}
+	for _, qosClass := range []corev1.PodQOSClass{corev1.PodQOSGuaranteed, corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
+		podCgroupDir := koordletutil.GetPodCgroupParentDir(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{UID: podUID},
+			Status:     corev1.PodStatus{QOSClass: qosClass},
+		})
+		if system.FileExists(procsResource.Path(podCgroupDir)) {
+			return qosClass
+		}
+	}
+	return ""
+}
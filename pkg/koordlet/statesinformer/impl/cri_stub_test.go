@@ -0,0 +1,220 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler/mockclient"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_criStub_GetAllPods(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(false)
+	helper.WriteCgroupFileContents("kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod123_456.slice", system.CPUProcs, "")
+
+	testingSandboxes := []*runtimeapi.PodSandbox{
+		{
+			Id:        "s0",
+			Metadata:  &runtimeapi.PodSandboxMetadata{Name: "test-pod", Namespace: "default", Uid: "123-456"},
+			State:     runtimeapi.PodSandboxState_SANDBOX_NOTREADY,
+			CreatedAt: 1000,
+		},
+		{
+			Id:        "s1",
+			Metadata:  &runtimeapi.PodSandboxMetadata{Name: "test-pod", Namespace: "default", Uid: "123-456", Attempt: 1},
+			State:     runtimeapi.PodSandboxState_SANDBOX_READY,
+			CreatedAt: 2000,
+			Labels: map[string]string{
+				"io.kubernetes.pod.name":  "test-pod",
+				"koordinator.sh/qosClass": "LS",
+			},
+			Annotations: map[string]string{
+				"test-annotation": "test",
+			},
+		},
+		{
+			Id:        "s2",
+			Metadata:  &runtimeapi.PodSandboxMetadata{Name: "test-pod-1", Namespace: "default", Uid: "789"},
+			State:     runtimeapi.PodSandboxState_SANDBOX_READY,
+			CreatedAt: 3000,
+		},
+	}
+	testingContainers := []*runtimeapi.Container{
+		{
+			Id:           "c0",
+			PodSandboxId: "s1",
+			Metadata:     &runtimeapi.ContainerMetadata{Name: "main"},
+			Image:        &runtimeapi.ImageSpec{Image: "nginx"},
+			State:        runtimeapi.ContainerState_CONTAINER_EXITED,
+			CreatedAt:    2000,
+		},
+		{
+			Id:           "c1",
+			PodSandboxId: "s1",
+			Metadata:     &runtimeapi.ContainerMetadata{Name: "main", Attempt: 1},
+			Image:        &runtimeapi.ImageSpec{Image: "nginx"},
+			State:        runtimeapi.ContainerState_CONTAINER_RUNNING,
+			CreatedAt:    2500,
+		},
+		{
+			Id:           "c2",
+			PodSandboxId: "s2",
+			Metadata:     &runtimeapi.ContainerMetadata{Name: "main"},
+			Image:        &runtimeapi.ImageSpec{Image: "busybox"},
+			State:        runtimeapi.ContainerState_CONTAINER_CREATED,
+			CreatedAt:    3000,
+		},
+	}
+	expectedPods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+				UID:       "123-456",
+				Labels: map[string]string{
+					"koordinator.sh/qosClass": "LS",
+				},
+				Annotations: map[string]string{
+					"test-annotation": "test",
+				},
+				CreationTimestamp: metav1.NewTime(time.Unix(0, 2000)),
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "nginx"}},
+			},
+			Status: corev1.PodStatus{
+				Phase:    corev1.PodRunning,
+				QOSClass: corev1.PodQOSBurstable,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "main",
+						Image:       "nginx",
+						ContainerID: "containerd://c1",
+						Ready:       true,
+						State: corev1.ContainerState{
+							Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Unix(0, 2500))},
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-pod-1",
+				Namespace:         "default",
+				UID:               "789",
+				Labels:            map[string]string{},
+				Annotations:       map[string]string{},
+				CreationTimestamp: metav1.NewTime(time.Unix(0, 3000)),
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "busybox"}},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "main",
+						Image:       "busybox",
+						ContainerID: "containerd://c2",
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mockclient.NewMockRuntimeServiceClient(ctrl)
+	c := &criStub{
+		runtimeName:   "containerd",
+		runtimeClient: client,
+		timeout:       time.Second,
+	}
+
+	client.EXPECT().ListPodSandbox(gomock.Any(), gomock.Any()).Return(&runtimeapi.ListPodSandboxResponse{Items: testingSandboxes}, nil)
+	client.EXPECT().ListContainers(gomock.Any(), gomock.Any()).Return(&runtimeapi.ListContainersResponse{Containers: testingContainers}, nil)
+	got, err := c.GetAllPods()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPods, got.Items)
+
+	client.EXPECT().ListPodSandbox(gomock.Any(), gomock.Any()).Return(nil, errors.New("test error"))
+	_, err = c.GetAllPods()
+	assert.Error(t, err)
+}
+
+func Test_buildPodFromRuntime_Phase(t *testing.T) {
+	tests := []struct {
+		name       string
+		sandbox    *runtimeapi.PodSandbox
+		containers map[string]*runtimeapi.Container
+		want       corev1.PodPhase
+	}{
+		{
+			name: "sandbox not ready",
+			sandbox: &runtimeapi.PodSandbox{
+				Metadata: &runtimeapi.PodSandboxMetadata{Uid: "123"},
+				State:    runtimeapi.PodSandboxState_SANDBOX_NOTREADY,
+			},
+			containers: map[string]*runtimeapi.Container{
+				"main": {Id: "c0", Metadata: &runtimeapi.ContainerMetadata{Name: "main"}, State: runtimeapi.ContainerState_CONTAINER_EXITED},
+			},
+			want: corev1.PodUnknown,
+		},
+		{
+			name: "sandbox ready without running container",
+			sandbox: &runtimeapi.PodSandbox{
+				Metadata: &runtimeapi.PodSandboxMetadata{Uid: "123"},
+				State:    runtimeapi.PodSandboxState_SANDBOX_READY,
+			},
+			want: corev1.PodPending,
+		},
+		{
+			name: "sandbox ready with running container",
+			sandbox: &runtimeapi.PodSandbox{
+				Metadata: &runtimeapi.PodSandboxMetadata{Uid: "123"},
+				State:    runtimeapi.PodSandboxState_SANDBOX_READY,
+			},
+			containers: map[string]*runtimeapi.Container{
+				"main": {Id: "c0", Metadata: &runtimeapi.ContainerMetadata{Name: "main"}, State: runtimeapi.ContainerState_CONTAINER_RUNNING},
+			},
+			want: corev1.PodRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			got := buildPodFromRuntime("containerd", tt.sandbox, tt.containers)
+			assert.Equal(t, tt.want, got.Status.Phase)
+		})
+	}
+}
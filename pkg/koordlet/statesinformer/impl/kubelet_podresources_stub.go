@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
+	"k8s.io/kubernetes/pkg/kubelet/apis/podresources"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	// defaultPodResourcesMaxSize is the max message size of the pod-resources API, same as the kubelet.
+	defaultPodResourcesMaxSize = 1024 * 1024 * 16
+)
+
+// PodResourcesStub lists the resources assigned to the pods via the kubelet pod-resources API.
+type PodResourcesStub interface {
+	ListPodResources() ([]*podresourcesapi.PodResources, error)
+}
+
+type podResourcesStub struct {
+	client  podresourcesapi.PodResourcesListerClient
+	timeout time.Duration
+}
+
+func NewPodResourcesStub(endpoint string, timeout time.Duration) (PodResourcesStub, error) {
+	client, _, err := podresources.GetV1Client(endpoint, timeout, defaultPodResourcesMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	return &podResourcesStub{
+		client:  client,
+		timeout: timeout,
+	}, nil
+}
+
+func (p *podResourcesStub) ListPodResources() ([]*podresourcesapi.PodResources, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	rsp, err := p.client.List(ctx, &podresourcesapi.ListPodResourcesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.GetPodResources(), nil
+}
+
+// parsePodAllocations converts the pod resources into the container allocations keyed by the pod key.
+func parsePodAllocations(podResources []*podresourcesapi.PodResources) map[string]map[string]*statesinformer.ContainerAllocation {
+	podAllocations := make(map[string]map[string]*statesinformer.ContainerAllocation, len(podResources))
+	for _, podResource := range podResources {
+		if podResource == nil {
+			continue
+		}
+		containerAllocations := make(map[string]*statesinformer.ContainerAllocation, len(podResource.Containers))
+		for _, container := range podResource.Containers {
+			if container == nil {
+				continue
+			}
+			allocation := &statesinformer.ContainerAllocation{}
+			if len(container.CpuIds) > 0 {
+				builder := cpuset.NewCPUSetBuilder()
+				for _, cpuID := range container.CpuIds {
+					builder.Add(int(cpuID))
+				}
+				allocation.CPUSet = builder.Result().String()
+			}
+			for _, device := range container.Devices {
+				if device == nil || len(device.DeviceIds) <= 0 {
+					continue
+				}
+				if allocation.Devices == nil {
+					allocation.Devices = map[corev1.ResourceName][]string{}
+				}
+				resourceName := corev1.ResourceName(device.ResourceName)
+				allocation.Devices[resourceName] = append(allocation.Devices[resourceName], device.DeviceIds...)
+			}
+			containerAllocations[container.Name] = allocation
+		}
+		podAllocations[util.GetNamespacedName(podResource.Namespace, podResource.Name)] = containerAllocations
+	}
+	return podAllocations
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+type fakePodResourcesListerClient struct {
+	podResources []*podresourcesapi.PodResources
+}
+
+func (f *fakePodResourcesListerClient) List(ctx context.Context, in *podresourcesapi.ListPodResourcesRequest, opts ...grpc.CallOption) (*podresourcesapi.ListPodResourcesResponse, error) {
+	return &podresourcesapi.ListPodResourcesResponse{PodResources: f.podResources}, nil
+}
+
+func (f *fakePodResourcesListerClient) GetAllocatableResources(ctx context.Context, in *podresourcesapi.AllocatableResourcesRequest, opts ...grpc.CallOption) (*podresourcesapi.AllocatableResourcesResponse, error) {
+	return &podresourcesapi.AllocatableResourcesResponse{}, nil
+}
+
+func Test_podResourcesStub_ListPodResources(t *testing.T) {
+	testingPodResources := []*podresourcesapi.PodResources{
+		{
+			Name:      "test-pod",
+			Namespace: "default",
+			Containers: []*podresourcesapi.ContainerResources{
+				{
+					Name:   "main",
+					CpuIds: []int64{3, 0, 1, 2},
+				},
+			},
+		},
+	}
+	p := &podResourcesStub{
+		client:  &fakePodResourcesListerClient{podResources: testingPodResources},
+		timeout: time.Second,
+	}
+	got, err := p.ListPodResources()
+	assert.NoError(t, err)
+	assert.Equal(t, testingPodResources, got)
+}
+
+func Test_parsePodAllocations(t *testing.T) {
+	tests := []struct {
+		name         string
+		podResources []*podresourcesapi.PodResources
+		want         map[string]map[string]*statesinformer.ContainerAllocation
+	}{
+		{
+			name: "empty pod resources",
+			want: map[string]map[string]*statesinformer.ContainerAllocation{},
+		},
+		{
+			name: "parse cpuset and devices",
+			podResources: []*podresourcesapi.PodResources{
+				nil,
+				{
+					Name:      "test-pod",
+					Namespace: "default",
+					Containers: []*podresourcesapi.ContainerResources{
+						{
+							Name:   "main",
+							CpuIds: []int64{3, 0, 1, 2, 8},
+							Devices: []*podresourcesapi.ContainerDevices{
+								{
+									ResourceName: "nvidia.com/gpu",
+									DeviceIds:    []string{"GPU-0"},
+								},
+								{
+									ResourceName: "nvidia.com/gpu",
+									DeviceIds:    []string{"GPU-1"},
+								},
+								{
+									ResourceName: "rdma/hca",
+								},
+							},
+						},
+						{
+							Name: "sidecar",
+						},
+					},
+				},
+			},
+			want: map[string]map[string]*statesinformer.ContainerAllocation{
+				"default/test-pod": {
+					"main": {
+						CPUSet: "0-3,8",
+						Devices: map[corev1.ResourceName][]string{
+							"nvidia.com/gpu": {"GPU-0", "GPU-1"},
+						},
+					},
+					"sidecar": {},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePodAllocations(tt.podResources)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -45,6 +45,7 @@ import (
 	clientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	clientsetv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1"
 	listerv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
@@ -97,6 +98,7 @@ type nodeMetricInformer struct {
 	statusUpdater      *statusUpdater
 
 	podsInformer     *podsInformer
+	nodeSLOInformer  *nodeSLOInformer
 	metricCache      metriccache.MetricCache
 	predictorFactory prediction.PredictorFactory
 
@@ -139,6 +141,12 @@ func (r *nodeMetricInformer) Setup(ctx *PluginOption, state *PluginState) {
 		klog.Fatalf("pods informer format error")
 	}
 	r.podsInformer = podsInformer
+	nodeSLOInformerIf := state.informerPlugins[nodeSLOInformerName]
+	nodeSLOInformer, ok := nodeSLOInformerIf.(*nodeSLOInformer)
+	if !ok {
+		klog.Fatalf("node slo informer format error")
+	}
+	r.nodeSLOInformer = nodeSLOInformer
 	r.predictorFactory = state.predictorFactory
 
 	r.nodeMetricInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -240,7 +248,7 @@ func (r *nodeMetricInformer) sync() {
 		return
 	}
 
-	nodeMetricInfo, podMetricInfo, prodReclaimableMetric := r.collectMetric()
+	nodeMetricInfo, podMetricInfo, hostAppMetricInfo, prodReclaimableMetric := r.collectMetric()
 	if nodeMetricInfo == nil {
 		klog.Warningf("node metric is not ready, skip this round.")
 		return
@@ -250,6 +258,7 @@ func (r *nodeMetricInformer) sync() {
 		UpdateTime:            &metav1.Time{Time: time.Now()},
 		NodeMetric:            nodeMetricInfo,
 		PodsMetric:            podMetricInfo,
+		HostApplicationMetric: hostAppMetricInfo,
 		ProdReclaimableMetric: prodReclaimableMetric,
 	}
 	retErr := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
@@ -321,7 +330,8 @@ func (r *nodeMetricInformer) generateQueryDuration() (start time.Time, end time.
 	return
 }
 
-func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*slov1alpha1.PodMetricInfo, *slov1alpha1.ReclaimableMetric) {
+func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*slov1alpha1.PodMetricInfo,
+	[]*slov1alpha1.HostApplicationMetricInfo, *slov1alpha1.ReclaimableMetric) {
 	spec := r.getNodeMetricSpec()
 	endTime := time.Now()
 	startTime := endTime.Add(-time.Duration(*spec.CollectPolicy.AggregateDurationSeconds) * time.Second)
@@ -366,8 +376,21 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		if len(gpus) > 0 {
 			r.fillGPUMetrics(podQueryParam, podMetric, string(podMeta.Pod.UID), gpus)
 		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.NetIOCollector) ||
+			features.DefaultKoordletFeatureGate.Enabled(features.DiskIOCollector) {
+			r.fillIOMetrics(podQueryParam, podMetric, string(podMeta.Pod.UID))
+		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.CPUBurst) {
+			r.fillCPUBurstBudget(podQueryParam, podMetric, string(podMeta.Pod.UID))
+		}
 		podsMetricInfo = append(podsMetricInfo, podMetric)
 	}
+
+	var hostAppMetricsInfo []*slov1alpha1.HostApplicationMetricInfo
+	if features.DefaultKoordletFeatureGate.Enabled(features.HostApplicationCollector) {
+		hostAppMetricsInfo = r.collectHostAppMetrics(podQueryParam)
+	}
+
 	prodReclaimable := &slov1alpha1.ReclaimableMetric{}
 	if p, err := prodPredictor.GetResult(); err != nil {
 		klog.Errorf("failed to get prediction, err %v", err)
@@ -379,7 +402,7 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		metrics.RecordNodeResourcePriorityReclaimable(string(corev1.ResourceMemory), metrics.UnitByte, string(apiext.PriorityProd), float64(p.Memory().Value()))
 	}
 
-	return nodeMetricInfo, podsMetricInfo, prodReclaimable
+	return nodeMetricInfo, podsMetricInfo, hostAppMetricsInfo, prodReclaimable
 }
 
 func (r *nodeMetricInformer) queryNodeMetric(start time.Time, end time.Time, aggregateType metriccache.AggregationType,
@@ -668,6 +691,71 @@ func (r *nodeMetricInformer) collectPodMetric(podMeta *statesinformer.PodMeta, q
 	return rtn, nil
 }
 
+// collectHostAppMetrics collects the metrics of the host applications declared in the NodeSLO.
+func (r *nodeMetricInformer) collectHostAppMetrics(queryParam metriccache.QueryParam) []*slov1alpha1.HostApplicationMetricInfo {
+	if r.nodeSLOInformer == nil {
+		return nil
+	}
+	nodeSLO := r.nodeSLOInformer.GetNodeSLO()
+	if nodeSLO == nil || len(nodeSLO.Spec.HostApplications) <= 0 {
+		return nil
+	}
+	querier, err := r.metricCache.Querier(*queryParam.Start, *queryParam.End)
+	if err != nil {
+		klog.V(4).Infof("get host application metric querier failed, error %v", err)
+		return nil
+	}
+	hostAppMetricsInfo := make([]*slov1alpha1.HostApplicationMetricInfo, 0, len(nodeSLO.Spec.HostApplications))
+	for i := range nodeSLO.Spec.HostApplications {
+		hostApp := &nodeSLO.Spec.HostApplications[i]
+		hostAppMetric, err := r.collectHostAppMetric(querier, hostApp, queryParam.Aggregate)
+		if err != nil {
+			klog.V(4).Infof("query host application metric failed, name %s, error %v", hostApp.Name, err)
+			continue
+		}
+		hostAppMetricsInfo = append(hostAppMetricsInfo, hostAppMetric)
+	}
+	return hostAppMetricsInfo
+}
+
+func (r *nodeMetricInformer) collectHostAppMetric(querier metriccache.Querier, hostApp *slov1alpha1.HostApplicationSpec,
+	aggregateType metriccache.AggregationType) (*slov1alpha1.HostApplicationMetricInfo, error) {
+	cpuAggregateResult, err := doQuery(querier, metriccache.HostAppCPUUsageMetric, metriccache.MetricPropertiesFunc.HostApplication(hostApp.Name))
+	if err != nil {
+		return nil, err
+	}
+	if cpuAggregateResult.Count() == 0 {
+		return nil, fmt.Errorf("no cpu usage collected for host application %s", hostApp.Name)
+	}
+	cpuUsed, err := cpuAggregateResult.Value(aggregateType)
+	if err != nil {
+		return nil, err
+	}
+	memAggregateResult, err := doQuery(querier, metriccache.HostAppMemoryUsageMetric, metriccache.MetricPropertiesFunc.HostApplication(hostApp.Name))
+	if err != nil {
+		return nil, err
+	}
+	if memAggregateResult.Count() == 0 {
+		return nil, fmt.Errorf("no memory usage collected for host application %s", hostApp.Name)
+	}
+	memUsed, err := memAggregateResult.Value(aggregateType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slov1alpha1.HostApplicationMetricInfo{
+		Name:     hostApp.Name,
+		Priority: hostApp.Priority,
+		QoS:      hostApp.QoS,
+		Usage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(int64(cpuUsed*1000), resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(int64(memUsed), resource.BinarySI),
+			},
+		},
+	}, nil
+}
+
 func (r *nodeMetricInformer) collectPodGPUMetric(queryparam metriccache.QueryParam, uid string, gpus koordletutil.GPUDevices) ([]schedulingv1alpha1.DeviceInfo, error) {
 	result := make([]schedulingv1alpha1.DeviceInfo, 0)
 	querier, err := r.metricCache.Querier(*queryparam.Start, *queryparam.End)
@@ -727,6 +815,83 @@ func (r *nodeMetricInformer) fillGPUMetrics(queryparam metriccache.QueryParam, i
 	info.PodUsage.Devices = podGPUMetrics
 }
 
+// fillIOMetrics fills the network and disk io throughput of the pod if the metrics are collected
+func (r *nodeMetricInformer) fillIOMetrics(queryParam metriccache.QueryParam, info *slov1alpha1.PodMetricInfo, uid string) {
+	querier, err := r.metricCache.Querier(*queryParam.Start, *queryParam.End)
+	if err != nil {
+		klog.V(5).Infof("get pod io metric querier failed, error %v", err)
+		return
+	}
+	for _, t := range []struct {
+		metric       metriccache.MetricResource
+		resourceName corev1.ResourceName
+		format       resource.Format
+	}{
+		{metric: metriccache.PodNetRxBytesMetric, resourceName: apiext.ResourceNetRxBandwidth, format: resource.BinarySI},
+		{metric: metriccache.PodNetTxBytesMetric, resourceName: apiext.ResourceNetTxBandwidth, format: resource.BinarySI},
+		{metric: metriccache.PodDiskReadBPSMetric, resourceName: apiext.ResourceDiskReadBandwidth, format: resource.BinarySI},
+		{metric: metriccache.PodDiskWriteBPSMetric, resourceName: apiext.ResourceDiskWriteBandwidth, format: resource.BinarySI},
+		{metric: metriccache.PodDiskReadIOPSMetric, resourceName: apiext.ResourceDiskReadIOPS, format: resource.DecimalSI},
+		{metric: metriccache.PodDiskWriteIOPSMetric, resourceName: apiext.ResourceDiskWriteIOPS, format: resource.DecimalSI},
+	} {
+		aggregateResult, err := doQuery(querier, t.metric, metriccache.MetricPropertiesFunc.Pod(uid))
+		if err != nil {
+			klog.V(5).Infof("query pod UID(%s) %s failed, error: %v", uid, t.resourceName, err)
+			continue
+		}
+		if aggregateResult.Count() == 0 {
+			continue
+		}
+		value, err := aggregateResult.Value(queryParam.Aggregate)
+		if err != nil {
+			klog.V(5).Infof("aggregate pod UID(%s) %s failed, error: %v", uid, t.resourceName, err)
+			continue
+		}
+		if info.PodUsage.ResourceList == nil {
+			info.PodUsage.ResourceList = corev1.ResourceList{}
+		}
+		info.PodUsage.ResourceList[t.resourceName] = *resource.NewQuantity(int64(value), t.format)
+	}
+}
+
+// fillCPUBurstBudget fills the latest cfs quota burst budget of the pod into the extensions if it is accounted
+func (r *nodeMetricInformer) fillCPUBurstBudget(queryParam metriccache.QueryParam, info *slov1alpha1.PodMetricInfo, uid string) {
+	querier, err := r.metricCache.Querier(*queryParam.Start, *queryParam.End)
+	if err != nil {
+		klog.V(5).Infof("get pod cpu burst metric querier failed, error %v", err)
+		return
+	}
+	var values [2]float64
+	for i, metric := range []metriccache.MetricResource{
+		metriccache.PodCPUBurstUsedSecondsMetric,
+		metriccache.PodCPUBurstAllowedSecondsMetric,
+	} {
+		aggregateResult, err := doQuery(querier, metric, metriccache.MetricPropertiesFunc.Pod(uid))
+		if err != nil {
+			klog.V(5).Infof("query pod UID(%s) cpu burst budget failed, error: %v", uid, err)
+			return
+		}
+		if aggregateResult.Count() == 0 {
+			return
+		}
+		values[i], err = aggregateResult.Value(metriccache.AggregationTypeLast)
+		if err != nil {
+			klog.V(5).Infof("aggregate pod UID(%s) cpu burst budget failed, error: %v", uid, err)
+			return
+		}
+	}
+	if info.Extensions == nil {
+		info.Extensions = &slov1alpha1.ExtensionsMap{}
+	}
+	if info.Extensions.Object == nil {
+		info.Extensions.Object = map[string]interface{}{}
+	}
+	info.Extensions.Object[slov1alpha1.PodMetricExtensionCPUBurstBudget] = slov1alpha1.PodCPUBurstBudget{
+		Used:    metav1.Duration{Duration: time.Duration(values[0] * float64(time.Second))},
+		Allowed: metav1.Duration{Duration: time.Duration(values[1] * float64(time.Second))},
+	}
+}
+
 const (
 	statusUpdateQPS   = 0.1
 	statusUpdateBurst = 2
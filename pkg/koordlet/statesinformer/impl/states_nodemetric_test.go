@@ -294,6 +294,10 @@ func Test_reporter_sync_with_single_node_metric(t *testing.T) {
 						metriccache.MetricPropertiesFunc.PodGPU("test-pod", "1", "2"))
 					assert.NoError(t, err)
 					buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, podGPU2Mem, 50, endTime.Sub(startTime))
+
+					podCPUBurstUsed, err := metriccache.PodCPUBurstUsedSecondsMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod("test-pod"))
+					assert.NoError(t, err)
+					buildMockEmptyQueryResult(ctrl, mockQuerier, mockResultFactory, podCPUBurstUsed)
 					return mockMetricCache
 				},
 				podsInformer: &podsInformer{
@@ -683,7 +687,8 @@ func Test_nodeMetricInformer_NewAndSetup(t *testing.T) {
 				state: &PluginState{
 					metricCache: mockmetriccache.NewMockMetricCache(ctrl),
 					informerPlugins: map[PluginName]informerPlugin{
-						podsInformerName: NewPodsInformer(),
+						podsInformerName:    NewPodsInformer(),
+						nodeSLOInformerName: NewNodeSLOInformer(),
 					},
 				},
 			},
@@ -1003,6 +1008,121 @@ func Test_nodeMetricInformer_collectPodMetric(t *testing.T) {
 	}
 }
 
+func Test_nodeMetricInformer_fillCPUBurstBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	startTime := now.Add(-time.Second * 120)
+	queryParam := metriccache.QueryParam{Start: &startTime, End: &now, Aggregate: metriccache.AggregationTypeAVG}
+
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+
+	usedQueryMeta, err := metriccache.PodCPUBurstUsedSecondsMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod("test-pod"))
+	assert.NoError(t, err)
+	buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, usedQueryMeta, 12.5, now.Sub(startTime))
+	allowedQueryMeta, err := metriccache.PodCPUBurstAllowedSecondsMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod("test-pod"))
+	assert.NoError(t, err)
+	buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, allowedQueryMeta, 60, now.Sub(startTime))
+
+	emptyUsedQueryMeta, err := metriccache.PodCPUBurstUsedSecondsMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod("test-pod-empty"))
+	assert.NoError(t, err)
+	buildMockEmptyQueryResult(ctrl, mockQuerier, mockResultFactory, emptyUsedQueryMeta)
+
+	r := &nodeMetricInformer{
+		metricCache: mockMetricCache,
+	}
+
+	// budget not accounted
+	info := &slov1alpha1.PodMetricInfo{Name: "test-pod-empty"}
+	r.fillCPUBurstBudget(queryParam, info, "test-pod-empty")
+	assert.Nil(t, info.Extensions)
+
+	info = &slov1alpha1.PodMetricInfo{Name: "test-pod"}
+	r.fillCPUBurstBudget(queryParam, info, "test-pod")
+	assert.NotNil(t, info.Extensions)
+	assert.Equal(t, slov1alpha1.PodCPUBurstBudget{
+		Used:    metav1.Duration{Duration: 12500 * time.Millisecond},
+		Allowed: metav1.Duration{Duration: time.Minute},
+	}, info.Extensions.Object[slov1alpha1.PodMetricExtensionCPUBurstBudget])
+}
+
+func Test_nodeMetricInformer_collectHostAppMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	startTime := now.Add(-time.Second * 120)
+	queryParam := metriccache.QueryParam{Start: &startTime, End: &now, Aggregate: metriccache.AggregationTypeAVG}
+
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).Times(1)
+
+	cpuQueryMeta, err := metriccache.HostAppCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication("nginx"))
+	assert.NoError(t, err)
+	buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, cpuQueryMeta, 1.5, now.Sub(startTime))
+	memQueryMeta, err := metriccache.HostAppMemoryUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication("nginx"))
+	assert.NoError(t, err)
+	buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, memQueryMeta, 1024*1024*1024, now.Sub(startTime))
+	emptyQueryMeta, err := metriccache.HostAppCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication("not-collected"))
+	assert.NoError(t, err)
+	buildMockEmptyQueryResult(ctrl, mockQuerier, mockResultFactory, emptyQueryMeta)
+	noMemCPUQueryMeta, err := metriccache.HostAppCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication("no-memory"))
+	assert.NoError(t, err)
+	buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, noMemCPUQueryMeta, 0.5, now.Sub(startTime))
+	noMemQueryMeta, err := metriccache.HostAppMemoryUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.HostApplication("no-memory"))
+	assert.NoError(t, err)
+	buildMockEmptyQueryResult(ctrl, mockQuerier, mockResultFactory, noMemQueryMeta)
+
+	r := &nodeMetricInformer{
+		metricCache: mockMetricCache,
+		nodeSLOInformer: &nodeSLOInformer{
+			nodeSLO: &slov1alpha1.NodeSLO{
+				Spec: slov1alpha1.NodeSLOSpec{
+					HostApplications: []slov1alpha1.HostApplicationSpec{
+						{
+							Name:     "nginx",
+							Priority: apiext.PriorityProd,
+							QoS:      apiext.QoSLS,
+						},
+						{
+							Name: "not-collected",
+							QoS:  apiext.QoSBE,
+						},
+						{
+							Name: "no-memory",
+							QoS:  apiext.QoSBE,
+						},
+					},
+				},
+			},
+		},
+	}
+	got := r.collectHostAppMetrics(queryParam)
+	assert.Equal(t, []*slov1alpha1.HostApplicationMetricInfo{
+		{
+			Name:     "nginx",
+			Priority: apiext.PriorityProd,
+			QoS:      apiext.QoSLS,
+			Usage: slov1alpha1.ResourceMap{
+				ResourceList: v1.ResourceList{
+					v1.ResourceCPU:    *resource.NewMilliQuantity(1500, resource.DecimalSI),
+					v1.ResourceMemory: *resource.NewQuantity(1024*1024*1024, resource.BinarySI),
+				},
+			},
+		},
+	}, got)
+
+	// no host application declared
+	r.nodeSLOInformer = &nodeSLOInformer{nodeSLO: &slov1alpha1.NodeSLO{}}
+	assert.Nil(t, r.collectHostAppMetrics(queryParam))
+}
+
 func buildMockQueryResult(ctrl *gomock.Controller, querier *mockmetriccache.MockQuerier, factory *mockmetriccache.MockAggregateResultFactory,
 	queryMeta metriccache.MetricMeta, value float64, duration time.Duration) {
 	result := mockmetriccache.NewMockAggregateResult(ctrl)
@@ -1013,6 +1133,14 @@ func buildMockQueryResult(ctrl *gomock.Controller, querier *mockmetriccache.Mock
 	querier.EXPECT().Query(queryMeta, gomock.Any(), result).SetArg(2, *result).Return(nil).AnyTimes()
 }
 
+func buildMockEmptyQueryResult(ctrl *gomock.Controller, querier *mockmetriccache.MockQuerier, factory *mockmetriccache.MockAggregateResultFactory,
+	queryMeta metriccache.MetricMeta) {
+	result := mockmetriccache.NewMockAggregateResult(ctrl)
+	result.EXPECT().Count().Return(0).AnyTimes()
+	factory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+	querier.EXPECT().Query(queryMeta, gomock.Any(), result).Return(nil).AnyTimes()
+}
+
 func Test_nodeMetricInformer_collectSystemAggregateMetric(t *testing.T) {
 	end := time.Now()
 	start := end.Add(-defaultAggregateDurationSeconds * time.Second)
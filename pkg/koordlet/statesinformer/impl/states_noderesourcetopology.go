@@ -329,17 +329,21 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 		}
 	}
 	// TODO: report lse/lsr pod from cgroup
-	var podAllocsJSON []byte
+	var podAllocs []extension.PodCPUAlloc
 	if len(data) > 0 {
-		podAllocs, err := s.calGuaranteedCpu(sharedPoolCPUs, string(data))
+		podAllocs, err = s.calGuaranteedCpu(sharedPoolCPUs, string(data))
 		if err != nil {
 			return nil, fmt.Errorf("failed to cal GuaranteedCpu, err: %v", err)
 		}
-		if len(podAllocs) != 0 {
-			podAllocsJSON, err = json.Marshal(podAllocs)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal pod allocs, err: %v", err)
-			}
+	} else if entries := getContainerCPUSetEntries(s.podsInformer.GetAllPods()); len(entries) > 0 {
+		// the state file is unavailable, use the cpus assigned by the kubelet from the pod-resources API
+		podAllocs = s.calGuaranteedCpuByEntries(sharedPoolCPUs, entries)
+	}
+	var podAllocsJSON []byte
+	if len(podAllocs) != 0 {
+		podAllocsJSON, err = json.Marshal(podAllocs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal pod allocs, err: %v", err)
 		}
 	}
 
@@ -460,7 +464,12 @@ func (s *nodeTopoInformer) calGuaranteedCpu(usedCPUs map[int32]*extension.CPUInf
 	if err != nil {
 		return nil, err
 	}
+	return s.calGuaranteedCpuByEntries(usedCPUs, checkpoint.Entries), nil
+}
 
+// calGuaranteedCpuByEntries calculates the cpus allocated by the kubelet to the pods not managed by koordinator,
+// where the entries are the cpusets of the containers keyed by the pod uid and the container name.
+func (s *nodeTopoInformer) calGuaranteedCpuByEntries(usedCPUs map[int32]*extension.CPUInfo, entries map[string]map[string]string) []extension.PodCPUAlloc {
 	pods := make(map[types.UID]*statesinformer.PodMeta)
 	managedPods := make(map[types.UID]struct{})
 	for _, podMeta := range s.podsInformer.GetAllPods() {
@@ -480,12 +489,12 @@ func (s *nodeTopoInformer) calGuaranteedCpu(usedCPUs map[int32]*extension.CPUInf
 	}
 
 	var podAllocs []extension.PodCPUAlloc
-	for podUID := range checkpoint.Entries {
+	for podUID := range entries {
 		if _, ok := managedPods[types.UID(podUID)]; ok {
 			continue
 		}
 		cpuSet := cpuset.NewCPUSet()
-		for container, cpuString := range checkpoint.Entries[podUID] {
+		for container, cpuString := range entries[podUID] {
 			if containerCPUSet, err := cpuset.Parse(cpuString); err != nil {
 				klog.Errorf("could not parse cpuset %q for container %q in pod %q: %v", cpuString, container, podUID, err)
 				continue
@@ -518,7 +527,26 @@ func (s *nodeTopoInformer) calGuaranteedCpu(usedCPUs map[int32]*extension.CPUInf
 	sort.Slice(podAllocs, func(i, j int) bool {
 		return string(podAllocs[i].UID) < string(podAllocs[j].UID)
 	})
-	return podAllocs, nil
+	return podAllocs
+}
+
+// getContainerCPUSetEntries returns the exclusive cpus assigned by the kubelet to the containers, keyed by the pod uid
+// and the container name like the entries of the cpu manager state file.
+func getContainerCPUSetEntries(podMetas []*statesinformer.PodMeta) map[string]map[string]string {
+	entries := map[string]map[string]string{}
+	for _, podMeta := range podMetas {
+		for containerName, allocation := range podMeta.ContainerAllocations {
+			if allocation == nil || allocation.CPUSet == "" {
+				continue
+			}
+			podUID := string(podMeta.Pod.UID)
+			if entries[podUID] == nil {
+				entries[podUID] = map[string]string{}
+			}
+			entries[podUID][containerName] = allocation.CPUSet
+		}
+	}
+	return entries
 }
 
 func (s *nodeTopoInformer) reportNodeTopology() {
@@ -306,6 +306,25 @@ func Test_calGuaranteedCpu(t *testing.T) {
 	}
 }
 
+func Test_getContainerCPUSetEntries(t *testing.T) {
+	podMetas := []*statesinformer.PodMeta{
+		{
+			Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "pod-1"}},
+			ContainerAllocations: map[string]*statesinformer.ContainerAllocation{
+				"main":    {CPUSet: "0-1"},
+				"sidecar": {},
+			},
+		},
+		{
+			Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "pod-2"}},
+		},
+	}
+	expected := map[string]map[string]string{
+		"pod-1": {"main": "0-1"},
+	}
+	assert.Equal(t, expected, getContainerCPUSetEntries(podMetas))
+}
+
 func Test_reportNodeTopology(t *testing.T) {
 	ctl := gomock.NewController(t)
 	defer ctl.Finish()
@@ -17,6 +17,8 @@ limitations under the License.
 package impl
 
 import (
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -39,6 +41,16 @@ import (
 
 const (
 	podsInformerName PluginName = "podsInformer"
+
+	// plegSyncRetryInterval is the initial interval to resync pods when the container created in pleg is not reported
+	// yet, since kubelet updates the pod status asynchronously.
+	plegSyncRetryInterval = 200 * time.Millisecond
+	// plegSyncMaxRetries is the max retries of resync for a container created in pleg. The sandbox containers are
+	// never reported in the pod status, so they are given up after the retries.
+	plegSyncMaxRetries = 4
+	// podsConsistencyCheckInterval is the min interval to list pods from the container runtime for the consistency
+	// check with the kubelet, since listing all containers can be expensive on the runtime.
+	podsConsistencyCheckInterval = time.Minute
 )
 
 type podsInformer struct {
@@ -51,10 +63,17 @@ type podsInformer struct {
 
 	// use pleg to accelerate the efficiency of Pod meta update
 	pleg       pleg.Pleg
-	podCreated chan string
+	plegEvents chan struct{}
+	// pendingContainers is the containers created in pleg but not reported in the pod status, with the retry times
+	pendingMutex      sync.Mutex
+	pendingContainers map[string]int
 
 	kubelet      KubeletStub
+	cri          CRIStub
+	podResources PodResourcesStub
 	nodeInformer *nodeInformer
+	// podsCheckedTime is the last time the pods from the kubelet and the container runtime are checked for consistency
+	podsCheckedTime time.Time
 
 	callbackRunner *callbackRunner
 }
@@ -69,7 +88,9 @@ func NewPodsInformer() *podsInformer {
 		podMap:       map[string]*statesinformer.PodMeta{},
 		podHasSynced: atomic.NewBool(false),
 		pleg:         p,
-		podCreated:   make(chan string, 1),
+		plegEvents:   make(chan struct{}, 1),
+
+		pendingContainers: map[string]int{},
 	}
 	return podsInformer
 }
@@ -95,21 +116,18 @@ func (s *podsInformer) Start(stopCh <-chan struct{}) {
 	if s.config.KubeletSyncInterval <= 0 {
 		return
 	}
-	stub, err := newKubeletStubFromConfig(s.nodeInformer.GetNode(), s.config)
-	if err != nil {
-		klog.Fatalf("create kubelet stub, %v", err)
+	if err := s.setupPodSources(); err != nil {
+		klog.Fatalf("setup pod sources failed, %v", err)
 	}
-	s.kubelet = stub
 	hdlID := s.pleg.AddHandler(pleg.PodLifeCycleHandlerFuncs{
 		PodAddedFunc: func(podID string) {
-			// There is no need to notify to update the data when the channel is not empty
-			if len(s.podCreated) == 0 {
-				s.podCreated <- podID
-				klog.V(5).Infof("new pod %v created, send event to sync pods", podID)
-			} else {
-				klog.V(5).Infof("new pod %v created, last event has not been consumed, no need to send event",
-					podID)
-			}
+			klog.V(5).Infof("new pod %v created, send event to sync pods", podID)
+			s.notifyPLEGEvent()
+		},
+		ContainerAddedFunc: func(podID, containerID string) {
+			klog.V(5).Infof("new container %v of pod %v created, send event to sync pods", containerID, podID)
+			s.addPendingContainer(containerID)
+			s.notifyPLEGEvent()
 		},
 	})
 	defer s.pleg.RemoverHandler(hdlID)
@@ -141,27 +159,59 @@ func (s *podsInformer) GetAllPods() []*statesinformer.PodMeta {
 	return pods
 }
 
+// setupPodSources creates the stubs of the pod sources according to the pod source mode.
+func (s *podsInformer) setupPodSources() error {
+	mode := s.config.PodSourceMode
+	switch mode {
+	case PodSourceKubelet, PodSourceAuto:
+		stub, err := newKubeletStubFromConfig(s.nodeInformer.GetNode(), s.config)
+		if err != nil {
+			return fmt.Errorf("create kubelet stub, %v", err)
+		}
+		s.kubelet = stub
+	case PodSourceCRI:
+	default:
+		return fmt.Errorf("unknown pod source mode %s", mode)
+	}
+
+	if mode == PodSourceCRI || mode == PodSourceAuto {
+		stub, err := newCRIStubFromConfig(s.config)
+		if err != nil && mode == PodSourceCRI {
+			return fmt.Errorf("create cri stub, %v", err)
+		} else if err != nil {
+			klog.Warningf("create cri stub failed, pods will not fall back to the container runtime, err: %v", err)
+		} else {
+			s.cri = stub
+		}
+	}
+	return nil
+}
+
 func (s *podsInformer) syncPods() error {
-	podList, err := s.kubelet.GetAllPods()
+	podList, err := s.getAllPods()
 
 	// when kubelet recovers from crash, podList may be empty.
 	if err != nil || len(podList.Items) == 0 {
-		klog.Warningf("get pods from kubelet failed, err: %v", err)
+		klog.Warningf("get pods failed, err: %v", err)
 		return err
 	}
+	podAllocations := s.getPodAllocations()
 	newPodMap := make(map[string]*statesinformer.PodMeta, len(podList.Items))
 	// reset pod container metrics
 	resetPodMetrics()
 	for _, pod := range podList.Items {
 		podMeta := &statesinformer.PodMeta{
-			Pod:       pod.DeepCopy(),
-			CgroupDir: genPodCgroupParentDir(&pod),
+			Pod:                  pod.DeepCopy(),
+			CgroupDir:            genPodCgroupParentDir(&pod),
+			ContainerAllocations: podAllocations[util.GetPodKey(&pod)],
 		}
 		newPodMap[string(pod.UID)] = podMeta
 		// record pod container metrics
 		recordPodResourceMetrics(podMeta)
 	}
+	s.podRWMutex.Lock()
 	s.podMap = newPodMap
+	s.podRWMutex.Unlock()
 	s.podHasSynced.Store(true)
 	s.podUpdatedTime = time.Now()
 	klog.Infof("get pods success, len %d, time %s", len(s.podMap), s.podUpdatedTime.String())
@@ -169,30 +219,123 @@ func (s *podsInformer) syncPods() error {
 	return nil
 }
 
+// getAllPods lists pods from the kubelet, and falls back to the container runtime when the kubelet is unhealthy if the
+// cri stub is set. When both sources are available, the pods of them are checked for consistency.
+func (s *podsInformer) getAllPods() (corev1.PodList, error) {
+	if s.kubelet == nil {
+		if s.cri == nil {
+			return corev1.PodList{}, fmt.Errorf("no pod source available")
+		}
+		return s.getAllPodsFromCRI()
+	}
+
+	podList, err := s.kubelet.GetAllPods()
+	metrics.RecordPodSourceSyncStatus(PodSourceKubelet, err)
+	if s.cri == nil {
+		return podList, err
+	}
+	if err != nil || len(podList.Items) == 0 {
+		klog.Warningf("get pods from kubelet failed, fall back to the container runtime, err: %v", err)
+		return s.getAllPodsFromCRI()
+	}
+	s.checkPodsConsistency(podList.Items)
+	return podList, nil
+}
+
+// getAllPodsFromCRI lists pods from the container runtime. Since the pods listed from the runtime have no container
+// resources, the known pods keep their last spec and only refresh the status.
+func (s *podsInformer) getAllPodsFromCRI() (corev1.PodList, error) {
+	podList, err := s.cri.GetAllPods()
+	metrics.RecordPodSourceSyncStatus(PodSourceCRI, err)
+	if err != nil {
+		return podList, err
+	}
+
+	s.podRWMutex.RLock()
+	defer s.podRWMutex.RUnlock()
+	for i := range podList.Items {
+		runtimePod := &podList.Items[i]
+		knownPodMeta, ok := s.podMap[string(runtimePod.UID)]
+		if !ok || knownPodMeta.Pod == nil {
+			continue
+		}
+		podList.Items[i] = *mergeRuntimePodStatus(knownPodMeta.Pod, runtimePod)
+	}
+	return podList, nil
+}
+
+// checkPodsConsistency compares the running pods from the kubelet with the ones from the container runtime, and
+// records the number of the pods running in only one of them.
+func (s *podsInformer) checkPodsConsistency(kubeletPods []corev1.Pod) {
+	if time.Since(s.podsCheckedTime) < podsConsistencyCheckInterval {
+		return
+	}
+	s.podsCheckedTime = time.Now()
+	runtimePodList, err := s.cri.GetAllPods()
+	metrics.RecordPodSourceSyncStatus(PodSourceCRI, err)
+	if err != nil {
+		klog.V(4).Infof("get pods from the container runtime failed, skip the consistency check, err: %v", err)
+		return
+	}
+	inconsistentPods := getInconsistentPods(kubeletPods, runtimePodList.Items)
+	if len(inconsistentPods) > 0 {
+		klog.V(4).Infof("running pods from kubelet and the container runtime are inconsistent, pods %v", inconsistentPods)
+	}
+	metrics.RecordPodSourceInconsistentPods(float64(len(inconsistentPods)))
+}
+
+// getPodAllocations gets the container allocations of the pods keyed by the pod key. The stub of the kubelet
+// pod-resources API is created on demand, so it is retried in the next sync if the creation fails.
+func (s *podsInformer) getPodAllocations() map[string]map[string]*statesinformer.ContainerAllocation {
+	if s.podResources == nil {
+		if s.config == nil || s.config.KubeletPodResourcesEndpoint == "" {
+			return nil
+		}
+		stub, err := NewPodResourcesStub(s.config.KubeletPodResourcesEndpoint, s.config.KubeletSyncTimeout)
+		if err != nil {
+			klog.Warningf("create kubelet pod resources stub failed, err: %v", err)
+			return nil
+		}
+		s.podResources = stub
+	}
+	podResources, err := s.podResources.ListPodResources()
+	if err != nil {
+		klog.Warningf("list pod resources from kubelet failed, err: %v", err)
+		return nil
+	}
+	return parsePodAllocations(podResources)
+}
+
 func (s *podsInformer) syncKubeletLoop(duration time.Duration, stopCh <-chan struct{}) {
 	timer := time.NewTimer(duration)
 	defer timer.Stop()
 	s.syncPods()
 	// TODO add a config to setup the values
 	rateLimiter := rate.NewLimiter(5, 10)
+	// whether a sync triggered by pleg events has been scheduled and not done
+	plegSyncScheduled := false
 	for {
 		select {
-		case <-s.podCreated:
-			if rateLimiter.Allow() {
-				// sync kubelet triggered immediately when the Pod is created
-				klog.V(4).Infof("new pod created, sync from kubelet immediately")
-				s.syncPods()
-				// reset timer to
-				if !timer.Stop() {
-					<-timer.C
-				}
-				timer.Reset(duration)
-			} else {
-				klog.V(4).Infof("new pod created, but sync rate limiter is not allowed")
+		case <-s.plegEvents:
+			// the scheduled sync will cover the later events
+			if plegSyncScheduled {
+				klog.V(5).Infof("pleg event received, sync pods has been scheduled")
+				continue
 			}
+			// sync pods triggered immediately when the pod or container is created, or delayed by the rate limiter
+			delay := rateLimiter.Reserve().Delay()
+			klog.V(4).Infof("pleg event received, sync pods after %v", delay)
+			resetTimer(timer, delay)
+			plegSyncScheduled = true
 		case <-timer.C:
-			timer.Reset(duration)
 			s.syncPods()
+			plegSyncScheduled = false
+			// resync quickly if some new containers are not reported yet, otherwise poll in the sync interval
+			if retries := s.checkPendingContainers(); retries > 0 {
+				timer.Reset(getPLEGSyncRetryInterval(retries, duration))
+			} else {
+				timer.Reset(duration)
+			}
 		case <-stopCh:
 			klog.Infof("sync kubelet loop is exited")
 			return
@@ -200,6 +343,87 @@ func (s *podsInformer) syncKubeletLoop(duration time.Duration, stopCh <-chan str
 	}
 }
 
+// notifyPLEGEvent notifies the sync loop to sync pods. There is no need to notify when the last event is not consumed.
+func (s *podsInformer) notifyPLEGEvent() {
+	select {
+	case s.plegEvents <- struct{}{}:
+	default:
+		klog.V(5).Infof("last pleg event has not been consumed, no need to send event")
+	}
+}
+
+func (s *podsInformer) addPendingContainer(containerID string) {
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+	if s.pendingContainers == nil {
+		s.pendingContainers = map[string]int{}
+	}
+	s.pendingContainers[containerID] = 0
+}
+
+// checkPendingContainers removes the pending containers which are reported in the pod status or run out of retries,
+// and returns the max retry times of the remaining ones.
+func (s *podsInformer) checkPendingContainers() int {
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+	if len(s.pendingContainers) <= 0 {
+		return 0
+	}
+
+	reportedContainers := map[string]struct{}{}
+	s.podRWMutex.RLock()
+	for _, podMeta := range s.podMap {
+		if podMeta.Pod == nil {
+			continue
+		}
+		for _, containerStatus := range podMeta.Pod.Status.ContainerStatuses {
+			if _, containerID, err := util.ParseContainerId(containerStatus.ContainerID); err == nil {
+				reportedContainers[containerID] = struct{}{}
+			}
+		}
+	}
+	s.podRWMutex.RUnlock()
+
+	maxRetries := 0
+	for containerID, retries := range s.pendingContainers {
+		if _, ok := reportedContainers[containerID]; ok {
+			delete(s.pendingContainers, containerID)
+			continue
+		}
+		retries++
+		if retries > plegSyncMaxRetries {
+			klog.V(5).Infof("container %v is not reported after %v retries, give up", containerID, plegSyncMaxRetries)
+			delete(s.pendingContainers, containerID)
+			continue
+		}
+		s.pendingContainers[containerID] = retries
+		if retries > maxRetries {
+			maxRetries = retries
+		}
+	}
+	return maxRetries
+}
+
+// getPLEGSyncRetryInterval returns the interval of the next resync with an exponential backoff, which is no longer
+// than the sync interval.
+func getPLEGSyncRetryInterval(retries int, syncInterval time.Duration) time.Duration {
+	interval := plegSyncRetryInterval << (retries - 1)
+	if interval > syncInterval {
+		return syncInterval
+	}
+	return interval
+}
+
+func resetTimer(timer *time.Timer, d time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(d)
+}
+
 func newKubeletStubFromConfig(node *corev1.Node, cfg *Config) (KubeletStub, error) {
 	var port int
 	var scheme string
@@ -235,6 +459,48 @@ func newKubeletStubFromConfig(node *corev1.Node, cfg *Config) (KubeletStub, erro
 	return NewKubeletStub(address, port, scheme, cfg.KubeletSyncTimeout, restConfig)
 }
 
+// mergeRuntimePodStatus refreshes the phase and the container states of the known pod with the pod listed from the
+// container runtime.
+func mergeRuntimePodStatus(knownPod *corev1.Pod, runtimePod *corev1.Pod) *corev1.Pod {
+	pod := knownPod.DeepCopy()
+	pod.Status.Phase = runtimePod.Status.Phase
+	runtimeStatuses := make(map[string]*corev1.ContainerStatus, len(runtimePod.Status.ContainerStatuses))
+	for i := range runtimePod.Status.ContainerStatuses {
+		runtimeStatuses[runtimePod.Status.ContainerStatuses[i].Name] = &runtimePod.Status.ContainerStatuses[i]
+	}
+	for i := range pod.Status.ContainerStatuses {
+		containerStatus := &pod.Status.ContainerStatuses[i]
+		runtimeStatus, ok := runtimeStatuses[containerStatus.Name]
+		if !ok {
+			continue
+		}
+		containerStatus.ContainerID = runtimeStatus.ContainerID
+		containerStatus.State = runtimeStatus.State
+		containerStatus.Ready = runtimeStatus.Ready
+	}
+	return pod
+}
+
+// getInconsistentPods returns the keys of the pods which are running in only one of the pod lists.
+func getInconsistentPods(kubeletPods, runtimePods []corev1.Pod) []string {
+	runningPods := map[string]int{}
+	for _, pods := range [][]corev1.Pod{kubeletPods, runtimePods} {
+		for i := range pods {
+			if pods[i].Status.Phase == corev1.PodRunning {
+				runningPods[util.GetPodKey(&pods[i])]++
+			}
+		}
+	}
+	var inconsistentPods []string
+	for podKey, count := range runningPods {
+		if count < 2 {
+			inconsistentPods = append(inconsistentPods, podKey)
+		}
+	}
+	sort.Strings(inconsistentPods)
+	return inconsistentPods
+}
+
 func genPodCgroupParentDir(pod *corev1.Pod) string {
 	// todo use cri interface to get pod cgroup dir
 	// e.g. kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod9dba1d9e_67ba_4db6_8a73_fb3ea297c363.slice/
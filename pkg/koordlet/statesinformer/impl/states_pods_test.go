@@ -32,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	podresourcesapi "k8s.io/kubelet/pkg/apis/podresources/v1"
 	kubeletconfiginternal "k8s.io/kubernetes/pkg/kubelet/apis/config"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
@@ -155,6 +156,255 @@ func Test_statesInformer_syncPods(t *testing.T) {
 	assert.Error(t, err)
 }
 
+type testCRIStub struct {
+	pods  corev1.PodList
+	err   error
+	calls int
+}
+
+func (t *testCRIStub) GetAllPods() (corev1.PodList, error) {
+	t.calls++
+	return t.pods, t.err
+}
+
+type testPodResourcesStub struct {
+	podResources []*podresourcesapi.PodResources
+}
+
+func (t *testPodResourcesStub) ListPodResources() ([]*podresourcesapi.PodResources, error) {
+	return t.podResources, nil
+}
+
+func Test_podsInformer_syncPodsWithAllocations(t *testing.T) {
+	testingPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			UID:       "123",
+		},
+	}
+	m := &podsInformer{
+		kubelet: &testKubeletStub{pods: corev1.PodList{
+			Items: []corev1.Pod{testingPod},
+		}},
+		podResources: &testPodResourcesStub{podResources: []*podresourcesapi.PodResources{
+			{
+				Name:      "test-pod",
+				Namespace: "default",
+				Containers: []*podresourcesapi.ContainerResources{
+					{
+						Name:   "main",
+						CpuIds: []int64{0, 1},
+					},
+				},
+			},
+		}},
+		podHasSynced:   atomic.NewBool(false),
+		callbackRunner: NewCallbackRunner(),
+	}
+	err := m.syncPods()
+	assert.NoError(t, err)
+	got := m.GetAllPods()
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, map[string]*statesinformer.ContainerAllocation{
+		"main": {CPUSet: "0-1"},
+	}, got[0].ContainerAllocations)
+}
+
+func Test_podsInformer_getAllPods(t *testing.T) {
+	testingKubeletPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			UID:       "123",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:         "main",
+					RestartCount: 1,
+				},
+			},
+		},
+	}
+	testingRuntimePod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			UID:       "123",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "main",
+					ContainerID: "containerd://c0",
+					Ready:       true,
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+	testingMergedPod := testingKubeletPod.DeepCopy()
+	testingMergedPod.Status.Phase = corev1.PodRunning
+	testingMergedPod.Status.ContainerStatuses[0].ContainerID = "containerd://c0"
+	testingMergedPod.Status.ContainerStatuses[0].Ready = true
+	testingMergedPod.Status.ContainerStatuses[0].State.Running = &corev1.ContainerStateRunning{}
+
+	tests := []struct {
+		name      string
+		kubelet   KubeletStub
+		cri       CRIStub
+		knownPods []corev1.Pod
+		want      []corev1.Pod
+		wantErr   bool
+	}{
+		{
+			name:    "no pod source",
+			wantErr: true,
+		},
+		{
+			name:    "get pods from kubelet",
+			kubelet: &testKubeletStub{pods: corev1.PodList{Items: []corev1.Pod{testingKubeletPod}}},
+			want:    []corev1.Pod{testingKubeletPod},
+		},
+		{
+			name:    "get pods from kubelet failed without cri",
+			kubelet: &testErrorKubeletStub{},
+			wantErr: true,
+		},
+		{
+			name:    "get pods from kubelet and check consistency",
+			kubelet: &testKubeletStub{pods: corev1.PodList{Items: []corev1.Pod{testingKubeletPod}}},
+			cri:     &testCRIStub{pods: corev1.PodList{Items: []corev1.Pod{testingRuntimePod}}},
+			want:    []corev1.Pod{testingKubeletPod},
+		},
+		{
+			name:    "fall back to cri when kubelet failed",
+			kubelet: &testErrorKubeletStub{},
+			cri:     &testCRIStub{pods: corev1.PodList{Items: []corev1.Pod{testingRuntimePod}}},
+			want:    []corev1.Pod{testingRuntimePod},
+		},
+		{
+			name:      "fall back to cri and merge the known pods",
+			kubelet:   &testKubeletStub{},
+			cri:       &testCRIStub{pods: corev1.PodList{Items: []corev1.Pod{testingRuntimePod}}},
+			knownPods: []corev1.Pod{testingKubeletPod},
+			want:      []corev1.Pod{*testingMergedPod},
+		},
+		{
+			name:      "get pods from cri only",
+			cri:       &testCRIStub{pods: corev1.PodList{Items: []corev1.Pod{testingRuntimePod}}},
+			knownPods: []corev1.Pod{testingKubeletPod},
+			want:      []corev1.Pod{*testingMergedPod},
+		},
+		{
+			name:    "get pods from cri failed",
+			cri:     &testCRIStub{err: errors.New("test error")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &podsInformer{
+				kubelet: tt.kubelet,
+				cri:     tt.cri,
+				podMap:  map[string]*statesinformer.PodMeta{},
+			}
+			for i := range tt.knownPods {
+				m.podMap[string(tt.knownPods[i].UID)] = &statesinformer.PodMeta{Pod: &tt.knownPods[i]}
+			}
+			got, gotErr := m.getAllPods()
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got.Items)
+			}
+		})
+	}
+}
+
+func Test_podsInformer_checkPodsConsistency(t *testing.T) {
+	testingPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			UID:       "123",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+	cri := &testCRIStub{pods: corev1.PodList{Items: []corev1.Pod{testingPod}}}
+	m := &podsInformer{
+		cri: cri,
+	}
+	m.checkPodsConsistency([]corev1.Pod{testingPod})
+	assert.Equal(t, 1, cri.calls)
+	// the container runtime is not listed again within the interval
+	m.checkPodsConsistency([]corev1.Pod{testingPod})
+	assert.Equal(t, 1, cri.calls)
+	m.podsCheckedTime = m.podsCheckedTime.Add(-podsConsistencyCheckInterval)
+	m.checkPodsConsistency([]corev1.Pod{testingPod})
+	assert.Equal(t, 2, cri.calls)
+}
+
+func Test_podsInformer_getPodAllocations(t *testing.T) {
+	m := &podsInformer{
+		config: NewDefaultConfig(),
+	}
+	// disabled if the endpoint is not specified
+	assert.Nil(t, m.getPodAllocations())
+	assert.Nil(t, m.podResources)
+
+	// retry to create the stub in the next sync if failed
+	m.config.KubeletPodResourcesEndpoint = "invalid://kubelet.sock"
+	assert.Nil(t, m.getPodAllocations())
+	assert.Nil(t, m.podResources)
+	m.config.KubeletPodResourcesEndpoint = "unix://" + filepath.Join(t.TempDir(), "kubelet.sock")
+	m.config.KubeletSyncTimeout = 100 * time.Millisecond
+	assert.Nil(t, m.getPodAllocations())
+	assert.NotNil(t, m.podResources)
+}
+
+func Test_getInconsistentPods(t *testing.T) {
+	newPod := func(name string, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	kubeletPods := []corev1.Pod{
+		newPod("pod-0", corev1.PodRunning),
+		newPod("pod-1", corev1.PodRunning),
+		newPod("pod-2", corev1.PodSucceeded),
+	}
+	runtimePods := []corev1.Pod{
+		newPod("pod-0", corev1.PodRunning),
+		newPod("pod-2", corev1.PodUnknown),
+		newPod("pod-3", corev1.PodRunning),
+	}
+	assert.Equal(t, []string{"default/pod-1", "default/pod-3"}, getInconsistentPods(kubeletPods, runtimePods))
+	assert.Nil(t, getInconsistentPods(kubeletPods[:1], runtimePods[:1]))
+}
+
 func Test_newKubeletStub(t *testing.T) {
 	testingNode := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -301,6 +551,96 @@ func Test_statesInformer_syncKubeletLoop(t *testing.T) {
 	close(stopCh)
 }
 
+type testCountingKubeletStub struct {
+	testKubeletStub
+	count *atomic.Int64
+}
+
+func (t *testCountingKubeletStub) GetAllPods() (corev1.PodList, error) {
+	t.count.Inc()
+	return t.pods, nil
+}
+
+func Test_podsInformer_syncKubeletLoopWithPLEGEvents(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	kubeletStub := &testCountingKubeletStub{
+		testKubeletStub: testKubeletStub{pods: corev1.PodList{
+			Items: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", UID: "123"},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{Name: "main", ContainerID: "containerd://c0"},
+						},
+					},
+				},
+			},
+		}},
+		count: atomic.NewInt64(0),
+	}
+	m := &podsInformer{
+		kubelet:        kubeletStub,
+		callbackRunner: NewCallbackRunner(),
+		podHasSynced:   atomic.NewBool(false),
+		podMap:         map[string]*statesinformer.PodMeta{},
+		plegEvents:     make(chan struct{}, 1),
+	}
+	go m.syncKubeletLoop(time.Hour, stopCh)
+	assert.Eventually(t, func() bool { return kubeletStub.count.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// sync immediately when the container is created
+	m.addPendingContainer("c0")
+	m.notifyPLEGEvent()
+	assert.Eventually(t, func() bool { return kubeletStub.count.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// resync with backoff until the container is reported or runs out of retries
+	m.addPendingContainer("sandbox")
+	m.notifyPLEGEvent()
+	assert.Eventually(t, func() bool { return kubeletStub.count.Load() == 3+plegSyncMaxRetries }, 10*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		m.pendingMutex.Lock()
+		defer m.pendingMutex.Unlock()
+		return len(m.pendingContainers) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_podsInformer_checkPendingContainers(t *testing.T) {
+	m := &podsInformer{
+		podMap: map[string]*statesinformer.PodMeta{
+			"123": {
+				Pod: &corev1.Pod{
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{Name: "main", ContainerID: "containerd://c0"},
+							{Name: "sidecar"},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, 0, m.checkPendingContainers())
+
+	m.addPendingContainer("c0")
+	m.addPendingContainer("c1")
+	assert.Equal(t, 1, m.checkPendingContainers())
+	assert.Equal(t, map[string]int{"c1": 1}, m.pendingContainers)
+
+	for i := 2; i <= plegSyncMaxRetries; i++ {
+		assert.Equal(t, i, m.checkPendingContainers())
+	}
+	assert.Equal(t, 0, m.checkPendingContainers())
+	assert.Equal(t, map[string]int{}, m.pendingContainers)
+}
+
+func Test_getPLEGSyncRetryInterval(t *testing.T) {
+	assert.Equal(t, plegSyncRetryInterval, getPLEGSyncRetryInterval(1, time.Minute))
+	assert.Equal(t, 4*plegSyncRetryInterval, getPLEGSyncRetryInterval(3, time.Minute))
+	assert.Equal(t, time.Second, getPLEGSyncRetryInterval(10, time.Second))
+}
+
 func Test_resetPodMetrics(t *testing.T) {
 	testingNode := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
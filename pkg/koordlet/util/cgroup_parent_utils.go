@@ -22,6 +22,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
@@ -85,3 +87,40 @@ func GetContainerCgroupParentDirByID(podParentDir string, containerID string) (s
 		containerDir,
 	), nil
 }
+
+const (
+	hostLSCgroupDir = "host-latency-sensitive"
+	hostBECgroupDir = "host-best-effort"
+)
+
+// GetHostAppCgroupRelativePath gets the relative cgroup dir of a host application.
+// If the cgroup path is not specified, the default dir is decided by the qos class.
+// @return host-latency-sensitive/nginx
+func GetHostAppCgroupRelativePath(hostAppSpec *slov1alpha1.HostApplicationSpec) string {
+	if hostAppSpec == nil {
+		return ""
+	}
+	if hostAppSpec.CgroupPath == nil {
+		cgroupBaseDir := ""
+		switch hostAppSpec.QoS {
+		case ext.QoSLSE, ext.QoSLSR, ext.QoSLS:
+			cgroupBaseDir = hostLSCgroupDir
+		case ext.QoSBE:
+			cgroupBaseDir = hostBECgroupDir
+			// empty string for QoSNone as default
+		}
+		return filepath.Join(cgroupBaseDir, hostAppSpec.Name)
+	} else {
+		cgroupBaseDir := ""
+		switch hostAppSpec.CgroupPath.Base {
+		case slov1alpha1.CgroupBaseTypeKubepods:
+			cgroupBaseDir = GetPodQoSRelativePath(corev1.PodQOSGuaranteed)
+		case slov1alpha1.CgroupBaseTypeKubeBurstable:
+			cgroupBaseDir = GetPodQoSRelativePath(corev1.PodQOSBurstable)
+		case slov1alpha1.CgroupBaseTypeKubeBesteffort:
+			cgroupBaseDir = GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+			// empty string for CgroupBaseTypeRoot as default
+		}
+		return filepath.Join(cgroupBaseDir, hostAppSpec.CgroupPath.ParentDir, hostAppSpec.CgroupPath.RelativePath)
+	}
+}
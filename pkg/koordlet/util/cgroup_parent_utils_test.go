@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,6 +25,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -279,3 +282,109 @@ func TestGetContainerCgroupParentDir_CgroupfsDriver(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetHostAppCgroupRelativePath(t *testing.T) {
+	type args struct {
+		hostAppSpec *slov1alpha1.HostApplicationSpec
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "nil host app",
+			args: args{
+				hostAppSpec: nil,
+			},
+			want: "",
+		},
+		{
+			name: "ls app with no cgroup",
+			args: args{
+				hostAppSpec: &slov1alpha1.HostApplicationSpec{
+					Name: "ls-app",
+					QoS:  ext.QoSLS,
+				},
+			},
+			want: filepath.Join(hostLSCgroupDir, "ls-app"),
+		},
+		{
+			name: "be app with no cgroup",
+			args: args{
+				hostAppSpec: &slov1alpha1.HostApplicationSpec{
+					Name: "be-app",
+					QoS:  ext.QoSBE,
+				},
+			},
+			want: filepath.Join(hostBECgroupDir, "be-app"),
+		},
+		{
+			name: "app with cgroup root base",
+			args: args{
+				hostAppSpec: &slov1alpha1.HostApplicationSpec{
+					Name: "test-app",
+					QoS:  ext.QoSLS,
+					CgroupPath: &slov1alpha1.CgroupPath{
+						Base:         slov1alpha1.CgroupBaseTypeRoot,
+						ParentDir:    "host-ls-app",
+						RelativePath: "test-app",
+					},
+				},
+			},
+			want: filepath.Join("", "host-ls-app", "test-app"),
+		},
+		{
+			name: "app with kubepods cgroup base",
+			args: args{
+				hostAppSpec: &slov1alpha1.HostApplicationSpec{
+					Name: "test-app",
+					QoS:  ext.QoSLS,
+					CgroupPath: &slov1alpha1.CgroupPath{
+						Base:         slov1alpha1.CgroupBaseTypeKubepods,
+						ParentDir:    "host-ls-app",
+						RelativePath: "test-app",
+					},
+				},
+			},
+			want: filepath.Join(GetPodQoSRelativePath(corev1.PodQOSGuaranteed), "host-ls-app", "test-app"),
+		},
+		{
+			name: "app with burstable cgroup base",
+			args: args{
+				hostAppSpec: &slov1alpha1.HostApplicationSpec{
+					Name: "test-app",
+					QoS:  ext.QoSLS,
+					CgroupPath: &slov1alpha1.CgroupPath{
+						Base:         slov1alpha1.CgroupBaseTypeKubeBurstable,
+						ParentDir:    "host-ls-app",
+						RelativePath: "test-app",
+					},
+				},
+			},
+			want: filepath.Join(GetPodQoSRelativePath(corev1.PodQOSBurstable), "host-ls-app", "test-app"),
+		},
+		{
+			name: "app with besteffort cgroup base",
+			args: args{
+				hostAppSpec: &slov1alpha1.HostApplicationSpec{
+					Name: "test-app",
+					QoS:  ext.QoSBE,
+					CgroupPath: &slov1alpha1.CgroupPath{
+						Base:         slov1alpha1.CgroupBaseTypeKubeBesteffort,
+						ParentDir:    "host-be-app",
+						RelativePath: "test-app",
+					},
+				},
+			},
+			want: filepath.Join(GetPodQoSRelativePath(corev1.PodQOSBestEffort), "host-be-app", "test-app"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetHostAppCgroupRelativePath(tt.args.hostAppSpec); got != tt.want {
+				t.Errorf("GetHostAppCgroupRelativePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
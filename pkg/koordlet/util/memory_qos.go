@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+// InjectPodMemoryQOSConfigFunc returns the pod-level memory qos config injected by the plugins, e.g. the qos grey
+// control plugins, and nil if not injected.
+// It is set by the qosmanager since the qosmanager depends on this package.
+var InjectPodMemoryQOSConfigFunc = func(pod *corev1.Pod) *slov1alpha1.PodMemoryQOSConfig {
+	return nil
+}
+
+// ContainerMemoryQOS describes the memory qos cgroup values of a container, nil means not to set.
+type ContainerMemoryQOS struct {
+	MemoryMin        *int64
+	MemoryLow        *int64
+	MemoryHigh       *int64
+	WmarkRatio       *int64
+	WmarkScaleFactor *int64
+	WmarkMinAdj      *int64
+	UsePriorityOom   *int64
+	Priority         *int64
+	OomKillGroup     *int64
+}
+
+// GetPodResourceQoSByQoSClass gets the resource qos config of the pod's koordinator qos class.
+func GetPodResourceQoSByQoSClass(pod *corev1.Pod, strategy *slov1alpha1.ResourceQOSStrategy) *slov1alpha1.ResourceQOS {
+	if strategy == nil {
+		return nil
+	}
+	var resourceQoS *slov1alpha1.ResourceQOS
+	podQoS := apiext.GetPodQoSClassWithDefault(pod)
+	switch podQoS {
+	case apiext.QoSLSE:
+		// currently LSE pods use the same strategy with LSR
+		resourceQoS = strategy.LSRClass
+	case apiext.QoSLSR:
+		resourceQoS = strategy.LSRClass
+	case apiext.QoSLS:
+		resourceQoS = strategy.LSClass
+	case apiext.QoSBE:
+		resourceQoS = strategy.BEClass
+	case apiext.QoSSystem:
+		resourceQoS = strategy.SystemClass
+	default:
+		// should never reach here
+	}
+	return resourceQoS
+}
+
+// GetPodMemoryQOSConfig returns the pod-level memory qos config from the pod annotation, or the one injected by the
+// plugins if the annotation is not set. It returns nil if neither exists.
+func GetPodMemoryQOSConfig(pod *corev1.Pod) *slov1alpha1.PodMemoryQOSConfig {
+	podCfg, err := slov1alpha1.GetPodMemoryQoSConfig(pod)
+	if err != nil { // ignore pod-level memory qos config when parse error
+		klog.Errorf("failed to parse memory qos config, pod %s, err: %s", util.GetPodKey(pod), err)
+		podCfg = nil
+	}
+	if podCfg == nil {
+		podCfg = InjectPodMemoryQOSConfigFunc(pod)
+	}
+	return podCfg
+}
+
+// MergePodMemoryQOS merges the pod-level memory qos config into the resource qos config of the pod, where the cfg is
+// expected to be deep-copied from the node-level config.
+// config overwrite: pod-level config > pod policy template > node-level config
+func MergePodMemoryQOS(pod *corev1.Pod, cfg *slov1alpha1.ResourceQOS, podCfg *slov1alpha1.PodMemoryQOSConfig) {
+	if cfg.MemoryQOS == nil {
+		cfg.MemoryQOS = &slov1alpha1.MemoryQOSCfg{}
+	}
+	policy := slov1alpha1.PodMemoryQOSPolicyDefault
+	if podCfg != nil {
+		policy = podCfg.Policy // policy="" is equal to policy="default"
+	}
+	klog.V(5).Infof("memory qos podPolicy=%s for pod %s", policy, util.GetPodKey(pod))
+
+	// if policy is not default, replace memory qos config with the policy template
+	if policy == slov1alpha1.PodMemoryQOSPolicyNone { // fully disable memory qos for policy=None
+		cfg.MemoryQOS.MemoryQOS = *sloconfig.NoneMemoryQOS()
+		cfg.MemoryQOS.Enable = pointer.Bool(false)
+		return
+	} else if policy == slov1alpha1.PodMemoryQOSPolicyAuto { // qos=None would be set with kubeQoS for policy=Auto
+		cfg.MemoryQOS.MemoryQOS = GetPodResourceQoSByQoSClass(pod, sloconfig.DefaultResourceQOSStrategy()).MemoryQOS.MemoryQOS
+	}
+
+	// no need to merge config if pod-level config is nil
+	if podCfg == nil {
+		return
+	}
+	// otherwise detailed pod-level config is specified, merge with node-level config for the pod
+	merged, err := util.MergeCfg(&cfg.MemoryQOS.MemoryQOS, &podCfg.MemoryQOS) // node config has been deep-copied
+	if err != nil {
+		// not change memory qos config if merge error
+		klog.Errorf("failed to merge memory qos config with node config, pod %s, err: %s", util.GetPodKey(pod), err)
+		return
+	}
+	cfg.MemoryQOS.MemoryQOS = *merged.(*slov1alpha1.MemoryQOS)
+	klog.V(6).Infof("get merged memory qos %v", util.DumpJSON(cfg.MemoryQOS))
+}
+
+// CalculateContainerMemoryQOS calculates the memory qos cgroup values of the container with the pod-level memory qos
+// config. The memory.high uses the node allocatable when the container's memory limit is not set.
+func CalculateContainerMemoryQOS(pod *corev1.Pod, container *corev1.Container, memoryQOS *slov1alpha1.MemoryQOS,
+	nodeAllocatable int64) *ContainerMemoryQOS {
+	// resources statically use configured values
+	result := &ContainerMemoryQOS{
+		WmarkRatio:       memoryQOS.WmarkRatio,
+		WmarkScaleFactor: memoryQOS.WmarkScalePermill,
+		WmarkMinAdj:      memoryQOS.WmarkMinAdj,
+		UsePriorityOom:   memoryQOS.PriorityEnable,
+		Priority:         memoryQOS.Priority,
+		OomKillGroup:     memoryQOS.OomKillGroup,
+	}
+
+	// resources calculated with container spec
+	var memRequest int64
+	var memLimit int64
+	if apiext.GetPodQoSClassRaw(pod) != apiext.QoSBE {
+		memRequest = container.Resources.Requests.Memory().Value()
+		memLimit = util.GetContainerMemoryByteLimit(container)
+	} else {
+		memRequest = util.GetContainerBatchMemoryByteRequest(container)
+		memLimit = util.GetContainerBatchMemoryByteLimit(container)
+	}
+	if memRequest < 0 {
+		// when container request not set, memory request is counted as zero but not unlimited(-1)
+		memRequest = 0
+	}
+	// memory.min, memory.low: if container's memory request is not set, just consider it as zero
+	if memoryQOS.MinLimitPercent != nil {
+		result.MemoryMin = pointer.Int64(memRequest * (*memoryQOS.MinLimitPercent) / 100)
+	}
+	if memoryQOS.LowLimitPercent != nil {
+		result.MemoryLow = pointer.Int64(memRequest * (*memoryQOS.LowLimitPercent) / 100)
+	}
+	// memory.high: if container's memory throttling factor is set as zero, disable memory.high by set to maximal;
+	// else if factor is set while container's limit not set, set memory.high with node memory allocatable
+	if memoryQOS.ThrottlingPercent != nil {
+		if *memoryQOS.ThrottlingPercent == 0 { // reset to system default if set 0
+			result.MemoryHigh = pointer.Int64(math.MaxInt64) // writing MaxInt64 is equal to write "max"
+		} else {
+			limit := memLimit
+			if limit <= 0 {
+				limit = nodeAllocatable
+			}
+			if limit > 0 {
+				result.MemoryHigh = pointer.Int64(((memRequest + (limit-memRequest)*(*memoryQOS.ThrottlingPercent)/100) /
+					system.PageSize) * system.PageSize)
+			}
+		}
+	}
+	// values improved: memory.low is no less than memory.min
+	if result.MemoryMin != nil && result.MemoryLow != nil && *result.MemoryLow > 0 &&
+		*result.MemoryLow < *result.MemoryMin {
+		result.MemoryLow = pointer.Int64(*result.MemoryMin)
+		klog.V(5).Infof("correct calculated memory.low for container since it is lower than memory.min,"+
+			" pod %s, container %s, current value %v", util.GetPodKey(pod), container.Name, *result.MemoryLow)
+	}
+	// values improved: memory.high is no less than memory.min
+	if result.MemoryHigh != nil && result.MemoryMin != nil && *result.MemoryHigh > 0 &&
+		*result.MemoryHigh < *result.MemoryMin {
+		result.MemoryHigh = pointer.Int64(*result.MemoryMin)
+		klog.V(5).Infof("correct calculated memory.high for container since it is lower than memory.min,"+
+			" pod %s, container %s, current value %v", util.GetPodKey(pod), container.Name, *result.MemoryHigh)
+	}
+	return result
+}
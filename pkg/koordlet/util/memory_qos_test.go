@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestCalculateContainerMemoryQOS(t *testing.T) {
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+		},
+	}
+	testContainer := &corev1.Container{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		},
+	}
+	tests := []struct {
+		name            string
+		container       *corev1.Container
+		memoryQOS       *slov1alpha1.MemoryQOS
+		nodeAllocatable int64
+		want            ContainerMemoryQOS
+	}{
+		{
+			name:      "calculate with container limit",
+			container: testContainer,
+			memoryQOS: &slov1alpha1.MemoryQOS{
+				MinLimitPercent:   pointer.Int64(50),
+				LowLimitPercent:   pointer.Int64(100),
+				ThrottlingPercent: pointer.Int64(50),
+				WmarkRatio:        pointer.Int64(95),
+				WmarkScalePermill: pointer.Int64(20),
+				PriorityEnable:    pointer.Int64(1),
+				Priority:          pointer.Int64(6),
+			},
+			want: ContainerMemoryQOS{
+				MemoryMin:        pointer.Int64(512 << 20),
+				MemoryLow:        pointer.Int64(1 << 30),
+				MemoryHigh:       pointer.Int64(1536 << 20),
+				WmarkRatio:       pointer.Int64(95),
+				WmarkScaleFactor: pointer.Int64(20),
+				UsePriorityOom:   pointer.Int64(1),
+				Priority:         pointer.Int64(6),
+			},
+		},
+		{
+			name:      "memory.high is max when throttling is disabled",
+			container: testContainer,
+			memoryQOS: &slov1alpha1.MemoryQOS{
+				MinLimitPercent:   pointer.Int64(100),
+				LowLimitPercent:   pointer.Int64(50),
+				ThrottlingPercent: pointer.Int64(0),
+			},
+			want: ContainerMemoryQOS{
+				MemoryMin:  pointer.Int64(1 << 30),
+				MemoryLow:  pointer.Int64(1 << 30),
+				MemoryHigh: pointer.Int64(math.MaxInt64),
+			},
+		},
+		{
+			name:      "calculate memory.high with node allocatable when container limit is not set",
+			container: &corev1.Container{},
+			memoryQOS: &slov1alpha1.MemoryQOS{
+				MinLimitPercent:   pointer.Int64(100),
+				ThrottlingPercent: pointer.Int64(50),
+			},
+			nodeAllocatable: 4 << 30,
+			want: ContainerMemoryQOS{
+				MemoryMin:  pointer.Int64(0),
+				MemoryHigh: pointer.Int64(2 << 30),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateContainerMemoryQOS(testPod, tt.container, tt.memoryQOS, tt.nodeAllocatable)
+			assert.Equal(t, &tt.want, got)
+		})
+	}
+}
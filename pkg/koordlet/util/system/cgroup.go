@@ -60,6 +60,14 @@ type MemoryStatRaw struct {
 	// add more fields
 }
 
+// BlkIOStatRaw is the accumulated block io of a cgroup summed over all devices.
+type BlkIOStatRaw struct {
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadIOs    uint64
+	WriteIOs   uint64
+}
+
 type NumaMemoryPages struct {
 	NumaId   int
 	PagesNum uint64
@@ -195,6 +203,40 @@ func ParseMemoryNumaStat(content string) ([]NumaMemoryPages, error) {
 	return stat, nil
 }
 
+// ParseBlkIOThrottleStat parses the blkio.throttle.io_service_bytes or blkio.throttle.io_serviced and returns the
+// read and write value summed over all devices.
+// e.g.
+// 8:0 Read 4096
+// 8:0 Write 8192
+// 8:0 Sync 0
+// 8:0 Async 12288
+// 8:0 Total 12288
+// Total 12288
+func ParseBlkIOThrottleStat(content string) (uint64, uint64, error) {
+	var read, write uint64
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		var value *uint64
+		switch fields[1] {
+		case "Read":
+			value = &read
+		case "Write":
+			value = &write
+		default:
+			continue
+		}
+		v, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse blkio stat failed, raw content %s, err: %v", content, err)
+		}
+		*value += v
+	}
+	return read, write, nil
+}
+
 func CalcCPUThrottledRatio(curPoint, prePoint *CPUStatRaw) float64 {
 	deltaPeriod := curPoint.NrPeriods - prePoint.NrPeriods
 	deltaThrottled := curPoint.NrThrottled - prePoint.NrThrottled
@@ -299,6 +299,45 @@ func ConvertCPUWeightToShares(v int64) (int64, error) {
 	return s, nil
 }
 
+// ParseIOStatV2 parses the io.stat and sums up the io of all devices.
+// e.g.
+// 8:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0
+// 253:0 rbytes=4096 wbytes=0 rios=1 wios=0 dbytes=0 dios=0
+func ParseIOStatV2(content string) (*BlkIOStatRaw, error) {
+	stat := &BlkIOStatRaw{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) <= 1 { // no io on the device
+			continue
+		}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("parse io.stat failed, raw content %s, invalid field %s", content, field)
+			}
+			var value *uint64
+			switch kv[0] {
+			case "rbytes":
+				value = &stat.ReadBytes
+			case "wbytes":
+				value = &stat.WriteBytes
+			case "rios":
+				value = &stat.ReadIOs
+			case "wios":
+				value = &stat.WriteIOs
+			default:
+				continue
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse io.stat failed, raw content %s, field %s, err: %v", content, field, err)
+			}
+			*value += v
+		}
+	}
+	return stat, nil
+}
+
 func ConvertCPUSharesToWeight(s string) (int64, error) {
 	isValid, msg := CPUSharesValidator.Validate(s)
 	if !isValid {
@@ -18,6 +18,8 @@ package system
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestParseCPUCFSQuotaV2(t *testing.T) {
@@ -445,3 +447,47 @@ func TestConvertCPUSharesToWeight(t *testing.T) {
 		}
 	}
 }
+
+func TestParseIOStatV2(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *BlkIOStatRaw
+		wantErr bool
+	}{
+		{
+			name: "sum all devices",
+			content: `8:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0
+253:0 rbytes=4096 wbytes=0 rios=1 wios=0 dbytes=0 dios=0
+`,
+			want: &BlkIOStatRaw{
+				ReadBytes:  8192,
+				WriteBytes: 8192,
+				ReadIOs:    2,
+				WriteIOs:   2,
+			},
+		},
+		{
+			name:    "empty content",
+			content: ``,
+			want:    &BlkIOStatRaw{},
+		},
+		{
+			name:    "invalid field",
+			content: `8:0 rbytes`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			content: `8:0 rbytes=invalid`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := ParseIOStatV2(tt.content)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -111,6 +111,8 @@ const ( // subsystems
 	CgroupCPUAcctDir string = "cpuacct/"
 	CgroupMemDir     string = "memory/"
 	CgroupBlkioDir   string = "blkio/"
+	CgroupNetClsDir  string = "net_cls/"
+	CgroupHugetlbDir string = "hugetlb/"
 
 	CgroupV2Dir = ""
 )
@@ -135,6 +137,7 @@ const (
 	CPUMaxName       = "cpu.max"
 	CPUMaxBurstName  = "cpu.max.burst"
 	CPUWeightName    = "cpu.weight"
+	CgroupKillName   = "cgroup.kill" // cgroups-v2 only, write-only
 
 	CPUSetCPUSName          = "cpuset.cpus"
 	CPUSetCPUSEffectiveName = "cpuset.cpus.effective"
@@ -161,6 +164,7 @@ const (
 	MemoryUsePriorityOomName   = "memory.use_priority_oom"
 	MemoryOomGroupName         = "memory.oom.group"
 	MemoryIdlePageStatsName    = "memory.idle_page_stats"
+	MemorySwapMaxName          = "memory.swap.max" // cgroups-v2 only
 
 	BlkioTRIopsName   = "blkio.throttle.read_iops_device"
 	BlkioTRBpsName    = "blkio.throttle.read_bps_device"
@@ -168,6 +172,17 @@ const (
 	BlkioTWBpsName    = "blkio.throttle.write_bps_device"
 	BlkioIOWeightName = "blkio.cost.weight"
 	BlkioIOQoSName    = "blkio.cost.qos"
+
+	BlkioIOServiceBytesName = "blkio.throttle.io_service_bytes"
+	BlkioIOServicedName     = "blkio.throttle.io_serviced"
+	IOStatName              = "io.stat"
+
+	NetClsClassIdName = "net_cls.classid"
+
+	HugetlbLimit2MBName = "hugetlb.2MB.limit_in_bytes"
+	HugetlbLimit1GBName = "hugetlb.1GB.limit_in_bytes"
+	HugetlbMax2MBName   = "hugetlb.2MB.max"
+	HugetlbMax1GBName   = "hugetlb.1GB.max"
 )
 
 var (
@@ -182,6 +197,7 @@ var (
 	MemoryPriorityValidator                 = &RangeValidator{min: 0, max: 12}
 	MemoryOomGroupValidator                 = &RangeValidator{min: 0, max: 1}
 	MemoryUsePriorityOomValidator           = &RangeValidator{min: 0, max: 1}
+	CgroupKillValidator                     = &RangeValidator{min: 1, max: 1}
 	MemoryWmarkMinAdjValidator              = &RangeValidator{min: -25, max: 50}
 	MemoryWmarkScaleFactorFileNameValidator = &RangeValidator{min: 1, max: 1000}
 	BlkioTRIopsValidator                    = &BlkIORangeValidator{min: 0, max: math.MaxInt64, resource: BlkioTRIopsName}
@@ -237,6 +253,14 @@ var (
 	BlkioIOWeight  = DefaultFactory.New(BlkioIOWeightName, CgroupBlkioDir).WithValidator(BlkioIOWeightValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	BlkioIOQoS     = DefaultFactory.New(BlkioIOQoSName, CgroupBlkioDir).WithValidator(BlkioIOQoSValidator).WithSupported(SupportedIfFileExistsInRootCgroup(BlkioIOQoSName, CgroupBlkioDir))
 
+	BlkioIOServiceBytes = DefaultFactory.New(BlkioIOServiceBytesName, CgroupBlkioDir)
+	BlkioIOServiced     = DefaultFactory.New(BlkioIOServicedName, CgroupBlkioDir)
+
+	NetClsClassId = DefaultFactory.New(NetClsClassIdName, CgroupNetClsDir).WithValidator(NaturalInt64Validator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
+	HugetlbLimit2MB = DefaultFactory.New(HugetlbLimit2MBName, CgroupHugetlbDir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	HugetlbLimit1GB = DefaultFactory.New(HugetlbLimit1GBName, CgroupHugetlbDir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
 	knownCgroupResources = []Resource{
 		CPUStat,
 		CPUShares,
@@ -272,6 +296,11 @@ var (
 		BlkioWriteBps,
 		BlkioIOWeight,
 		BlkioIOQoS,
+		BlkioIOServiceBytes,
+		BlkioIOServiced,
+		NetClsClassId,
+		HugetlbLimit2MB,
+		HugetlbLimit1GB,
 	}
 
 	CPUCFSQuotaV2  = DefaultFactory.NewV2(CPUCFSQuotaName, CPUMaxName)
@@ -291,6 +320,7 @@ var (
 	CPUSetEffectiveV2        = DefaultFactory.NewV2(CPUSetCPUSEffectiveName, CPUSetCPUSEffectiveName) // TODO: unify the R/W
 	CPUTasksV2               = DefaultFactory.NewV2(CPUTasksName, CPUThreadsName)
 	CPUProcsV2               = DefaultFactory.NewV2(CPUProcsName, CPUProcsName)
+	CgroupKillV2             = DefaultFactory.NewV2(CgroupKillName, CgroupKillName).WithValidator(CgroupKillValidator).WithCheckSupported(SupportedIfFileExists)
 	MemoryLimitV2            = DefaultFactory.NewV2(MemoryLimitName, MemoryMaxName)
 	MemoryUsageV2            = DefaultFactory.NewV2(MemoryUsageName, MemoryCurrentName)
 	MemoryStatV2             = DefaultFactory.NewV2(MemoryStatName, MemoryStatName)
@@ -304,6 +334,13 @@ var (
 	MemoryPriorityV2         = DefaultFactory.NewV2(MemoryPriorityName, MemoryPriorityName).WithValidator(MemoryPriorityValidator).WithCheckSupported(SupportedIfFileExists)
 	MemoryUsePriorityOomV2   = DefaultFactory.NewV2(MemoryUsePriorityOomName, MemoryUsePriorityOomName).WithValidator(MemoryUsePriorityOomValidator).WithCheckSupported(SupportedIfFileExists)
 	MemoryOomGroupV2         = DefaultFactory.NewV2(MemoryOomGroupName, MemoryOomGroupName).WithValidator(MemoryOomGroupValidator).WithCheckSupported(SupportedIfFileExists)
+	MemorySwapMaxV2          = DefaultFactory.NewV2(MemorySwapMaxName, MemorySwapMaxName).WithValidator(NaturalInt64Validator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
+	HugetlbLimit2MBV2 = DefaultFactory.NewV2(HugetlbLimit2MBName, HugetlbMax2MBName).WithValidator(NaturalInt64Validator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	HugetlbLimit1GBV2 = DefaultFactory.NewV2(HugetlbLimit1GBName, HugetlbMax1GBName).WithValidator(NaturalInt64Validator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
+	// io.stat contains both the bytes and the ios of cgroups-v2
+	BlkioIOServiceBytesV2 = DefaultFactory.NewV2(BlkioIOServiceBytesName, IOStatName)
 
 	knownCgroupV2Resources = []Resource{
 		CPUCFSQuotaV2,
@@ -321,6 +358,7 @@ var (
 		CPUSetEffectiveV2,
 		CPUTasksV2,
 		CPUProcsV2,
+		CgroupKillV2,
 		MemoryLimitV2,
 		MemoryUsageV2,
 		MemoryStatV2,
@@ -334,8 +372,12 @@ var (
 		MemoryPriorityV2,
 		MemoryUsePriorityOomV2,
 		MemoryOomGroupV2,
+		MemorySwapMaxV2,
+		HugetlbLimit2MBV2,
+		HugetlbLimit1GBV2,
 		BlkioIOWeight,
 		BlkioIOQoS,
+		BlkioIOServiceBytesV2,
 	}
 )
 
@@ -18,6 +18,8 @@ package system
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestParseCPUStatRaw(t *testing.T) {
@@ -264,3 +266,47 @@ func TestCalcCPUThrottledRatio(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBlkIOThrottleStat(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantRead  uint64
+		wantWrite uint64
+		wantErr   bool
+	}{
+		{
+			name: "sum all devices",
+			content: `8:16 Read 1024
+8:16 Write 2048
+8:16 Sync 0
+8:16 Async 3072
+8:16 Total 3072
+8:0 Read 4096
+8:0 Write 8192
+8:0 Sync 0
+8:0 Async 12288
+8:0 Total 12288
+Total 15360`,
+			wantRead:  5120,
+			wantWrite: 10240,
+		},
+		{
+			name:    "empty content",
+			content: `Total 0`,
+		},
+		{
+			name:    "invalid value",
+			content: `8:0 Read invalid`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRead, gotWrite, gotErr := ParseBlkIOThrottleStat(tt.content)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.wantRead, gotRead)
+			assert.Equal(t, tt.wantWrite, gotWrite)
+		})
+	}
+}
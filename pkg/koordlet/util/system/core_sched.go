@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CoreSchedScopeType is the scope of the tasks which the core scheduling operation applies to.
+type CoreSchedScopeType uint
+
+const (
+	// CoreSchedScopeThread is the single thread of the pid.
+	CoreSchedScopeThread CoreSchedScopeType = 0
+	// CoreSchedScopeThreadGroup is all threads of the thread group of the pid.
+	CoreSchedScopeThreadGroup CoreSchedScopeType = 1
+	// CoreSchedScopeProcessGroup is all processes of the process group of the pid.
+	CoreSchedScopeProcessGroup CoreSchedScopeType = 2
+)
+
+// CoreSchedInterface manages the core scheduling cookies of the tasks via prctl(PR_SCHED_CORE).
+// Tasks with different cookies never run concurrently on the SMT siblings of a physical core.
+// https://docs.kernel.org/admin-guide/hw-vuln/core-scheduling.html
+type CoreSchedInterface interface {
+	// Get returns the cookie of the task, 0 means the task has no cookie.
+	Get(pidType CoreSchedScopeType, pid uint32) (uint64, error)
+	// Create creates a new cookie for the tasks of the pid and the scope.
+	Create(pidType CoreSchedScopeType, pid uint32) error
+	// Assign shares the cookie of the task pidFrom to the tasks of pidsTo, and returns the pids failed to assign.
+	Assign(pidFrom uint32, pidTypeTo CoreSchedScopeType, pidsTo ...uint32) ([]uint32, error)
+	// Clear clears the cookies of the tasks, and returns the pids failed to clear.
+	Clear(pidType CoreSchedScopeType, pids ...uint32) ([]uint32, error)
+}
+
+// FakeCoreSched is a fake CoreSchedInterface which records the cookies in memory.
+// NOTE: this should be used only for testing purposes.
+type FakeCoreSched struct {
+	lock       sync.Mutex
+	nextCookie uint64
+	// pid -> cookie
+	cookies map[uint32]uint64
+}
+
+func NewFakeCoreSched() *FakeCoreSched {
+	return &FakeCoreSched{
+		nextCookie: 1,
+		cookies:    map[uint32]uint64{},
+	}
+}
+
+// SetTask adds a task with the given cookie.
+func (f *FakeCoreSched) SetTask(pid uint32, cookie uint64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.cookies[pid] = cookie
+}
+
+// DeleteTask removes the task as it exits.
+func (f *FakeCoreSched) DeleteTask(pid uint32) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.cookies, pid)
+}
+
+func (f *FakeCoreSched) Get(pidType CoreSchedScopeType, pid uint32) (uint64, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	cookie, ok := f.cookies[pid]
+	if !ok {
+		return 0, fmt.Errorf("no such process %v", pid)
+	}
+	return cookie, nil
+}
+
+func (f *FakeCoreSched) Create(pidType CoreSchedScopeType, pid uint32) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, ok := f.cookies[pid]; !ok {
+		return fmt.Errorf("no such process %v", pid)
+	}
+	f.cookies[pid] = f.nextCookie
+	f.nextCookie++
+	return nil
+}
+
+func (f *FakeCoreSched) Assign(pidFrom uint32, pidTypeTo CoreSchedScopeType, pidsTo ...uint32) ([]uint32, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	cookie, ok := f.cookies[pidFrom]
+	if !ok {
+		return pidsTo, fmt.Errorf("no such process %v", pidFrom)
+	}
+	return f.setCookies(cookie, pidsTo)
+}
+
+func (f *FakeCoreSched) Clear(pidType CoreSchedScopeType, pids ...uint32) ([]uint32, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.setCookies(0, pids)
+}
+
+func (f *FakeCoreSched) setCookies(cookie uint64, pids []uint32) ([]uint32, error) {
+	var failed []uint32
+	for _, pid := range pids {
+		if _, ok := f.cookies[pid]; !ok {
+			failed = append(failed, pid)
+			continue
+		}
+		f.cookies[pid] = cookie
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("no such processes %v", failed)
+	}
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+type coreSched struct{}
+
+func NewCoreSched() CoreSchedInterface {
+	return &coreSched{}
+}
+
+func (s *coreSched) Get(pidType CoreSchedScopeType, pid uint32) (uint64, error) {
+	var cookie uint64
+	err := unix.Prctl(unix.PR_SCHED_CORE, unix.PR_SCHED_CORE_GET, uintptr(pid), uintptr(pidType),
+		uintptr(unsafe.Pointer(&cookie)))
+	if err != nil {
+		return 0, err
+	}
+	return cookie, nil
+}
+
+func (s *coreSched) Create(pidType CoreSchedScopeType, pid uint32) error {
+	return unix.Prctl(unix.PR_SCHED_CORE, unix.PR_SCHED_CORE_CREATE, uintptr(pid), uintptr(pidType), 0)
+}
+
+func (s *coreSched) Assign(pidFrom uint32, pidTypeTo CoreSchedScopeType, pidsTo ...uint32) ([]uint32, error) {
+	return runInIsolatedThread(func() ([]uint32, error) {
+		// pull the cookie to the current thread, and then push it to the targets
+		err := unix.Prctl(unix.PR_SCHED_CORE, unix.PR_SCHED_CORE_SHARE_FROM, uintptr(pidFrom),
+			uintptr(CoreSchedScopeThread), 0)
+		if err != nil {
+			return pidsTo, fmt.Errorf("failed to share cookie from pid %v, err: %w", pidFrom, err)
+		}
+		return shareToTasks(pidTypeTo, pidsTo)
+	})
+}
+
+func (s *coreSched) Clear(pidType CoreSchedScopeType, pids ...uint32) ([]uint32, error) {
+	return runInIsolatedThread(func() ([]uint32, error) {
+		// push the empty cookie of the current thread to the targets
+		cookie, err := s.Get(CoreSchedScopeThread, 0)
+		if err != nil {
+			return pids, err
+		}
+		if cookie != 0 {
+			return pids, fmt.Errorf("current thread has a non-empty cookie %v", cookie)
+		}
+		return shareToTasks(pidType, pids)
+	})
+}
+
+func shareToTasks(pidType CoreSchedScopeType, pids []uint32) ([]uint32, error) {
+	var failed []uint32
+	var errs []error
+	for _, pid := range pids {
+		err := unix.Prctl(unix.PR_SCHED_CORE, unix.PR_SCHED_CORE_SHARE_TO, uintptr(pid), uintptr(pidType), 0)
+		if err != nil {
+			failed = append(failed, pid)
+			errs = append(errs, fmt.Errorf("pid %v: %w", pid, err))
+		}
+	}
+	return failed, utilerrors.NewAggregate(errs)
+}
+
+// runInIsolatedThread runs the function which may change the cookie of the current thread in a dedicated OS thread.
+// The thread is never unlocked, so it is terminated when the goroutine exits and the other goroutines are not affected.
+func runInIsolatedThread(fn func() ([]uint32, error)) ([]uint32, error) {
+	type result struct {
+		failed []uint32
+		err    error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		runtime.LockOSThread()
+		failed, err := fn()
+		ch <- result{failed: failed, err: err}
+	}()
+	r := <-ch
+	return r.failed, r.err
+}
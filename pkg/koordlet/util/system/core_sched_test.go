@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeCoreSched(t *testing.T) {
+	f := NewFakeCoreSched()
+	f.SetTask(1, 0)
+	f.SetTask(2, 0)
+	f.SetTask(3, 0)
+
+	_, err := f.Get(CoreSchedScopeThread, 4)
+	assert.Error(t, err)
+	assert.Error(t, f.Create(CoreSchedScopeThread, 4))
+
+	assert.NoError(t, f.Create(CoreSchedScopeThread, 1))
+	cookie, err := f.Get(CoreSchedScopeThread, 1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uint64(0), cookie)
+
+	failed, err := f.Assign(1, CoreSchedScopeThread, 2, 3, 4)
+	assert.Error(t, err)
+	assert.Equal(t, []uint32{4}, failed)
+	got, _ := f.Get(CoreSchedScopeThread, 3)
+	assert.Equal(t, cookie, got)
+
+	failed, err = f.Clear(CoreSchedScopeThread, 2, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, failed)
+	got, _ = f.Get(CoreSchedScopeThread, 3)
+	assert.Equal(t, uint64(0), got)
+}
@@ -0,0 +1,45 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import "fmt"
+
+type coreSched struct{}
+
+// NewCoreSched returns a CoreSchedInterface which is not supported for non-linux os
+func NewCoreSched() CoreSchedInterface {
+	return &coreSched{}
+}
+
+func (s *coreSched) Get(pidType CoreSchedScopeType, pid uint32) (uint64, error) {
+	return 0, fmt.Errorf("only support linux")
+}
+
+func (s *coreSched) Create(pidType CoreSchedScopeType, pid uint32) error {
+	return fmt.Errorf("only support linux")
+}
+
+func (s *coreSched) Assign(pidFrom uint32, pidTypeTo CoreSchedScopeType, pidsTo ...uint32) ([]uint32, error) {
+	return pidsTo, fmt.Errorf("only support linux")
+}
+
+func (s *coreSched) Clear(pidType CoreSchedScopeType, pids ...uint32) ([]uint32, error) {
+	return pids, fmt.Errorf("only support linux")
+}
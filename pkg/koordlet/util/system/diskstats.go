@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	ProcDiskStatsName = "diskstats"
+
+	SysBlockSubDir        = "block"
+	SysVirtualBlockSubDir = "devices/virtual/block"
+
+	// DiskSectorSize is the sector size used by the kernel to count the disk io, which is always 512 bytes
+	DiskSectorSize = 512
+)
+
+func GetProcDiskStatsPath() string {
+	return filepath.Join(Conf.ProcRootDir, ProcDiskStatsName)
+}
+
+// DiskStat is the io counters of a block device in /proc/diskstats.
+type DiskStat struct {
+	ReadIOs      uint64
+	ReadSectors  uint64
+	WriteIOs     uint64
+	WriteSectors uint64
+}
+
+func (d *DiskStat) ReadBytes() uint64 {
+	return d.ReadSectors * DiskSectorSize
+}
+
+func (d *DiskStat) WriteBytes() uint64 {
+	return d.WriteSectors * DiskSectorSize
+}
+
+// IsPhysicalDisk returns whether the block device is a whole physical disk, excluding partitions and virtual
+// devices like loop, ram and device-mapper.
+func IsPhysicalDisk(devName string) bool {
+	return FileExists(filepath.Join(Conf.SysRootDir, SysBlockSubDir, devName)) &&
+		!FileExists(filepath.Join(Conf.SysRootDir, SysVirtualBlockSubDir, devName))
+}
+
+// GetDiskStats reads and parses /proc/diskstats.
+func GetDiskStats() (map[string]*DiskStat, error) {
+	content, err := os.ReadFile(GetProcDiskStatsPath())
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiskStats(string(content))
+}
+
+// ParseDiskStats parses the content of /proc/diskstats into the stats of each block device.
+// e.g.
+// 8       0 sda 16358 3851 1209298 9536 10632 10207 459994 10470 0 24736 20007 0 0 0 0
+// 8       1 sda1 16264 3851 1204986 9498 10632 10207 459994 10470 0 24696 19969 0 0 0 0
+func ParseDiskStats(content string) (map[string]*DiskStat, error) {
+	stats := map[string]*DiskStat{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 10 {
+			return nil, fmt.Errorf("failed to parse diskstats line %q, fields not enough", line)
+		}
+		stat := &DiskStat{}
+		for _, t := range []struct {
+			index int
+			value *uint64
+		}{
+			{index: 3, value: &stat.ReadIOs},
+			{index: 5, value: &stat.ReadSectors},
+			{index: 7, value: &stat.WriteIOs},
+			{index: 9, value: &stat.WriteSectors},
+		} {
+			v, err := strconv.ParseUint(fields[t.index], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse diskstats line %q, err: %w", line, err)
+			}
+			*t.value = v
+		}
+		stats[fields[2]] = stat
+	}
+	return stats, nil
+}
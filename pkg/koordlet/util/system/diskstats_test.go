@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiskStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]*DiskStat
+		wantErr bool
+	}{
+		{
+			name: "parse diskstats",
+			content: `   8       0 sda 16358 3851 1209298 9536 10632 10207 459994 10470 0 24736 20007 0 0 0 0
+   8       1 sda1 16264 3851 1204986 9498 10632 10207 459994 10470 0 24696 19969 0 0 0 0
+   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0
+`,
+			want: map[string]*DiskStat{
+				"sda": {
+					ReadIOs:      16358,
+					ReadSectors:  1209298,
+					WriteIOs:     10632,
+					WriteSectors: 459994,
+				},
+				"sda1": {
+					ReadIOs:      16264,
+					ReadSectors:  1204986,
+					WriteIOs:     10632,
+					WriteSectors: 459994,
+				},
+				"loop0": {},
+			},
+		},
+		{
+			name:    "fields not enough",
+			content: `   8       0 sda 16358 3851 1209298`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			content: `   8       0 sda invalid 3851 1209298 9536 10632 10207 459994 10470 0 24736 20007 0 0 0 0`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := ParseDiskStats(tt.content)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsPhysicalDisk(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.MkDirAll(filepath.Join(SysBlockSubDir, "sda"))
+	helper.MkDirAll(filepath.Join(SysBlockSubDir, "loop0"))
+	helper.MkDirAll(filepath.Join(SysVirtualBlockSubDir, "loop0"))
+	assert.True(t, IsPhysicalDisk("sda"))
+	assert.False(t, IsPhysicalDisk("sda1"))
+	assert.False(t, IsPhysicalDisk("loop0"))
+}
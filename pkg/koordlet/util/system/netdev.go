@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	ProcNetRouteName = "net/route"
+	ProcNetDevName   = "net/dev"
+
+	SysNetSubDir        = "class/net"
+	SysNetSpeedSubPath  = "speed"
+	SysVirtualNetSubDir = "devices/virtual/net"
+
+	// LoopbackInterfaceName is the name of the loopback network interface
+	LoopbackInterfaceName = "lo"
+
+	// defaultRouteDestination is the destination of the default route in /proc/net/route
+	defaultRouteDestination = "00000000"
+)
+
+func GetProcNetRoutePath() string {
+	return filepath.Join(Conf.ProcRootDir, ProcNetRouteName)
+}
+
+func GetProcNetDevPath() string {
+	return filepath.Join(Conf.ProcRootDir, ProcNetDevName)
+}
+
+// GetProcPIDNetDevPath returns the net/dev path of the network namespace which the process belongs to.
+func GetProcPIDNetDevPath(pid int32) string {
+	return filepath.Join(Conf.ProcRootDir, strconv.FormatInt(int64(pid), 10), ProcNetDevName)
+}
+
+func GetNetInterfaceSpeedPath(ifName string) string {
+	return filepath.Join(Conf.SysRootDir, SysNetSubDir, ifName, SysNetSpeedSubPath)
+}
+
+// GetDefaultRouteInterface returns the name of the network interface which the default route goes through.
+// e.g.
+// Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+// eth0	00000000	FDFFA8C0	0003	0	0	0	00000000	0	0	0
+func GetDefaultRouteInterface() (string, error) {
+	content, err := os.ReadFile(GetProcNetRoutePath())
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 2 { // skip the header
+			continue
+		}
+		if fields[1] == defaultRouteDestination {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("default route not found in %s", GetProcNetRoutePath())
+}
+
+// GetNetInterfaceSpeedMbps returns the link speed of the network interface in Mbps.
+func GetNetInterfaceSpeedMbps(ifName string) (int64, error) {
+	content, err := os.ReadFile(GetNetInterfaceSpeedPath(ifName))
+	if err != nil {
+		return 0, err
+	}
+	speed, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse speed of interface %s, err: %w", ifName, err)
+	}
+	// virtual or down interfaces report -1 or 0
+	if speed <= 0 {
+		return 0, fmt.Errorf("speed of interface %s is unknown: %d", ifName, speed)
+	}
+	return speed, nil
+}
+
+// NetDevStat is the traffic counters of a network interface in /proc/net/dev.
+type NetDevStat struct {
+	RxBytes   uint64
+	RxPackets uint64
+	TxBytes   uint64
+	TxPackets uint64
+}
+
+// IsVirtualNetInterface returns whether the network interface is a virtual device, e.g. veth, bridge, tunnel.
+func IsVirtualNetInterface(ifName string) bool {
+	return FileExists(filepath.Join(Conf.SysRootDir, SysVirtualNetSubDir, ifName))
+}
+
+// GetNetDevStats reads and parses the net/dev file at the given path.
+func GetNetDevStats(netDevPath string) (map[string]*NetDevStat, error) {
+	content, err := os.ReadFile(netDevPath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNetDevStats(string(content))
+}
+
+// ParseNetDevStats parses the content of /proc/net/dev into the stats of each interface.
+// e.g.
+// Inter-|   Receive                                                |  Transmit
+//
+//	face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+//	  lo: 2776770   11307    0    0    0     0          0         0  2776770   11307    0    0    0     0       0          0
+//	eth0: 1215645    2751    0    0    0     0          0         0  1782404    4324    0    0    0   427       0          0
+func ParseNetDevStats(content string) (map[string]*NetDevStat, error) {
+	stats := map[string]*NetDevStat{}
+	for _, line := range strings.Split(content, "\n") {
+		sepIdx := strings.Index(line, ":")
+		if sepIdx < 0 { // skip the headers
+			continue
+		}
+		ifName := strings.TrimSpace(line[:sepIdx])
+		fields := strings.Fields(line[sepIdx+1:])
+		if len(fields) < 10 {
+			return nil, fmt.Errorf("failed to parse net dev line %q, fields not enough", line)
+		}
+		stat := &NetDevStat{}
+		for _, t := range []struct {
+			index int
+			value *uint64
+		}{
+			{index: 0, value: &stat.RxBytes},
+			{index: 1, value: &stat.RxPackets},
+			{index: 8, value: &stat.TxBytes},
+			{index: 9, value: &stat.TxPackets},
+		} {
+			v, err := strconv.ParseUint(fields[t.index], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse net dev line %q, err: %w", line, err)
+			}
+			*t.value = v
+		}
+		stats[ifName] = stat
+	}
+	return stats, nil
+}
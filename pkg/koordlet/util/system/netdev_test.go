@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDefaultRouteInterface(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "route file not exist",
+			wantErr: true,
+		},
+		{
+			name: "parse default route",
+			content: `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+docker0	000011AC	00000000	0001	0	0	0	0000FFFF	0	0	0
+eth0	00000000	FDFFA8C0	0003	0	0	0	00000000	0	0	0
+eth0	00FFA8C0	00000000	0001	0	0	0	00FFFFFF	0	0	0
+`,
+			want: "eth0",
+		},
+		{
+			name: "no default route",
+			content: `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00FFA8C0	00000000	0001	0	0	0	00FFFFFF	0	0	0
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.content != "" {
+				helper.WriteProcSubFileContents(ProcNetRouteName, tt.content)
+			}
+			got, gotErr := GetDefaultRouteInterface()
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetNetInterfaceSpeedMbps(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:    "speed file not exist",
+			wantErr: true,
+		},
+		{
+			name:    "parse speed",
+			content: "10000\n",
+			want:    10000,
+		},
+		{
+			name:    "unknown speed",
+			content: "-1\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid speed",
+			content: "invalid\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.content != "" {
+				helper.WriteFileContents(filepath.Join(SysNetSubDir, "eth0", SysNetSpeedSubPath), tt.content)
+			}
+			got, gotErr := GetNetInterfaceSpeedMbps("eth0")
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseNetDevStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]*NetDevStat
+		wantErr bool
+	}{
+		{
+			name: "parse net dev",
+			content: `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 2776770   11307    0    0    0     0          0         0  2776770   11307    0    0    0     0       0          0
+  eth0: 1215645    2751    0    0    0     0          0         0  1782404    4324    0    0    0   427       0          0
+`,
+			want: map[string]*NetDevStat{
+				"lo": {
+					RxBytes:   2776770,
+					RxPackets: 11307,
+					TxBytes:   2776770,
+					TxPackets: 11307,
+				},
+				"eth0": {
+					RxBytes:   1215645,
+					RxPackets: 2751,
+					TxBytes:   1782404,
+					TxPackets: 4324,
+				},
+			},
+		},
+		{
+			name:    "fields not enough",
+			content: `  eth0: 1215645    2751    0    0`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			content: `  eth0: 1215645    2751    0    0    0     0          0         0  invalid    4324    0    0    0   427       0          0`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := ParseNetDevStats(tt.content)
+			assert.Equal(t, tt.wantErr, gotErr != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsVirtualNetInterface(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.MkDirAll(filepath.Join(SysVirtualNetSubDir, "veth0"))
+	assert.True(t, IsVirtualNetInterface("veth0"))
+	assert.False(t, IsVirtualNetInterface("eth0"))
+}
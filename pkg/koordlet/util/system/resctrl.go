@@ -36,9 +36,18 @@ const (
 	RdtInfoDir string = "info"
 	L3CatDir   string = "L3"
 
-	ResctrlSchemataName string = "schemata"
-	ResctrlCbmMaskName  string = "cbm_mask"
-	ResctrlTasksName    string = "tasks"
+	ResctrlSchemataName   string = "schemata"
+	ResctrlCbmMaskName    string = "cbm_mask"
+	ResctrlNumClosidsName string = "num_closids"
+	ResctrlTasksName      string = "tasks"
+
+	// ResctrlMonDataDir is the dir of the monitoring data of a resctrl group, which contains a sub dir for each l3 domain
+	ResctrlMonDataDir        string = "mon_data"
+	ResctrlMonL3DirPrefix    string = "mon_L3_"
+	ResctrlLLCOccupancyName  string = "llc_occupancy"
+	ResctrlMBMTotalBytesName string = "mbm_total_bytes"
+	ResctrlMBMLocalBytesName string = "mbm_local_bytes"
+	resctrlMonUnavailable    string = "Unavailable"
 
 	// L3SchemataPrefix is the prefix of l3 cat schemata
 	L3SchemataPrefix = "L3"
@@ -105,6 +114,7 @@ var (
 	ResctrlSchemata  = NewCommonResctrlResource(ResctrlSchemataName, "")
 	ResctrlTasks     = NewCommonResctrlResource(ResctrlTasksName, "")
 	ResctrlL3CbmMask = NewCommonResctrlResource(ResctrlCbmMaskName, filepath.Join(RdtInfoDir, L3CatDir))
+	ResctrlL3Closids = NewCommonResctrlResource(ResctrlNumClosidsName, filepath.Join(RdtInfoDir, L3CatDir))
 )
 
 var _ Resource = &ResctrlResource{}
@@ -475,6 +485,79 @@ func ReadCatL3CbmString() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// ReadCatL3NumClosids reads and returns the number of the CLOSIDs for cat l3, including the one of the resctrl root
+func ReadCatL3NumClosids() (int, error) {
+	numClosidsFile := ResctrlL3Closids.Path("")
+	out, err := os.ReadFile(numClosidsFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read l3 num_closids, path %s, err: %v", numClosidsFile, err)
+	}
+	numClosids, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse l3 num_closids, path %s, err: %v", numClosidsFile, err)
+	}
+	return numClosids, nil
+}
+
+// ListResctrlGroups returns the names of the resctrl control groups under the resctrl root, excluding the root itself.
+func ListResctrlGroups() ([]string, error) {
+	entries, err := os.ReadDir(GetResctrlSubsystemDirPath())
+	if err != nil {
+		return nil, err
+	}
+	var groups []string
+	for _, entry := range entries {
+		// a control group has the tasks file, while the info and mon_groups dirs do not
+		if !entry.IsDir() || !FileExists(GetResctrlTasksFilePath(entry.Name())) {
+			continue
+		}
+		groups = append(groups, entry.Name())
+	}
+	return groups, nil
+}
+
+// ReadResctrlMonData reads the monitoring data of the resctrl group and sums it over the l3 domains.
+// e.g. /sys/fs/resctrl/LS/mon_data/mon_L3_00/llc_occupancy -> {"llc_occupancy": 1048576}
+// The monitoring files are omitted if they are unavailable on any of the domains.
+func ReadResctrlMonData(groupPath string) (map[string]uint64, error) {
+	monDataDir := filepath.Join(GetResctrlGroupRootDirPath(groupPath), ResctrlMonDataDir)
+	entries, err := os.ReadDir(monDataDir)
+	if err != nil {
+		return nil, err
+	}
+	monData := map[string]uint64{}
+	unavailable := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), ResctrlMonL3DirPrefix) {
+			continue
+		}
+		for _, name := range []string{ResctrlLLCOccupancyName, ResctrlMBMTotalBytesName, ResctrlMBMLocalBytesName} {
+			content, err := os.ReadFile(filepath.Join(monDataDir, entry.Name(), name))
+			if err != nil {
+				if os.IsNotExist(err) { // the monitoring feature is not supported
+					unavailable[name] = true
+					continue
+				}
+				return nil, err
+			}
+			valueStr := strings.TrimSpace(string(content))
+			if valueStr == resctrlMonUnavailable {
+				unavailable[name] = true
+				continue
+			}
+			value, err := strconv.ParseUint(valueStr, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s of domain %s, err: %v", name, entry.Name(), err)
+			}
+			monData[name] += value
+		}
+	}
+	for name := range unavailable {
+		delete(monData, name)
+	}
+	return monData, nil
+}
+
 // ReadResctrlTasksMap reads and returns the map of given resctrl group's task ids
 func ReadResctrlTasksMap(groupPath string) (map[int32]struct{}, error) {
 	tasksPath := GetResctrlTasksFilePath(groupPath)
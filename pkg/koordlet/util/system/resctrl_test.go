@@ -96,6 +96,128 @@ func Test_ReadResctrlTasksMap(t *testing.T) {
 	}
 }
 
+func Test_ReadCatL3NumClosids(t *testing.T) {
+	tests := []struct {
+		name       string
+		numClosids string
+		want       int
+		wantErr    bool
+	}{
+		{
+			name:    "file not exist",
+			wantErr: true,
+		},
+		{
+			name:       "parse correctly",
+			numClosids: "16\n",
+			want:       16,
+		},
+		{
+			name:       "parse error for invalid content",
+			numClosids: "1a\n",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sysFSRootDir := t.TempDir()
+			Conf = &Config{
+				SysFSRootDir: sysFSRootDir,
+			}
+			if tt.numClosids != "" {
+				l3CatDir := filepath.Join(sysFSRootDir, ResctrlDir, RdtInfoDir, L3CatDir)
+				err := os.MkdirAll(l3CatDir, 0700)
+				assert.NoError(t, err)
+				err = os.WriteFile(filepath.Join(l3CatDir, ResctrlNumClosidsName), []byte(tt.numClosids), 0666)
+				assert.NoError(t, err)
+			}
+
+			got, err := ReadCatL3NumClosids()
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ListResctrlGroups(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	_, err := ListResctrlGroups()
+	assert.Error(t, err)
+
+	helper.WriteFileContents(GetResctrlTasksFilePath(""), "")
+	helper.WriteFileContents(GetResctrlTasksFilePath("LS"), "")
+	helper.WriteFileContents(GetResctrlTasksFilePath("BE"), "")
+	helper.MkDirAll(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, L3CatDir))
+	helper.MkDirAll(filepath.Join(GetResctrlSubsystemDirPath(), "mon_groups"))
+	got, err := ListResctrlGroups()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BE", "LS"}, got)
+}
+
+func Test_ReadResctrlMonData(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    map[string]uint64
+		wantErr bool
+	}{
+		{
+			name:    "mon data not exist",
+			wantErr: true,
+		},
+		{
+			name: "sum over l3 domains",
+			files: map[string]string{
+				"mon_L3_00/llc_occupancy":   "1048576\n",
+				"mon_L3_00/mbm_total_bytes": "2000\n",
+				"mon_L3_00/mbm_local_bytes": "1000\n",
+				"mon_L3_01/llc_occupancy":   "1048576\n",
+				"mon_L3_01/mbm_total_bytes": "3000\n",
+				"mon_L3_01/mbm_local_bytes": "1000\n",
+			},
+			want: map[string]uint64{
+				ResctrlLLCOccupancyName:  2097152,
+				ResctrlMBMTotalBytesName: 5000,
+				ResctrlMBMLocalBytesName: 2000,
+			},
+		},
+		{
+			name: "omit unavailable and unsupported counters",
+			files: map[string]string{
+				"mon_L3_00/llc_occupancy":   "1048576\n",
+				"mon_L3_00/mbm_total_bytes": "Unavailable\n",
+				"mon_L3_01/llc_occupancy":   "1048576\n",
+				"mon_L3_01/mbm_total_bytes": "3000\n",
+			},
+			want: map[string]uint64{
+				ResctrlLLCOccupancyName: 2097152,
+			},
+		},
+		{
+			name: "parse error for invalid content",
+			files: map[string]string{
+				"mon_L3_00/llc_occupancy": "1a\n",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewFileTestUtil(t)
+			defer helper.Cleanup()
+			monDataDir := filepath.Join(GetResctrlGroupRootDirPath("LS"), ResctrlMonDataDir)
+			for name, content := range tt.files {
+				helper.WriteFileContents(filepath.Join(monDataDir, name), content)
+			}
+
+			got, err := ReadResctrlMonData("LS")
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestResctrlSchemataRaw(t *testing.T) {
 	type fields struct {
 		l3Num     int
@@ -31,11 +31,16 @@ func Test_GetInstance(t *testing.T) {
 		CPUSuppressThresholdPercent:        pointer.Int64(-1),
 		CPUEvictBESatisfactionUpperPercent: pointer.Int64(70),
 		CPUEvictBESatisfactionLowerPercent: pointer.Int64(70),
+		MemoryEvictThresholdPercent:        pointer.Int64(70),
+		MemoryEvictKillThresholdPercent:    pointer.Int64(60),
+		MemorySuppressThresholdPercent:     pointer.Int64(80),
 	}
 	info, err := GetValidatorInstance().StructWithTrans(strategy)
 	fmt.Println(info)
 	assert.True(t, info["ResourceThresholdStrategy.CPUEvictBESatisfactionUpperPercent"] != "", info["ResourceThresholdStrategy.CPUEvictBESatisfactionUpperPercent"])
 	assert.True(t, info["ResourceThresholdStrategy.CPUEvictBESatisfactionLowerPercent"] != "", info["ResourceThresholdStrategy.CPUEvictBESatisfactionLowerPercent"])
 	assert.True(t, info["ResourceThresholdStrategy.CPUSuppressThresholdPercent"] != "", info["ResourceThresholdStrategy.CPUSuppressThresholdPercent"])
+	assert.True(t, info["ResourceThresholdStrategy.MemoryEvictKillThresholdPercent"] != "", info["ResourceThresholdStrategy.MemoryEvictKillThresholdPercent"])
+	assert.True(t, info["ResourceThresholdStrategy.MemorySuppressThresholdPercent"] != "", info["ResourceThresholdStrategy.MemorySuppressThresholdPercent"])
 	assert.NoError(t, err)
 }